@@ -3,6 +3,7 @@ package methodstixobjects
 import (
 	"github.com/av-belyakov/methodstixobjects/commonlibs"
 	"github.com/av-belyakov/methodstixobjects/datamodels/commonpropertiesstixdo"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
 	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
 )
 
@@ -12,8 +13,19 @@ func NewCommonPropertiesDomainObjectSTIX() *commonpropertiesstixdo.CommonPropert
 		Created:            commonlibs.TimeNowRFC3339(),
 		Modified:           "1970-01-01T00:00:00+00:00",
 		Labels:             make([]string, 0),
-		Extensions:         map[string]string{},
+		Extensions:         stixhelpers.ExtensionsTypeSTIX{},
 		ExternalReferences: make([]stixhelpers.ExternalReferenceTypeElementSTIX, 0),
 		ObjectMarkingRefs:  make([]stixhelpers.IdentifierTypeSTIX, 0),
 	}
 }
+
+// newIncidentCoreCommonPropertiesDomainObjectSTIX создает общие свойства объектов определяемых расширением "incident-core"
+// ("event", "impact", "task"), расширения таких объектов содержат признак "new-sdo" с идентификатором id
+func newIncidentCoreCommonPropertiesDomainObjectSTIX(id string) *commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX {
+	cpdo := NewCommonPropertiesDomainObjectSTIX()
+	cpdo.SetValueExtensions(id, domainobjectsstix.NewSDOExtensionSTIX{
+		ExtensionType: stixhelpers.ExtensionTypeNewSDO,
+	})
+
+	return cpdo
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
 	"github.com/av-belyakov/methodstixobjects/datamodels/somecomplextypesstixco"
-	"github.com/av-belyakov/methodstixobjects/datamodels/someextensionsstixco"
 	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
 )
 
@@ -277,7 +276,7 @@ func NewUserAccountCyberObservableObjectSTIX() *cyberobservableobjectsstix.UserA
 		CredentialLastChanged: "1970-01-01T00:00:00+00:00",
 		AccountFirstLogin:     "1970-01-01T00:00:00+00:00",
 		AccountLastLogin:      "1970-01-01T00:00:00+00:00",
-		Extensions:            make(map[string]someextensionsstixco.UNIXAccountExtensionSTIX),
+		Extensions:            make(map[string]interface{}),
 	}
 }
 
@@ -44,6 +44,20 @@ func NewCourseOfActionDomainObjectsSTIX() *domainobjectsstix.CourseOfActionDomai
 	}
 }
 
+// NewEventDomainObjectsSTIX создает STIX объект "Event", по терминалогии STIX, описывающий событие произошедшее в рамках инцидента
+func NewEventDomainObjectsSTIX() *domainobjectsstix.EventDomainObjectsSTIX {
+	cpo := NewCommonPropertiesObjectSTIX()
+	cpo.SetAnyType("event")
+
+	return &domainobjectsstix.EventDomainObjectsSTIX{
+		CommonPropertiesObjectSTIX:       *cpo.Get(),
+		CommonPropertiesDomainObjectSTIX: *newIncidentCoreCommonPropertiesDomainObjectSTIX(domainobjectsstix.EventExtensionDefinitionID),
+		EventTypes:                       []stixhelpers.OpenVocabTypeSTIX(nil),
+		SightingRefs:                     []stixhelpers.IdentifierTypeSTIX(nil),
+		ChangedObjects:                   []domainobjectsstix.StateChangeTypeSTIX(nil),
+	}
+}
+
 // NewGroupingDomainObjectsSTIX создает STIX объект "Grouping", по терминалогии STIX, объединяет различные объекты STIX в рамках какого то общего контекста
 func NewGroupingDomainObjectsSTIX() *domainobjectsstix.GroupingDomainObjectsSTIX {
 	cpo := NewCommonPropertiesObjectSTIX()
@@ -69,6 +83,31 @@ func NewIdentityDomainObjectsSTIX() *domainobjectsstix.IdentityDomainObjectsSTIX
 	}
 }
 
+// NewImpactDomainObjectsSTIX создает STIX объект "Impact", по терминалогии STIX, описывающий последствия инцидента
+func NewImpactDomainObjectsSTIX() *domainobjectsstix.ImpactDomainObjectsSTIX {
+	cpo := NewCommonPropertiesObjectSTIX()
+	cpo.SetAnyType("impact")
+
+	return &domainobjectsstix.ImpactDomainObjectsSTIX{
+		CommonPropertiesObjectSTIX:       *cpo.Get(),
+		CommonPropertiesDomainObjectSTIX: *newIncidentCoreCommonPropertiesDomainObjectSTIX(domainobjectsstix.ImpactExtensionDefinitionID),
+		ImpactedEntityCounts:             map[string]int{},
+		ImpactedRefs:                     []stixhelpers.IdentifierTypeSTIX(nil),
+	}
+}
+
+// NewIncidentDomainObjectsSTIX создает STIX объект "Incident", по терминалогии STIX, содержащий сведения об инциденте информационной безопасности
+func NewIncidentDomainObjectsSTIX() *domainobjectsstix.IncidentDomainObjectsSTIX {
+	cpo := NewCommonPropertiesObjectSTIX()
+	cpo.SetAnyType("incident")
+
+	return &domainobjectsstix.IncidentDomainObjectsSTIX{
+		CommonPropertiesObjectSTIX:       *cpo.Get(),
+		CommonPropertiesDomainObjectSTIX: *NewCommonPropertiesDomainObjectSTIX(),
+		KillChainPhases:                  []stixhelpers.KillChainPhasesTypeElementSTIX(nil),
+	}
+}
+
 // NewIndicatorDomainObjectsSTIX создает STIX объект "Indicator", по терминалогии STIX, содержит шаблон который может быть использован для обнаружения
 // подозрительной или вредоносной киберактивности
 func NewIndicatorDomainObjectsSTIX() *domainobjectsstix.IndicatorDomainObjectsSTIX {
@@ -228,6 +267,20 @@ func NewReportDomainObjectsSTIX() *domainobjectsstix.ReportDomainObjectsSTIX {
 	}
 }
 
+// NewTaskDomainObjectsSTIX создает STIX объект "Task", по терминалогии STIX, описывающий задачу по реагированию на инцидент
+func NewTaskDomainObjectsSTIX() *domainobjectsstix.TaskDomainObjectsSTIX {
+	cpo := NewCommonPropertiesObjectSTIX()
+	cpo.SetAnyType("task")
+
+	return &domainobjectsstix.TaskDomainObjectsSTIX{
+		CommonPropertiesObjectSTIX:       *cpo.Get(),
+		CommonPropertiesDomainObjectSTIX: *newIncidentCoreCommonPropertiesDomainObjectSTIX(domainobjectsstix.TaskExtensionDefinitionID),
+		TaskTypes:                        []stixhelpers.OpenVocabTypeSTIX(nil),
+		ImpactedEntityCounts:             map[string]int{},
+		ChangedObjects:                   []domainobjectsstix.StateChangeTypeSTIX(nil),
+	}
+}
+
 // NewThreatActorDomainObjectsSTIX создает STIX объект "Threat Actor", по терминалогии STIX, содержит информацию о физических лицах или их
 // группах и организациях которые могут действовать со злым умыслом.
 func NewThreatActorDomainObjectsSTIX() *domainobjectsstix.ThreatActorDomainObjectsSTIX {
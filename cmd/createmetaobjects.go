@@ -0,0 +1,65 @@
+package methodstixobjects
+
+import (
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// NewMarkingDefinitionObjectSTIX создает объект "marking-definition", по терминалогии STIX, содержащий метки данных
+// ссылающиеся на требования к обработке или совместному использованию данных, с уникальным идентификатором
+func NewMarkingDefinitionObjectSTIX() *stixhelpers.MarkingDefinitionObjectSTIX {
+	return &stixhelpers.MarkingDefinitionObjectSTIX{
+		CommonDataMarkingsTypeSTIX: stixhelpers.CommonDataMarkingsTypeSTIX{
+			ID:          "marking-definition--" + commonlibs.GetUUIDv4(),
+			SpecVersion: "2.1",
+			Created:     commonlibs.TimeNowRFC3339(),
+		},
+		Type:               "marking-definition",
+		Definition:         map[string]string{},
+		ExternalReferences: make([]stixhelpers.ExternalReferenceTypeElementSTIX, 0),
+		ObjectMarkingRefs:  make([]stixhelpers.IdentifierTypeSTIX, 0),
+	}
+}
+
+// NewStatementMarkingDefinitionObjectSTIX создает объект "marking-definition" с маркером типа "statement",
+// например, "Copyright 2019, Example Corp"
+func NewStatementMarkingDefinitionObjectSTIX(statement string) *stixhelpers.MarkingDefinitionObjectSTIX {
+	md := NewMarkingDefinitionObjectSTIX()
+	md.SetValueStatement(statement)
+
+	return md
+}
+
+// NewLanguageContentObjectSTIX создает объект "language-content", по терминалогии STIX, содержащий переводы
+// текстовых свойств объекта STIX на языки отличные от языка исходного объекта
+func NewLanguageContentObjectSTIX() *stixhelpers.LanguageContentTypeSTIX {
+	return &stixhelpers.LanguageContentTypeSTIX{
+		Type:               "language-content",
+		ID:                 "language-content--" + commonlibs.GetUUIDv4(),
+		SpecVersion:        "2.1",
+		Created:            commonlibs.TimeNowRFC3339(),
+		Modified:           commonlibs.TimeNowRFC3339(),
+		ObjectModified:     "1970-01-01T00:00:00+00:00",
+		Contents:           map[string]map[string]interface{}{},
+		Labels:             make([]string, 0),
+		ExternalReferences: make([]stixhelpers.ExternalReferenceTypeElementSTIX, 0),
+		ObjectMarkingRefs:  make([]stixhelpers.IdentifierTypeSTIX, 0),
+	}
+}
+
+// NewExtensionDefinitionObjectSTIX создает объект "extension-definition", по терминалогии STIX, описывающий
+// расширение спецификации STIX, с уникальным идентификатором
+func NewExtensionDefinitionObjectSTIX() *stixhelpers.ExtensionDefinitionObjectSTIX {
+	return &stixhelpers.ExtensionDefinitionObjectSTIX{
+		Type:                "extension-definition",
+		ID:                  "extension-definition--" + commonlibs.GetUUIDv4(),
+		SpecVersion:         "2.1",
+		Created:             commonlibs.TimeNowRFC3339(),
+		Modified:            commonlibs.TimeNowRFC3339(),
+		Labels:              make([]string, 0),
+		ExternalReferences:  make([]stixhelpers.ExternalReferenceTypeElementSTIX, 0),
+		ObjectMarkingRefs:   make([]stixhelpers.IdentifierTypeSTIX, 0),
+		ExtensionTypes:      make([]stixhelpers.EnumTypeSTIX, 0),
+		ExtensionProperties: make([]string, 0),
+	}
+}
@@ -9,8 +9,9 @@ import (
 // свойства для все объектов STIX типа Relationship Objects
 func NewOptionalCommonPropertiesRelationshipObjectSTIX() *relationshipobjectsstix.OptionalCommonPropertiesRelationshipObjectSTIX {
 	return &relationshipobjectsstix.OptionalCommonPropertiesRelationshipObjectSTIX{
-		Created:  "1970-01-01T00:00:00+00:00",
-		Modified: "1970-01-01T00:00:00+00:00",
+		Created:    "1970-01-01T00:00:00+00:00",
+		Modified:   "1970-01-01T00:00:00+00:00",
+		Extensions: stixhelpers.ExtensionsTypeSTIX{},
 	}
 }
 
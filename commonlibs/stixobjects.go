@@ -0,0 +1,103 @@
+package commonlibs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ParseTimestamp разбирает время в формате RFC3339
+func ParseTimestamp(v string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, v)
+}
+
+// CompareTimestamps сравнивает два значения времени в формате RFC3339 с учетом часового пояса,
+// возвращает -1, 0 или 1. Если значения не удается разобрать, они сравниваются как строки
+func CompareTimestamps(a, b string) int {
+	ta, errA := ParseTimestamp(a)
+	tb, errB := ParseTimestamp(b)
+	if errA != nil || errB != nil {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+
+		return 0
+	}
+
+	switch {
+	case ta.Before(tb):
+		return -1
+	case ta.After(tb):
+		return 1
+	}
+
+	return 0
+}
+
+// ToMap преобразует объект STIX (тип пакета methodstixobjects или json.RawMessage) в map[string]interface{}
+func ToMap(obj interface{}) (map[string]interface{}, error) {
+	var (
+		b   []byte
+		err error
+	)
+
+	switch v := obj.(type) {
+	case json.RawMessage:
+		b = v
+	case *json.RawMessage:
+		if v == nil {
+			return nil, fmt.Errorf("the object must not be empty")
+		}
+		b = *v
+	default:
+		if b, err = json.Marshal(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	m := map[string]interface{}{}
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FromMap создает новое значение того же типа, что и original (тип пакета methodstixobjects или json.RawMessage),
+// заполненное данными m
+func FromMap(m map[string]interface{}, original interface{}) (interface{}, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	switch original.(type) {
+	case json.RawMessage:
+		return json.RawMessage(b), nil
+	case *json.RawMessage:
+		raw := json.RawMessage(b)
+
+		return &raw, nil
+	}
+
+	t := reflect.TypeOf(original)
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+
+	v := reflect.New(t)
+	if err := json.Unmarshal(b, v.Interface()); err != nil {
+		return nil, err
+	}
+
+	if isPtr {
+		return v.Interface(), nil
+	}
+
+	return v.Elem().Interface(), nil
+}
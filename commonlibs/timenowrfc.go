@@ -5,6 +5,9 @@ import (
 	"time"
 )
 
+// DefaultDateTime значение времени, которым по умолчанию заполняются поля объектов STIX
+const DefaultDateTime = "1970-01-01T00:00:00+00:00"
+
 // TimeNowRFC3339 возвращает текущее время в формате RFC3339
 func TimeNowRFC3339() string {
 	return fmt.Sprint(time.Now().Format(time.RFC3339))
@@ -0,0 +1,47 @@
+package commonlibs
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// STIXNamespaceUUID пространство имен, используемое спецификацией STIX 2.1 для формирования
+// детерминированных идентификаторов объектов
+const STIXNamespaceUUID = "00abedb4-aa42-466c-9c01-fed23315a9b7"
+
+// GetUUIDv4 возвращает случайный UUID версии 4, например, "ff26c055-6336-4bc5-b98d-13d6226742dd"
+func GetUUIDv4() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "00000000-0000-4000-8000-000000000000"
+	}
+
+	//версия 4 и вариант RFC 4122
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// GetUUIDv5 возвращает UUID версии 5, сформированный на основании пространства имен namespace
+// (UUID в строковом виде) и имени name. Для одинаковых значений namespace и name результат всегда одинаков
+func GetUUIDv5(namespace, name string) (string, error) {
+	ns, err := hex.DecodeString(strings.ReplaceAll(namespace, "-", ""))
+	if err != nil || len(ns) != 16 {
+		return "", fmt.Errorf("invalid namespace UUID '%s'", namespace)
+	}
+
+	h := sha1.New()
+	h.Write(ns)
+	h.Write([]byte(name))
+	b := h.Sum(nil)[:16]
+
+	//версия 5 и вариант RFC 4122
+	b[6] = (b[6] & 0x0f) | 0x50
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
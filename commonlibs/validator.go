@@ -64,6 +64,7 @@ const (
 	ssn                      string = `^\d{3}[- ]?\d{2}[- ]?\d{4}$`
 	winPath                  string = `^[a-zA-Z]:\\(?:[^\\/:*?"<>|\r\n]+\\)*[^\\/:*?"<>|\r\n]*$`
 	unixPath                 string = `^(/[^/\x00]*)+/?$`
+	languageTag              string = `^((?i)(([a-z]{2,3}(-[a-z]{3}){0,3})|[a-z]{4,8})(-[a-z]{4})?(-([a-z]{2}|[0-9]{3}))?(-([0-9a-z]{5,8}|[0-9][0-9a-z]{3}))*(-[0-9a-wy-z](-[0-9a-z]{2,8})+)*(-x(-[0-9a-z]{1,8})+)?|x(-[0-9a-z]{1,8})+)$`
 )
 
 //IsIPAddress проверяет значение на соответствие его ip адресу сети Интернет
@@ -269,6 +270,13 @@ func IsURLSubdomain(value string) bool {
 	return patterCheckFileName.MatchString(value)
 }
 
+//IsLanguageTag проверяет значение на соответствие его языковому тегу RFC 5646, например, "en", "ru-RU" или "zh-Hans-CN"
+func IsLanguageTag(value string) bool {
+	patterCheckFileName := regexp.MustCompile(languageTag)
+
+	return patterCheckFileName.MatchString(value)
+}
+
 /*// проверяет значение на соответствие
 func (value string) bool {
 	patterCheckFileName := regexp.MustCompile()
@@ -54,6 +54,11 @@ func (e *OptionalCommonPropertiesCyberObservableObjectSTIX) SetValueObjectMarkin
 	e.ObjectMarkingRefs = v
 }
 
+// GetTLPLevel возвращает наиболее ограничивающий уровень TLP (1.0 или 2.0) из указанных в поле ObjectMarkingRefs
+func (e *OptionalCommonPropertiesCyberObservableObjectSTIX) GetTLPLevel() stixhelpers.TLPLevelSTIX {
+	return stixhelpers.GetMostRestrictiveTLPLevel(e.ObjectMarkingRefs)
+}
+
 // -------- GranularMarkings property ---------
 func (e *OptionalCommonPropertiesCyberObservableObjectSTIX) GetGranularMarkings() []stixhelpers.GranularMarkingsTypeSTIX {
 	return e.GranularMarkings
@@ -153,18 +153,39 @@ func (e *CommonPropertiesDomainObjectSTIX) SetAnyLabels(i interface{}) {
 }
 
 // -------- Extensions property ---------
-func (e *CommonPropertiesDomainObjectSTIX) GetExtensions() map[string]string {
+func (e *CommonPropertiesDomainObjectSTIX) GetExtensions() stixhelpers.ExtensionsTypeSTIX {
 	return e.Extensions
 }
 
-// SetValueExtensions устанавливает значение для поля Extensions
-func (e *CommonPropertiesDomainObjectSTIX) SetValueExtensions(k, v string) {
+// GetExtension возвращает содержимое расширения, для зарегистрированных расширений в виде
+// значения зарегистрированного типа
+func (e *CommonPropertiesDomainObjectSTIX) GetExtension(k string) (interface{}, bool, error) {
+	return e.Extensions.GetExtension(k)
+}
+
+// SetValueExtensions устанавливает значение расширения k для поля Extensions
+func (e *CommonPropertiesDomainObjectSTIX) SetValueExtensions(k string, v interface{}) {
+	if e.Extensions == nil {
+		e.Extensions = stixhelpers.ExtensionsTypeSTIX{}
+	}
+
 	e.Extensions[k] = v
 }
 
-// SetAnyExtensions устанавливает ЛЮБОЕ значение для поля Extensions
+// SetAnyExtensions устанавливает ЛЮБОЕ значение расширения k для поля Extensions, значение
+// преобразуется в строку
 func (e *CommonPropertiesDomainObjectSTIX) SetAnyExtensions(k string, i interface{}) {
-	e.Extensions[k] = fmt.Sprint(i)
+	e.SetValueExtensions(k, fmt.Sprint(i))
+}
+
+// SetAnyTypedExtensions устанавливает ЛЮБОЕ значение расширения k для поля Extensions без преобразования
+// в строку, если расширение зарегистрировано значение преобразуется к зарегистрированному типу
+func (e *CommonPropertiesDomainObjectSTIX) SetAnyTypedExtensions(k string, i interface{}) {
+	e.SetValueExtensions(k, i)
+
+	if v, ok, err := e.Extensions.GetExtension(k); ok && err == nil {
+		e.Extensions[k] = v
+	}
 }
 
 // -------- CreatedByRef property ---------
@@ -194,6 +215,11 @@ func (e *CommonPropertiesDomainObjectSTIX) SetValueObjectMarkingRefs(v []stixhel
 	e.ObjectMarkingRefs = v
 }
 
+// GetTLPLevel возвращает наиболее ограничивающий уровень TLP (1.0 или 2.0) из указанных в поле ObjectMarkingRefs
+func (e *CommonPropertiesDomainObjectSTIX) GetTLPLevel() stixhelpers.TLPLevelSTIX {
+	return stixhelpers.GetMostRestrictiveTLPLevel(e.ObjectMarkingRefs)
+}
+
 // -------- GranularMarkings property ---------
 func (e *CommonPropertiesDomainObjectSTIX) GetGranularMarkings() []stixhelpers.GranularMarkingsTypeSTIX {
 	return e.GranularMarkings
@@ -247,6 +273,11 @@ func (e *CommonPropertiesDomainObjectSTIX) ValidateStructCommonFields() bool {
 		}
 	}
 
+	//проверяем поле Extensions
+	if !e.Extensions.CheckExtensionsTypeSTIX() {
+		return false
+	}
+
 	return true
 }
 
@@ -266,11 +297,15 @@ func (e CommonPropertiesDomainObjectSTIX) SanitizeStruct() CommonPropertiesDomai
 	//обработка содержимого списка поля ExternalReferences
 	e.ExternalReferences = sanitizeStructExternalReferencesTypeSTIX(e.ExternalReferences)
 
-	//обработка содержимого списка поля Extension
+	//обработка содержимого списка поля Extension, обрабатываются только строковые значения
 	if len(e.Extensions) > 0 {
-		newExtension := make(map[string]string, len(e.Extensions))
+		newExtension := make(stixhelpers.ExtensionsTypeSTIX, len(e.Extensions))
 		for extKey, extValue := range e.Extensions {
-			newExtension[extKey] = commonlibs.StringSanitize(extValue)
+			if v, ok := extValue.(string); ok {
+				extValue = commonlibs.StringSanitize(v)
+			}
+
+			newExtension[extKey] = extValue
 		}
 
 		e.Extensions = newExtension
@@ -352,12 +387,12 @@ func (e CommonPropertiesDomainObjectSTIX) ToStringBeautiful(num int) string {
 		return str.String()
 	}(e.GranularMarkings, num+1)))
 	str.WriteString(fmt.Sprintf("%s'defanged': '%v'\n", ws, e.Defanged))
-	str.WriteString(fmt.Sprintf("%s'extensions': \n%v", ws, func(l map[string]string, num int) string {
+	str.WriteString(fmt.Sprintf("%s'extensions': \n%v", ws, func(l stixhelpers.ExtensionsTypeSTIX, num int) string {
 		str := strings.Builder{}
 		ws := commonlibs.GetWhitespace(num)
 
 		for k, v := range l {
-			str.WriteString(fmt.Sprintf("%s'%s': '%s'\n", ws, k, v))
+			str.WriteString(fmt.Sprintf("%s'%s': '%v'\n", ws, k, v))
 		}
 
 		return str.String()
@@ -394,3 +429,14 @@ func sanitizeStructExternalReferencesTypeSTIX(list []stixhelpers.ExternalReferen
 
 	return ert
 }
+
+// AddDataForIndexing добавляет к данным для индексации общие свойства объекта: метки и внешние идентификаторы
+func (e CommonPropertiesDomainObjectSTIX) AddDataForIndexing(dataForIndex map[string]string) {
+	if v := stixhelpers.JoinForIndexing(e.Labels); v != "" {
+		dataForIndex["labels"] = v
+	}
+
+	if v := stixhelpers.GetExternalIDsForIndexing(e.ExternalReferences); v != "" {
+		dataForIndex["external_id"] = v
+	}
+}
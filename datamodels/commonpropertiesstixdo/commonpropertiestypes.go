@@ -18,17 +18,17 @@ import (
 // ObjectMarkingRefs - определяет список ID ссылающиеся на объект "marking-definition", по терминалогии STIX, в котором содержатся значения применяющиеся к этому объекту
 // GranularMarkings - определяет список "гранулярных меток" (granular_markings) относящихся к этому объекту
 // Defanged - определяет были ли определены данные содержащиеся в объекте
-// Extensions - может содержать дополнительную информацию, относящуюся к объекту
+// Extensions - может содержать расширения объекта (property-extension), где ключ это идентификатор объекта "extension-definition"
 type CommonPropertiesDomainObjectSTIX struct {
 	Revoked            bool                                           `json:"revoked" bson:"revoked"`
 	Defanged           bool                                           `json:"defanged" bson:"defanged"`
 	Сonfidence         int                                            `json:"confidence" bson:"confidence"`
 	Lang               string                                         `json:"lang" bson:"lang"`
 	SpecVersion        string                                         `json:"spec_version" bson:"spec_version" required:"true"`
-	Created            string                                         `json:"created" bson:"created" required:"true"`
-	Modified           string                                         `json:"modified" bson:"modified" required:"true"`
+	Created            string                                         `json:"created" bson:"created" required:"true" timestamp:"true"`
+	Modified           string                                         `json:"modified" bson:"modified" required:"true" timestamp:"true"`
 	Labels             []string                                       `json:"labels" bson:"labels"`
-	Extensions         map[string]string                              `json:"extensions" bson:"extensions"`
+	Extensions         stixhelpers.ExtensionsTypeSTIX                 `json:"extensions" bson:"extensions"`
 	CreatedByRef       stixhelpers.IdentifierTypeSTIX                 `json:"created_by_ref" bson:"created_by_ref"`
 	ExternalReferences []stixhelpers.ExternalReferenceTypeElementSTIX `json:"external_references" bson:"external_references"`
 	ObjectMarkingRefs  []stixhelpers.IdentifierTypeSTIX               `json:"object_marking_refs" bson:"object_marking_refs"`
@@ -196,5 +196,13 @@ func (e ArtifactCyberObservableObjectSTIX) GeneratingDataForIndexing() map[strin
 		dataForIndex["value"] = e.URL
 	}
 
+	if e.MimeType != "" {
+		dataForIndex["mime_type"] = e.MimeType
+	}
+
+	if v := e.Hashes.GetValuesForIndexing(); v != "" {
+		dataForIndex["hashes"] = v
+	}
+
 	return dataForIndex
 }
@@ -144,5 +144,13 @@ func (e AutonomousSystemCyberObservableObjectSTIX) GeneratingDataForIndexing() m
 		dataForIndex["name"] = e.Name
 	}
 
+	if e.Number != 0 {
+		dataForIndex["number"] = fmt.Sprint(e.Number)
+	}
+
+	if e.RIR != "" {
+		dataForIndex["rir"] = e.RIR
+	}
+
 	return dataForIndex
 }
@@ -245,8 +245,14 @@ func (e DirectoryCyberObservableObjectSTIX) ToStringBeautiful(num int) string {
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e DirectoryCyberObservableObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.Path != "" {
+		dataForIndex["path"] = e.Path
+	}
+
+	return dataForIndex
 }
@@ -143,5 +143,9 @@ func (e EmailAddressCyberObservableObjectSTIX) GeneratingDataForIndexing() map[s
 		dataForIndex["value"] = e.Value
 	}
 
+	if e.DisplayName != "" {
+		dataForIndex["display_name"] = e.DisplayName
+	}
+
 	return dataForIndex
 }
@@ -450,8 +450,22 @@ func (e EmailMessageCyberObservableObjectSTIX) ToStringBeautiful(num int) string
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e EmailMessageCyberObservableObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.Subject != "" {
+		dataForIndex["subject"] = e.Subject
+	}
+
+	if e.MessageID != "" {
+		dataForIndex["message_id"] = e.MessageID
+	}
+
+	if e.Body != "" {
+		dataForIndex["body"] = e.Body
+	}
+
+	return dataForIndex
 }
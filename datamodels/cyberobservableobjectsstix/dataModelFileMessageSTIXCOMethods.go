@@ -44,12 +44,12 @@ func (e FileCyberObservableObjectSTIX) DecodeJSON(raw *json.RawMessage) (interfa
 
 	ext := map[string]interface{}{}
 	for key, value := range commonObject.Extensions {
-		e, err := datamodels.DecodingExtensionsSTIX(key, value)
+		v, err := datamodels.DecodingExtensionsSTIX(key, value)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		ext[key] = e
+		ext[key] = v
 	}
 
 	e.Extensions = ext
@@ -312,9 +312,8 @@ func (fstix FileCyberObservableObjectSTIX) ValidateStruct() bool {
 	}
 
 	if len(fstix.Extensions) > 0 {
-		for _, v := range fstix.Extensions {
-
-			if !datamodels.CheckingExtensionsSTIX(v) {
+		for k, v := range fstix.Extensions {
+			if !datamodels.CheckingExtensionSTIX(k, v) {
 				return false
 			}
 		}
@@ -337,7 +336,7 @@ func (fstix FileCyberObservableObjectSTIX) SanitizeStruct() FileCyberObservableO
 
 	tmp := make(map[string]interface{}, esize)
 	for k, v := range fstix.Extensions {
-		result := datamodels.SanitizeExtensionsSTIX(v)
+		result := datamodels.SanitizeExtensionSTIX(k, v)
 		tmp[k] = result
 	}
 	fstix.Extensions = tmp
@@ -403,5 +402,13 @@ func (fstix FileCyberObservableObjectSTIX) GeneratingDataForIndexing() map[strin
 		dataForIndex["name"] = fstix.Name
 	}
 
+	if fstix.MimeType != "" {
+		dataForIndex["mime_type"] = fstix.MimeType
+	}
+
+	if v := fstix.Hashes.GetValuesForIndexing(); v != "" {
+		dataForIndex["hashes"] = v
+	}
+
 	return dataForIndex
 }
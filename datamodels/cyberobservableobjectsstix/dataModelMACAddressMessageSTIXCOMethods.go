@@ -102,8 +102,14 @@ func (e MACAddressCyberObservableObjectSTIX) ToStringBeautiful(num int) string {
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e MACAddressCyberObservableObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.Value != "" {
+		dataForIndex["value"] = e.Value
+	}
+
+	return dataForIndex
 }
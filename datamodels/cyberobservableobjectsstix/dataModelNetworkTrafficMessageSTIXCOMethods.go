@@ -46,12 +46,12 @@ func (e NetworkTrafficCyberObservableObjectSTIX) DecodeJSON(raw *json.RawMessage
 	if len(commonObject.Extensions) > 0 {
 		ext := map[string]interface{}{}
 		for key, value := range commonObject.Extensions {
-			e, err := datamodels.DecodingExtensionsSTIX(key, value)
+			v, err := datamodels.DecodingExtensionsSTIX(key, value)
 			if err != nil {
-				continue
+				return nil, err
 			}
 
-			ext[key] = e
+			ext[key] = v
 		}
 
 		e.Extensions = ext
@@ -404,8 +404,8 @@ func (e NetworkTrafficCyberObservableObjectSTIX) ValidateStruct() bool {
 	}
 
 	if len(e.Extensions) > 0 {
-		for _, v := range e.Extensions {
-			if !datamodels.CheckingExtensionsSTIX(v) {
+		for k, v := range e.Extensions {
+			if !datamodels.CheckingExtensionSTIX(k, v) {
 				return false
 			}
 		}
@@ -420,7 +420,7 @@ func (e NetworkTrafficCyberObservableObjectSTIX) SanitizeStruct() NetworkTraffic
 	if esize > 0 {
 		tmp := make(map[string]interface{}, esize)
 		for k, v := range e.Extensions {
-			result := datamodels.SanitizeExtensionsSTIX(v)
+			result := datamodels.SanitizeExtensionSTIX(k, v)
 			tmp[k] = result
 		}
 
@@ -534,8 +534,22 @@ func (e NetworkTrafficCyberObservableObjectSTIX) ToStringBeautiful(num int) stri
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e NetworkTrafficCyberObservableObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if v := stixhelpers.JoinForIndexing(e.Protocols); v != "" {
+		dataForIndex["protocols"] = v
+	}
+
+	if e.SrcPort != 0 {
+		dataForIndex["src_port"] = fmt.Sprint(e.SrcPort)
+	}
+
+	if e.DstPort != 0 {
+		dataForIndex["dst_port"] = fmt.Sprint(e.DstPort)
+	}
+
+	return dataForIndex
 }
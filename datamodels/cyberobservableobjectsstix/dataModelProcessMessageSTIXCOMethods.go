@@ -41,12 +41,12 @@ func (pstix ProcessCyberObservableObjectSTIX) DecodeJSON(raw *json.RawMessage) (
 
 	ext := map[string]interface{}{}
 	for key, value := range commonObject.Extensions {
-		e, err := datamodels.DecodingExtensionsSTIX(key, value)
+		v, err := datamodels.DecodingExtensionsSTIX(key, value)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		ext[key] = e
+		ext[key] = v
 	}
 	pstix.Extensions = ext
 
@@ -281,8 +281,8 @@ func (e ProcessCyberObservableObjectSTIX) ValidateStruct() bool {
 	}
 
 	if len(e.Extensions) > 0 {
-		for _, v := range e.Extensions {
-			if !datamodels.CheckingExtensionsSTIX(v) {
+		for k, v := range e.Extensions {
+			if !datamodels.CheckingExtensionSTIX(k, v) {
 				return false
 			}
 		}
@@ -310,7 +310,7 @@ func (e ProcessCyberObservableObjectSTIX) SanitizeStruct() ProcessCyberObservabl
 	esize := len(e.Extensions)
 	tmp := make(map[string]interface{}, esize)
 	for k, v := range e.Extensions {
-		result := datamodels.SanitizeExtensionsSTIX(v)
+		result := datamodels.SanitizeExtensionSTIX(k, v)
 		tmp[k] = result
 	}
 
@@ -390,8 +390,22 @@ func (e ProcessCyberObservableObjectSTIX) ToStringBeautiful(num int) string {
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e ProcessCyberObservableObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.CommandLine != "" {
+		dataForIndex["command_line"] = e.CommandLine
+	}
+
+	if e.Cwd != "" {
+		dataForIndex["cwd"] = e.Cwd
+	}
+
+	if e.PID != 0 {
+		dataForIndex["pid"] = fmt.Sprint(e.PID)
+	}
+
+	return dataForIndex
 }
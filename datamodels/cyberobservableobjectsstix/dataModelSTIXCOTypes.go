@@ -6,7 +6,6 @@ import (
 	"github.com/av-belyakov/methodstixobjects/datamodels/commonproperties"
 	"github.com/av-belyakov/methodstixobjects/datamodels/commonpropertiesstixco"
 	"github.com/av-belyakov/methodstixobjects/datamodels/somecomplextypesstixco"
-	"github.com/av-belyakov/methodstixobjects/datamodels/someextensionsstixco"
 	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
 )
 
@@ -56,9 +55,9 @@ type DirectoryCyberObservableObjectSTIX struct {
 	commonpropertiesstixco.OptionalCommonPropertiesCyberObservableObjectSTIX
 	Path         string                           `json:"path" bson:"path" required:"true"`
 	PathEnc      string                           `json:"path_enc" bson:"path_enc"`
-	Ctime        string                           `json:"ctime" bson:"ctime"`
-	Mtime        string                           `json:"mtime" bson:"mtime"`
-	Atime        string                           `json:"atime" bson:"atime"`
+	Ctime        string                           `json:"ctime" bson:"ctime" timestamp:"true"`
+	Mtime        string                           `json:"mtime" bson:"mtime" timestamp:"true"`
+	Atime        string                           `json:"atime" bson:"atime" timestamp:"true"`
 	ContainsRefs []stixhelpers.IdentifierTypeSTIX `json:"contains_refs" bson:"contains_refs"`
 }
 
@@ -110,7 +109,7 @@ type EmailMessageCyberObservableObjectSTIX struct {
 	MessageID              string                                         `json:"message_id" bson:"message_id"`
 	Subject                string                                         `json:"subject" bson:"subject"`
 	Body                   string                                         `json:"body" bson:"body"`
-	Date                   string                                         `json:"date" bson:"date"`
+	Date                   string                                         `json:"date" bson:"date" timestamp:"true"`
 	ReceivedLines          []string                                       `json:"received_lines" bson:"received_lines"`
 	FromRef                stixhelpers.IdentifierTypeSTIX                 `json:"from_ref" bson:"from_ref"`
 	SenderRef              stixhelpers.IdentifierTypeSTIX                 `json:"sender_ref" bson:"sender_ref"`
@@ -145,9 +144,9 @@ type CommonFileCyberObservableObjectSTIX struct {
 	NameEnc            string                           `json:"name_enc" bson:"name_enc"`
 	MagicNumberHex     string                           `json:"magic_number_hex" bson:"magic_number_hex"`
 	MimeType           string                           `json:"mime_type" bson:"mime_type"`
-	Ctime              string                           `json:"ctime" bson:"ctime"`
-	Mtime              string                           `json:"mtime" bson:"mtime"`
-	Atime              string                           `json:"atime" bson:"atime"`
+	Ctime              string                           `json:"ctime" bson:"ctime" timestamp:"true"`
+	Mtime              string                           `json:"mtime" bson:"mtime" timestamp:"true"`
+	Atime              string                           `json:"atime" bson:"atime" timestamp:"true"`
 	Hashes             stixhelpers.HashesTypeSTIX       `json:"hashes" bson:"hashes"`
 	ParentDirectoryRef stixhelpers.IdentifierTypeSTIX   `json:"parent_directory_ref" bson:"parent_directory_ref"`
 	ContentRef         stixhelpers.IdentifierTypeSTIX   `json:"content_ref" bson:"content_ref"`
@@ -178,9 +177,9 @@ type FileCyberObservableObjectSTIX struct {
 	NameEnc            string                           `json:"name_enc" bson:"name_enc"`
 	MagicNumberHex     string                           `json:"magic_number_hex" bson:"magic_number_hex"`
 	MimeType           string                           `json:"mime_type" bson:"mime_type"`
-	Ctime              string                           `json:"ctime" bson:"ctime"`
-	Mtime              string                           `json:"mtime" bson:"mtime"`
-	Atime              string                           `json:"atime" bson:"atime"`
+	Ctime              string                           `json:"ctime" bson:"ctime" timestamp:"true"`
+	Mtime              string                           `json:"mtime" bson:"mtime" timestamp:"true"`
+	Atime              string                           `json:"atime" bson:"atime" timestamp:"true"`
 	ParentDirectoryRef stixhelpers.IdentifierTypeSTIX   `json:"parent_directory_ref" bson:"parent_directory_ref"`
 	Hashes             stixhelpers.HashesTypeSTIX       `json:"hashes" bson:"hashes"`
 	ContentRef         stixhelpers.IdentifierTypeSTIX   `json:"content_ref" bson:"content_ref"`
@@ -272,8 +271,8 @@ type CommonNetworkTrafficCyberObservableObjectSTIX struct {
 	DstPackets        int                              `json:"dst_packets" bson:"dst_packets"`
 	SrcByteCount      uint64                           `json:"src_byte_count" bson:"src_byte_count"`
 	DstByteCount      uint64                           `json:"dst_byte_count" bson:"dst_byte_count"`
-	Start             string                           `json:"start" bson:"start"`
-	End               string                           `json:"end" bson:"end"`
+	Start             string                           `json:"start" bson:"start" timestamp:"true"`
+	End               string                           `json:"end" bson:"end" timestamp:"true"`
 	Protocols         []string                         `json:"protocols" bson:"protocols"`
 	SrcRef            stixhelpers.IdentifierTypeSTIX   `json:"src_ref" bson:"src_ref"`
 	DstRef            stixhelpers.IdentifierTypeSTIX   `json:"dst_ref" bson:"dst_ref"`
@@ -319,8 +318,8 @@ type NetworkTrafficCyberObservableObjectSTIX struct {
 	DstPackets        int                              `json:"dst_packets" bson:"dst_packets"`
 	SrcByteCount      uint64                           `json:"src_byte_count" bson:"src_byte_count"`
 	DstByteCount      uint64                           `json:"dst_byte_count" bson:"dst_byte_count"`
-	Start             string                           `json:"start" bson:"start"`
-	End               string                           `json:"end" bson:"end"`
+	Start             string                           `json:"start" bson:"start" timestamp:"true"`
+	End               string                           `json:"end" bson:"end" timestamp:"true"`
 	Protocols         []string                         `json:"protocols" bson:"protocols"`
 	SrcRef            stixhelpers.IdentifierTypeSTIX   `json:"src_ref" bson:"src_ref"`
 	DstRef            stixhelpers.IdentifierTypeSTIX   `json:"dst_ref" bson:"dst_ref"`
@@ -358,7 +357,7 @@ type CommonProcessCyberObservableObjectSTIX struct {
 	PID                  int                              `json:"pid" bson:"pid"`
 	Cwd                  string                           `json:"cwd" bson:"cwd"`
 	CommandLine          string                           `json:"command_line" bson:"command_line"`
-	CreatedTime          string                           `json:"created_time" bson:"created_time"`
+	CreatedTime          string                           `json:"created_time" bson:"created_time" timestamp:"true"`
 	CreatorUserRef       stixhelpers.IdentifierTypeSTIX   `json:"creator_user_ref" bson:"creator_user_ref"`
 	ImageRef             stixhelpers.IdentifierTypeSTIX   `json:"image_ref" bson:"image_ref"`
 	ParentRef            stixhelpers.IdentifierTypeSTIX   `json:"parent_ref" bson:"parent_ref"`
@@ -394,7 +393,7 @@ type ProcessCyberObservableObjectSTIX struct {
 	PID                  int                              `json:"pid" bson:"pid"`
 	Cwd                  string                           `json:"cwd" bson:"cwd"`
 	CommandLine          string                           `json:"command_line" bson:"command_line"`
-	CreatedTime          string                           `json:"created_time" bson:"created_time"`
+	CreatedTime          string                           `json:"created_time" bson:"created_time" timestamp:"true"`
 	CreatorUserRef       stixhelpers.IdentifierTypeSTIX   `json:"creator_user_ref" bson:"creator_user_ref"`
 	ImageRef             stixhelpers.IdentifierTypeSTIX   `json:"image_ref" bson:"image_ref"`
 	ParentRef            stixhelpers.IdentifierTypeSTIX   `json:"parent_ref" bson:"parent_ref"`
@@ -433,6 +432,58 @@ type URLCyberObservableObjectSTIX struct {
 	Value string `json:"value" bson:"value"`
 }
 
+// CommonUserAccountCyberObservableObjectSTIX общий объект "User Account Object", по терминалогии STIX, содержит экземпляр любого типа учетной записи пользователя, включая,
+// учетные записи операционной системы, устройства, службы обмена сообщениями и платформы социальных сетей и других прочих учетных записей
+// Поскольку все свойства этого объекта являются необязательными, по крайней мере одно из свойств, определенных ниже, ДОЛЖНО быть инициализировано
+// при использовании этого объекта
+// Extensions - содержит словарь расширяющий тип "User Account Object" одно из расширений "unix-account-ext", реализуется описанным ниже
+// типом, UNIXAccountExtensionSTIX кроме этого производитель может созавать свои собственные типы расширений. Ключи данного словаря
+// идентифицируют тип расширения по имени, значения являются содержимым экземпляра расширения
+// UserID - содержит идентификатор учетной записи. Формат идентификатора зависит от системы в которой находится данная учетная запись пользователя,
+// и может быть числовым идентификатором, идентификатором GUID, именем учетной записи, адресом электронной почты и т.д. Свойство  UserId должно
+// быть заполнено любым значанием, являющимся уникальным идентификатором системы, членом которой является учетная запись. Например, в системах UNIX он
+// будет заполнено значением UID
+// Credential - содержит учетные данные пользователя в открытом виде. Предназначено только для закрытого применения при изучении метаданных вредоносных
+// программ при их исследовании (например, жестко закодированный пароль администратора домена, который вредоносная программа пытается использовать
+// реализации тактики для бокового (латерального) перемещения) и не должно применяться для совместного пользования PII
+// AccountLogin - содержит логин пользователя. Используется в тех случаях,когда свойство UserId указывает другие данные, чем то, что пользователь вводит
+// при входе в систему
+// AccountType - содержит одно, из заранее определенных (предложенных) значений. Является типом аккаунта. Значения этого свойства берутся из множества
+// закрепленного в открытом словаре, account-type-ov
+// DisplayName - содержит отображаемое имя учетной записи, которое будет отображаться в пользовательских интерфейсах. В Unix, это равносильно полю
+// gecos (gecos это поле учётной записи пользователя в файле /etc/passwd )
+// IsServiceAccount - содержит индикатор, сигнализирующий что, учетная запись связана с сетевой службой или системным процессом (демоном), а не с
+// конкретным человеком. (системный пользователь)
+// IsPrivileged - содержит индикатор, сигнализирующий что, учетная запись имеет повышенные привилегии (например, в случае root в Unix или учетной
+// записи администратора Windows)
+// CanEscalatePrivs  - содержит индикатор, сигнализирующий что, учетная запись имеет возможность повышать привилегии (например, в случае sudo в
+// Unix или учетной записи администратора домена Windows)
+// IsDisabled  - содержит индикатор, сигнализирующий что, учетная запись отключена
+// AccountCreated - время, в формате "2016-05-12T08:17:27.000Z", создания аккаунта
+// AccountExpires - время, в формате "2016-05-12T08:17:27.000Z", истечения срока действия учетной записи.
+// CredentialLastChanged - время, в формате "2016-05-12T08:17:27.000Z", когда учетные данные учетной записи были изменены в последний раз.
+// AccountFirstLogin - время, в формате "2016-05-12T08:17:27.000Z", первого доступа к учетной записи
+// AccountLastLogin - время, в формате "2016-05-12T08:17:27.000Z", когда к учетной записи был последний доступ.
+type CommonUserAccountCyberObservableObjectSTIX struct {
+	commonproperties.CommonPropertiesObjectSTIX
+	commonpropertiesstixco.OptionalCommonPropertiesCyberObservableObjectSTIX
+	IsServiceAccount      bool                          `json:"is_service_account" bson:"is_service_account"`
+	IsPrivileged          bool                          `json:"is_privileged" bson:"is_privileged"`
+	CanEscalatePrivs      bool                          `json:"can_escalate_privs" bson:"can_escalate_privs"`
+	IsDisabled            bool                          `json:"is_disabled" bson:"is_disabled"`
+	UserID                string                        `json:"user_id" bson:"user_id"`
+	Credential            string                        `json:"credential" bson:"credential"`
+	AccountLogin          string                        `json:"account_login" bson:"account_login"`
+	DisplayName           string                        `json:"display_name" bson:"display_name"`
+	AccountCreated        string                        `json:"account_created" bson:"account_created" timestamp:"true"`
+	AccountExpires        string                        `json:"account_expires" bson:"account_expires" timestamp:"true"`
+	CredentialLastChanged string                        `json:"credential_last_changed" bson:"credential_last_changed" timestamp:"true"`
+	AccountFirstLogin     string                        `json:"account_first_login" bson:"account_first_login" timestamp:"true"`
+	AccountLastLogin      string                        `json:"account_last_login" bson:"account_last_login" timestamp:"true"`
+	AccountType           stixhelpers.OpenVocabTypeSTIX `json:"account_type" bson:"account_type"`
+	Extensions            map[string]*json.RawMessage   `json:"extensions" bson:"extensions"`
+}
+
 // UserAccountCyberObservableObjectSTIX объект "User Account Object", по терминалогии STIX, содержит экземпляр любого типа учетной записи пользователя, включая,
 // учетные записи операционной системы, устройства, службы обмена сообщениями и платформы социальных сетей и других прочих учетных записей
 // Поскольку все свойства этого объекта являются необязательными, по крайней мере одно из свойств, определенных ниже, ДОЛЖНО быть инициализировано
@@ -468,21 +519,21 @@ type URLCyberObservableObjectSTIX struct {
 type UserAccountCyberObservableObjectSTIX struct {
 	commonproperties.CommonPropertiesObjectSTIX
 	commonpropertiesstixco.OptionalCommonPropertiesCyberObservableObjectSTIX
-	IsServiceAccount      bool                                                     `json:"is_service_account" bson:"is_service_account"`
-	IsPrivileged          bool                                                     `json:"is_privileged" bson:"is_privileged"`
-	CanEscalatePrivs      bool                                                     `json:"can_escalate_privs" bson:"can_escalate_privs"`
-	IsDisabled            bool                                                     `json:"is_disabled" bson:"is_disabled"`
-	UserID                string                                                   `json:"user_id" bson:"user_id"`
-	Credential            string                                                   `json:"credential" bson:"credential"`
-	AccountLogin          string                                                   `json:"account_login" bson:"account_login"`
-	DisplayName           string                                                   `json:"display_name" bson:"display_name"`
-	AccountCreated        string                                                   `json:"account_created" bson:"account_created"`
-	AccountExpires        string                                                   `json:"account_expires" bson:"account_expires"`
-	CredentialLastChanged string                                                   `json:"credential_last_changed" bson:"credential_last_changed"`
-	AccountFirstLogin     string                                                   `json:"account_first_login" bson:"account_first_login"`
-	AccountLastLogin      string                                                   `json:"account_last_login" bson:"account_last_login"`
-	AccountType           stixhelpers.OpenVocabTypeSTIX                            `json:"account_type" bson:"account_type"`
-	Extensions            map[string]someextensionsstixco.UNIXAccountExtensionSTIX `json:"extensions" bson:"extensions"`
+	IsServiceAccount      bool                          `json:"is_service_account" bson:"is_service_account"`
+	IsPrivileged          bool                          `json:"is_privileged" bson:"is_privileged"`
+	CanEscalatePrivs      bool                          `json:"can_escalate_privs" bson:"can_escalate_privs"`
+	IsDisabled            bool                          `json:"is_disabled" bson:"is_disabled"`
+	UserID                string                        `json:"user_id" bson:"user_id"`
+	Credential            string                        `json:"credential" bson:"credential"`
+	AccountLogin          string                        `json:"account_login" bson:"account_login"`
+	DisplayName           string                        `json:"display_name" bson:"display_name"`
+	AccountCreated        string                        `json:"account_created" bson:"account_created" timestamp:"true"`
+	AccountExpires        string                        `json:"account_expires" bson:"account_expires" timestamp:"true"`
+	CredentialLastChanged string                        `json:"credential_last_changed" bson:"credential_last_changed" timestamp:"true"`
+	AccountFirstLogin     string                        `json:"account_first_login" bson:"account_first_login" timestamp:"true"`
+	AccountLastLogin      string                        `json:"account_last_login" bson:"account_last_login" timestamp:"true"`
+	AccountType           stixhelpers.OpenVocabTypeSTIX `json:"account_type" bson:"account_type"`
+	Extensions            map[string]interface{}        `json:"extensions" bson:"extensions"`
 }
 
 // WindowsRegistryKeyCyberObservableObjectSTIX объект "Windows Registry Key Object", по терминалогии STIX. Содержит описание значений полей
@@ -499,7 +550,7 @@ type WindowsRegistryKeyCyberObservableObjectSTIX struct {
 	commonpropertiesstixco.OptionalCommonPropertiesCyberObservableObjectSTIX
 	NumberOfSubkeys int                                                   `json:"number_of_subkeys" bson:"number_of_subkeys"`
 	Key             string                                                `json:"key" bson:"key"`
-	ModifiedTime    string                                                `json:"modified_time" bson:"modified_time"`
+	ModifiedTime    string                                                `json:"modified_time" bson:"modified_time" timestamp:"true"`
 	CreatorUserRef  stixhelpers.IdentifierTypeSTIX                        `json:"creator_user_ref" bson:"creator_user_ref"`
 	Values          []somecomplextypesstixco.WindowsRegistryValueTypeSTIX `json:"values" bson:"values"`
 }
@@ -533,8 +584,8 @@ type X509CertificateCyberObservableObjectSTIX struct {
 	Subject                   string                                          `json:"subject" bson:"subject"`
 	SubjectPublicKeyAlgorithm string                                          `json:"subject_public_key_algorithm" bson:"subject_public_key_algorithm"`
 	SubjectPublicKeyModulus   string                                          `json:"subject_public_key_modulus" bson:"subject_public_key_modulus"`
-	ValidityNotBefore         string                                          `json:"validity_not_before" bson:"validity_not_before"`
-	ValidityNotAfter          string                                          `json:"validity_not_after" bson:"validity_not_after"`
+	ValidityNotBefore         string                                          `json:"validity_not_before" bson:"validity_not_before" timestamp:"true"`
+	ValidityNotAfter          string                                          `json:"validity_not_after" bson:"validity_not_after" timestamp:"true"`
 	Hashes                    stixhelpers.HashesTypeSTIX                      `json:"hashes" bson:"hashes"`
 	X509V3Extensions          somecomplextypesstixco.X509V3ExtensionsTypeSTIX `json:"x509_v3_extensions" bson:"x509_v3_extensions"`
 }
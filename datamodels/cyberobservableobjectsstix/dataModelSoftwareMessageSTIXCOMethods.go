@@ -209,5 +209,21 @@ func (e SoftwareCyberObservableObjectSTIX) GeneratingDataForIndexing() map[strin
 		dataForIndex["name"] = e.Name
 	}
 
+	if e.Vendor != "" {
+		dataForIndex["vendor"] = e.Vendor
+	}
+
+	if e.Version != "" {
+		dataForIndex["version"] = e.Version
+	}
+
+	if e.CPE != "" {
+		dataForIndex["cpe"] = e.CPE
+	}
+
+	if e.SwID != "" {
+		dataForIndex["swid"] = e.SwID
+	}
+
 	return dataForIndex
 }
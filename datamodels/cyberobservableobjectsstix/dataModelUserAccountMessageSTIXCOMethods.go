@@ -17,10 +17,45 @@ import (
 
 // DecoderJSON выполняет декодирование JSON объекта
 func (e UserAccountCyberObservableObjectSTIX) DecodeJSON(raw *json.RawMessage) (interface{}, error) {
-	if err := json.Unmarshal(*raw, &e); err != nil {
+	var commonObject CommonUserAccountCyberObservableObjectSTIX
+	if err := json.Unmarshal(*raw, &commonObject); err != nil {
 		return nil, err
 	}
 
+	e = UserAccountCyberObservableObjectSTIX{
+		CommonPropertiesObjectSTIX:                        commonObject.CommonPropertiesObjectSTIX,
+		OptionalCommonPropertiesCyberObservableObjectSTIX: commonObject.OptionalCommonPropertiesCyberObservableObjectSTIX,
+		IsServiceAccount:                                  commonObject.IsServiceAccount,
+		IsPrivileged:                                      commonObject.IsPrivileged,
+		CanEscalatePrivs:                                  commonObject.CanEscalatePrivs,
+		IsDisabled:                                        commonObject.IsDisabled,
+		UserID:                                            commonObject.UserID,
+		Credential:                                        commonObject.Credential,
+		AccountLogin:                                      commonObject.AccountLogin,
+		DisplayName:                                       commonObject.DisplayName,
+		AccountCreated:                                    commonObject.AccountCreated,
+		AccountExpires:                                    commonObject.AccountExpires,
+		CredentialLastChanged:                             commonObject.CredentialLastChanged,
+		AccountFirstLogin:                                 commonObject.AccountFirstLogin,
+		AccountLastLogin:                                  commonObject.AccountLastLogin,
+		AccountType:                                       commonObject.AccountType,
+	}
+
+	if len(commonObject.Extensions) == 0 {
+		return e, nil
+	}
+
+	ext := map[string]interface{}{}
+	for key, value := range commonObject.Extensions {
+		v, err := datamodels.DecodingExtensionsSTIX(key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		ext[key] = v
+	}
+	e.Extensions = ext
+
 	return e, nil
 }
 
@@ -315,17 +350,27 @@ func (e *UserAccountCyberObservableObjectSTIX) SetValueAccountType(v stixhelpers
 }
 
 // -------- Extensions property ---------
-func (e *UserAccountCyberObservableObjectSTIX) GetExtensions() map[string]someextensionsstixco.UNIXAccountExtensionSTIX {
+func (e *UserAccountCyberObservableObjectSTIX) GetExtensions() map[string]interface{} {
 	return e.Extensions
 }
 
 // SetValueExtensions добаляет значение в Extensions
-func (e *UserAccountCyberObservableObjectSTIX) SetValueExtensions(k string, v someextensionsstixco.UNIXAccountExtensionSTIX) {
-	e.Extensions[k] = v
+func (e *UserAccountCyberObservableObjectSTIX) SetValueExtensions(k string, i interface{}) {
+	e.Extensions[k] = i
 }
 
 // SetFullValueExtensions добаляет полное значение в Extensions
 func (e *UserAccountCyberObservableObjectSTIX) SetFullValueExtensions(v map[string]someextensionsstixco.UNIXAccountExtensionSTIX) {
+	ext := make(map[string]interface{}, len(v))
+	for k, value := range v {
+		ext[k] = value
+	}
+
+	e.Extensions = ext
+}
+
+// SetFullValueAnyExtensions добаляет полное значение в Extensions, значения расширений могут быть любого типа
+func (e *UserAccountCyberObservableObjectSTIX) SetFullValueAnyExtensions(v map[string]interface{}) {
 	e.Extensions = v
 }
 
@@ -339,6 +384,12 @@ func (e UserAccountCyberObservableObjectSTIX) ValidateStruct() bool {
 		return false
 	}
 
+	for k, v := range e.Extensions {
+		if !datamodels.CheckingExtensionSTIX(k, v) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -351,12 +402,9 @@ func (e UserAccountCyberObservableObjectSTIX) SanitizeStruct() UserAccountCyberO
 	e.DisplayName = commonlibs.StringSanitize(e.DisplayName)
 
 	esize := len(e.Extensions)
-	tmp := make(map[string]someextensionsstixco.UNIXAccountExtensionSTIX, esize)
+	tmp := make(map[string]interface{}, esize)
 	for k, v := range e.Extensions {
-		result := datamodels.SanitizeExtensionsSTIX(v)
-		if ct, ok := result.(someextensionsstixco.UNIXAccountExtensionSTIX); ok {
-			tmp[k] = ct
-		}
+		tmp[k] = datamodels.SanitizeExtensionSTIX(k, v)
 	}
 	e.Extensions = tmp
 
@@ -394,7 +442,7 @@ func (e UserAccountCyberObservableObjectSTIX) ToStringBeautiful(num int) string
 	str.WriteString(fmt.Sprintf("%s'credential_last_changed': '%v'\n", ws, e.CredentialLastChanged))
 	str.WriteString(fmt.Sprintf("%s'account_first_login': '%v'\n", ws, e.AccountFirstLogin))
 	str.WriteString(fmt.Sprintf("%s'account_last_login': '%v'\n", ws, e.AccountLastLogin))
-	str.WriteString(fmt.Sprintf("%s'extensions': \n%v", ws, func(l map[string]someextensionsstixco.UNIXAccountExtensionSTIX, num int) string {
+	str.WriteString(fmt.Sprintf("%s'extensions': \n%v", ws, func(l map[string]interface{}, num int) string {
 		str := strings.Builder{}
 		ws := commonlibs.GetWhitespace(num)
 
@@ -410,8 +458,22 @@ func (e UserAccountCyberObservableObjectSTIX) ToStringBeautiful(num int) string
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e UserAccountCyberObservableObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.UserID != "" {
+		dataForIndex["user_id"] = e.UserID
+	}
+
+	if e.AccountLogin != "" {
+		dataForIndex["account_login"] = e.AccountLogin
+	}
+
+	if e.DisplayName != "" {
+		dataForIndex["display_name"] = e.DisplayName
+	}
+
+	return dataForIndex
 }
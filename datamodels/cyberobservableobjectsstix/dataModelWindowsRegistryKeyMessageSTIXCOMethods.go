@@ -192,8 +192,25 @@ func (e WindowsRegistryKeyCyberObservableObjectSTIX) ToStringBeautiful(num int)
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e WindowsRegistryKeyCyberObservableObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.Key != "" {
+		dataForIndex["key"] = e.Key
+	}
+
+	if len(e.Values) > 0 {
+		values := make([]string, 0, len(e.Values)*2)
+		for _, v := range e.Values {
+			values = append(values, v.Name, v.Data)
+		}
+
+		if v := stixhelpers.JoinForIndexing(values); v != "" {
+			dataForIndex["values"] = v
+		}
+	}
+
+	return dataForIndex
 }
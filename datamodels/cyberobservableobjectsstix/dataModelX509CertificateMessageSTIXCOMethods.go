@@ -330,8 +330,26 @@ func (e X509CertificateCyberObservableObjectSTIX) ToStringBeautiful(num int) str
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e X509CertificateCyberObservableObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.Subject != "" {
+		dataForIndex["subject"] = e.Subject
+	}
+
+	if e.Issuer != "" {
+		dataForIndex["issuer"] = e.Issuer
+	}
+
+	if e.SerialNumber != "" {
+		dataForIndex["serial_number"] = e.SerialNumber
+	}
+
+	if v := e.Hashes.GetValuesForIndexing(); v != "" {
+		dataForIndex["hashes"] = v
+	}
+
+	return dataForIndex
 }
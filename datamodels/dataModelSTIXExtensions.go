@@ -11,7 +11,7 @@ import (
 	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
 )
 
-// DecodingExtensionsSTIX декодирует следующие типы STIX расширений:
+// CheckingExtensionsSTIX выполняет проверку полей следующих типов STIX расширений:
 // - "archive-ext"
 // - "ntfs-ext"
 // - "pdf-ext"
@@ -24,123 +24,9 @@ import (
 // - "windows-process-ext"
 // - "windows-service-ext"
 // - "unix-account-ext"
-func DecodingExtensionsSTIX(extType string, rawMsg *json.RawMessage) (interface{}, error) {
-	var err error
-
-	//fmt.Printf("func 'decodingExtensionsSTIX', extType = %s, \n", extType)
-
-	switch extType {
-	case "archive-ext":
-		var archiveExt someextensionsstixco.ArchiveFileExtensionSTIX
-		err = json.Unmarshal(*rawMsg, &archiveExt)
-
-		return archiveExt, err
-	case "ntfs-ext":
-		var ntfsExt someextensionsstixco.NTFSFileExtensionSTIX
-		err = json.Unmarshal(*rawMsg, &ntfsExt)
-
-		return ntfsExt, err
-	case "pdf-ext":
-		var pdfExt someextensionsstixco.PDFFileExtensionSTIX
-		err = json.Unmarshal(*rawMsg, &pdfExt)
-
-		return pdfExt, err
-	case "raster-image-ext":
-		var rasterImageExt someextensionsstixco.RasterImageFileExtensionSTIX
-		err = json.Unmarshal(*rawMsg, &rasterImageExt)
-
-		//fmt.Printf("func 'decodingExtensionsSTIX', rasterImageExt = %v, \n", rasterImageExt)
-
-		return rasterImageExt, err
-	case "windows-pebinary-ext":
-		var windowsPebinaryExt someextensionsstixco.WindowsPEBinaryFileExtensionSTIX
-		err = json.Unmarshal(*rawMsg, &windowsPebinaryExt)
-
-		return windowsPebinaryExt, err
-	case "http-request-ext":
-		var httpRequestExt someextensionsstixco.HTTPRequestExtensionSTIX
-		err = json.Unmarshal(*rawMsg, &httpRequestExt)
-
-		return httpRequestExt, err
-	case "icmp-ext":
-		var icmpExt someextensionsstixco.ICMPExtensionSTIX
-		err := json.Unmarshal(*rawMsg, &icmpExt)
-
-		return icmpExt, err
-	case "socket-ext":
-		var socketExt someextensionsstixco.NetworkSocketExtensionSTIX
-		err := json.Unmarshal(*rawMsg, &socketExt)
-
-		return socketExt, err
-	case "tcp-ext":
-		var tcpExt someextensionsstixco.TCPExtensionSTIX
-		err := json.Unmarshal(*rawMsg, &tcpExt)
-
-		return tcpExt, err
-	case "windows-process-ext":
-		var windowsProcessExt someextensionsstixco.WindowsProcessExtensionSTIX
-		err := json.Unmarshal(*rawMsg, &windowsProcessExt)
-
-		return windowsProcessExt, err
-	case "windows-service-ext":
-		var windowsServiceExt someextensionsstixco.WindowsServiceExtensionSTIX
-		err := json.Unmarshal(*rawMsg, &windowsServiceExt)
-
-		return windowsServiceExt, err
-	case "unix-account-ext":
-		var unixAccountExt someextensionsstixco.UNIXAccountExtensionSTIX
-		err := json.Unmarshal(*rawMsg, &unixAccountExt)
-
-		return unixAccountExt, err
-	default:
-		return struct{}{}, nil
-	}
-}
-
-// CheckingExtensionsSTIX выполняет проверку полей следующих типов STIX расширений:
-// - "archive-ext"
-// - "windows-pebinary-ext"
-// - "http-request-ext"
-// - "windows-service-ext"
+// подробный перечень ошибок возвращает ValidatingExtensionsSTIX
 func CheckingExtensionsSTIX(extType interface{}) bool {
-	switch et := extType.(type) {
-	case someextensionsstixco.ArchiveFileExtensionSTIX:
-		for _, v := range et.ContainsRefs {
-			if !v.CheckIdentifierTypeSTIX() {
-				return false
-			}
-		}
-
-	case someextensionsstixco.NTFSFileExtensionSTIX:
-		if len(et.AlternateDataStreams) == 0 {
-			return true
-		}
-
-		for _, v := range et.AlternateDataStreams {
-			if !v.Hashes.CheckHashesTypeSTIX() {
-				return false
-			}
-		}
-
-	case someextensionsstixco.WindowsPEBinaryFileExtensionSTIX:
-		if !et.FileHeaderHashes.CheckHashesTypeSTIX() {
-			return false
-		}
-
-	case someextensionsstixco.HTTPRequestExtensionSTIX:
-		if !et.MessageBodyDataRef.CheckIdentifierTypeSTIX() {
-			return false
-		}
-
-	case someextensionsstixco.WindowsServiceExtensionSTIX:
-		for _, v := range et.ServiceDllRefs {
-			if !v.CheckIdentifierTypeSTIX() {
-				return false
-			}
-		}
-	}
-
-	return true
+	return len(ValidatingExtensionsSTIX(extType)) == 0
 }
 
 // SanitizeExtensionsSTIX для ряда полей следующих типов STIX расширений:
@@ -176,7 +62,10 @@ func SanitizeExtensionsSTIX(extType interface{}) interface{} {
 
 	switch et := extType.(type) {
 	case someextensionsstixco.ArchiveFileExtensionSTIX:
-		return commonlibs.StringSanitize(et.Comment)
+		return someextensionsstixco.ArchiveFileExtensionSTIX{
+			ContainsRefs: et.ContainsRefs,
+			Comment:      commonlibs.StringSanitize(et.Comment),
+		}
 
 	case someextensionsstixco.NTFSFileExtensionSTIX:
 		var tmpType someextensionsstixco.NTFSFileExtensionSTIX
@@ -521,6 +410,9 @@ func ToStringBeautiful(extType interface{}) string {
 		str.WriteString(fmt.Sprintf("\t\t'home_dir': '%s'\n", et.HomeDir))
 		str.WriteString(fmt.Sprintf("\t\t'shell': '%s'\n", et.Shell))
 
+	case json.RawMessage:
+		str.WriteString(fmt.Sprintf("'%s'\n", string(et)))
+
 	default:
 		str.WriteString(fmt.Sprintf("'%v'\n", et))
 
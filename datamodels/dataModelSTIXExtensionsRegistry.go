@@ -0,0 +1,171 @@
+package datamodels
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/av-belyakov/methodstixobjects/datamodels/someextensionsstixco"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// ExtensionHandlerSTIX обработчики расширения объектов STIX CO
+// Decoder - декодирует содержимое расширения (обязателен)
+// Validator - проверяет декодированное значение расширения (может отсутствовать)
+// Sanitizer - выполняет замену некоторых специальных символов на их HTML код (может отсутствовать)
+type ExtensionHandlerSTIX struct {
+	Decoder   func(rawMsg *json.RawMessage) (interface{}, error)
+	Validator func(ext interface{}) bool
+	Sanitizer func(ext interface{}) interface{}
+}
+
+// extensionHandlers реестр обработчиков расширений объектов STIX CO
+var extensionHandlers = struct {
+	sync.RWMutex
+	list map[string]ExtensionHandlerSTIX
+}{list: map[string]ExtensionHandlerSTIX{}}
+
+func init() {
+	builtin := map[string]interface{}{
+		"archive-ext":          someextensionsstixco.ArchiveFileExtensionSTIX{},
+		"ntfs-ext":             someextensionsstixco.NTFSFileExtensionSTIX{},
+		"pdf-ext":              someextensionsstixco.PDFFileExtensionSTIX{},
+		"raster-image-ext":     someextensionsstixco.RasterImageFileExtensionSTIX{},
+		"windows-pebinary-ext": someextensionsstixco.WindowsPEBinaryFileExtensionSTIX{},
+		"http-request-ext":     someextensionsstixco.HTTPRequestExtensionSTIX{},
+		"icmp-ext":             someextensionsstixco.ICMPExtensionSTIX{},
+		"socket-ext":           someextensionsstixco.NetworkSocketExtensionSTIX{},
+		"tcp-ext":              someextensionsstixco.TCPExtensionSTIX{},
+		"windows-process-ext":  someextensionsstixco.WindowsProcessExtensionSTIX{},
+		"windows-service-ext":  someextensionsstixco.WindowsServiceExtensionSTIX{},
+		"unix-account-ext":     someextensionsstixco.UNIXAccountExtensionSTIX{},
+	}
+
+	for extType, sample := range builtin {
+		_ = RegisterExtensionHandlerSTIX(extType, ExtensionHandlerSTIX{
+			Decoder:   NewExtensionDecoderSTIX(sample),
+			Validator: CheckingExtensionsSTIX,
+			Sanitizer: SanitizeExtensionsSTIX,
+		})
+	}
+}
+
+// RegisterExtensionHandlerSTIX регистрирует обработчики расширения extType объектов STIX CO. Повторная
+// регистрация расширения заменяет предыдущую, что позволяет переопределять и встроенные расширения
+func RegisterExtensionHandlerSTIX(extType string, handler ExtensionHandlerSTIX) error {
+	if extType == "" {
+		return fmt.Errorf("the extension type must not be empty")
+	}
+
+	if handler.Decoder == nil {
+		return fmt.Errorf("the decoder of the extension '%s' must not be empty", extType)
+	}
+
+	extensionHandlers.Lock()
+	defer extensionHandlers.Unlock()
+
+	extensionHandlers.list[extType] = handler
+
+	return nil
+}
+
+// UnregisterExtensionHandlerSTIX удаляет обработчики расширения extType из реестра
+func UnregisterExtensionHandlerSTIX(extType string) {
+	extensionHandlers.Lock()
+	defer extensionHandlers.Unlock()
+
+	delete(extensionHandlers.list, extType)
+}
+
+// GetExtensionHandlerSTIX возвращает обработчики расширения extType
+func GetExtensionHandlerSTIX(extType string) (ExtensionHandlerSTIX, bool) {
+	extensionHandlers.RLock()
+	defer extensionHandlers.RUnlock()
+
+	handler, ok := extensionHandlers.list[extType]
+
+	return handler, ok
+}
+
+// NewExtensionDecoderSTIX возвращает декодер, выполняющий декодирование расширения в значение
+// того же типа что и sample, например, NewExtensionDecoderSTIX(MyExtension{})
+func NewExtensionDecoderSTIX(sample interface{}) func(rawMsg *json.RawMessage) (interface{}, error) {
+	t := reflect.TypeOf(sample)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return func(rawMsg *json.RawMessage) (interface{}, error) {
+		v := reflect.New(t)
+		if err := json.Unmarshal(*rawMsg, v.Interface()); err != nil {
+			return nil, err
+		}
+
+		return v.Elem().Interface(), nil
+	}
+}
+
+// DecodingExtensionsSTIX декодирует расширение extType объекта STIX CO с помощью зарегистрированного
+// декодера. Расширения, определяемые объектами "extension-definition" и зарегистрированные с помощью
+// stixhelpers.RegisterExtensionSTIX, декодируются в зарегистрированный тип. Содержимое неизвестных
+// расширений сохраняется в виде json.RawMessage и при кодировании в JSON остается неизменным
+func DecodingExtensionsSTIX(extType string, rawMsg *json.RawMessage) (interface{}, error) {
+	if rawMsg == nil {
+		return nil, fmt.Errorf("the extension '%s' must not be empty", extType)
+	}
+
+	if handler, ok := GetExtensionHandlerSTIX(extType); ok {
+		ext, err := handler.Decoder(rawMsg)
+		if err != nil {
+			return nil, fmt.Errorf("extension '%s': %w", extType, err)
+		}
+
+		return ext, nil
+	}
+
+	if stixhelpers.IsRegisteredExtensionSTIX(extType) {
+		return stixhelpers.DecodeExtensionSTIX(extType, *rawMsg)
+	}
+
+	if !json.Valid(*rawMsg) {
+		return nil, fmt.Errorf("extension '%s': invalid JSON", extType)
+	}
+
+	raw := make(json.RawMessage, len(*rawMsg))
+	copy(raw, *rawMsg)
+
+	return raw, nil
+}
+
+// CheckingExtensionSTIX выполняет проверку расширения extType с помощью зарегистрированной функции проверки
+func CheckingExtensionSTIX(extType string, ext interface{}) bool {
+	if extType == "" {
+		return false
+	}
+
+	if handler, ok := GetExtensionHandlerSTIX(extType); ok {
+		if handler.Validator == nil {
+			return true
+		}
+
+		return handler.Validator(ext)
+	}
+
+	if raw, ok := ext.(json.RawMessage); ok {
+		return json.Valid(raw)
+	}
+
+	return stixhelpers.ExtensionsTypeSTIX{extType: ext}.CheckExtensionsTypeSTIX()
+}
+
+// SanitizeExtensionSTIX выполняет замену некоторых специальных символов на их HTML код в расширении
+// extType с помощью зарегистрированной функции, содержимое прочих расширений возвращается без изменений
+func SanitizeExtensionSTIX(extType string, ext interface{}) interface{} {
+	handler, ok := GetExtensionHandlerSTIX(extType)
+	if !ok || handler.Sanitizer == nil {
+		return ext
+	}
+
+	return handler.Sanitizer(ext)
+}
@@ -0,0 +1,321 @@
+package datamodels
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/datamodels/somecomplextypesstixco"
+	"github.com/av-belyakov/methodstixobjects/datamodels/someextensionsstixco"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+var (
+	hexPattern          = regexp.MustCompile(`^([0-9a-fA-F]{2})+$`)
+	socketOptionPattern = regexp.MustCompile(`^(SO|ICMP|ICMP6|IP|IPV6|MCAST|TCP|IRLMP)_[A-Z0-9_]+$`)
+)
+
+// значения перечислений используемых в расширениях STIX CO
+var (
+	addressFamilyEnum = []stixhelpers.EnumTypeSTIX{
+		"AF_UNSPEC", "AF_INET", "AF_IPX", "AF_APPLETALK", "AF_NETBIOS", "AF_INET6", "AF_IRDA", "AF_BTH",
+	}
+	socketTypeEnum = []stixhelpers.EnumTypeSTIX{
+		"SOCK_STREAM", "SOCK_DGRAM", "SOCK_RAW", "SOCK_RDM", "SOCK_SEQPACKET",
+	}
+	integrityLevelEnum = []stixhelpers.EnumTypeSTIX{
+		"low", "medium", "high", "system",
+	}
+	serviceStartTypeEnum = []stixhelpers.EnumTypeSTIX{
+		"SERVICE_AUTO_START", "SERVICE_BOOT_START", "SERVICE_DEMAND_START", "SERVICE_DISABLED", "SERVICE_SYSTEM_ALERT",
+	}
+	serviceTypeEnum = []stixhelpers.EnumTypeSTIX{
+		"SERVICE_KERNEL_DRIVER", "SERVICE_FILE_SYSTEM_DRIVER", "SERVICE_WIN32_OWN_PROCESS", "SERVICE_WIN32_SHARE_PROCESS",
+	}
+	serviceStatusEnum = []stixhelpers.EnumTypeSTIX{
+		"SERVICE_CONTINUE_PENDING", "SERVICE_PAUSE_PENDING", "SERVICE_PAUSED", "SERVICE_RUNNING",
+		"SERVICE_START_PENDING", "SERVICE_STOP_PENDING", "SERVICE_STOPPED",
+	}
+)
+
+// ExtensionPropertyErrorSTIX ошибка в значении свойства расширения STIX CO
+// Property - путь к свойству в формате селектора, например, "sections.[0].entropy"
+// Message - описание ошибки
+type ExtensionPropertyErrorSTIX struct {
+	Property string
+	Message  string
+}
+
+func (e ExtensionPropertyErrorSTIX) Error() string {
+	return fmt.Sprintf("the property '%s' %s", e.Property, e.Message)
+}
+
+type extensionErrors []ExtensionPropertyErrorSTIX
+
+func (l *extensionErrors) add(property, message string) {
+	*l = append(*l, ExtensionPropertyErrorSTIX{Property: property, Message: message})
+}
+
+func (l *extensionErrors) required(property, value string) {
+	if value == "" {
+		l.add(property, "is required")
+	}
+}
+
+func (l *extensionErrors) hex(property, value string) {
+	if value != "" && !hexPattern.MatchString(value) {
+		l.add(property, "must contain a hex value")
+	}
+}
+
+func (l *extensionErrors) enum(property string, value stixhelpers.EnumTypeSTIX, list []stixhelpers.EnumTypeSTIX) {
+	if value == "" {
+		return
+	}
+
+	for _, v := range list {
+		if v == value {
+			return
+		}
+	}
+
+	l.add(property, fmt.Sprintf("contains an unknown value '%s'", value))
+}
+
+func (l *extensionErrors) notNegative(property string, value int) {
+	if value < 0 {
+		l.add(property, "must not be negative")
+	}
+}
+
+func (l *extensionErrors) hashes(property string, value stixhelpers.HashesTypeSTIX) {
+	if !value.CheckHashesTypeSTIX() {
+		l.add(property, "contains invalid hashes")
+	}
+}
+
+func (l *extensionErrors) ref(property string, value stixhelpers.IdentifierTypeSTIX, types ...string) {
+	if value == "" {
+		return
+	}
+
+	if !value.CheckIdentifierTypeSTIX() {
+		l.add(property, "contains an invalid identifier")
+
+		return
+	}
+
+	for _, t := range types {
+		if strings.HasPrefix(string(value), t+"--") {
+			return
+		}
+	}
+
+	l.add(property, fmt.Sprintf("must refer to an object of the type '%s'", strings.Join(types, "' or '")))
+}
+
+// atLeastOne проверяет наличие хотя бы одного свойства расширения ext
+func (l *extensionErrors) atLeastOne(ext interface{}) {
+	v := reflect.ValueOf(ext)
+	if !isEmptyValue(v) {
+		return
+	}
+
+	names := make([]string, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		names = append(names, strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0])
+	}
+
+	l.add(names[0], fmt.Sprintf("or one of the properties '%s' must be present", strings.Join(names[1:], "', '")))
+}
+
+// isEmptyValue проверяет является ли значение пустым, пустыми считаются нулевые значения, пустые строки,
+// списки и словари, а также структуры все поля которых пусты
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isEmptyValue(v.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+
+	return v.IsZero()
+}
+
+// ValidatingExtensionsSTIX выполняет проверку полей следующих типов STIX расширений в соответствии
+// со спецификацией STIX 2.1 и возвращает перечень ошибок по каждому свойству:
+// - "archive-ext"
+// - "ntfs-ext"
+// - "pdf-ext"
+// - "raster-image-ext"
+// - "windows-pebinary-ext"
+// - "http-request-ext"
+// - "icmp-ext"
+// - "socket-ext"
+// - "tcp-ext"
+// - "windows-process-ext"
+// - "windows-service-ext"
+// - "unix-account-ext"
+// расширения "pdf-ext", "raster-image-ext", "windows-process-ext", "windows-service-ext" и "unix-account-ext"
+// должны содержать хотя бы одно свойство, для прочих типов возвращается пустой перечень
+func ValidatingExtensionsSTIX(extType interface{}) []ExtensionPropertyErrorSTIX {
+	errs := extensionErrors{}
+
+	switch et := extType.(type) {
+	case someextensionsstixco.ArchiveFileExtensionSTIX:
+		if len(et.ContainsRefs) == 0 {
+			errs.add("contains_refs", "is required")
+		}
+		for k, v := range et.ContainsRefs {
+			errs.ref(fmt.Sprintf("contains_refs.[%d]", k), v, "file", "directory")
+		}
+
+	case someextensionsstixco.NTFSFileExtensionSTIX:
+		if et.SID == "" && len(et.AlternateDataStreams) == 0 {
+			errs.add("sid", "or 'alternate_data_streams' must be present")
+		}
+		for k, v := range et.AlternateDataStreams {
+			errs.required(fmt.Sprintf("alternate_data_streams.[%d].name", k), v.Name)
+			errs.hashes(fmt.Sprintf("alternate_data_streams.[%d].hashes", k), v.Hashes)
+		}
+
+	case someextensionsstixco.PDFFileExtensionSTIX:
+		errs.atLeastOne(et)
+		for k := range et.DocumentInfoDict {
+			if k == "" {
+				errs.add("document_info_dict", "must not contain empty keys")
+			}
+		}
+
+	case someextensionsstixco.RasterImageFileExtensionSTIX:
+		errs.atLeastOne(et)
+		errs.notNegative("image_height", et.ImageHeight)
+		errs.notNegative("image_width", et.ImageWidth)
+		errs.notNegative("bits_per_pixel", et.BitsPerPixel)
+		errs.notNegative("exif_tags.xResolution", et.ExifTags.XResolution)
+		errs.notNegative("exif_tags.yResolution", et.ExifTags.YResolution)
+
+	case someextensionsstixco.WindowsPEBinaryFileExtensionSTIX:
+		errs.required("pe_type", string(et.PeType))
+		errs.hex("imphash", et.Imphash)
+		errs.hex("machine_hex", et.MachineHex)
+		errs.notNegative("number_of_sections", et.NumberOfSections)
+		errs.hex("pointer_to_symbol_table_hex", et.PointerToSymbolTableHex)
+		errs.notNegative("number_of_symbols", et.NumberOfSymbols)
+		errs.notNegative("size_of_optional_header", et.SizeOfOptionalHeader)
+		errs.hex("characteristics_hex", et.CharacteristicsHex)
+		errs.hashes("file_header_hashes", et.FileHeaderHashes)
+		validatingPEOptionalHeader(&errs, et.OptionalHeader)
+
+		for k, v := range et.Sections {
+			errs.required(fmt.Sprintf("sections.[%d].name", k), v.Name)
+			if v.Entropy < 0 || v.Entropy > 8 {
+				errs.add(fmt.Sprintf("sections.[%d].entropy", k), "must be in the range from 0 to 8")
+			}
+			errs.hashes(fmt.Sprintf("sections.[%d].hashes", k), v.Hashes)
+		}
+
+	case someextensionsstixco.HTTPRequestExtensionSTIX:
+		errs.required("request_method", et.RequestMethod)
+		errs.required("request_value", et.RequestValue)
+		errs.notNegative("message_body_length", et.MessageBodyLength)
+		errs.ref("message_body_data_ref", et.MessageBodyDataRef, "artifact")
+
+	case someextensionsstixco.ICMPExtensionSTIX:
+		errs.required("icmp_type_hex", et.ICMPTypeHex)
+		errs.hex("icmp_type_hex", et.ICMPTypeHex)
+		errs.required("icmp_code_hex", et.ICMPCodeHex)
+		errs.hex("icmp_code_hex", et.ICMPCodeHex)
+
+	case someextensionsstixco.NetworkSocketExtensionSTIX:
+		errs.required("address_family", string(et.AddressFamily))
+		errs.enum("address_family", et.AddressFamily, addressFamilyEnum)
+		errs.enum("socket_type", et.SocketType, socketTypeEnum)
+		errs.notNegative("socket_descriptor", et.SocketDescriptor)
+		options := make([]string, 0, len(et.Options))
+		for k := range et.Options {
+			options = append(options, k)
+		}
+		sort.Strings(options)
+
+		for _, k := range options {
+			if !socketOptionPattern.MatchString(k) {
+				errs.add("options."+k, "must begin with one of the prefixes SO_, ICMP_, ICMP6_, IP_, IPV6_, MCAST_, TCP_ or IRLMP_")
+			}
+		}
+
+	case someextensionsstixco.TCPExtensionSTIX:
+		if et.SrcFlagsHex == "" && et.DstFlagsHex == "" {
+			errs.add("src_flags_hex", "or 'dst_flags_hex' must be present")
+		}
+		errs.hex("src_flags_hex", et.SrcFlagsHex)
+		errs.hex("dst_flags_hex", et.DstFlagsHex)
+
+	case someextensionsstixco.WindowsProcessExtensionSTIX:
+		errs.atLeastOne(et)
+		errs.enum("integrity_level", et.IntegrityLevel, integrityLevelEnum)
+
+	case someextensionsstixco.WindowsServiceExtensionSTIX:
+		errs.atLeastOne(et)
+		errs.enum("start_type", et.StartType, serviceStartTypeEnum)
+		errs.enum("service_type", et.ServiceType, serviceTypeEnum)
+		errs.enum("service_status", et.ServiceStatus, serviceStatusEnum)
+		for k, v := range et.ServiceDllRefs {
+			errs.ref(fmt.Sprintf("service_dll_refs.[%d]", k), v, "file")
+		}
+
+	case someextensionsstixco.UNIXAccountExtensionSTIX:
+		errs.atLeastOne(et)
+		for k, v := range et.Groups {
+			errs.required(fmt.Sprintf("groups.[%d]", k), v)
+		}
+	}
+
+	return errs
+}
+
+// validatingPEOptionalHeader выполняет проверку полей заголовка "optional_header" расширения "windows-pebinary-ext"
+func validatingPEOptionalHeader(errs *extensionErrors, oh somecomplextypesstixco.WindowsPEOptionalHeaderTypeSTIX) {
+	for _, v := range []struct {
+		name, value string
+	}{
+		{"magic_hex", oh.MagicHex},
+		{"win32_version_value_hex", oh.Win32VersionValueHex},
+		{"checksum_hex", oh.ChecksumHex},
+		{"subsystem_hex", oh.SubsystemHex},
+		{"dll_characteristics_hex", oh.DllCharacteristicsHex},
+		{"loader_flags_hex", oh.LoaderFlagsHex},
+	} {
+		errs.hex("optional_header."+v.name, v.value)
+	}
+
+	for _, v := range []struct {
+		name  string
+		value int
+	}{
+		{"size_of_code", oh.SizeOfCode},
+		{"size_of_initialized_data", oh.SizeOfInitializedData},
+		{"size_of_uninitialized_data", oh.SizeOfUninitializedData},
+		{"size_of_image", oh.SizeOfImage},
+		{"size_of_headers", oh.SizeOfHeaders},
+		{"size_of_stack_reserve", oh.SizeOfStackReserve},
+		{"size_of_stack_commit", oh.SizeOfStackCommit},
+		{"size_of_heap_reserve", oh.SizeOfHeapReserve},
+		{"size_of_heap_commit", oh.SizeOfHeapCommit},
+		{"number_of_rva_and_sizes", oh.NumberOfRvaAndSizes},
+	} {
+		errs.notNegative("optional_header."+v.name, v.value)
+	}
+
+	for k, v := range oh.Hashes {
+		errs.hashes(fmt.Sprintf("optional_header.hashes.[%d]", k), v)
+	}
+}
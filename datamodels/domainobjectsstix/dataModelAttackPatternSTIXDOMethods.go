@@ -210,5 +210,7 @@ func (e AttackPatternDomainObjectsSTIX) GeneratingDataForIndexing() map[string]s
 		dataForIndex["aliases"] = strTmp
 	}
 
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
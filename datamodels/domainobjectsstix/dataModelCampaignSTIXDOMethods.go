@@ -249,5 +249,11 @@ func (e CampaignDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string
 		dataForIndex["aliases"] = strTmp
 	}
 
+	if e.Objective != "" {
+		dataForIndex["objective"] = e.Objective
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
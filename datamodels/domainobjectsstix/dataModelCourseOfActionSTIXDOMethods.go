@@ -117,3 +117,23 @@ func (e CourseOfActionDomainObjectsSTIX) ToStringBeautiful(num int) string {
 
 	return str.String()
 }
+
+// GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
+func (e CourseOfActionDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
+	dataForIndex := map[string]string{
+		"id":   e.ID,
+		"type": e.Type,
+	}
+
+	if e.Name != "" {
+		dataForIndex["name"] = e.Name
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
+}
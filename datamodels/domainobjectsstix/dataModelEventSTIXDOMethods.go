@@ -0,0 +1,300 @@
+package domainobjectsstix
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+/* --- EventDomainObjectsSTIX --- */
+
+// DecoderJSON выполняет декодирование JSON объекта
+func (e EventDomainObjectsSTIX) DecodeJSON(raw *json.RawMessage) (interface{}, error) {
+	if err := json.Unmarshal(*raw, &e); err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
+
+// EncoderJSON выполняет кодирование в JSON объект
+func (e EventDomainObjectsSTIX) EncodeJSON(interface{}) (*[]byte, error) {
+	result, err := json.Marshal(e)
+
+	return &result, err
+}
+
+// Get возвращает объект "Event", по терминалогии STIX, описывающий событие произошедшее в рамках инцидента
+// Обязательное значение в поле Status
+func (e *EventDomainObjectsSTIX) Get() (*EventDomainObjectsSTIX, error) {
+	if e.GetStatus() == "" {
+		err := fmt.Errorf("the required value 'Status' must not be empty")
+
+		return &EventDomainObjectsSTIX{}, err
+	}
+
+	return e, nil
+}
+
+// -------- Name property ---------
+func (e *EventDomainObjectsSTIX) GetName() string {
+	return e.Name
+}
+
+// SetValueName устанавливает значение для поля Name
+func (e *EventDomainObjectsSTIX) SetValueName(v string) {
+	e.Name = v
+}
+
+// SetAnyName устанавливает ЛЮБОЕ значение для поля Name
+func (e *EventDomainObjectsSTIX) SetAnyName(i interface{}) {
+	e.Name = fmt.Sprint(i)
+}
+
+// -------- Description property ---------
+func (e *EventDomainObjectsSTIX) GetDescription() string {
+	return e.Description
+}
+
+// SetValueDescription устанавливает значение для поля Description
+func (e *EventDomainObjectsSTIX) SetValueDescription(v string) {
+	e.Description = v
+}
+
+// SetAnyDescription устанавливает ЛЮБОЕ значение для поля Description
+func (e *EventDomainObjectsSTIX) SetAnyDescription(i interface{}) {
+	e.Description = fmt.Sprint(i)
+}
+
+// -------- Status property ---------
+func (e *EventDomainObjectsSTIX) GetStatus() stixhelpers.EnumTypeSTIX {
+	return e.Status
+}
+
+// SetValueStatus устанавливает значение для поля Status
+func (e *EventDomainObjectsSTIX) SetValueStatus(v stixhelpers.EnumTypeSTIX) {
+	e.Status = v
+}
+
+// -------- Goal property ---------
+func (e *EventDomainObjectsSTIX) GetGoal() string {
+	return e.Goal
+}
+
+// SetValueGoal устанавливает значение для поля Goal
+func (e *EventDomainObjectsSTIX) SetValueGoal(v string) {
+	e.Goal = v
+}
+
+// SetAnyGoal устанавливает ЛЮБОЕ значение для поля Goal
+func (e *EventDomainObjectsSTIX) SetAnyGoal(i interface{}) {
+	e.Goal = fmt.Sprint(i)
+}
+
+// -------- EventTypes property ---------
+func (e *EventDomainObjectsSTIX) GetEventTypes() []stixhelpers.OpenVocabTypeSTIX {
+	return e.EventTypes
+}
+
+// SetValueEventTypes устанавливает значение для поля EventTypes
+func (e *EventDomainObjectsSTIX) SetValueEventTypes(v stixhelpers.OpenVocabTypeSTIX) {
+	e.EventTypes = append(e.EventTypes, v)
+}
+
+// -------- StartTime property ---------
+func (e *EventDomainObjectsSTIX) GetStartTime() string {
+	return e.StartTime
+}
+
+// SetValueStartTime устанавливает значение в формате RFC3339 для поля StartTime
+func (e *EventDomainObjectsSTIX) SetValueStartTime(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.StartTime = v
+
+	return nil
+}
+
+// SetAnyStartTime устанавливает значение для поля StartTime
+// принимает число (timestamp 13 символов) или строку в формате RFC3339
+func (e *EventDomainObjectsSTIX) SetAnyStartTime(i interface{}) error {
+	if str, ok := i.(string); ok {
+		return e.SetValueStartTime(str)
+	}
+
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.StartTime = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+
+	return nil
+}
+
+// -------- StartTimeFidelity property ---------
+func (e *EventDomainObjectsSTIX) GetStartTimeFidelity() stixhelpers.EnumTypeSTIX {
+	return e.StartTimeFidelity
+}
+
+// SetValueStartTimeFidelity устанавливает значение для поля StartTimeFidelity
+func (e *EventDomainObjectsSTIX) SetValueStartTimeFidelity(v stixhelpers.EnumTypeSTIX) {
+	e.StartTimeFidelity = v
+}
+
+// -------- EndTime property ---------
+func (e *EventDomainObjectsSTIX) GetEndTime() string {
+	return e.EndTime
+}
+
+// SetValueEndTime устанавливает значение в формате RFC3339 для поля EndTime
+func (e *EventDomainObjectsSTIX) SetValueEndTime(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.EndTime = v
+
+	return nil
+}
+
+// SetAnyEndTime устанавливает значение для поля EndTime
+// принимает число (timestamp 13 символов) или строку в формате RFC3339
+func (e *EventDomainObjectsSTIX) SetAnyEndTime(i interface{}) error {
+	if str, ok := i.(string); ok {
+		return e.SetValueEndTime(str)
+	}
+
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.EndTime = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+
+	return nil
+}
+
+// -------- EndTimeFidelity property ---------
+func (e *EventDomainObjectsSTIX) GetEndTimeFidelity() stixhelpers.EnumTypeSTIX {
+	return e.EndTimeFidelity
+}
+
+// SetValueEndTimeFidelity устанавливает значение для поля EndTimeFidelity
+func (e *EventDomainObjectsSTIX) SetValueEndTimeFidelity(v stixhelpers.EnumTypeSTIX) {
+	e.EndTimeFidelity = v
+}
+
+// -------- SightingRefs property ---------
+func (e *EventDomainObjectsSTIX) GetSightingRefs() []stixhelpers.IdentifierTypeSTIX {
+	return e.SightingRefs
+}
+
+// SetValueSightingRefs устанавливает значение для поля SightingRefs
+func (e *EventDomainObjectsSTIX) SetValueSightingRefs(v stixhelpers.IdentifierTypeSTIX) {
+	e.SightingRefs = append(e.SightingRefs, v)
+}
+
+// -------- ChangedObjects property ---------
+func (e *EventDomainObjectsSTIX) GetChangedObjects() []StateChangeTypeSTIX {
+	return e.ChangedObjects
+}
+
+// SetValueChangedObjects устанавливает значение для поля ChangedObjects
+func (e *EventDomainObjectsSTIX) SetValueChangedObjects(v StateChangeTypeSTIX) {
+	e.ChangedObjects = append(e.ChangedObjects, v)
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе EventDomainObjectsSTIX
+func (e EventDomainObjectsSTIX) ValidateStruct() bool {
+	if !(regexp.MustCompile(`^(event--)[0-9a-f|-]+$`).MatchString(e.ID)) {
+		return false
+	}
+
+	//обязательное поле
+	if !isEnumValue(e.Status, "not-occurred", "occurred", "ongoing", "pending", "undetected") {
+		return false
+	}
+
+	if !isNewSDO(e.Extensions, EventExtensionDefinitionID) {
+		return false
+	}
+
+	if !isValidTimeInterval(e.StartTime, e.StartTimeFidelity, e.EndTime, e.EndTimeFidelity) {
+		return false
+	}
+
+	if !isValidRefs(e.SightingRefs, "sighting") {
+		return false
+	}
+
+	if !isValidChangedObjects(e.ChangedObjects) {
+		return false
+	}
+
+	return e.ValidateStructCommonFields()
+}
+
+// SanitizeStruct для ряда полей, выполняет замену некоторых специальных символов на их HTML код
+func (e EventDomainObjectsSTIX) SanitizeStruct() EventDomainObjectsSTIX {
+	e.CommonPropertiesDomainObjectSTIX = e.CommonPropertiesDomainObjectSTIX.SanitizeStruct()
+
+	e.Name = commonlibs.StringSanitize(e.Name)
+	e.Description = commonlibs.StringSanitize(e.Description)
+	e.Goal = commonlibs.StringSanitize(e.Goal)
+	e.EventTypes = sanitizeOpenVocabList(e.EventTypes)
+
+	return e
+}
+
+// GetID возвращает ID STIX объекта
+func (e EventDomainObjectsSTIX) GetID() string {
+	return e.ID
+}
+
+// GetType возвращает Type STIX объекта
+func (e EventDomainObjectsSTIX) GetType() string {
+	return e.Type
+}
+
+// ToStringBeautiful выполняет красивое представление информации содержащейся в типе
+func (e EventDomainObjectsSTIX) ToStringBeautiful(num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	str.WriteString(e.CommonPropertiesObjectSTIX.ToStringBeautiful(num))
+	str.WriteString(e.CommonPropertiesDomainObjectSTIX.ToStringBeautiful(num))
+	str.WriteString(fmt.Sprintf("%s'name': '%s'\n", ws, e.Name))
+	str.WriteString(fmt.Sprintf("%s'description': '%s'\n", ws, e.Description))
+	str.WriteString(fmt.Sprintf("%s'status': '%v'\n", ws, e.Status))
+	str.WriteString(fmt.Sprintf("%s'goal': '%s'\n", ws, e.Goal))
+	str.WriteString(fmt.Sprintf("%s'event_types': '%v'\n", ws, e.EventTypes))
+	str.WriteString(fmt.Sprintf("%s'start_time': '%s'\n", ws, e.StartTime))
+	str.WriteString(fmt.Sprintf("%s'start_time_fidelity': '%v'\n", ws, e.StartTimeFidelity))
+	str.WriteString(fmt.Sprintf("%s'end_time': '%s'\n", ws, e.EndTime))
+	str.WriteString(fmt.Sprintf("%s'end_time_fidelity': '%v'\n", ws, e.EndTimeFidelity))
+	str.WriteString(fmt.Sprintf("%s'sighting_refs': '%v'\n", ws, e.SightingRefs))
+	str.WriteString(fmt.Sprintf("%s'changed_objects': \n%v", ws, changedObjectsToString(e.ChangedObjects, num+1)))
+
+	return str.String()
+}
+
+// GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
+func (e EventDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
+	dataForIndex := map[string]string{
+		"id":     e.ID,
+		"type":   e.Type,
+		"status": string(e.Status),
+	}
+
+	if e.Name != "" {
+		dataForIndex["name"] = e.Name
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
+}
@@ -160,3 +160,27 @@ func (e GroupingDomainObjectsSTIX) ToStringBeautiful(num int) string {
 
 	return str.String()
 }
+
+// GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
+func (e GroupingDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
+	dataForIndex := map[string]string{
+		"id":   e.ID,
+		"type": e.Type,
+	}
+
+	if e.Name != "" {
+		dataForIndex["name"] = e.Name
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	if e.Context != "" {
+		dataForIndex["context"] = string(e.Context)
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
+}
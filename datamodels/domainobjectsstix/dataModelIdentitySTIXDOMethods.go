@@ -213,3 +213,39 @@ func (e IdentityDomainObjectsSTIX) ToStringBeautiful(num int) string {
 
 	return str.String()
 }
+
+// GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
+func (e IdentityDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
+	dataForIndex := map[string]string{
+		"id":   e.ID,
+		"type": e.Type,
+	}
+
+	if e.Name != "" {
+		dataForIndex["name"] = e.Name
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	if e.ContactInformation != "" {
+		dataForIndex["contact_information"] = e.ContactInformation
+	}
+
+	if e.IdentityClass != "" {
+		dataForIndex["identity_class"] = string(e.IdentityClass)
+	}
+
+	if v := stixhelpers.JoinForIndexing(e.Roles); v != "" {
+		dataForIndex["roles"] = v
+	}
+
+	if v := stixhelpers.JoinForIndexing(e.Sectors); v != "" {
+		dataForIndex["sectors"] = v
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
+}
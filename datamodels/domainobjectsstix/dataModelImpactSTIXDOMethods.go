@@ -0,0 +1,462 @@
+package domainobjectsstix
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// impactCategories значения категорий последствий объекта "impact", для каждой категории определено
+// расширение с ключом "<категория>-ext"
+var impactCategories = []stixhelpers.EnumTypeSTIX{
+	"availability", "confidentiality", "external", "integrity", "monetary", "physical", "traceability",
+}
+
+func init() {
+	for k, v := range map[stixhelpers.EnumTypeSTIX]interface{ ValidateStruct() bool }{
+		"availability":    AvailabilityImpactExtensionSTIX{},
+		"confidentiality": ConfidentialityImpactExtensionSTIX{},
+		"external":        ExternalImpactExtensionSTIX{},
+		"integrity":       IntegrityImpactExtensionSTIX{},
+		"monetary":        MonetaryImpactExtensionSTIX{},
+		"physical":        PhysicalImpactExtensionSTIX{},
+		"traceability":    TraceabilityImpactExtensionSTIX{},
+	} {
+		_ = stixhelpers.RegisterExtensionSTIX(impactCategoryExtensionKey(k), v, func(i interface{}) bool {
+			ext, ok := i.(interface{ ValidateStruct() bool })
+
+			return ok && ext.ValidateStruct()
+		})
+	}
+}
+
+/* --- ImpactDomainObjectsSTIX --- */
+
+// DecoderJSON выполняет декодирование JSON объекта
+func (e ImpactDomainObjectsSTIX) DecodeJSON(raw *json.RawMessage) (interface{}, error) {
+	if err := json.Unmarshal(*raw, &e); err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
+
+// EncoderJSON выполняет кодирование в JSON объект
+func (e ImpactDomainObjectsSTIX) EncodeJSON(interface{}) (*[]byte, error) {
+	result, err := json.Marshal(e)
+
+	return &result, err
+}
+
+// Get возвращает объект "Impact", по терминалогии STIX, описывающий последствия инцидента
+// Обязательное значение в поле ImpactCategory
+func (e *ImpactDomainObjectsSTIX) Get() (*ImpactDomainObjectsSTIX, error) {
+	if e.GetImpactCategory() == "" {
+		err := fmt.Errorf("the required value 'ImpactCategory' must not be empty")
+
+		return &ImpactDomainObjectsSTIX{}, err
+	}
+
+	return e, nil
+}
+
+// -------- ImpactCategory property ---------
+func (e *ImpactDomainObjectsSTIX) GetImpactCategory() stixhelpers.EnumTypeSTIX {
+	return e.ImpactCategory
+}
+
+// SetValueImpactCategory устанавливает значение для поля ImpactCategory
+func (e *ImpactDomainObjectsSTIX) SetValueImpactCategory(v stixhelpers.EnumTypeSTIX) {
+	e.ImpactCategory = v
+}
+
+// -------- Description property ---------
+func (e *ImpactDomainObjectsSTIX) GetDescription() string {
+	return e.Description
+}
+
+// SetValueDescription устанавливает значение для поля Description
+func (e *ImpactDomainObjectsSTIX) SetValueDescription(v string) {
+	e.Description = v
+}
+
+// SetAnyDescription устанавливает ЛЮБОЕ значение для поля Description
+func (e *ImpactDomainObjectsSTIX) SetAnyDescription(i interface{}) {
+	e.Description = fmt.Sprint(i)
+}
+
+// -------- Criticality property ---------
+func (e *ImpactDomainObjectsSTIX) GetCriticality() int {
+	return e.Criticality
+}
+
+// SetValueCriticality устанавливает значение для поля Criticality
+func (e *ImpactDomainObjectsSTIX) SetValueCriticality(v int) {
+	e.Criticality = v
+}
+
+// SetAnyCriticality устанавливает ЛЮБОЕ значение для поля Criticality
+func (e *ImpactDomainObjectsSTIX) SetAnyCriticality(i interface{}) {
+	e.Criticality = commonlibs.ConversionAnyToInt(i)
+}
+
+// -------- Recoverability property ---------
+func (e *ImpactDomainObjectsSTIX) GetRecoverability() stixhelpers.EnumTypeSTIX {
+	return e.Recoverability
+}
+
+// SetValueRecoverability устанавливает значение для поля Recoverability
+func (e *ImpactDomainObjectsSTIX) SetValueRecoverability(v stixhelpers.EnumTypeSTIX) {
+	e.Recoverability = v
+}
+
+// -------- ImpactedEntityCounts property ---------
+func (e *ImpactDomainObjectsSTIX) GetImpactedEntityCounts() map[string]int {
+	return e.ImpactedEntityCounts
+}
+
+// SetValueImpactedEntityCounts устанавливает значение k для поля ImpactedEntityCounts
+func (e *ImpactDomainObjectsSTIX) SetValueImpactedEntityCounts(k string, v int) {
+	if e.ImpactedEntityCounts == nil {
+		e.ImpactedEntityCounts = map[string]int{}
+	}
+
+	e.ImpactedEntityCounts[k] = v
+}
+
+// -------- ImpactedRefs property ---------
+func (e *ImpactDomainObjectsSTIX) GetImpactedRefs() []stixhelpers.IdentifierTypeSTIX {
+	return e.ImpactedRefs
+}
+
+// SetValueImpactedRefs устанавливает значение для поля ImpactedRefs
+func (e *ImpactDomainObjectsSTIX) SetValueImpactedRefs(v stixhelpers.IdentifierTypeSTIX) {
+	e.ImpactedRefs = append(e.ImpactedRefs, v)
+}
+
+// -------- StartTime property ---------
+func (e *ImpactDomainObjectsSTIX) GetStartTime() string {
+	return e.StartTime
+}
+
+// SetValueStartTime устанавливает значение в формате RFC3339 для поля StartTime
+func (e *ImpactDomainObjectsSTIX) SetValueStartTime(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.StartTime = v
+
+	return nil
+}
+
+// SetAnyStartTime устанавливает значение для поля StartTime
+// принимает число (timestamp 13 символов) или строку в формате RFC3339
+func (e *ImpactDomainObjectsSTIX) SetAnyStartTime(i interface{}) error {
+	if str, ok := i.(string); ok {
+		return e.SetValueStartTime(str)
+	}
+
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.StartTime = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+
+	return nil
+}
+
+// -------- StartTimeFidelity property ---------
+func (e *ImpactDomainObjectsSTIX) GetStartTimeFidelity() stixhelpers.EnumTypeSTIX {
+	return e.StartTimeFidelity
+}
+
+// SetValueStartTimeFidelity устанавливает значение для поля StartTimeFidelity
+func (e *ImpactDomainObjectsSTIX) SetValueStartTimeFidelity(v stixhelpers.EnumTypeSTIX) {
+	e.StartTimeFidelity = v
+}
+
+// -------- EndTime property ---------
+func (e *ImpactDomainObjectsSTIX) GetEndTime() string {
+	return e.EndTime
+}
+
+// SetValueEndTime устанавливает значение в формате RFC3339 для поля EndTime
+func (e *ImpactDomainObjectsSTIX) SetValueEndTime(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.EndTime = v
+
+	return nil
+}
+
+// SetAnyEndTime устанавливает значение для поля EndTime
+// принимает число (timestamp 13 символов) или строку в формате RFC3339
+func (e *ImpactDomainObjectsSTIX) SetAnyEndTime(i interface{}) error {
+	if str, ok := i.(string); ok {
+		return e.SetValueEndTime(str)
+	}
+
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.EndTime = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+
+	return nil
+}
+
+// -------- EndTimeFidelity property ---------
+func (e *ImpactDomainObjectsSTIX) GetEndTimeFidelity() stixhelpers.EnumTypeSTIX {
+	return e.EndTimeFidelity
+}
+
+// SetValueEndTimeFidelity устанавливает значение для поля EndTimeFidelity
+func (e *ImpactDomainObjectsSTIX) SetValueEndTimeFidelity(v stixhelpers.EnumTypeSTIX) {
+	e.EndTimeFidelity = v
+}
+
+// -------- SupersededByRef property ---------
+func (e *ImpactDomainObjectsSTIX) GetSupersededByRef() stixhelpers.IdentifierTypeSTIX {
+	return e.SupersededByRef
+}
+
+// SetValueSupersededByRef устанавливает значение для поля SupersededByRef
+func (e *ImpactDomainObjectsSTIX) SetValueSupersededByRef(v stixhelpers.IdentifierTypeSTIX) {
+	e.SupersededByRef = v
+}
+
+// -------- Impact category extension ---------
+
+// GetImpactCategoryExtension возвращает расширение объекта соответствующее категории ImpactCategory,
+// например, AvailabilityImpactExtensionSTIX для категории "availability"
+func (e *ImpactDomainObjectsSTIX) GetImpactCategoryExtension() (interface{}, bool) {
+	if e.ImpactCategory == "" {
+		return nil, false
+	}
+
+	v, ok, err := e.Extensions.GetExtension(impactCategoryExtensionKey(e.ImpactCategory))
+	if !ok || err != nil {
+		return nil, false
+	}
+
+	return v, true
+}
+
+// SetValueImpactCategoryExtension устанавливает расширение объекта соответствующее категории последствий, если
+// значение ImpactCategory не задано, оно определяется по типу расширения
+func (e *ImpactDomainObjectsSTIX) SetValueImpactCategoryExtension(v interface{}) error {
+	var category stixhelpers.EnumTypeSTIX
+
+	switch v.(type) {
+	case AvailabilityImpactExtensionSTIX:
+		category = "availability"
+	case ConfidentialityImpactExtensionSTIX:
+		category = "confidentiality"
+	case ExternalImpactExtensionSTIX:
+		category = "external"
+	case IntegrityImpactExtensionSTIX:
+		category = "integrity"
+	case MonetaryImpactExtensionSTIX:
+		category = "monetary"
+	case PhysicalImpactExtensionSTIX:
+		category = "physical"
+	case TraceabilityImpactExtensionSTIX:
+		category = "traceability"
+	default:
+		return fmt.Errorf("unsupported type '%T' of the impact category extension", v)
+	}
+
+	if e.ImpactCategory == "" {
+		e.ImpactCategory = category
+	}
+
+	if e.ImpactCategory != category {
+		return fmt.Errorf("the extension of the impact category '%s' does not match the impact category '%s'", category, e.ImpactCategory)
+	}
+
+	e.SetValueExtensions(impactCategoryExtensionKey(category), v)
+
+	return nil
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе ImpactDomainObjectsSTIX
+func (e ImpactDomainObjectsSTIX) ValidateStruct() bool {
+	if !(regexp.MustCompile(`^(impact--)[0-9a-f|-]+$`).MatchString(e.ID)) {
+		return false
+	}
+
+	//обязательное поле
+	if !isEnumValue(e.ImpactCategory, impactCategories...) {
+		return false
+	}
+
+	//допускается только расширение соответствующее категории последствий
+	for _, v := range impactCategories {
+		if _, ok := e.Extensions[impactCategoryExtensionKey(v)]; ok && v != e.ImpactCategory {
+			return false
+		}
+	}
+
+	if !isNewSDO(e.Extensions, ImpactExtensionDefinitionID) {
+		return false
+	}
+
+	if e.Criticality < 0 || e.Criticality > 100 {
+		return false
+	}
+
+	if e.Recoverability != "" && !isEnumValue(e.Recoverability, recoverabilityEnum...) {
+		return false
+	}
+
+	if !isValidEntityCounts(e.ImpactedEntityCounts) {
+		return false
+	}
+
+	if !isValidRefs(e.ImpactedRefs) {
+		return false
+	}
+
+	if e.SupersededByRef != "" && !isValidRefs([]stixhelpers.IdentifierTypeSTIX{e.SupersededByRef}, "impact") {
+		return false
+	}
+
+	if !isValidTimeInterval(e.StartTime, e.StartTimeFidelity, e.EndTime, e.EndTimeFidelity) {
+		return false
+	}
+
+	return e.ValidateStructCommonFields()
+}
+
+// SanitizeStruct для ряда полей, выполняет замену некоторых специальных символов на их HTML код
+func (e ImpactDomainObjectsSTIX) SanitizeStruct() ImpactDomainObjectsSTIX {
+	e.CommonPropertiesDomainObjectSTIX = e.CommonPropertiesDomainObjectSTIX.SanitizeStruct()
+
+	e.Description = commonlibs.StringSanitize(e.Description)
+
+	if len(e.ImpactedEntityCounts) > 0 {
+		counts := make(map[string]int, len(e.ImpactedEntityCounts))
+		for k, v := range e.ImpactedEntityCounts {
+			counts[commonlibs.StringSanitize(k)] = v
+		}
+
+		e.ImpactedEntityCounts = counts
+	}
+
+	return e
+}
+
+// GetID возвращает ID STIX объекта
+func (e ImpactDomainObjectsSTIX) GetID() string {
+	return e.ID
+}
+
+// GetType возвращает Type STIX объекта
+func (e ImpactDomainObjectsSTIX) GetType() string {
+	return e.Type
+}
+
+// ToStringBeautiful выполняет красивое представление информации содержащейся в типе
+func (e ImpactDomainObjectsSTIX) ToStringBeautiful(num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	str.WriteString(e.CommonPropertiesObjectSTIX.ToStringBeautiful(num))
+	str.WriteString(e.CommonPropertiesDomainObjectSTIX.ToStringBeautiful(num))
+	str.WriteString(fmt.Sprintf("%s'impact_category': '%v'\n", ws, e.ImpactCategory))
+	str.WriteString(fmt.Sprintf("%s'description': '%s'\n", ws, e.Description))
+	str.WriteString(fmt.Sprintf("%s'criticality': '%d'\n", ws, e.Criticality))
+	str.WriteString(fmt.Sprintf("%s'recoverability': '%v'\n", ws, e.Recoverability))
+	str.WriteString(fmt.Sprintf("%s'impacted_entity_counts': '%v'\n", ws, e.ImpactedEntityCounts))
+	str.WriteString(fmt.Sprintf("%s'impacted_refs': '%v'\n", ws, e.ImpactedRefs))
+	str.WriteString(fmt.Sprintf("%s'start_time': '%s'\n", ws, e.StartTime))
+	str.WriteString(fmt.Sprintf("%s'start_time_fidelity': '%v'\n", ws, e.StartTimeFidelity))
+	str.WriteString(fmt.Sprintf("%s'end_time': '%s'\n", ws, e.EndTime))
+	str.WriteString(fmt.Sprintf("%s'end_time_fidelity': '%v'\n", ws, e.EndTimeFidelity))
+	str.WriteString(fmt.Sprintf("%s'superseded_by_ref': '%v'\n", ws, e.SupersededByRef))
+
+	return str.String()
+}
+
+// GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
+func (e ImpactDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
+	dataForIndex := map[string]string{
+		"id":              e.ID,
+		"type":            e.Type,
+		"impact_category": string(e.ImpactCategory),
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
+}
+
+/* --- Impact category extensions --- */
+
+// ValidateStruct является валидатором параметров содержащихся в типе AvailabilityImpactExtensionSTIX
+func (e AvailabilityImpactExtensionSTIX) ValidateStruct() bool {
+	return e.AvailabilityImpact >= 0 && e.AvailabilityImpact <= 100
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе ConfidentialityImpactExtensionSTIX
+func (e ConfidentialityImpactExtensionSTIX) ValidateStruct() bool {
+	if !isEnumValue(e.LossType, "confirmed-loss", "contained", "exploited-loss", "none", "suspected-loss") {
+		return false
+	}
+
+	return e.RecordCount >= 0 && e.RecordSize >= 0
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе ExternalImpactExtensionSTIX
+func (e ExternalImpactExtensionSTIX) ValidateStruct() bool {
+	return e.ImpactType != ""
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе IntegrityImpactExtensionSTIX
+func (e IntegrityImpactExtensionSTIX) ValidateStruct() bool {
+	if !isEnumValue(e.Alteration, "full-replacement", "partial-replacement", "potential-replacement") {
+		return false
+	}
+
+	return e.RecordCount >= 0 && e.RecordSize >= 0
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе MonetaryImpactExtensionSTIX
+func (e MonetaryImpactExtensionSTIX) ValidateStruct() bool {
+	if e.Variety == "" {
+		return false
+	}
+
+	if e.ConversionTime != "" {
+		if _, err := time.Parse(time.RFC3339, e.ConversionTime); err != nil {
+			return false
+		}
+	}
+
+	if e.ConversionRate < 0 || e.MinAmount < 0 || e.MaxAmount < 0 {
+		return false
+	}
+
+	return e.MaxAmount == 0 || e.MinAmount <= e.MaxAmount
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе PhysicalImpactExtensionSTIX
+func (e PhysicalImpactExtensionSTIX) ValidateStruct() bool {
+	return isEnumValue(e.ImpactType, "damaged-functional", "damaged-nonfunctional", "destruction", "none", "unknown")
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе TraceabilityImpactExtensionSTIX
+func (e TraceabilityImpactExtensionSTIX) ValidateStruct() bool {
+	return isEnumValue(e.TraceabilityImpact, "accountability-lost", "partial-accountability", "provable-accountability")
+}
+
+// impactCategoryExtensionKey возвращает ключ расширения соответствующего категории последствий
+func impactCategoryExtensionKey(category stixhelpers.EnumTypeSTIX) string {
+	return string(category) + "-ext"
+}
@@ -0,0 +1,480 @@
+package domainobjectsstix
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// Идентификаторы объектов "extension-definition" расширения "incident-core", объект "incident" дополняется
+// расширением типа "property-extension", объекты "event", "impact" и "task" определяются собственными
+// расширениями типа "new-sdo"
+const (
+	IncidentCoreExtensionDefinitionID = "extension-definition--ef765651-680c-498d-9894-99799f2fa126"
+	EventExtensionDefinitionID        = "extension-definition--4ca6de00-5b0d-45ef-a1dc-ea7279ea910e"
+	ImpactExtensionDefinitionID       = "extension-definition--7cc33dd6-f6a1-489b-98ea-522d351d71b9"
+	TaskExtensionDefinitionID         = "extension-definition--2074a052-8be4-4932-849e-f5e7798e0030"
+)
+
+func init() {
+	_ = stixhelpers.RegisterExtensionSTIX(IncidentCoreExtensionDefinitionID, IncidentCoreExtensionSTIX{}, func(i interface{}) bool {
+		ext, ok := i.(IncidentCoreExtensionSTIX)
+
+		return ok && ext.ValidateStruct()
+	})
+
+	for _, id := range []string{EventExtensionDefinitionID, ImpactExtensionDefinitionID, TaskExtensionDefinitionID} {
+		_ = stixhelpers.RegisterExtensionSTIX(id, NewSDOExtensionSTIX{}, func(i interface{}) bool {
+			ext, ok := i.(NewSDOExtensionSTIX)
+
+			return ok && ext.ExtensionType == stixhelpers.ExtensionTypeNewSDO
+		})
+	}
+}
+
+// значения перечислений используемых в объектах расширения "incident-core"
+var (
+	recoverabilityEnum = []stixhelpers.EnumTypeSTIX{
+		"extended", "not-applicable", "not-recoverable", "regular", "supplemented",
+	}
+	timestampFidelityEnum = []stixhelpers.EnumTypeSTIX{
+		"day", "hour", "minute", "month", "second", "year",
+	}
+)
+
+/* --- IncidentDomainObjectsSTIX --- */
+
+// DecoderJSON выполняет декодирование JSON объекта
+func (e IncidentDomainObjectsSTIX) DecodeJSON(raw *json.RawMessage) (interface{}, error) {
+	if err := json.Unmarshal(*raw, &e); err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
+
+// EncoderJSON выполняет кодирование в JSON объект
+func (e IncidentDomainObjectsSTIX) EncodeJSON(interface{}) (*[]byte, error) {
+	result, err := json.Marshal(e)
+
+	return &result, err
+}
+
+// Get возвращает объект "Incident", по терминалогии STIX, содержащий сведения об инциденте информационной безопасности
+// Обязательное значение в поле Name
+func (e *IncidentDomainObjectsSTIX) Get() (*IncidentDomainObjectsSTIX, error) {
+	if e.GetName() == "" {
+		err := fmt.Errorf("the required value 'Name' must not be empty")
+
+		return &IncidentDomainObjectsSTIX{}, err
+	}
+
+	return e, nil
+}
+
+// -------- Name property ---------
+func (e *IncidentDomainObjectsSTIX) GetName() string {
+	return e.Name
+}
+
+// SetValueName устанавливает значение для поля Name
+func (e *IncidentDomainObjectsSTIX) SetValueName(v string) {
+	e.Name = v
+}
+
+// SetAnyName устанавливает ЛЮБОЕ значение для поля Name
+func (e *IncidentDomainObjectsSTIX) SetAnyName(i interface{}) {
+	e.Name = fmt.Sprint(i)
+}
+
+// -------- Description property ---------
+func (e *IncidentDomainObjectsSTIX) GetDescription() string {
+	return e.Description
+}
+
+// SetValueDescription устанавливает значение для поля Description
+func (e *IncidentDomainObjectsSTIX) SetValueDescription(v string) {
+	e.Description = v
+}
+
+// SetAnyDescription устанавливает ЛЮБОЕ значение для поля Description
+func (e *IncidentDomainObjectsSTIX) SetAnyDescription(i interface{}) {
+	e.Description = fmt.Sprint(i)
+}
+
+// -------- KillChainPhases property ---------
+func (e *IncidentDomainObjectsSTIX) GetKillChainPhases() []stixhelpers.KillChainPhasesTypeElementSTIX {
+	return e.KillChainPhases
+}
+
+func (e *IncidentDomainObjectsSTIX) SetValueKillChainPhases(v stixhelpers.KillChainPhasesTypeElementSTIX) {
+	e.KillChainPhases = append(e.KillChainPhases, v)
+}
+
+func (e *IncidentDomainObjectsSTIX) SetFullValueKillChainPhases(v []stixhelpers.KillChainPhasesTypeElementSTIX) {
+	e.KillChainPhases = v
+}
+
+// -------- IncidentCore extension ---------
+
+// GetIncidentCoreExtension возвращает расширение "incident-core" объекта
+func (e *IncidentDomainObjectsSTIX) GetIncidentCoreExtension() (IncidentCoreExtensionSTIX, bool) {
+	v, ok, err := e.Extensions.GetExtension(IncidentCoreExtensionDefinitionID)
+	if !ok || err != nil {
+		return IncidentCoreExtensionSTIX{}, false
+	}
+
+	ext, ok := v.(IncidentCoreExtensionSTIX)
+
+	return ext, ok
+}
+
+// SetValueIncidentCoreExtension устанавливает расширение "incident-core" объекта, если тип расширения
+// не задан, ему присваивается значение "property-extension"
+func (e *IncidentDomainObjectsSTIX) SetValueIncidentCoreExtension(v IncidentCoreExtensionSTIX) {
+	if v.ExtensionType == "" {
+		v.ExtensionType = stixhelpers.ExtensionTypePropertyExtension
+	}
+
+	e.SetValueExtensions(IncidentCoreExtensionDefinitionID, v)
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе IncidentDomainObjectsSTIX
+func (e IncidentDomainObjectsSTIX) ValidateStruct() bool {
+	if !(regexp.MustCompile(`^(incident--)[0-9a-f|-]+$`).MatchString(e.ID)) {
+		return false
+	}
+
+	//обязательное поле
+	if e.Name == "" {
+		return false
+	}
+
+	for _, v := range e.KillChainPhases {
+		if v.KillChainName == "" || v.PhaseName == "" {
+			return false
+		}
+	}
+
+	//расширение объекта "incident" должно иметь тип "property-extension"
+	if ext, ok := e.GetIncidentCoreExtension(); ok && !ext.ValidateStruct() {
+		return false
+	}
+
+	return e.ValidateStructCommonFields()
+}
+
+// SanitizeStruct для ряда полей, выполняет замену некоторых специальных символов на их HTML код
+func (e IncidentDomainObjectsSTIX) SanitizeStruct() IncidentDomainObjectsSTIX {
+	e.CommonPropertiesDomainObjectSTIX = e.CommonPropertiesDomainObjectSTIX.SanitizeStruct()
+
+	e.Name = commonlibs.StringSanitize(e.Name)
+	e.Description = commonlibs.StringSanitize(e.Description)
+
+	newKillChainPhases := make([]stixhelpers.KillChainPhasesTypeElementSTIX, 0, len(e.KillChainPhases))
+	for _, v := range e.KillChainPhases {
+		newKillChainPhases = append(newKillChainPhases, v.SanitizeStructKillChainPhasesTypeElementSTIX())
+	}
+
+	e.KillChainPhases = newKillChainPhases
+
+	if ext, ok := e.GetIncidentCoreExtension(); ok {
+		e.SetValueIncidentCoreExtension(ext.SanitizeStruct())
+	}
+
+	return e
+}
+
+// GetID возвращает ID STIX объекта
+func (e IncidentDomainObjectsSTIX) GetID() string {
+	return e.ID
+}
+
+// GetType возвращает Type STIX объекта
+func (e IncidentDomainObjectsSTIX) GetType() string {
+	return e.Type
+}
+
+// ToStringBeautiful выполняет красивое представление информации содержащейся в типе
+func (e IncidentDomainObjectsSTIX) ToStringBeautiful(num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	str.WriteString(e.CommonPropertiesObjectSTIX.ToStringBeautiful(num))
+	str.WriteString(e.CommonPropertiesDomainObjectSTIX.ToStringBeautiful(num))
+	str.WriteString(fmt.Sprintf("%s'name': '%s'\n", ws, e.Name))
+	str.WriteString(fmt.Sprintf("%s'description': '%s'\n", ws, e.Description))
+	str.WriteString(fmt.Sprintf("%s'kill_chain_phases': \n%v", ws, func(l []stixhelpers.KillChainPhasesTypeElementSTIX, num int) string {
+		str := strings.Builder{}
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'key': '%v' 'kill_chain_name': '%s'\n", ws, k, v.KillChainName))
+			str.WriteString(fmt.Sprintf("%s'key': '%v' 'phase_name': '%s'\n", ws, k, v.PhaseName))
+		}
+
+		return str.String()
+	}(e.KillChainPhases, num+1)))
+
+	if ext, ok := e.GetIncidentCoreExtension(); ok {
+		str.WriteString(fmt.Sprintf("%s'incident_core_extension': \n%s", ws, ext.ToStringBeautiful(num+1)))
+	}
+
+	return str.String()
+}
+
+// GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
+func (e IncidentDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
+	dataForIndex := map[string]string{
+		"id":   e.ID,
+		"type": e.Type,
+	}
+
+	if e.Name != "" {
+		dataForIndex["name"] = e.Name
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
+}
+
+/* --- IncidentCoreExtensionSTIX --- */
+
+// ValidateStruct является валидатором параметров содержащихся в типе IncidentCoreExtensionSTIX
+func (e IncidentCoreExtensionSTIX) ValidateStruct() bool {
+	if e.ExtensionType != stixhelpers.ExtensionTypePropertyExtension {
+		return false
+	}
+
+	//обязательные поля
+	if !isEnumValue(e.Determination, "blocked", "failed-attempt", "false-positive", "low-value", "successful-attempt", "suspected", "tbd") {
+		return false
+	}
+
+	if !isEnumValue(e.InvestigationStatus, "closed", "new", "open") {
+		return false
+	}
+
+	if e.Recoverability != "" && !isEnumValue(e.Recoverability, recoverabilityEnum...) {
+		return false
+	}
+
+	if e.Criticality < 0 || e.Criticality > 100 {
+		return false
+	}
+
+	for _, v := range e.Scores {
+		if v.Name == "" {
+			return false
+		}
+	}
+
+	for _, refs := range []struct {
+		objType string
+		list    []stixhelpers.IdentifierTypeSTIX
+	}{
+		{"impact", e.ImpactRefs},
+		{"event", e.EventRefs},
+		{"task", e.TaskRefs},
+		{"sighting", e.SightingRefs},
+		{"observed-data", e.OtherObservedDataRefs},
+	} {
+		if !isValidRefs(refs.list, refs.objType) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SanitizeStruct для ряда полей, выполняет замену некоторых специальных символов на их HTML код
+func (e IncidentCoreExtensionSTIX) SanitizeStruct() IncidentCoreExtensionSTIX {
+	e.DetectionMethods = sanitizeOpenVocabList(e.DetectionMethods)
+	e.IncidentTypes = sanitizeOpenVocabList(e.IncidentTypes)
+
+	if len(e.Scores) > 0 {
+		scores := make([]IncidentScoreTypeSTIX, 0, len(e.Scores))
+		for _, v := range e.Scores {
+			scores = append(scores, IncidentScoreTypeSTIX{
+				Name:        commonlibs.StringSanitize(v.Name),
+				Value:       v.Value,
+				Description: commonlibs.StringSanitize(v.Description),
+			})
+		}
+
+		e.Scores = scores
+	}
+
+	return e
+}
+
+// ToStringBeautiful выполняет красивое представление информации содержащейся в типе
+func (e IncidentCoreExtensionSTIX) ToStringBeautiful(num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	listToString := func(name string, l []stixhelpers.IdentifierTypeSTIX) string {
+		str := strings.Builder{}
+		str.WriteString(fmt.Sprintf("%s'%s': \n", ws, name))
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s\t'%s '%d'': '%v'\n", ws, strings.TrimSuffix(name, "s"), k, v))
+		}
+
+		return str.String()
+	}
+
+	str.WriteString(fmt.Sprintf("%s'extension_type': '%v'\n", ws, e.ExtensionType))
+	str.WriteString(fmt.Sprintf("%s'determination': '%v'\n", ws, e.Determination))
+	str.WriteString(fmt.Sprintf("%s'investigation_status': '%v'\n", ws, e.InvestigationStatus))
+	str.WriteString(fmt.Sprintf("%s'blocked': '%v'\n", ws, e.Blocked))
+	str.WriteString(fmt.Sprintf("%s'malicious': '%v'\n", ws, e.Malicious))
+	str.WriteString(fmt.Sprintf("%s'criticality': '%d'\n", ws, e.Criticality))
+	str.WriteString(fmt.Sprintf("%s'recoverability': '%v'\n", ws, e.Recoverability))
+	str.WriteString(fmt.Sprintf("%s'detection_methods': '%v'\n", ws, e.DetectionMethods))
+	str.WriteString(fmt.Sprintf("%s'incident_types': '%v'\n", ws, e.IncidentTypes))
+	str.WriteString(fmt.Sprintf("%s'scores': \n", ws))
+	for k, v := range e.Scores {
+		str.WriteString(fmt.Sprintf("%s\t'score '%d'': 'name': '%s' 'value': '%v' 'description': '%s'\n", ws, k, v.Name, v.Value, v.Description))
+	}
+	str.WriteString(listToString("impact_refs", e.ImpactRefs))
+	str.WriteString(listToString("event_refs", e.EventRefs))
+	str.WriteString(listToString("task_refs", e.TaskRefs))
+	str.WriteString(listToString("sighting_refs", e.SightingRefs))
+	str.WriteString(listToString("other_observed_data_refs", e.OtherObservedDataRefs))
+
+	return str.String()
+}
+
+// isNewSDO проверяет содержат ли расширения объекта признак объекта определяемого расширением типа "new-sdo"
+// с идентификатором id
+func isNewSDO(extensions stixhelpers.ExtensionsTypeSTIX, id string) bool {
+	v, ok, err := extensions.GetExtension(id)
+	if !ok || err != nil {
+		return false
+	}
+
+	ext, ok := v.(NewSDOExtensionSTIX)
+
+	return ok && ext.ExtensionType == stixhelpers.ExtensionTypeNewSDO
+}
+
+// isValidTimeInterval проверяет время начала и окончания, а также их точность
+func isValidTimeInterval(startTime string, startFidelity stixhelpers.EnumTypeSTIX, endTime string, endFidelity stixhelpers.EnumTypeSTIX) bool {
+	for _, v := range []string{startTime, endTime} {
+		if v == "" {
+			continue
+		}
+
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			return false
+		}
+	}
+
+	for _, v := range []stixhelpers.EnumTypeSTIX{startFidelity, endFidelity} {
+		if v != "" && !isEnumValue(v, timestampFidelityEnum...) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidRefs проверяет идентификаторы списка list, идентификаторы должны ссылаться на объекты одного из типов types
+func isValidRefs(list []stixhelpers.IdentifierTypeSTIX, types ...string) bool {
+	for _, v := range list {
+		if v == "" || !v.CheckIdentifierTypeSTIX() {
+			return false
+		}
+
+		if len(types) == 0 {
+			continue
+		}
+
+		var isType bool
+		for _, t := range types {
+			if strings.HasPrefix(string(v), t+"--") {
+				isType = true
+
+				break
+			}
+		}
+
+		if !isType {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidChangedObjects проверяет список изменений состояния объектов
+func isValidChangedObjects(list []StateChangeTypeSTIX) bool {
+	for _, v := range list {
+		if v.InitialRef == "" && v.ResultRef == "" {
+			return false
+		}
+
+		if !v.InitialRef.CheckIdentifierTypeSTIX() || !v.ResultRef.CheckIdentifierTypeSTIX() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidEntityCounts проверяет количество затронутых сущностей
+func isValidEntityCounts(counts map[string]int) bool {
+	for k, v := range counts {
+		if k == "" || v < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sanitizeOpenVocabList выполняет замену некоторых специальных символов на их HTML код в значениях списка
+func sanitizeOpenVocabList(l []stixhelpers.OpenVocabTypeSTIX) []stixhelpers.OpenVocabTypeSTIX {
+	if len(l) == 0 {
+		return l
+	}
+
+	nl := make([]stixhelpers.OpenVocabTypeSTIX, 0, len(l))
+	for _, v := range l {
+		nl = append(nl, v.SanitizeStructOpenVocabTypeSTIX())
+	}
+
+	return nl
+}
+
+// changedObjectsToString выполняет красивое представление списка изменений состояния объектов
+func changedObjectsToString(l []StateChangeTypeSTIX, num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	for k, v := range l {
+		str.WriteString(fmt.Sprintf("%s'changed_object '%d'': 'initial_ref': '%v' 'result_ref': '%v'\n", ws, k, v.InitialRef, v.ResultRef))
+	}
+
+	return str.String()
+}
+
+func isEnumValue(v stixhelpers.EnumTypeSTIX, list ...stixhelpers.EnumTypeSTIX) bool {
+	for _, item := range list {
+		if v == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -295,3 +295,35 @@ func (e IndicatorDomainObjectsSTIX) ToStringBeautiful(num int) string {
 
 	return str.String()
 }
+
+// GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
+func (e IndicatorDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
+	dataForIndex := map[string]string{
+		"id":   e.ID,
+		"type": e.Type,
+	}
+
+	if e.Name != "" {
+		dataForIndex["name"] = e.Name
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	if e.Pattern != "" {
+		dataForIndex["pattern"] = e.Pattern
+	}
+
+	if e.PatternType != "" {
+		dataForIndex["pattern_type"] = string(e.PatternType)
+	}
+
+	if v := stixhelpers.JoinForIndexing(e.IndicatorTypes); v != "" {
+		dataForIndex["indicator_types"] = v
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
+}
@@ -304,5 +304,11 @@ func (istix InfrastructureDomainObjectsSTIX) GeneratingDataForIndexing() map[str
 		dataForIndex["aliases"] = strTmp
 	}
 
+	if v := stixhelpers.JoinForIndexing(istix.InfrastructureTypes); v != "" {
+		dataForIndex["infrastructure_types"] = v
+	}
+
+	istix.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
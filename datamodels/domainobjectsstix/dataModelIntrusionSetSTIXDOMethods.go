@@ -332,5 +332,11 @@ func (e IntrusionSetDomainObjectsSTIX) GeneratingDataForIndexing() map[string]st
 		dataForIndex["aliases"] = strTmp
 	}
 
+	if v := stixhelpers.JoinForIndexing(e.Goals); v != "" {
+		dataForIndex["goals"] = v
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
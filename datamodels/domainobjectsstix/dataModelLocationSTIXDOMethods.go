@@ -270,5 +270,23 @@ func (e LocationDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string
 		dataForIndex["street_address"] = e.StreetAddress
 	}
 
+	if e.Country != "" {
+		dataForIndex["country"] = e.Country
+	}
+
+	if e.AdministrativeArea != "" {
+		dataForIndex["administrative_area"] = e.AdministrativeArea
+	}
+
+	if e.City != "" {
+		dataForIndex["city"] = e.City
+	}
+
+	if e.Region != "" {
+		dataForIndex["region"] = string(e.Region)
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
@@ -452,9 +452,23 @@ func (e MalwareAnalysisDomainObjectsSTIX) GeneratingDataForIndexing() map[string
 		"type": e.Type,
 	}
 
-	if e.Result != "" {
+	if e.ResultName != "" {
 		dataForIndex["result_name"] = e.ResultName
 	}
 
+	if e.Product != "" {
+		dataForIndex["product"] = e.Product
+	}
+
+	if e.Result != "" {
+		dataForIndex["result"] = string(e.Result)
+	}
+
+	if e.AvResult != "" {
+		dataForIndex["av_result"] = string(e.AvResult)
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
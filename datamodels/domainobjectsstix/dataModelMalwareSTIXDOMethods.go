@@ -471,5 +471,15 @@ func (e MalwareDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string
 		dataForIndex["aliases"] = strTmp
 	}
 
+	if v := stixhelpers.JoinForIndexing(e.MalwareTypes); v != "" {
+		dataForIndex["malware_types"] = v
+	}
+
+	if v := stixhelpers.JoinForIndexing(e.Capabilities); v != "" {
+		dataForIndex["capabilities"] = v
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
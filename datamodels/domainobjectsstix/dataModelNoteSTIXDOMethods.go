@@ -206,8 +206,10 @@ func (e NoteDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
 			strTmp += fmt.Sprintf(" %s", v)
 		}
 
-		dataForIndex["aliases"] = strTmp
+		dataForIndex["authors"] = strTmp
 	}
 
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
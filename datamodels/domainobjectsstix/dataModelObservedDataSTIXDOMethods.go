@@ -207,8 +207,12 @@ func (e ObservedDataDomainObjectsSTIX) ToStringBeautiful(num int) string {
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e ObservedDataDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
 }
@@ -205,8 +205,18 @@ func (e OpinionDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string
 			strTmp += fmt.Sprintf(" %s", v)
 		}
 
-		dataForIndex["aliases"] = strTmp
+		dataForIndex["authors"] = strTmp
 	}
 
+	if e.Explanation != "" {
+		dataForIndex["explanation"] = e.Explanation
+	}
+
+	if e.Opinion != "" {
+		dataForIndex["opinion"] = string(e.Opinion)
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
@@ -234,5 +234,11 @@ func (e ReportDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
 		dataForIndex["description"] = e.Description
 	}
 
+	if v := stixhelpers.JoinForIndexing(e.ReportTypes); v != "" {
+		dataForIndex["report_types"] = v
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
@@ -35,8 +35,8 @@ type CampaignDomainObjectsSTIX struct {
 	Name        string   `json:"name" bson:"name" required:"true"`
 	Objective   string   `json:"objective" bson:"objective"`
 	Description string   `json:"description" bson:"description"`
-	FirstSeen   string   `json:"first_seen" bson:"first_seen"`
-	LastSeen    string   `json:"last_seen" bson:"last_seen"`
+	FirstSeen   string   `json:"first_seen" bson:"first_seen" timestamp:"true"`
+	LastSeen    string   `json:"last_seen" bson:"last_seen" timestamp:"true"`
 	Aliases     []string `json:"aliases" bson:"aliases"`
 }
 
@@ -86,6 +86,247 @@ type IdentityDomainObjectsSTIX struct {
 	Sectors            []stixhelpers.OpenVocabTypeSTIX `json:"sectors" bson:"sectors"`
 }
 
+// IncidentDomainObjectsSTIX объект "Incident", по терминалогии STIX, содержит сведения об инциденте информационной безопасности.
+// Подробная информация об инциденте передается в расширении "incident-core" (IncidentCoreExtensionSTIX)
+// Name - имя используемое для идентификации "Incident" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Description - более подробное описание
+// KillChainPhases - список цепочки фактов, к которым относится инцидент
+type IncidentDomainObjectsSTIX struct {
+	commonproperties.CommonPropertiesObjectSTIX
+	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
+	Name            string                                       `json:"name" bson:"name" required:"true"`
+	Description     string                                       `json:"description" bson:"description"`
+	KillChainPhases []stixhelpers.KillChainPhasesTypeElementSTIX `json:"kill_chain_phases" bson:"kill_chain_phases"`
+}
+
+// IncidentCoreExtensionSTIX расширение "incident-core" объекта "Incident", определяемое объектом
+// "extension-definition" с идентификатором IncidentCoreExtensionDefinitionID
+// ExtensionType - тип расширения, всегда "property-extension" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Determination - результат расследования инцидента, одно из значений "blocked", "failed-attempt", "false-positive",
+// "low-value", "successful-attempt", "suspected", "tbd" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// InvestigationStatus - состояние расследования, одно из значений "closed", "new", "open" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Blocked - была ли вредоносная активность заблокирована
+// Malicious - является ли инцидент результатом вредоносной активности
+// Criticality - критичность инцидента, значение от 0 до 100
+// Recoverability - возможность восстановления, одно из значений "extended", "not-applicable", "not-recoverable",
+// "regular", "supplemented"
+// DetectionMethods - способы обнаружения инцидента
+// IncidentTypes - заранее определенный (предложенный) перечень типов инцидентов
+// Scores - список оценок инцидента
+// ImpactRefs - список ссылок на объекты "impact", описывающие последствия инцидента
+// EventRefs - список ссылок на объекты "event", описывающие события инцидента
+// TaskRefs - список ссылок на объекты "task", описывающие задачи по реагированию на инцидент
+// SightingRefs - список ссылок на объекты "sighting", связанные с инцидентом
+// OtherObservedDataRefs - список ссылок на объекты "observed-data", связанные с инцидентом
+type IncidentCoreExtensionSTIX struct {
+	ExtensionType         stixhelpers.EnumTypeSTIX         `json:"extension_type" bson:"extension_type" required:"true"`
+	Determination         stixhelpers.EnumTypeSTIX         `json:"determination" bson:"determination" required:"true"`
+	InvestigationStatus   stixhelpers.EnumTypeSTIX         `json:"investigation_status" bson:"investigation_status" required:"true"`
+	Blocked               bool                             `json:"blocked" bson:"blocked"`
+	Malicious             bool                             `json:"malicious" bson:"malicious"`
+	Criticality           int                              `json:"criticality" bson:"criticality"`
+	Recoverability        stixhelpers.EnumTypeSTIX         `json:"recoverability" bson:"recoverability"`
+	DetectionMethods      []stixhelpers.OpenVocabTypeSTIX  `json:"detection_methods" bson:"detection_methods"`
+	IncidentTypes         []stixhelpers.OpenVocabTypeSTIX  `json:"incident_types" bson:"incident_types"`
+	Scores                []IncidentScoreTypeSTIX          `json:"scores" bson:"scores"`
+	ImpactRefs            []stixhelpers.IdentifierTypeSTIX `json:"impact_refs" bson:"impact_refs"`
+	EventRefs             []stixhelpers.IdentifierTypeSTIX `json:"event_refs" bson:"event_refs"`
+	TaskRefs              []stixhelpers.IdentifierTypeSTIX `json:"task_refs" bson:"task_refs"`
+	SightingRefs          []stixhelpers.IdentifierTypeSTIX `json:"sighting_refs" bson:"sighting_refs"`
+	OtherObservedDataRefs []stixhelpers.IdentifierTypeSTIX `json:"other_observed_data_refs" bson:"other_observed_data_refs"`
+}
+
+// IncidentScoreTypeSTIX оценка инцидента
+// Name - наименование оценки (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Value - значение оценки (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Description - описание оценки
+type IncidentScoreTypeSTIX struct {
+	Name        string  `json:"name" bson:"name" required:"true"`
+	Value       float64 `json:"value" bson:"value" required:"true"`
+	Description string  `json:"description" bson:"description"`
+}
+
+// NewSDOExtensionSTIX признак объекта определяемого расширением типа "new-sdo", содержится в свойстве extensions
+// объектов "event", "impact" и "task" с ключами EventExtensionDefinitionID, ImpactExtensionDefinitionID и
+// TaskExtensionDefinitionID соответственно
+// ExtensionType - тип расширения, всегда "new-sdo" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+type NewSDOExtensionSTIX struct {
+	ExtensionType stixhelpers.EnumTypeSTIX `json:"extension_type" bson:"extension_type" required:"true"`
+}
+
+// AvailabilityImpactExtensionSTIX расширение "availability-ext" объекта "Impact" категории "availability"
+// AvailabilityImpact - процент потери доступности, значение от 0 до 100 (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+type AvailabilityImpactExtensionSTIX struct {
+	AvailabilityImpact int `json:"availability_impact" bson:"availability_impact" required:"true"`
+}
+
+// ConfidentialityImpactExtensionSTIX расширение "confidentiality-ext" объекта "Impact" категории "confidentiality"
+// InformationType - тип информации, конфиденциальность которой нарушена
+// LossType - тип нарушения конфиденциальности, одно из значений "confirmed-loss", "contained", "exploited-loss",
+// "none", "suspected-loss" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// RecordCount - количество затронутых записей
+// RecordSize - размер затронутых записей в байтах
+type ConfidentialityImpactExtensionSTIX struct {
+	InformationType stixhelpers.OpenVocabTypeSTIX `json:"information_type" bson:"information_type"`
+	LossType        stixhelpers.EnumTypeSTIX      `json:"loss_type" bson:"loss_type" required:"true"`
+	RecordCount     int                           `json:"record_count" bson:"record_count"`
+	RecordSize      int                           `json:"record_size" bson:"record_size"`
+}
+
+// ExternalImpactExtensionSTIX расширение "external-ext" объекта "Impact" категории "external"
+// ImpactType - тип воздействия на внешние по отношению к организации объекты (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+type ExternalImpactExtensionSTIX struct {
+	ImpactType stixhelpers.OpenVocabTypeSTIX `json:"impact_type" bson:"impact_type" required:"true"`
+}
+
+// IntegrityImpactExtensionSTIX расширение "integrity-ext" объекта "Impact" категории "integrity"
+// Alteration - характер изменения данных, одно из значений "full-replacement", "partial-replacement",
+// "potential-replacement" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// InformationType - тип информации, целостность которой нарушена
+// RecordCount - количество затронутых записей
+// RecordSize - размер затронутых записей в байтах
+type IntegrityImpactExtensionSTIX struct {
+	Alteration      stixhelpers.EnumTypeSTIX      `json:"alteration" bson:"alteration" required:"true"`
+	InformationType stixhelpers.OpenVocabTypeSTIX `json:"information_type" bson:"information_type"`
+	RecordCount     int                           `json:"record_count" bson:"record_count"`
+	RecordSize      int                           `json:"record_size" bson:"record_size"`
+}
+
+// MonetaryImpactExtensionSTIX расширение "monetary-ext" объекта "Impact" категории "monetary"
+// Variety - вид финансовых потерь (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// ConversionRate - курс пересчета из валюты CurrencyActual в валюту Currency
+// ConversionTime - время, в формате "2016-05-12T08:17:27.000Z", на которое определен курс пересчета
+// Currency - код валюты по ISO 4217
+// CurrencyActual - код валюты по ISO 4217 в которой были понесены потери
+// MaxAmount - максимальная оценка потерь
+// MinAmount - минимальная оценка потерь
+type MonetaryImpactExtensionSTIX struct {
+	Variety        stixhelpers.OpenVocabTypeSTIX `json:"variety" bson:"variety" required:"true"`
+	ConversionRate float64                       `json:"conversion_rate" bson:"conversion_rate"`
+	ConversionTime string                        `json:"conversion_time" bson:"conversion_time" timestamp:"true"`
+	Currency       string                        `json:"currency" bson:"currency"`
+	CurrencyActual string                        `json:"currency_actual" bson:"currency_actual"`
+	MaxAmount      float64                       `json:"max_amount" bson:"max_amount"`
+	MinAmount      float64                       `json:"min_amount" bson:"min_amount"`
+}
+
+// PhysicalImpactExtensionSTIX расширение "physical-ext" объекта "Impact" категории "physical"
+// AssetType - тип физического актива
+// ImpactType - характер воздействия, одно из значений "damaged-functional", "damaged-nonfunctional", "destruction",
+// "none", "unknown" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+type PhysicalImpactExtensionSTIX struct {
+	AssetType  stixhelpers.OpenVocabTypeSTIX `json:"asset_type" bson:"asset_type"`
+	ImpactType stixhelpers.EnumTypeSTIX      `json:"impact_type" bson:"impact_type" required:"true"`
+}
+
+// TraceabilityImpactExtensionSTIX расширение "traceability-ext" объекта "Impact" категории "traceability"
+// TraceabilityImpact - возможность отслеживания действий, одно из значений "accountability-lost",
+// "partial-accountability", "provable-accountability" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+type TraceabilityImpactExtensionSTIX struct {
+	TraceabilityImpact stixhelpers.EnumTypeSTIX `json:"traceability_impact" bson:"traceability_impact" required:"true"`
+}
+
+// EventDomainObjectsSTIX объект "Event", по терминалогии STIX, определяемый расширением "incident-core", описывает событие
+// произошедшее в рамках инцидента
+// Name - имя используемое для идентификации "Event"
+// Description - более подробное описание
+// Status - состояние события, одно из значений "not-occurred", "occurred", "ongoing", "pending", "undetected" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Goal - цель, которую преследовал нарушитель
+// EventTypes - заранее определенный (предложенный) перечень типов события
+// StartTime - время, в формате "2016-05-12T08:17:27.000Z", начала события
+// StartTimeFidelity - точность времени начала события, одно из значений "day", "hour", "minute", "month", "second", "year"
+// EndTime - время, в формате "2016-05-12T08:17:27.000Z", окончания события
+// EndTimeFidelity - точность времени окончания события
+// SightingRefs - список ссылок на объекты "sighting", связанные с событием
+// ChangedObjects - список изменений состояния объектов вызванных событием
+type EventDomainObjectsSTIX struct {
+	commonproperties.CommonPropertiesObjectSTIX
+	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
+	Name              string                           `json:"name" bson:"name"`
+	Description       string                           `json:"description" bson:"description"`
+	Status            stixhelpers.EnumTypeSTIX         `json:"status" bson:"status" required:"true"`
+	Goal              string                           `json:"goal" bson:"goal"`
+	EventTypes        []stixhelpers.OpenVocabTypeSTIX  `json:"event_types" bson:"event_types"`
+	StartTime         string                           `json:"start_time" bson:"start_time" timestamp:"true"`
+	StartTimeFidelity stixhelpers.EnumTypeSTIX         `json:"start_time_fidelity" bson:"start_time_fidelity"`
+	EndTime           string                           `json:"end_time" bson:"end_time" timestamp:"true"`
+	EndTimeFidelity   stixhelpers.EnumTypeSTIX         `json:"end_time_fidelity" bson:"end_time_fidelity"`
+	SightingRefs      []stixhelpers.IdentifierTypeSTIX `json:"sighting_refs" bson:"sighting_refs"`
+	ChangedObjects    []StateChangeTypeSTIX            `json:"changed_objects" bson:"changed_objects"`
+}
+
+// ImpactDomainObjectsSTIX объект "Impact", по терминалогии STIX, определяемый расширением "incident-core", описывает последствия
+// инцидента
+// ImpactCategory - категория последствий, одно из значений "availability", "confidentiality", "external", "integrity",
+// "monetary", "physical", "traceability" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Description - более подробное описание
+// Criticality - критичность последствий, значение от 0 до 100
+// Recoverability - возможность восстановления, одно из значений "extended", "not-applicable", "not-recoverable",
+// "regular", "supplemented"
+// ImpactedEntityCounts - количество затронутых сущностей по их типам
+// ImpactedRefs - список ссылок на затронутые объекты
+// StartTime - время, в формате "2016-05-12T08:17:27.000Z", начала воздействия
+// StartTimeFidelity - точность времени начала воздействия, одно из значений "day", "hour", "minute", "month", "second", "year"
+// EndTime - время, в формате "2016-05-12T08:17:27.000Z", окончания воздействия
+// EndTimeFidelity - точность времени окончания воздействия
+// SupersededByRef - ссылка на объект "impact", заменяющий данный объект
+type ImpactDomainObjectsSTIX struct {
+	commonproperties.CommonPropertiesObjectSTIX
+	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
+	ImpactCategory       stixhelpers.EnumTypeSTIX         `json:"impact_category" bson:"impact_category" required:"true"`
+	Description          string                           `json:"description" bson:"description"`
+	Criticality          int                              `json:"criticality" bson:"criticality"`
+	Recoverability       stixhelpers.EnumTypeSTIX         `json:"recoverability" bson:"recoverability"`
+	ImpactedEntityCounts map[string]int                   `json:"impacted_entity_counts" bson:"impacted_entity_counts"`
+	ImpactedRefs         []stixhelpers.IdentifierTypeSTIX `json:"impacted_refs" bson:"impacted_refs"`
+	StartTime            string                           `json:"start_time" bson:"start_time" timestamp:"true"`
+	StartTimeFidelity    stixhelpers.EnumTypeSTIX         `json:"start_time_fidelity" bson:"start_time_fidelity"`
+	EndTime              string                           `json:"end_time" bson:"end_time" timestamp:"true"`
+	EndTimeFidelity      stixhelpers.EnumTypeSTIX         `json:"end_time_fidelity" bson:"end_time_fidelity"`
+	SupersededByRef      stixhelpers.IdentifierTypeSTIX   `json:"superseded_by_ref" bson:"superseded_by_ref"`
+}
+
+// TaskDomainObjectsSTIX объект "Task", по терминалогии STIX, определяемый расширением "incident-core", описывает задачу
+// по реагированию на инцидент
+// Name - имя используемое для идентификации "Task" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Description - более подробное описание
+// TaskTypes - заранее определенный (предложенный) перечень типов задачи
+// Outcome - результат выполнения задачи, одно из значений "cancelled", "failed", "ongoing", "pending", "successful", "unknown"
+// Priority - приоритет задачи, значение от 0 до 100
+// Owner - ссылка на объект "identity", ответственный за выполнение задачи
+// Error - описание ошибки возникшей при выполнении задачи
+// ImpactedEntityCounts - количество затронутых сущностей по их типам
+// StartTime - время, в формате "2016-05-12T08:17:27.000Z", начала выполнения задачи
+// StartTimeFidelity - точность времени начала выполнения задачи, одно из значений "day", "hour", "minute", "month", "second", "year"
+// EndTime - время, в формате "2016-05-12T08:17:27.000Z", окончания выполнения задачи
+// EndTimeFidelity - точность времени окончания выполнения задачи
+// ChangedObjects - список изменений состояния объектов в результате выполнения задачи
+type TaskDomainObjectsSTIX struct {
+	commonproperties.CommonPropertiesObjectSTIX
+	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
+	Name                 string                          `json:"name" bson:"name" required:"true"`
+	Description          string                          `json:"description" bson:"description"`
+	TaskTypes            []stixhelpers.OpenVocabTypeSTIX `json:"task_types" bson:"task_types"`
+	Outcome              stixhelpers.EnumTypeSTIX        `json:"outcome" bson:"outcome"`
+	Priority             int                             `json:"priority" bson:"priority"`
+	Owner                stixhelpers.IdentifierTypeSTIX  `json:"owner" bson:"owner"`
+	Error                string                          `json:"error" bson:"error"`
+	ImpactedEntityCounts map[string]int                  `json:"impacted_entity_counts" bson:"impacted_entity_counts"`
+	StartTime            string                          `json:"start_time" bson:"start_time" timestamp:"true"`
+	StartTimeFidelity    stixhelpers.EnumTypeSTIX        `json:"start_time_fidelity" bson:"start_time_fidelity"`
+	EndTime              string                          `json:"end_time" bson:"end_time" timestamp:"true"`
+	EndTimeFidelity      stixhelpers.EnumTypeSTIX        `json:"end_time_fidelity" bson:"end_time_fidelity"`
+	ChangedObjects       []StateChangeTypeSTIX           `json:"changed_objects" bson:"changed_objects"`
+}
+
+// StateChangeTypeSTIX изменение состояния объекта
+// InitialRef - ссылка на объект в исходном состоянии
+// ResultRef - ссылка на объект в результирующем состоянии
+type StateChangeTypeSTIX struct {
+	InitialRef stixhelpers.IdentifierTypeSTIX `json:"initial_ref" bson:"initial_ref"`
+	ResultRef  stixhelpers.IdentifierTypeSTIX `json:"result_ref" bson:"result_ref"`
+}
+
 // IndicatorDomainObjectsSTIX объект "Indicator", по терминалогии STIX, содержит шаблон который может быть использован для обнаружения
 // подозрительной или вредоносной киберактивности
 // Name - имя используемое для идентификации "Indicator" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
@@ -104,8 +345,8 @@ type IndicatorDomainObjectsSTIX struct {
 	Pattern         string                                       `json:"pattern" bson:"pattern" required:"true"`
 	PatternVersion  string                                       `json:"pattern_version" bson:"pattern_version"`
 	Description     string                                       `json:"description" bson:"description"`
-	ValidFrom       string                                       `json:"valid_from" bson:"valid_from" required:"true"`
-	ValidUntil      string                                       `json:"valid_until" bson:"valid_until"`
+	ValidFrom       string                                       `json:"valid_from" bson:"valid_from" required:"true" timestamp:"true"`
+	ValidUntil      string                                       `json:"valid_until" bson:"valid_until" timestamp:"true"`
 	PatternType     stixhelpers.OpenVocabTypeSTIX                `json:"pattern_type" bson:"pattern_type" required:"true"`
 	KillChainPhases []stixhelpers.KillChainPhasesTypeElementSTIX `json:"kill_chain_phases" bson:"kill_chain_phases"`
 	IndicatorTypes  []stixhelpers.OpenVocabTypeSTIX              `json:"indicator_types" bson:"indicator_types"`
@@ -125,8 +366,8 @@ type InfrastructureDomainObjectsSTIX struct {
 	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
 	Name                string                                       `json:"name" bson:"name" required:"true"`
 	Description         string                                       `json:"description" bson:"description"`
-	FirstSeen           string                                       `json:"first_seen" bson:"first_seen"`
-	LastSeen            string                                       `json:"last_seen" bson:"last_seen"`
+	FirstSeen           string                                       `json:"first_seen" bson:"first_seen" timestamp:"true"`
+	LastSeen            string                                       `json:"last_seen" bson:"last_seen" timestamp:"true"`
 	Aliases             []string                                     `json:"aliases" bson:"aliases"`
 	KillChainPhases     []stixhelpers.KillChainPhasesTypeElementSTIX `json:"kill_chain_phases" bson:"kill_chain_phases"`
 	InfrastructureTypes []stixhelpers.OpenVocabTypeSTIX              `json:"infrastructure_types" bson:"infrastructure_types"`
@@ -149,8 +390,8 @@ type IntrusionSetDomainObjectsSTIX struct {
 	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
 	Name                 string                          `json:"name" bson:"name" required:"true"`
 	Description          string                          `json:"description" bson:"description"`
-	FirstSeen            string                          `json:"first_seen" bson:"first_seen"`
-	LastSeen             string                          `json:"last_seen" bson:"last_seen"`
+	FirstSeen            string                          `json:"first_seen" bson:"first_seen" timestamp:"true"`
+	LastSeen             string                          `json:"last_seen" bson:"last_seen" timestamp:"true"`
 	Aliases              []string                        `json:"aliases" bson:"aliases"`
 	Goals                []string                        `json:"goals" bson:"goals"`
 	ResourceLevel        stixhelpers.OpenVocabTypeSTIX   `json:"resource_level" bson:"resource_level"`
@@ -209,8 +450,8 @@ type MalwareDomainObjectsSTIX struct {
 	IsFamily                  bool                                         `json:"is_family" bson:"is_family" required:"true"`
 	Name                      string                                       `json:"name" bson:"name"`
 	Description               string                                       `json:"description" bson:"description"`
-	FirstSeen                 string                                       `json:"first_seen" bson:"first_seen"`
-	LastSeen                  string                                       `json:"last_seen" bson:"last_seen"`
+	FirstSeen                 string                                       `json:"first_seen" bson:"first_seen" timestamp:"true"`
+	LastSeen                  string                                       `json:"last_seen" bson:"last_seen" timestamp:"true"`
 	Aliases                   []string                                     `json:"aliases" bson:"aliases"`
 	KillChainPhases           []stixhelpers.KillChainPhasesTypeElementSTIX `json:"kill_chain_phases" bson:"kill_chain_phases"`
 	MalwareTypes              []stixhelpers.OpenVocabTypeSTIX              `json:"malware_types" bson:"malware_types"`
@@ -251,9 +492,9 @@ type MalwareAnalysisDomainObjectsSTIX struct {
 	ConfigurationVersion      string                           `json:"configuration_version" bson:"configuration_version"`
 	AnalysisEngineVersion     string                           `json:"analysis_engine_version" bson:"analysis_engine_version"`
 	AnalysisDefinitionVersion string                           `json:"analysis_definition_version" bson:"analysis_definition_version"`
-	Submitted                 string                           `json:"submitted" bson:"submitted"`
-	AnalysisStarted           string                           `json:"analysis_started" bson:"analysis_started"`
-	AnalysisEnded             string                           `json:"analysis_ended" bson:"analysis_ended"`
+	Submitted                 string                           `json:"submitted" bson:"submitted" timestamp:"true"`
+	AnalysisStarted           string                           `json:"analysis_started" bson:"analysis_started" timestamp:"true"`
+	AnalysisEnded             string                           `json:"analysis_ended" bson:"analysis_ended" timestamp:"true"`
 	Modules                   []string                         `json:"modules" bson:"modules"`
 	HostVMRef                 stixhelpers.IdentifierTypeSTIX   `json:"host_vm_ref" bson:"host_vm_ref"`
 	OperatingSystemRef        stixhelpers.IdentifierTypeSTIX   `json:"operating_system_ref" bson:"operating_system_ref"`
@@ -290,8 +531,8 @@ type ObservedDataDomainObjectsSTIX struct {
 	commonproperties.CommonPropertiesObjectSTIX
 	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
 	NumberObserved int                              `json:"number_observed" bson:"number_observed" required:"true"`
-	FirstObserved  string                           `json:"first_observed" bson:"first_observed" required:"true"`
-	LastObserved   string                           `json:"last_observed" bson:"last_observed" required:"true"`
+	FirstObserved  string                           `json:"first_observed" bson:"first_observed" required:"true" timestamp:"true"`
+	LastObserved   string                           `json:"last_observed" bson:"last_observed" required:"true" timestamp:"true"`
 	ObjectRefs     []stixhelpers.IdentifierTypeSTIX `json:"object_refs" bson:"object_refs"`
 }
 
@@ -324,7 +565,7 @@ type ReportDomainObjectsSTIX struct {
 	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
 	Name        string                           `json:"name" bson:"name" required:"true"`
 	Description string                           `json:"description" bson:"description"`
-	Published   string                           `json:"published" bson:"published" required:"true"`
+	Published   string                           `json:"published" bson:"published" required:"true" timestamp:"true"`
 	ReportTypes []stixhelpers.OpenVocabTypeSTIX  `json:"report_types" bson:"report_types"`
 	ObjectRefs  []stixhelpers.IdentifierTypeSTIX `json:"object_refs" bson:"object_refs" required:"true"`
 }
@@ -351,8 +592,8 @@ type ThreatActorDomainObjectsSTIX struct {
 	commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX
 	Name                 string                          `json:"name" bson:"name" required:"true"`
 	Description          string                          `json:"description" bson:"description"`
-	FirstSeen            string                          `json:"first_seen" bson:"first_seen"`
-	LastSeen             string                          `json:"last_seen" bson:"last_seen"`
+	FirstSeen            string                          `json:"first_seen" bson:"first_seen" timestamp:"true"`
+	LastSeen             string                          `json:"last_seen" bson:"last_seen" timestamp:"true"`
 	Aliases              []string                        `json:"aliases" bson:"aliases"`
 	Goals                []string                        `json:"goals" bson:"goals"`
 	Sophistication       stixhelpers.OpenVocabTypeSTIX   `json:"sophistication" bson:"sophistication"`
@@ -0,0 +1,351 @@
+package domainobjectsstix
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+/* --- TaskDomainObjectsSTIX --- */
+
+// DecoderJSON выполняет декодирование JSON объекта
+func (e TaskDomainObjectsSTIX) DecodeJSON(raw *json.RawMessage) (interface{}, error) {
+	if err := json.Unmarshal(*raw, &e); err != nil {
+		return e, err
+	}
+
+	return e, nil
+}
+
+// EncoderJSON выполняет кодирование в JSON объект
+func (e TaskDomainObjectsSTIX) EncodeJSON(interface{}) (*[]byte, error) {
+	result, err := json.Marshal(e)
+
+	return &result, err
+}
+
+// Get возвращает объект "Task", по терминалогии STIX, описывающий задачу по реагированию на инцидент
+// Обязательное значение в поле Name
+func (e *TaskDomainObjectsSTIX) Get() (*TaskDomainObjectsSTIX, error) {
+	if e.GetName() == "" {
+		err := fmt.Errorf("the required value 'Name' must not be empty")
+
+		return &TaskDomainObjectsSTIX{}, err
+	}
+
+	return e, nil
+}
+
+// -------- Name property ---------
+func (e *TaskDomainObjectsSTIX) GetName() string {
+	return e.Name
+}
+
+// SetValueName устанавливает значение для поля Name
+func (e *TaskDomainObjectsSTIX) SetValueName(v string) {
+	e.Name = v
+}
+
+// SetAnyName устанавливает ЛЮБОЕ значение для поля Name
+func (e *TaskDomainObjectsSTIX) SetAnyName(i interface{}) {
+	e.Name = fmt.Sprint(i)
+}
+
+// -------- Description property ---------
+func (e *TaskDomainObjectsSTIX) GetDescription() string {
+	return e.Description
+}
+
+// SetValueDescription устанавливает значение для поля Description
+func (e *TaskDomainObjectsSTIX) SetValueDescription(v string) {
+	e.Description = v
+}
+
+// SetAnyDescription устанавливает ЛЮБОЕ значение для поля Description
+func (e *TaskDomainObjectsSTIX) SetAnyDescription(i interface{}) {
+	e.Description = fmt.Sprint(i)
+}
+
+// -------- TaskTypes property ---------
+func (e *TaskDomainObjectsSTIX) GetTaskTypes() []stixhelpers.OpenVocabTypeSTIX {
+	return e.TaskTypes
+}
+
+// SetValueTaskTypes устанавливает значение для поля TaskTypes
+func (e *TaskDomainObjectsSTIX) SetValueTaskTypes(v stixhelpers.OpenVocabTypeSTIX) {
+	e.TaskTypes = append(e.TaskTypes, v)
+}
+
+// -------- Outcome property ---------
+func (e *TaskDomainObjectsSTIX) GetOutcome() stixhelpers.EnumTypeSTIX {
+	return e.Outcome
+}
+
+// SetValueOutcome устанавливает значение для поля Outcome
+func (e *TaskDomainObjectsSTIX) SetValueOutcome(v stixhelpers.EnumTypeSTIX) {
+	e.Outcome = v
+}
+
+// -------- Priority property ---------
+func (e *TaskDomainObjectsSTIX) GetPriority() int {
+	return e.Priority
+}
+
+// SetValuePriority устанавливает значение для поля Priority
+func (e *TaskDomainObjectsSTIX) SetValuePriority(v int) {
+	e.Priority = v
+}
+
+// SetAnyPriority устанавливает ЛЮБОЕ значение для поля Priority
+func (e *TaskDomainObjectsSTIX) SetAnyPriority(i interface{}) {
+	e.Priority = commonlibs.ConversionAnyToInt(i)
+}
+
+// -------- Owner property ---------
+func (e *TaskDomainObjectsSTIX) GetOwner() stixhelpers.IdentifierTypeSTIX {
+	return e.Owner
+}
+
+// SetValueOwner устанавливает значение для поля Owner
+func (e *TaskDomainObjectsSTIX) SetValueOwner(v stixhelpers.IdentifierTypeSTIX) {
+	e.Owner = v
+}
+
+// -------- Error property ---------
+func (e *TaskDomainObjectsSTIX) GetError() string {
+	return e.Error
+}
+
+// SetValueError устанавливает значение для поля Error
+func (e *TaskDomainObjectsSTIX) SetValueError(v string) {
+	e.Error = v
+}
+
+// SetAnyError устанавливает ЛЮБОЕ значение для поля Error
+func (e *TaskDomainObjectsSTIX) SetAnyError(i interface{}) {
+	e.Error = fmt.Sprint(i)
+}
+
+// -------- ImpactedEntityCounts property ---------
+func (e *TaskDomainObjectsSTIX) GetImpactedEntityCounts() map[string]int {
+	return e.ImpactedEntityCounts
+}
+
+// SetValueImpactedEntityCounts устанавливает значение k для поля ImpactedEntityCounts
+func (e *TaskDomainObjectsSTIX) SetValueImpactedEntityCounts(k string, v int) {
+	if e.ImpactedEntityCounts == nil {
+		e.ImpactedEntityCounts = map[string]int{}
+	}
+
+	e.ImpactedEntityCounts[k] = v
+}
+
+// -------- StartTime property ---------
+func (e *TaskDomainObjectsSTIX) GetStartTime() string {
+	return e.StartTime
+}
+
+// SetValueStartTime устанавливает значение в формате RFC3339 для поля StartTime
+func (e *TaskDomainObjectsSTIX) SetValueStartTime(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.StartTime = v
+
+	return nil
+}
+
+// SetAnyStartTime устанавливает значение для поля StartTime
+// принимает число (timestamp 13 символов) или строку в формате RFC3339
+func (e *TaskDomainObjectsSTIX) SetAnyStartTime(i interface{}) error {
+	if str, ok := i.(string); ok {
+		return e.SetValueStartTime(str)
+	}
+
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.StartTime = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+
+	return nil
+}
+
+// -------- StartTimeFidelity property ---------
+func (e *TaskDomainObjectsSTIX) GetStartTimeFidelity() stixhelpers.EnumTypeSTIX {
+	return e.StartTimeFidelity
+}
+
+// SetValueStartTimeFidelity устанавливает значение для поля StartTimeFidelity
+func (e *TaskDomainObjectsSTIX) SetValueStartTimeFidelity(v stixhelpers.EnumTypeSTIX) {
+	e.StartTimeFidelity = v
+}
+
+// -------- EndTime property ---------
+func (e *TaskDomainObjectsSTIX) GetEndTime() string {
+	return e.EndTime
+}
+
+// SetValueEndTime устанавливает значение в формате RFC3339 для поля EndTime
+func (e *TaskDomainObjectsSTIX) SetValueEndTime(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.EndTime = v
+
+	return nil
+}
+
+// SetAnyEndTime устанавливает значение для поля EndTime
+// принимает число (timestamp 13 символов) или строку в формате RFC3339
+func (e *TaskDomainObjectsSTIX) SetAnyEndTime(i interface{}) error {
+	if str, ok := i.(string); ok {
+		return e.SetValueEndTime(str)
+	}
+
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.EndTime = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+
+	return nil
+}
+
+// -------- EndTimeFidelity property ---------
+func (e *TaskDomainObjectsSTIX) GetEndTimeFidelity() stixhelpers.EnumTypeSTIX {
+	return e.EndTimeFidelity
+}
+
+// SetValueEndTimeFidelity устанавливает значение для поля EndTimeFidelity
+func (e *TaskDomainObjectsSTIX) SetValueEndTimeFidelity(v stixhelpers.EnumTypeSTIX) {
+	e.EndTimeFidelity = v
+}
+
+// -------- ChangedObjects property ---------
+func (e *TaskDomainObjectsSTIX) GetChangedObjects() []StateChangeTypeSTIX {
+	return e.ChangedObjects
+}
+
+// SetValueChangedObjects устанавливает значение для поля ChangedObjects
+func (e *TaskDomainObjectsSTIX) SetValueChangedObjects(v StateChangeTypeSTIX) {
+	e.ChangedObjects = append(e.ChangedObjects, v)
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе TaskDomainObjectsSTIX
+func (e TaskDomainObjectsSTIX) ValidateStruct() bool {
+	if !(regexp.MustCompile(`^(task--)[0-9a-f|-]+$`).MatchString(e.ID)) {
+		return false
+	}
+
+	//обязательное поле
+	if e.Name == "" {
+		return false
+	}
+
+	if !isNewSDO(e.Extensions, TaskExtensionDefinitionID) {
+		return false
+	}
+
+	if e.Outcome != "" && !isEnumValue(e.Outcome, "cancelled", "failed", "ongoing", "pending", "successful", "unknown") {
+		return false
+	}
+
+	if e.Priority < 0 || e.Priority > 100 {
+		return false
+	}
+
+	if e.Owner != "" && !isValidRefs([]stixhelpers.IdentifierTypeSTIX{e.Owner}, "identity") {
+		return false
+	}
+
+	if !isValidEntityCounts(e.ImpactedEntityCounts) {
+		return false
+	}
+
+	if !isValidTimeInterval(e.StartTime, e.StartTimeFidelity, e.EndTime, e.EndTimeFidelity) {
+		return false
+	}
+
+	if !isValidChangedObjects(e.ChangedObjects) {
+		return false
+	}
+
+	return e.ValidateStructCommonFields()
+}
+
+// SanitizeStruct для ряда полей, выполняет замену некоторых специальных символов на их HTML код
+func (e TaskDomainObjectsSTIX) SanitizeStruct() TaskDomainObjectsSTIX {
+	e.CommonPropertiesDomainObjectSTIX = e.CommonPropertiesDomainObjectSTIX.SanitizeStruct()
+
+	e.Name = commonlibs.StringSanitize(e.Name)
+	e.Description = commonlibs.StringSanitize(e.Description)
+	e.Error = commonlibs.StringSanitize(e.Error)
+	e.TaskTypes = sanitizeOpenVocabList(e.TaskTypes)
+
+	if len(e.ImpactedEntityCounts) > 0 {
+		counts := make(map[string]int, len(e.ImpactedEntityCounts))
+		for k, v := range e.ImpactedEntityCounts {
+			counts[commonlibs.StringSanitize(k)] = v
+		}
+
+		e.ImpactedEntityCounts = counts
+	}
+
+	return e
+}
+
+// GetID возвращает ID STIX объекта
+func (e TaskDomainObjectsSTIX) GetID() string {
+	return e.ID
+}
+
+// GetType возвращает Type STIX объекта
+func (e TaskDomainObjectsSTIX) GetType() string {
+	return e.Type
+}
+
+// ToStringBeautiful выполняет красивое представление информации содержащейся в типе
+func (e TaskDomainObjectsSTIX) ToStringBeautiful(num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	str.WriteString(e.CommonPropertiesObjectSTIX.ToStringBeautiful(num))
+	str.WriteString(e.CommonPropertiesDomainObjectSTIX.ToStringBeautiful(num))
+	str.WriteString(fmt.Sprintf("%s'name': '%s'\n", ws, e.Name))
+	str.WriteString(fmt.Sprintf("%s'description': '%s'\n", ws, e.Description))
+	str.WriteString(fmt.Sprintf("%s'task_types': '%v'\n", ws, e.TaskTypes))
+	str.WriteString(fmt.Sprintf("%s'outcome': '%v'\n", ws, e.Outcome))
+	str.WriteString(fmt.Sprintf("%s'priority': '%d'\n", ws, e.Priority))
+	str.WriteString(fmt.Sprintf("%s'owner': '%v'\n", ws, e.Owner))
+	str.WriteString(fmt.Sprintf("%s'error': '%s'\n", ws, e.Error))
+	str.WriteString(fmt.Sprintf("%s'impacted_entity_counts': '%v'\n", ws, e.ImpactedEntityCounts))
+	str.WriteString(fmt.Sprintf("%s'start_time': '%s'\n", ws, e.StartTime))
+	str.WriteString(fmt.Sprintf("%s'start_time_fidelity': '%v'\n", ws, e.StartTimeFidelity))
+	str.WriteString(fmt.Sprintf("%s'end_time': '%s'\n", ws, e.EndTime))
+	str.WriteString(fmt.Sprintf("%s'end_time_fidelity': '%v'\n", ws, e.EndTimeFidelity))
+	str.WriteString(fmt.Sprintf("%s'changed_objects': \n%v", ws, changedObjectsToString(e.ChangedObjects, num+1)))
+
+	return str.String()
+}
+
+// GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
+func (e TaskDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
+	dataForIndex := map[string]string{
+		"id":   e.ID,
+		"type": e.Type,
+	}
+
+	if e.Name != "" {
+		dataForIndex["name"] = e.Name
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
+	return dataForIndex
+}
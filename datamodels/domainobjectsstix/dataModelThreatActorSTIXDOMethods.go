@@ -451,5 +451,11 @@ func (e ThreatActorDomainObjectsSTIX) GeneratingDataForIndexing() map[string]str
 		dataForIndex["aliases"] += " " + strTmp
 	}
 
+	if v := stixhelpers.JoinForIndexing(e.ThreatActorTypes); v != "" {
+		dataForIndex["threat_actor_types"] = v
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
@@ -260,5 +260,15 @@ func (e ToolDomainObjectsSTIX) GeneratingDataForIndexing() map[string]string {
 		dataForIndex["aliases"] = strTmp
 	}
 
+	if v := stixhelpers.JoinForIndexing(e.ToolTypes); v != "" {
+		dataForIndex["tool_types"] = v
+	}
+
+	if e.ToolVersion != "" {
+		dataForIndex["tool_version"] = e.ToolVersion
+	}
+
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
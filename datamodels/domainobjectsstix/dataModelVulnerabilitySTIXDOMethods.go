@@ -136,5 +136,7 @@ func (e VulnerabilityDomainObjectsSTIX) GeneratingDataForIndexing() map[string]s
 		dataForIndex["description"] = e.Description
 	}
 
+	e.CommonPropertiesDomainObjectSTIX.AddDataForIndexing(dataForIndex)
+
 	return dataForIndex
 }
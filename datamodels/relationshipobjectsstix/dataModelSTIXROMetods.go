@@ -90,8 +90,49 @@ func (e *OptionalCommonPropertiesRelationshipObjectSTIX) SetAnyModified(i interf
 	e.Modified = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
 }
 
+// -------- Extensions property ---------
+func (e *OptionalCommonPropertiesRelationshipObjectSTIX) GetExtensions() stixhelpers.ExtensionsTypeSTIX {
+	return e.Extensions
+}
+
+// GetExtension возвращает содержимое расширения, для зарегистрированных расширений в виде
+// значения зарегистрированного типа
+func (e *OptionalCommonPropertiesRelationshipObjectSTIX) GetExtension(k string) (interface{}, bool, error) {
+	return e.Extensions.GetExtension(k)
+}
+
+// SetValueExtensions устанавливает значение расширения k для поля Extensions
+func (e *OptionalCommonPropertiesRelationshipObjectSTIX) SetValueExtensions(k string, v interface{}) {
+	if e.Extensions == nil {
+		e.Extensions = stixhelpers.ExtensionsTypeSTIX{}
+	}
+
+	e.Extensions[k] = v
+}
+
+// SetAnyExtensions устанавливает ЛЮБОЕ значение расширения k для поля Extensions, значение
+// преобразуется в строку
+func (e *OptionalCommonPropertiesRelationshipObjectSTIX) SetAnyExtensions(k string, i interface{}) {
+	e.SetValueExtensions(k, fmt.Sprint(i))
+}
+
+// SetAnyTypedExtensions устанавливает ЛЮБОЕ значение расширения k для поля Extensions без преобразования
+// в строку, если расширение зарегистрировано значение преобразуется к зарегистрированному типу
+func (e *OptionalCommonPropertiesRelationshipObjectSTIX) SetAnyTypedExtensions(k string, i interface{}) {
+	e.SetValueExtensions(k, i)
+
+	if v, ok, err := e.Extensions.GetExtension(k); ok && err == nil {
+		e.Extensions[k] = v
+	}
+}
+
+// ValidateStructCommonFields выполняет проверку полей типа на соответствие корректным значениям
 func (e *OptionalCommonPropertiesRelationshipObjectSTIX) ValidateStructCommonFields() bool {
-	return !(regexp.MustCompile(`^[0-9a-z.]+$`).MatchString(e.SpecVersion))
+	if !e.Extensions.CheckExtensionsTypeSTIX() {
+		return false
+	}
+
+	return (regexp.MustCompile(`^[0-9a-z.]+$`).MatchString(e.SpecVersion))
 }
 
 // ToStringBeautiful выполняет красивое представление информации содержащейся в типе
@@ -102,6 +143,16 @@ func (e *OptionalCommonPropertiesRelationshipObjectSTIX) ToStringBeautiful(num i
 	str.WriteString(fmt.Sprintf("%s'spec_version': '%s'\n", ws, e.SpecVersion))
 	str.WriteString(fmt.Sprintf("%s'created': '%v'\n", ws, e.Created))
 	str.WriteString(fmt.Sprintf("%s'modified': '%v'\n", ws, e.Modified))
+	str.WriteString(fmt.Sprintf("%s'extensions': \n%v", ws, func(l stixhelpers.ExtensionsTypeSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'%s': '%v'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.Extensions, num+1)))
 
 	return str.String()
 }
@@ -305,10 +356,28 @@ func (e RelationshipObjectSTIX) ToStringBeautiful(num int) string {
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e RelationshipObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.RelationshipType != "" {
+		dataForIndex["relationship_type"] = e.RelationshipType
+	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	if e.SourceRef != "" {
+		dataForIndex["source_ref"] = string(e.SourceRef)
+	}
+
+	if e.TargetRef != "" {
+		dataForIndex["target_ref"] = string(e.TargetRef)
+	}
+
+	return dataForIndex
 }
 
 /* --- SightingObjectSTIX --- */
@@ -548,8 +617,18 @@ func (e SightingObjectSTIX) ToStringBeautiful(num int) string {
 
 // GeneratingDataForIndexing выполняет генерацию данных для их последующей индексации
 func (e SightingObjectSTIX) GeneratingDataForIndexing() map[string]string {
-	return map[string]string{
+	dataForIndex := map[string]string{
 		"id":   e.ID,
 		"type": e.Type,
 	}
+
+	if e.Description != "" {
+		dataForIndex["description"] = e.Description
+	}
+
+	if e.SightingOfRef != "" {
+		dataForIndex["sighting_of_ref"] = string(e.SightingOfRef)
+	}
+
+	return dataForIndex
 }
@@ -11,10 +11,12 @@ import (
 // SpecVersion - версия STIX спецификации (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ).
 // Created - время создания объекта, в формате "2016-05-12T08:17:27.000Z" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ).
 // Modified - время создания объекта, в формате "2016-05-12T08:17:27.000Z" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ).
+// Extensions - может содержать расширения объекта (property-extension), где ключ это идентификатор объекта "extension-definition".
 type OptionalCommonPropertiesRelationshipObjectSTIX struct {
-	SpecVersion string `json:"spec_version" bson:"spec_version"`
-	Created     string `json:"created" bson:"created"`
-	Modified    string `json:"modified" bson:"modified"`
+	SpecVersion string                         `json:"spec_version" bson:"spec_version"`
+	Created     string                         `json:"created" bson:"created" timestamp:"true"`
+	Modified    string                         `json:"modified" bson:"modified" timestamp:"true"`
+	Extensions  stixhelpers.ExtensionsTypeSTIX `json:"extensions" bson:"extensions"`
 }
 
 // RelationshipObjectSTIX объект "Relationship", по терминалогии STIX, используется для связывания двух Domain Object STIX (SDO) или
@@ -32,8 +34,8 @@ type RelationshipObjectSTIX struct {
 	OptionalCommonPropertiesRelationshipObjectSTIX
 	RelationshipType string                         `json:"relationship_type" bson:"relationship_type"`
 	Description      string                         `json:"description" bson:"description"`
-	StartTime        string                         `json:"start_time" bson:"start_time"`
-	StopTime         string                         `json:"stop_time" bson:"stop_time"`
+	StartTime        string                         `json:"start_time" bson:"start_time" timestamp:"true"`
+	StopTime         string                         `json:"stop_time" bson:"stop_time" timestamp:"true"`
 	SourceRef        stixhelpers.IdentifierTypeSTIX `json:"source_ref" bson:"source_ref"`
 	TargetRef        stixhelpers.IdentifierTypeSTIX `json:"target_ref" bson:"target_ref"`
 }
@@ -54,8 +56,8 @@ type SightingObjectSTIX struct {
 	Summary          bool                             `json:"summary" bson:"summary"`
 	Count            int                              `json:"count" bson:"count"`
 	Description      string                           `json:"description" bson:"description"`
-	FirstSeen        string                           `json:"first_seen" bson:"first_seen"`
-	LastSeen         string                           `json:"last_seen" bson:"last_seen"`
+	FirstSeen        string                           `json:"first_seen" bson:"first_seen" timestamp:"true"`
+	LastSeen         string                           `json:"last_seen" bson:"last_seen" timestamp:"true"`
 	SightingOfRef    stixhelpers.IdentifierTypeSTIX   `json:"sighting_of_ref" bson:"sighting_of_ref"`
 	ObservedDataRefs []stixhelpers.IdentifierTypeSTIX `json:"observed_data_refs" bson:"observed_data_refs"`
 	WhereSightedRefs []stixhelpers.IdentifierTypeSTIX `json:"where_sighted_refs" bson:"where_sighted_refs"`
@@ -205,7 +205,7 @@ type WindowsServiceExtensionSTIX struct {
 // Shell - содержит командную оболочку аккаунта
 type UNIXAccountExtensionSTIX struct {
 	GID     int      `json:"gid" bson:"gid"`
-	Groups  []string `json:"group" bson:"group"`
+	Groups  []string `json:"groups" bson:"groups"`
 	HomeDir string   `json:"home_dir" bson:"home_dir"`
 	Shell   string   `json:"shell" bson:"shell"`
 }
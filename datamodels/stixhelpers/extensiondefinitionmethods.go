@@ -0,0 +1,501 @@
+package stixhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+)
+
+/********** 			Extension Definition STIX (МЕТОДЫ)			**********/
+
+// DecoderJSON выполняет декодирование JSON объекта
+func (e ExtensionDefinitionObjectSTIX) DecodeJSON(raw *json.RawMessage) (interface{}, error) {
+	if err := json.Unmarshal(*raw, &e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// EncoderJSON выполняет кодирование в JSON объект
+func (e ExtensionDefinitionObjectSTIX) EncodeJSON(interface{}) (*[]byte, error) {
+	result, err := json.Marshal(e)
+
+	return &result, err
+}
+
+// Get возвращает объект "extension-definition", по терминалогии STIX, описывающий расширение.
+// Обязательные значения в полях CreatedByRef, Name, Schema, Version и ExtensionTypes
+func (e *ExtensionDefinitionObjectSTIX) Get() (*ExtensionDefinitionObjectSTIX, error) {
+	if e.GetCreatedByRef() == "" {
+		err := fmt.Errorf("the required value 'CreatedByRef' must not be empty")
+
+		return &ExtensionDefinitionObjectSTIX{}, err
+	}
+
+	if e.GetName() == "" {
+		err := fmt.Errorf("the required value 'Name' must not be empty")
+
+		return &ExtensionDefinitionObjectSTIX{}, err
+	}
+
+	if e.GetSchema() == "" {
+		err := fmt.Errorf("the required value 'Schema' must not be empty")
+
+		return &ExtensionDefinitionObjectSTIX{}, err
+	}
+
+	if e.GetVersion() == "" {
+		err := fmt.Errorf("the required value 'Version' must not be empty")
+
+		return &ExtensionDefinitionObjectSTIX{}, err
+	}
+
+	if len(e.GetExtensionTypes()) == 0 {
+		err := fmt.Errorf("the required value 'ExtensionTypes' must not be empty")
+
+		return &ExtensionDefinitionObjectSTIX{}, err
+	}
+
+	return e, nil
+}
+
+// -------- SpecVersion property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetSpecVersion() string {
+	return e.SpecVersion
+}
+
+// SetValueSpecVersion устанавливает значение для поля SpecVersion
+func (e *ExtensionDefinitionObjectSTIX) SetValueSpecVersion(v string) {
+	e.SpecVersion = v
+}
+
+// SetAnySpecVersion устанавливает ЛЮБОЕ значение для поля SpecVersion
+func (e *ExtensionDefinitionObjectSTIX) SetAnySpecVersion(i interface{}) {
+	e.SpecVersion = fmt.Sprint(i)
+}
+
+// -------- ID property ---------
+
+// SetValueID устанавливает значение для поля ID
+func (e *ExtensionDefinitionObjectSTIX) SetValueID(v string) {
+	e.ID = v
+}
+
+// SetAnyID устанавливает ЛЮБОЕ значение для поля ID
+func (e *ExtensionDefinitionObjectSTIX) SetAnyID(i interface{}) {
+	e.ID = fmt.Sprint(i)
+}
+
+// -------- Created property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetCreated() string {
+	return e.Created
+}
+
+// SetValueCreated устанавливает значение в формате RFC3339 для поля Created
+func (e *ExtensionDefinitionObjectSTIX) SetValueCreated(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.Created = v
+
+	return nil
+}
+
+// SetAnyCreated устанавливает ЛЮБОЕ значение для поля Created
+func (e *ExtensionDefinitionObjectSTIX) SetAnyCreated(i interface{}) {
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.Created = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+}
+
+// -------- Modified property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetModified() string {
+	return e.Modified
+}
+
+// SetValueModified устанавливает значение в формате RFC3339 для поля Modified
+func (e *ExtensionDefinitionObjectSTIX) SetValueModified(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.Modified = v
+
+	return nil
+}
+
+// SetAnyModified устанавливает ЛЮБОЕ значение для поля Modified
+func (e *ExtensionDefinitionObjectSTIX) SetAnyModified(i interface{}) {
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.Modified = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+}
+
+// -------- CreatedByRef property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetCreatedByRef() IdentifierTypeSTIX {
+	return e.CreatedByRef
+}
+
+func (e *ExtensionDefinitionObjectSTIX) SetValueCreatedByRef(v IdentifierTypeSTIX) {
+	e.CreatedByRef = v
+}
+
+// -------- Revoked property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetRevoked() bool {
+	return e.Revoked
+}
+
+// SetValueRevoked устанавливает значение для поля Revoked
+func (e *ExtensionDefinitionObjectSTIX) SetValueRevoked(v bool) {
+	e.Revoked = v
+}
+
+// SetAnyRevoked устанавливает ЛЮБОЕ значение для поля Revoked
+func (e *ExtensionDefinitionObjectSTIX) SetAnyRevoked(i interface{}) {
+	if v, ok := i.(bool); ok {
+		e.Revoked = v
+	}
+}
+
+// -------- Labels property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetLabels() []string {
+	return e.Labels
+}
+
+// SetValueLabels устанавливает значение для поля Labels
+func (e *ExtensionDefinitionObjectSTIX) SetValueLabels(v string) {
+	e.Labels = append(e.Labels, v)
+}
+
+// SetAnyLabels устанавливает ЛЮБОЕ значение для поля Labels
+func (e *ExtensionDefinitionObjectSTIX) SetAnyLabels(i interface{}) {
+	e.Labels = append(e.Labels, fmt.Sprint(i))
+}
+
+// -------- ExternalReferences property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetExternalReferences() []ExternalReferenceTypeElementSTIX {
+	return e.ExternalReferences
+}
+
+func (e *ExtensionDefinitionObjectSTIX) SetValueExternalReferences(v []ExternalReferenceTypeElementSTIX) {
+	e.ExternalReferences = v
+}
+
+// -------- ObjectMarkingRefs property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetObjectMarkingRefs() []IdentifierTypeSTIX {
+	return e.ObjectMarkingRefs
+}
+
+func (e *ExtensionDefinitionObjectSTIX) SetValueObjectMarkingRefs(v []IdentifierTypeSTIX) {
+	e.ObjectMarkingRefs = v
+}
+
+// -------- GranularMarkings property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetGranularMarkings() []GranularMarkingsTypeSTIX {
+	return e.GranularMarkings
+}
+
+func (e *ExtensionDefinitionObjectSTIX) SetValueGranularMarkings(v []GranularMarkingsTypeSTIX) {
+	e.GranularMarkings = v
+}
+
+// -------- Name property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetName() string {
+	return e.Name
+}
+
+// SetValueName устанавливает значение для поля Name
+func (e *ExtensionDefinitionObjectSTIX) SetValueName(v string) {
+	e.Name = v
+}
+
+// SetAnyName устанавливает ЛЮБОЕ значение для поля Name
+func (e *ExtensionDefinitionObjectSTIX) SetAnyName(i interface{}) {
+	e.Name = fmt.Sprint(i)
+}
+
+// -------- Description property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetDescription() string {
+	return e.Description
+}
+
+// SetValueDescription устанавливает значение для поля Description
+func (e *ExtensionDefinitionObjectSTIX) SetValueDescription(v string) {
+	e.Description = v
+}
+
+// SetAnyDescription устанавливает ЛЮБОЕ значение для поля Description
+func (e *ExtensionDefinitionObjectSTIX) SetAnyDescription(i interface{}) {
+	e.Description = fmt.Sprint(i)
+}
+
+// -------- Schema property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetSchema() string {
+	return e.Schema
+}
+
+// SetValueSchema устанавливает значение для поля Schema
+func (e *ExtensionDefinitionObjectSTIX) SetValueSchema(v string) {
+	e.Schema = v
+}
+
+// SetAnySchema устанавливает ЛЮБОЕ значение для поля Schema
+func (e *ExtensionDefinitionObjectSTIX) SetAnySchema(i interface{}) {
+	e.Schema = fmt.Sprint(i)
+}
+
+// -------- Version property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetVersion() string {
+	return e.Version
+}
+
+// SetValueVersion устанавливает значение для поля Version
+func (e *ExtensionDefinitionObjectSTIX) SetValueVersion(v string) {
+	e.Version = v
+}
+
+// SetAnyVersion устанавливает ЛЮБОЕ значение для поля Version
+func (e *ExtensionDefinitionObjectSTIX) SetAnyVersion(i interface{}) {
+	e.Version = fmt.Sprint(i)
+}
+
+// -------- ExtensionTypes property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetExtensionTypes() []EnumTypeSTIX {
+	return e.ExtensionTypes
+}
+
+// SetValueExtensionTypes устанавливает значение для поля ExtensionTypes
+func (e *ExtensionDefinitionObjectSTIX) SetValueExtensionTypes(v EnumTypeSTIX) {
+	e.ExtensionTypes = append(e.ExtensionTypes, v)
+}
+
+// SetAnyExtensionTypes устанавливает ЛЮБОЕ значение для поля ExtensionTypes
+func (e *ExtensionDefinitionObjectSTIX) SetAnyExtensionTypes(i interface{}) {
+	e.ExtensionTypes = append(e.ExtensionTypes, EnumTypeSTIX(fmt.Sprint(i)))
+}
+
+// IsExtensionType проверяет содержит ли объект указанный тип расширения
+func (e *ExtensionDefinitionObjectSTIX) IsExtensionType(v EnumTypeSTIX) bool {
+	for _, et := range e.ExtensionTypes {
+		if et == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// -------- ExtensionProperties property ---------
+func (e *ExtensionDefinitionObjectSTIX) GetExtensionProperties() []string {
+	return e.ExtensionProperties
+}
+
+// SetValueExtensionProperties устанавливает значение для поля ExtensionProperties
+func (e *ExtensionDefinitionObjectSTIX) SetValueExtensionProperties(v string) {
+	e.ExtensionProperties = append(e.ExtensionProperties, v)
+}
+
+// SetAnyExtensionProperties устанавливает ЛЮБОЕ значение для поля ExtensionProperties
+func (e *ExtensionDefinitionObjectSTIX) SetAnyExtensionProperties(i interface{}) {
+	e.ExtensionProperties = append(e.ExtensionProperties, fmt.Sprint(i))
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе ExtensionDefinitionObjectSTIX.
+// Помимо общих полей проверяются значения ExtensionTypes, формат Version, а так же то, что свойство
+// ExtensionProperties заполняется только для расширений типа "toplevel-property-extension"
+func (e ExtensionDefinitionObjectSTIX) ValidateStruct() bool {
+	if e.Type != "extension-definition" {
+		return false
+	}
+
+	if !(regexp.MustCompile(`^(extension-definition--)[0-9a-f|-]+$`).MatchString(e.ID)) {
+		return false
+	}
+
+	if !(regexp.MustCompile(`^[0-9a-z.]+$`).MatchString(e.SpecVersion)) {
+		return false
+	}
+
+	if _, err := time.Parse(time.RFC3339, e.Created); err != nil {
+		return false
+	}
+
+	if _, err := time.Parse(time.RFC3339, e.Modified); err != nil {
+		return false
+	}
+
+	if e.CreatedByRef == "" || !e.CreatedByRef.CheckIdentifierTypeSTIX() {
+		return false
+	}
+
+	if e.Name == "" || e.Schema == "" {
+		return false
+	}
+
+	if !(regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+$`).MatchString(e.Version)) {
+		return false
+	}
+
+	if len(e.ExtensionTypes) == 0 {
+		return false
+	}
+
+	for _, v := range e.ExtensionTypes {
+		if !isExtensionType(string(v)) {
+			return false
+		}
+	}
+
+	if len(e.ExtensionProperties) > 0 && !e.IsExtensionType(ExtensionTypeToplevelPropertyExtension) {
+		return false
+	}
+
+	for _, v := range e.ExternalReferences {
+		if !v.CheckExternalReferenceTypeElementSTIX() {
+			return false
+		}
+	}
+
+	for _, v := range e.ObjectMarkingRefs {
+		if !v.CheckIdentifierTypeSTIX() {
+			return false
+		}
+	}
+
+	for _, v := range e.GranularMarkings {
+		if !v.CheckGranularMarkingsTypeSTIX() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SanitizeStruct для ряда полей, выполняет замену некоторых специальных символов на их HTML код
+func (e ExtensionDefinitionObjectSTIX) SanitizeStruct() ExtensionDefinitionObjectSTIX {
+	e.Name = commonlibs.StringSanitize(e.Name)
+	e.Description = commonlibs.StringSanitize(e.Description)
+
+	if len(e.Labels) > 0 {
+		nl := make([]string, 0, len(e.Labels))
+		for _, l := range e.Labels {
+			nl = append(nl, commonlibs.StringSanitize(l))
+		}
+
+		e.Labels = nl
+	}
+
+	if len(e.ExternalReferences) > 0 {
+		er := make([]ExternalReferenceTypeElementSTIX, 0, len(e.ExternalReferences))
+		for _, v := range e.ExternalReferences {
+			er = append(er, v.SanitizeStructExternalReferenceTypeElementSTIX())
+		}
+
+		e.ExternalReferences = er
+	}
+
+	return e
+}
+
+// GetID возвращает ID STIX объекта
+func (e ExtensionDefinitionObjectSTIX) GetID() string {
+	return e.ID
+}
+
+// GetType возвращает Type STIX объекта
+func (e ExtensionDefinitionObjectSTIX) GetType() string {
+	return e.Type
+}
+
+// ToStringBeautiful выполняет красивое представление информации содержащейся в типе
+func (e ExtensionDefinitionObjectSTIX) ToStringBeautiful(num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	str.WriteString(fmt.Sprintf("%s'type': '%s'\n", ws, e.Type))
+	str.WriteString(fmt.Sprintf("%s'id': '%s'\n", ws, e.ID))
+	str.WriteString(fmt.Sprintf("%s'spec_version': '%s'\n", ws, e.SpecVersion))
+	str.WriteString(fmt.Sprintf("%s'created': '%v'\n", ws, e.Created))
+	str.WriteString(fmt.Sprintf("%s'modified': '%v'\n", ws, e.Modified))
+	str.WriteString(fmt.Sprintf("%s'created_by_ref': '%v'\n", ws, e.CreatedByRef))
+	str.WriteString(fmt.Sprintf("%s'revoked': '%v'\n", ws, e.Revoked))
+	str.WriteString(fmt.Sprintf("%s'labels': \n%v", ws, func(l []string, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'label '%d'': '%s'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.Labels, num+1)))
+	str.WriteString(fmt.Sprintf("%s'name': '%s'\n", ws, e.Name))
+	str.WriteString(fmt.Sprintf("%s'description': '%s'\n", ws, e.Description))
+	str.WriteString(fmt.Sprintf("%s'schema': '%s'\n", ws, e.Schema))
+	str.WriteString(fmt.Sprintf("%s'version': '%s'\n", ws, e.Version))
+	str.WriteString(fmt.Sprintf("%s'extension_types': \n%v", ws, func(l []EnumTypeSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'extension_type '%d'': '%s'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.ExtensionTypes, num+1)))
+	str.WriteString(fmt.Sprintf("%s'extension_properties': \n%v", ws, func(l []string, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'extension_property '%d'': '%s'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.ExtensionProperties, num+1)))
+	str.WriteString(fmt.Sprintf("%s'external_references': \n%v", ws, func(l []ExternalReferenceTypeElementSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+		dubleWs := commonlibs.GetWhitespace(num + 1)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'external_references element '%d'':\n", ws, k))
+			str.WriteString(fmt.Sprintf("%s'source_name': '%s'\n", dubleWs, v.SourceName))
+			str.WriteString(fmt.Sprintf("%s'description': '%s'\n", dubleWs, v.Description))
+			str.WriteString(fmt.Sprintf("%s'url': '%s'\n", dubleWs, v.URL))
+			str.WriteString(fmt.Sprintf("%s'hashes': '%s'\n", dubleWs, v.Hashes))
+			str.WriteString(fmt.Sprintf("%s'external_id': '%s'\n", dubleWs, v.ExternalID))
+		}
+
+		return str.String()
+	}(e.ExternalReferences, num+1)))
+	str.WriteString(fmt.Sprintf("%s'object_marking_refs': \n%v", ws, func(l []IdentifierTypeSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'ref '%d'': '%v'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.ObjectMarkingRefs, num+1)))
+	str.WriteString(fmt.Sprintf("%s'granular_markings': \n%v", ws, func(l []GranularMarkingsTypeSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'granular_markings number %d.'\n", ws, k))
+			str.WriteString(fmt.Sprintf("%s'lang': '%s'\n", ws, v.Lang))
+			str.WriteString(fmt.Sprintf("%s'marking_ref': '%v'\n", ws, v.MarkingRef))
+			str.WriteString(fmt.Sprintf("%s'selectors': '%v'\n", ws, v.Selectors))
+		}
+
+		return str.String()
+	}(e.GranularMarkings, num+1)))
+
+	return str.String()
+}
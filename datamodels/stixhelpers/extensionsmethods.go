@@ -0,0 +1,206 @@
+package stixhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+/********** 			Расширения объектов STIX (property-extension)			**********/
+
+// Типы расширений определяемые объектом "extension-definition"
+const (
+	ExtensionTypeNewSDO                    EnumTypeSTIX = "new-sdo"
+	ExtensionTypeNewSCO                    EnumTypeSTIX = "new-sco"
+	ExtensionTypeNewSRO                    EnumTypeSTIX = "new-sro"
+	ExtensionTypePropertyExtension         EnumTypeSTIX = "property-extension"
+	ExtensionTypeToplevelPropertyExtension EnumTypeSTIX = "toplevel-property-extension"
+)
+
+// extensionRegistration сведения о зарегистрированном расширении
+type extensionRegistration struct {
+	valueType reflect.Type
+	validator func(interface{}) bool
+}
+
+// extensionsRegistry реестр расширений, содержит соответствие идентификаторов объектов
+// "extension-definition" типам Go
+var extensionsRegistry = struct {
+	sync.RWMutex
+	list map[string]extensionRegistration
+}{list: map[string]extensionRegistration{}}
+
+// RegisterExtensionSTIX регистрирует тип Go для расширения с идентификатором id. Параметр sample является
+// значением регистрируемого типа, например, MyExtension{}. Функция validator, если она задана, вызывается
+// при проверке объектов содержащих данное расширение и получает значение зарегистрированного типа.
+// Повторная регистрация расширения с тем же идентификатором заменяет предыдущую
+func RegisterExtensionSTIX(id string, sample interface{}, validator func(interface{}) bool) error {
+	if id == "" {
+		return fmt.Errorf("the extension identifier must not be empty")
+	}
+
+	if sample == nil {
+		return fmt.Errorf("the type of the extension '%s' must not be empty", id)
+	}
+
+	t := reflect.TypeOf(sample)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	extensionsRegistry.Lock()
+	defer extensionsRegistry.Unlock()
+
+	extensionsRegistry.list[id] = extensionRegistration{valueType: t, validator: validator}
+
+	return nil
+}
+
+// UnregisterExtensionSTIX удаляет расширение из реестра
+func UnregisterExtensionSTIX(id string) {
+	extensionsRegistry.Lock()
+	defer extensionsRegistry.Unlock()
+
+	delete(extensionsRegistry.list, id)
+}
+
+// IsRegisteredExtensionSTIX проверяет зарегистрировано ли расширение
+func IsRegisteredExtensionSTIX(id string) bool {
+	_, ok := getExtensionRegistration(id)
+
+	return ok
+}
+
+// DecodeExtensionSTIX декодирует содержимое расширения с идентификатором id. Для зарегистрированного
+// расширения возвращается значение зарегистрированного типа, для прочих map[string]interface{}
+// или значение иного типа соответствующее JSON
+func DecodeExtensionSTIX(id string, raw json.RawMessage) (interface{}, error) {
+	reg, ok := getExtensionRegistration(id)
+	if !ok {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+
+		return v, nil
+	}
+
+	v := reflect.New(reg.valueType)
+	if err := json.Unmarshal(raw, v.Interface()); err != nil {
+		return nil, fmt.Errorf("extension '%s': %w", id, err)
+	}
+
+	return v.Elem().Interface(), nil
+}
+
+func getExtensionRegistration(id string) (extensionRegistration, bool) {
+	extensionsRegistry.RLock()
+	defer extensionsRegistry.RUnlock()
+
+	reg, ok := extensionsRegistry.list[id]
+
+	return reg, ok
+}
+
+// UnmarshalJSON выполняет декодирование расширений с учетом зарегистрированных типов
+func (e *ExtensionsTypeSTIX) UnmarshalJSON(data []byte) error {
+	tmp := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+
+	if tmp == nil {
+		*e = nil
+
+		return nil
+	}
+
+	list := make(ExtensionsTypeSTIX, len(tmp))
+	for k, v := range tmp {
+		value, err := DecodeExtensionSTIX(k, v)
+		if err != nil {
+			return err
+		}
+
+		list[k] = value
+	}
+
+	*e = list
+
+	return nil
+}
+
+// GetExtension возвращает содержимое расширения. Если расширение зарегистрировано, а его содержимое
+// хранится не в виде значения зарегистрированного типа (например, было установлено как map[string]interface{}),
+// выполняется его преобразование
+func (e ExtensionsTypeSTIX) GetExtension(id string) (interface{}, bool, error) {
+	value, ok := e[id]
+	if !ok {
+		return nil, false, nil
+	}
+
+	reg, isReg := getExtensionRegistration(id)
+	if !isReg || reflect.TypeOf(value) == reg.valueType {
+		return value, true, nil
+	}
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.Type().Elem() == reg.valueType {
+		return v.Elem().Interface(), true, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, true, err
+	}
+
+	result, err := DecodeExtensionSTIX(id, b)
+
+	return result, true, err
+}
+
+// CheckExtensionsTypeSTIX выполняет проверку расширений. Для зарегистрированных расширений проверяется
+// возможность приведения содержимого к зарегистрированному типу и вызывается функция проверки, для
+// прочих проверяется значение свойства extension_type, если оно задано
+func (e ExtensionsTypeSTIX) CheckExtensionsTypeSTIX() bool {
+	for k, v := range e {
+		if k == "" {
+			return false
+		}
+
+		if m, ok := v.(map[string]interface{}); ok {
+			if et, ok := m["extension_type"]; ok && !isExtensionType(fmt.Sprint(et)) {
+				return false
+			}
+		}
+
+		reg, ok := getExtensionRegistration(k)
+		if !ok {
+			continue
+		}
+
+		value, _, err := e.GetExtension(k)
+		if err != nil {
+			return false
+		}
+
+		if reg.validator != nil && !reg.validator(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isExtensionType(v string) bool {
+	switch EnumTypeSTIX(v) {
+	case ExtensionTypeNewSDO,
+		ExtensionTypeNewSCO,
+		ExtensionTypeNewSRO,
+		ExtensionTypePropertyExtension,
+		ExtensionTypeToplevelPropertyExtension:
+		return true
+	}
+
+	return false
+}
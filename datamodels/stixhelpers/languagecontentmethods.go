@@ -0,0 +1,510 @@
+package stixhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+)
+
+/********** 			Language Content STIX (МЕТОДЫ)			**********/
+
+// DecoderJSON выполняет декодирование JSON объекта
+func (e LanguageContentTypeSTIX) DecodeJSON(raw *json.RawMessage) (interface{}, error) {
+	if err := json.Unmarshal(*raw, &e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// EncoderJSON выполняет кодирование в JSON объект
+func (e LanguageContentTypeSTIX) EncodeJSON(interface{}) (*[]byte, error) {
+	result, err := json.Marshal(e)
+
+	return &result, err
+}
+
+// Get возвращает объект "language-content", по терминалогии STIX, содержащий переводы свойств объекта.
+// Обязательные значения в полях ObjectRef и Contents
+func (e *LanguageContentTypeSTIX) Get() (*LanguageContentTypeSTIX, error) {
+	if e.GetObjectRef() == "" {
+		err := fmt.Errorf("the required value 'ObjectRef' must not be empty")
+
+		return &LanguageContentTypeSTIX{}, err
+	}
+
+	if len(e.GetContents()) == 0 {
+		err := fmt.Errorf("the required value 'Contents' must not be empty")
+
+		return &LanguageContentTypeSTIX{}, err
+	}
+
+	return e, nil
+}
+
+// -------- SpecVersion property ---------
+func (e *LanguageContentTypeSTIX) GetSpecVersion() string {
+	return e.SpecVersion
+}
+
+// SetValueSpecVersion устанавливает значение для поля SpecVersion
+func (e *LanguageContentTypeSTIX) SetValueSpecVersion(v string) {
+	e.SpecVersion = v
+}
+
+// SetAnySpecVersion устанавливает ЛЮБОЕ значение для поля SpecVersion
+func (e *LanguageContentTypeSTIX) SetAnySpecVersion(i interface{}) {
+	e.SpecVersion = fmt.Sprint(i)
+}
+
+// -------- ID property ---------
+
+// SetValueID устанавливает значение для поля ID
+func (e *LanguageContentTypeSTIX) SetValueID(v string) {
+	e.ID = v
+}
+
+// SetAnyID устанавливает ЛЮБОЕ значение для поля ID
+func (e *LanguageContentTypeSTIX) SetAnyID(i interface{}) {
+	e.ID = fmt.Sprint(i)
+}
+
+// -------- Created property ---------
+func (e *LanguageContentTypeSTIX) GetCreated() string {
+	return e.Created
+}
+
+// SetValueCreated устанавливает значение в формате RFC3339 для поля Created
+func (e *LanguageContentTypeSTIX) SetValueCreated(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.Created = v
+
+	return nil
+}
+
+// SetAnyCreated устанавливает ЛЮБОЕ значение для поля Created
+func (e *LanguageContentTypeSTIX) SetAnyCreated(i interface{}) {
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.Created = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+}
+
+// -------- Modified property ---------
+func (e *LanguageContentTypeSTIX) GetModified() string {
+	return e.Modified
+}
+
+// SetValueModified устанавливает значение в формате RFC3339 для поля Modified
+func (e *LanguageContentTypeSTIX) SetValueModified(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.Modified = v
+
+	return nil
+}
+
+// SetAnyModified устанавливает ЛЮБОЕ значение для поля Modified
+func (e *LanguageContentTypeSTIX) SetAnyModified(i interface{}) {
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.Modified = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+}
+
+// -------- ObjectRef property ---------
+func (e *LanguageContentTypeSTIX) GetObjectRef() IdentifierTypeSTIX {
+	return e.ObjectRef
+}
+
+func (e *LanguageContentTypeSTIX) SetValueObjectRef(v IdentifierTypeSTIX) {
+	e.ObjectRef = v
+}
+
+// -------- ObjectModified property ---------
+func (e *LanguageContentTypeSTIX) GetObjectModified() string {
+	return e.ObjectModified
+}
+
+// SetValueObjectModified устанавливает значение в формате RFC3339 для поля ObjectModified
+func (e *LanguageContentTypeSTIX) SetValueObjectModified(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.ObjectModified = v
+
+	return nil
+}
+
+// SetAnyObjectModified устанавливает ЛЮБОЕ значение для поля ObjectModified
+func (e *LanguageContentTypeSTIX) SetAnyObjectModified(i interface{}) {
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.ObjectModified = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+}
+
+// -------- Contents property ---------
+func (e *LanguageContentTypeSTIX) GetContents() map[string]map[string]interface{} {
+	return e.Contents
+}
+
+// GetContentsLang возвращает переведенные свойства объекта для указанного языка
+func (e *LanguageContentTypeSTIX) GetContentsLang(lang string) map[string]interface{} {
+	return e.Contents[lang]
+}
+
+// GetLanguages возвращает отсортированный список языков для которых есть переводы
+func (e *LanguageContentTypeSTIX) GetLanguages() []string {
+	list := make([]string, 0, len(e.Contents))
+	for k := range e.Contents {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// SetValueContents устанавливает переведенные свойства объекта для указанного языка
+func (e *LanguageContentTypeSTIX) SetValueContents(lang string, v map[string]interface{}) {
+	if e.Contents == nil {
+		e.Contents = map[string]map[string]interface{}{}
+	}
+
+	e.Contents[lang] = v
+}
+
+// SetAnyContents устанавливает ЛЮБОЕ значение перевода отдельного свойства объекта для указанного языка
+func (e *LanguageContentTypeSTIX) SetAnyContents(lang, property string, i interface{}) {
+	if e.Contents == nil {
+		e.Contents = map[string]map[string]interface{}{}
+	}
+
+	if _, ok := e.Contents[lang]; !ok {
+		e.Contents[lang] = map[string]interface{}{}
+	}
+
+	e.Contents[lang][property] = i
+}
+
+// -------- CreatedByRef property ---------
+func (e *LanguageContentTypeSTIX) GetCreatedByRef() IdentifierTypeSTIX {
+	return e.CreatedByRef
+}
+
+func (e *LanguageContentTypeSTIX) SetValueCreatedByRef(v IdentifierTypeSTIX) {
+	e.CreatedByRef = v
+}
+
+// -------- Revoked property ---------
+func (e *LanguageContentTypeSTIX) GetRevoked() bool {
+	return e.Revoked
+}
+
+// SetValueRevoked устанавливает значение для поля Revoked
+func (e *LanguageContentTypeSTIX) SetValueRevoked(v bool) {
+	e.Revoked = v
+}
+
+// SetAnyRevoked устанавливает ЛЮБОЕ значение для поля Revoked
+func (e *LanguageContentTypeSTIX) SetAnyRevoked(i interface{}) {
+	if v, ok := i.(bool); ok {
+		e.Revoked = v
+	}
+}
+
+// -------- Labels property ---------
+func (e *LanguageContentTypeSTIX) GetLabels() []string {
+	return e.Labels
+}
+
+// SetValueLabels устанавливает значение для поля Labels
+func (e *LanguageContentTypeSTIX) SetValueLabels(v string) {
+	e.Labels = append(e.Labels, v)
+}
+
+// SetAnyLabels устанавливает ЛЮБОЕ значение для поля Labels
+func (e *LanguageContentTypeSTIX) SetAnyLabels(i interface{}) {
+	e.Labels = append(e.Labels, fmt.Sprint(i))
+}
+
+// -------- Сonfidence property ---------
+func (e *LanguageContentTypeSTIX) GetСonfidence() int {
+	return e.Сonfidence
+}
+
+// SetValueConfidence устанавливает значение для поля Сonfidence
+func (e *LanguageContentTypeSTIX) SetValueConfidence(v int) {
+	e.Сonfidence = v
+}
+
+// SetAnyСonfidence устанавливает ЛЮБОЕ значение для поля Сonfidence
+func (e *LanguageContentTypeSTIX) SetAnyСonfidence(i interface{}) {
+	e.Сonfidence = commonlibs.ConversionAnyToInt(i)
+}
+
+// -------- ExternalReferences property ---------
+func (e *LanguageContentTypeSTIX) GetExternalReferences() []ExternalReferenceTypeElementSTIX {
+	return e.ExternalReferences
+}
+
+func (e *LanguageContentTypeSTIX) SetValueExternalReferences(v []ExternalReferenceTypeElementSTIX) {
+	e.ExternalReferences = v
+}
+
+// -------- ObjectMarkingRefs property ---------
+func (e *LanguageContentTypeSTIX) GetObjectMarkingRefs() []IdentifierTypeSTIX {
+	return e.ObjectMarkingRefs
+}
+
+func (e *LanguageContentTypeSTIX) SetValueObjectMarkingRefs(v []IdentifierTypeSTIX) {
+	e.ObjectMarkingRefs = v
+}
+
+// -------- GranularMarkings property ---------
+func (e *LanguageContentTypeSTIX) GetGranularMarkings() []GranularMarkingsTypeSTIX {
+	return e.GranularMarkings
+}
+
+func (e *LanguageContentTypeSTIX) SetValueGranularMarkings(v []GranularMarkingsTypeSTIX) {
+	e.GranularMarkings = v
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе LanguageContentTypeSTIX.
+// Ключи поля Contents должны быть языковыми тегами RFC 5646. Проверка того, что переведенные
+// свойства существуют в исходном объекте, выполняется пакетом languagecontent
+func (e LanguageContentTypeSTIX) ValidateStruct() bool {
+	if e.Type != "language-content" {
+		return false
+	}
+
+	if !(regexp.MustCompile(`^(language-content--)[0-9a-f|-]+$`).MatchString(e.ID)) {
+		return false
+	}
+
+	if !(regexp.MustCompile(`^[0-9a-z.]+$`).MatchString(e.SpecVersion)) {
+		return false
+	}
+
+	if _, err := time.Parse(time.RFC3339, e.Created); err != nil {
+		return false
+	}
+
+	if _, err := time.Parse(time.RFC3339, e.Modified); err != nil {
+		return false
+	}
+
+	if e.ObjectModified != "" {
+		if _, err := time.Parse(time.RFC3339, e.ObjectModified); err != nil {
+			return false
+		}
+	}
+
+	if e.ObjectRef == "" || !e.ObjectRef.CheckIdentifierTypeSTIX() {
+		return false
+	}
+
+	if len(e.Contents) == 0 {
+		return false
+	}
+
+	for lang, v := range e.Contents {
+		if !commonlibs.IsLanguageTag(lang) || len(v) == 0 {
+			return false
+		}
+	}
+
+	if e.Сonfidence < 0 || e.Сonfidence > 100 {
+		return false
+	}
+
+	if !e.CreatedByRef.CheckIdentifierTypeSTIX() {
+		return false
+	}
+
+	for _, v := range e.ExternalReferences {
+		if !v.CheckExternalReferenceTypeElementSTIX() {
+			return false
+		}
+	}
+
+	for _, v := range e.ObjectMarkingRefs {
+		if !v.CheckIdentifierTypeSTIX() {
+			return false
+		}
+	}
+
+	for _, v := range e.GranularMarkings {
+		if !v.CheckGranularMarkingsTypeSTIX() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SanitizeStruct для ряда полей, выполняет замену некоторых специальных символов на их HTML код
+func (e LanguageContentTypeSTIX) SanitizeStruct() LanguageContentTypeSTIX {
+	if len(e.Contents) > 0 {
+		contents := make(map[string]map[string]interface{}, len(e.Contents))
+		for lang, v := range e.Contents {
+			c := make(map[string]interface{}, len(v))
+			for property, value := range v {
+				c[property] = sanitizeContent(value)
+			}
+
+			contents[lang] = c
+		}
+
+		e.Contents = contents
+	}
+
+	if len(e.ExternalReferences) > 0 {
+		er := make([]ExternalReferenceTypeElementSTIX, 0, len(e.ExternalReferences))
+		for _, v := range e.ExternalReferences {
+			er = append(er, v.SanitizeStructExternalReferenceTypeElementSTIX())
+		}
+
+		e.ExternalReferences = er
+	}
+
+	return e
+}
+
+// sanitizeContent выполняет замену специальных символов во всех строковых значениях перевода
+func sanitizeContent(i interface{}) interface{} {
+	switch v := i.(type) {
+	case string:
+		return commonlibs.StringSanitize(v)
+
+	case []interface{}:
+		list := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			list = append(list, sanitizeContent(value))
+		}
+
+		return list
+
+	case []string:
+		list := make([]string, 0, len(v))
+		for _, value := range v {
+			list = append(list, commonlibs.StringSanitize(value))
+		}
+
+		return list
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			m[k] = sanitizeContent(value)
+		}
+
+		return m
+	}
+
+	return i
+}
+
+// GetID возвращает ID STIX объекта
+func (e LanguageContentTypeSTIX) GetID() string {
+	return e.ID
+}
+
+// GetType возвращает Type STIX объекта
+func (e LanguageContentTypeSTIX) GetType() string {
+	return e.Type
+}
+
+// ToStringBeautiful выполняет красивое представление информации содержащейся в типе
+func (e LanguageContentTypeSTIX) ToStringBeautiful(num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	str.WriteString(fmt.Sprintf("%s'type': '%s'\n", ws, e.Type))
+	str.WriteString(fmt.Sprintf("%s'id': '%s'\n", ws, e.ID))
+	str.WriteString(fmt.Sprintf("%s'spec_version': '%s'\n", ws, e.SpecVersion))
+	str.WriteString(fmt.Sprintf("%s'created': '%v'\n", ws, e.Created))
+	str.WriteString(fmt.Sprintf("%s'modified': '%v'\n", ws, e.Modified))
+	str.WriteString(fmt.Sprintf("%s'created_by_ref': '%v'\n", ws, e.CreatedByRef))
+	str.WriteString(fmt.Sprintf("%s'revoked': '%v'\n", ws, e.Revoked))
+	str.WriteString(fmt.Sprintf("%s'labels': \n%v", ws, func(l []string, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'label '%d'': '%s'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.Labels, num+1)))
+	str.WriteString(fmt.Sprintf("%s'confidence': '%v'\n", ws, e.Сonfidence))
+	str.WriteString(fmt.Sprintf("%s'object_ref': '%v'\n", ws, e.ObjectRef))
+	str.WriteString(fmt.Sprintf("%s'object_modified': '%v'\n", ws, e.ObjectModified))
+	str.WriteString(fmt.Sprintf("%s'contents': \n%v", ws, func(l map[string]map[string]interface{}, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+		dubleWs := commonlibs.GetWhitespace(num + 1)
+
+		for _, lang := range e.GetLanguages() {
+			str.WriteString(fmt.Sprintf("%s'%s':\n", ws, lang))
+
+			properties := make([]string, 0, len(l[lang]))
+			for k := range l[lang] {
+				properties = append(properties, k)
+			}
+			sort.Strings(properties)
+
+			for _, k := range properties {
+				str.WriteString(fmt.Sprintf("%s'%s': '%v'\n", dubleWs, k, l[lang][k]))
+			}
+		}
+
+		return str.String()
+	}(e.Contents, num+1)))
+	str.WriteString(fmt.Sprintf("%s'external_references': \n%v", ws, func(l []ExternalReferenceTypeElementSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+		dubleWs := commonlibs.GetWhitespace(num + 1)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'external_references element '%d'':\n", ws, k))
+			str.WriteString(fmt.Sprintf("%s'source_name': '%s'\n", dubleWs, v.SourceName))
+			str.WriteString(fmt.Sprintf("%s'description': '%s'\n", dubleWs, v.Description))
+			str.WriteString(fmt.Sprintf("%s'url': '%s'\n", dubleWs, v.URL))
+			str.WriteString(fmt.Sprintf("%s'hashes': '%s'\n", dubleWs, v.Hashes))
+			str.WriteString(fmt.Sprintf("%s'external_id': '%s'\n", dubleWs, v.ExternalID))
+		}
+
+		return str.String()
+	}(e.ExternalReferences, num+1)))
+	str.WriteString(fmt.Sprintf("%s'object_marking_refs': \n%v", ws, func(l []IdentifierTypeSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'ref '%d'': '%v'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.ObjectMarkingRefs, num+1)))
+	str.WriteString(fmt.Sprintf("%s'granular_markings': \n%v", ws, func(l []GranularMarkingsTypeSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'granular_markings number %d.'\n", ws, k))
+			str.WriteString(fmt.Sprintf("%s'lang': '%s'\n", ws, v.Lang))
+			str.WriteString(fmt.Sprintf("%s'marking_ref': '%v'\n", ws, v.MarkingRef))
+			str.WriteString(fmt.Sprintf("%s'selectors': '%v'\n", ws, v.Selectors))
+		}
+
+		return str.String()
+	}(e.GranularMarkings, num+1)))
+
+	return str.String()
+}
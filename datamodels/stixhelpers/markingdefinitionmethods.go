@@ -0,0 +1,442 @@
+package stixhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+)
+
+/********** 			Data Markings STIX (МЕТОДЫ)			**********/
+
+// -------- SpecVersion property ---------
+func (e *CommonDataMarkingsTypeSTIX) GetSpecVersion() string {
+	return e.SpecVersion
+}
+
+// SetValueSpecVersion устанавливает значение для поля SpecVersion
+func (e *CommonDataMarkingsTypeSTIX) SetValueSpecVersion(v string) {
+	e.SpecVersion = v
+}
+
+// SetAnySpecVersion устанавливает ЛЮБОЕ значение для поля SpecVersion
+func (e *CommonDataMarkingsTypeSTIX) SetAnySpecVersion(i interface{}) {
+	e.SpecVersion = fmt.Sprint(i)
+}
+
+// -------- ID property ---------
+
+// SetValueID устанавливает значение для поля ID
+func (e *CommonDataMarkingsTypeSTIX) SetValueID(v string) {
+	e.ID = v
+}
+
+// SetAnyID устанавливает ЛЮБОЕ значение для поля ID
+func (e *CommonDataMarkingsTypeSTIX) SetAnyID(i interface{}) {
+	e.ID = fmt.Sprint(i)
+}
+
+// -------- Created property ---------
+func (e *CommonDataMarkingsTypeSTIX) GetCreated() string {
+	return e.Created
+}
+
+// SetValueCreated устанавливает значение в формате RFC3339 для поля Created
+func (e *CommonDataMarkingsTypeSTIX) SetValueCreated(v string) error {
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return err
+	}
+
+	e.Created = v
+
+	return nil
+}
+
+// SetAnyCreated устанавливает ЛЮБОЕ значение для поля Created
+func (e *CommonDataMarkingsTypeSTIX) SetAnyCreated(i interface{}) {
+	tmp := commonlibs.ConversionAnyToInt(i)
+	e.Created = commonlibs.GetDateTimeFormatRFC3339(int64(tmp))
+}
+
+/* --- MarkingDefinitionObjectSTIX --- */
+
+// DecoderJSON выполняет декодирование JSON объекта
+func (e MarkingDefinitionObjectSTIX) DecodeJSON(raw *json.RawMessage) (interface{}, error) {
+	if err := json.Unmarshal(*raw, &e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// EncoderJSON выполняет кодирование в JSON объект
+func (e MarkingDefinitionObjectSTIX) EncodeJSON(interface{}) (*[]byte, error) {
+	result, err := json.Marshal(e)
+
+	return &result, err
+}
+
+// Get возвращает объект "marking-definition", по терминалогии STIX, содержащий метки данных.
+// Обязательные значения в полях DefinitionType и Definition, если маркер не задан через расширение
+func (e *MarkingDefinitionObjectSTIX) Get() (*MarkingDefinitionObjectSTIX, error) {
+	if e.GetDefinitionType() == "" && len(e.GetExtensions()) > 0 {
+		return e, nil
+	}
+
+	if e.GetDefinitionType() == "" {
+		err := fmt.Errorf("the required value 'DefinitionType' must not be empty")
+
+		return &MarkingDefinitionObjectSTIX{}, err
+	}
+
+	if len(e.GetDefinition()) == 0 {
+		err := fmt.Errorf("the required value 'Definition' must not be empty")
+
+		return &MarkingDefinitionObjectSTIX{}, err
+	}
+
+	return e, nil
+}
+
+// -------- Name property ---------
+func (e *MarkingDefinitionObjectSTIX) GetName() string {
+	return e.Name
+}
+
+// SetValueName устанавливает значение для поля Name
+func (e *MarkingDefinitionObjectSTIX) SetValueName(v string) {
+	e.Name = v
+}
+
+// SetAnyName устанавливает ЛЮБОЕ значение для поля Name
+func (e *MarkingDefinitionObjectSTIX) SetAnyName(i interface{}) {
+	e.Name = fmt.Sprint(i)
+}
+
+// -------- DefinitionType and Definition properties ---------
+func (e *MarkingDefinitionObjectSTIX) GetDefinitionType() string {
+	return e.DefinitionType
+}
+
+func (e *MarkingDefinitionObjectSTIX) GetDefinition() map[string]string {
+	return e.Definition
+}
+
+// GetStatement возвращает текст маркера типа "statement"
+func (e *MarkingDefinitionObjectSTIX) GetStatement() string {
+	if e.DefinitionType != DefinitionTypeStatement {
+		return ""
+	}
+
+	return e.Definition[DefinitionTypeStatement]
+}
+
+// SetValueStatement устанавливает маркер типа "statement", при этом поля DefinitionType
+// и Definition заполняются согласно спецификации
+func (e *MarkingDefinitionObjectSTIX) SetValueStatement(v string) {
+	e.DefinitionType = DefinitionTypeStatement
+	e.Definition = map[string]string{DefinitionTypeStatement: v}
+}
+
+// GetTLP возвращает уровень маркера типа "tlp" (TLP 1.0)
+func (e *MarkingDefinitionObjectSTIX) GetTLP() string {
+	if e.DefinitionType != DefinitionTypeTLP {
+		return ""
+	}
+
+	return e.Definition[DefinitionTypeTLP]
+}
+
+// SetValueTLP устанавливает маркер типа "tlp", значение должно быть одним из "white", "green", "amber", "red"
+func (e *MarkingDefinitionObjectSTIX) SetValueTLP(v string) error {
+	if _, ok := tlpMarkingDefinitionIDs[v]; !ok {
+		return fmt.Errorf("invalid TLP value '%s', expected one of 'white', 'green', 'amber', 'red'", v)
+	}
+
+	e.DefinitionType = DefinitionTypeTLP
+	e.Definition = map[string]string{DefinitionTypeTLP: v}
+
+	return nil
+}
+
+// GetTLP20 возвращает уровень маркера TLP 2.0 заданного через расширение TLP20ExtensionDefinitionID
+func (e *MarkingDefinitionObjectSTIX) GetTLP20() string {
+	ext, ok := e.Extensions[TLP20ExtensionDefinitionID]
+	if !ok {
+		return ""
+	}
+
+	switch v := ext.(type) {
+	case map[string]interface{}:
+		if tlp, ok := v["tlp_2_0"].(string); ok {
+			return tlp
+		}
+	case map[string]string:
+		return v["tlp_2_0"]
+	}
+
+	return ""
+}
+
+// GetTLPLevel возвращает уровень TLP маркера, как для TLP 1.0, так и для TLP 2.0
+func (e *MarkingDefinitionObjectSTIX) GetTLPLevel() TLPLevelSTIX {
+	if tlp := e.GetTLP(); tlp != "" {
+		return GetTLPLevelSTIX(tlp)
+	}
+
+	return GetTLPLevelSTIX(e.GetTLP20())
+}
+
+// -------- Extensions property ---------
+func (e *MarkingDefinitionObjectSTIX) GetExtensions() map[string]interface{} {
+	return e.Extensions
+}
+
+func (e *MarkingDefinitionObjectSTIX) SetValueExtensions(k string, i interface{}) {
+	if e.Extensions == nil {
+		e.Extensions = map[string]interface{}{}
+	}
+
+	e.Extensions[k] = i
+}
+
+// -------- CreatedByRef property ---------
+func (e *MarkingDefinitionObjectSTIX) GetCreatedByRef() IdentifierTypeSTIX {
+	return e.CreatedByRef
+}
+
+func (e *MarkingDefinitionObjectSTIX) SetValueCreatedByRef(v IdentifierTypeSTIX) {
+	e.CreatedByRef = v
+}
+
+// -------- ExternalReferences property ---------
+func (e *MarkingDefinitionObjectSTIX) GetExternalReferences() []ExternalReferenceTypeElementSTIX {
+	return e.ExternalReferences
+}
+
+func (e *MarkingDefinitionObjectSTIX) SetValueExternalReferences(v []ExternalReferenceTypeElementSTIX) {
+	e.ExternalReferences = v
+}
+
+// -------- ObjectMarkingRefs property ---------
+func (e *MarkingDefinitionObjectSTIX) GetObjectMarkingRefs() []IdentifierTypeSTIX {
+	return e.ObjectMarkingRefs
+}
+
+func (e *MarkingDefinitionObjectSTIX) SetValueObjectMarkingRefs(v []IdentifierTypeSTIX) {
+	e.ObjectMarkingRefs = v
+}
+
+// -------- GranularMarkings property ---------
+func (e *MarkingDefinitionObjectSTIX) GetGranularMarkings() []GranularMarkingsTypeSTIX {
+	return e.GranularMarkings
+}
+
+func (e *MarkingDefinitionObjectSTIX) SetValueGranularMarkings(v []GranularMarkingsTypeSTIX) {
+	e.GranularMarkings = v
+}
+
+// ValidateStruct является валидатором параметров содержащихся в типе MarkingDefinitionObjectSTIX.
+// Помимо общих полей проверяется соответствие содержимого Definition значению DefinitionType,
+// а для маркеров TLP 1.0 соответствие идентификатора объекта идентификатору определенному OASIS
+func (e MarkingDefinitionObjectSTIX) ValidateStruct() bool {
+	if e.Type != "marking-definition" {
+		return false
+	}
+
+	if !(regexp.MustCompile(`^(marking-definition--)[0-9a-f|-]+$`).MatchString(e.ID)) {
+		return false
+	}
+
+	if !(regexp.MustCompile(`^[0-9a-z.]+$`).MatchString(e.SpecVersion)) {
+		return false
+	}
+
+	if _, err := time.Parse(time.RFC3339, e.Created); err != nil {
+		return false
+	}
+
+	if !e.validateDefinition() {
+		return false
+	}
+
+	if !e.CreatedByRef.CheckIdentifierTypeSTIX() {
+		return false
+	}
+
+	for _, v := range e.ExternalReferences {
+		if !v.CheckExternalReferenceTypeElementSTIX() {
+			return false
+		}
+	}
+
+	for _, v := range e.ObjectMarkingRefs {
+		if !v.CheckIdentifierTypeSTIX() {
+			return false
+		}
+	}
+
+	for _, v := range e.GranularMarkings {
+		if !v.CheckGranularMarkingsTypeSTIX() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateDefinition проверяет соответствие содержимого Definition значению DefinitionType
+func (e MarkingDefinitionObjectSTIX) validateDefinition() bool {
+	switch e.DefinitionType {
+	case DefinitionTypeStatement:
+		if len(e.Definition) != 1 {
+			return false
+		}
+
+		return strings.TrimSpace(e.Definition[DefinitionTypeStatement]) != ""
+
+	case DefinitionTypeTLP:
+		if len(e.Definition) != 1 {
+			return false
+		}
+
+		id, ok := tlpMarkingDefinitionIDs[e.Definition[DefinitionTypeTLP]]
+		if !ok {
+			return false
+		}
+
+		return string(id) == e.ID
+
+	case "":
+		//маркер может быть задан только через расширение, например, TLP 2.0
+		if len(e.Definition) > 0 || len(e.Extensions) == 0 {
+			return false
+		}
+
+		if _, ok := e.Extensions[TLP20ExtensionDefinitionID]; !ok {
+			return true
+		}
+
+		id, ok := tlp20MarkingDefinitionIDs[e.GetTLP20()]
+		if !ok {
+			return false
+		}
+
+		return string(id) == e.ID
+	}
+
+	return false
+}
+
+// SanitizeStruct для ряда полей, выполняет замену некоторых специальных символов на их HTML код
+func (e MarkingDefinitionObjectSTIX) SanitizeStruct() MarkingDefinitionObjectSTIX {
+	e.Name = commonlibs.StringSanitize(e.Name)
+
+	if len(e.Definition) > 0 {
+		d := make(map[string]string, len(e.Definition))
+		for k, v := range e.Definition {
+			d[k] = commonlibs.StringSanitize(v)
+		}
+
+		e.Definition = d
+	}
+
+	if len(e.ExternalReferences) > 0 {
+		er := make([]ExternalReferenceTypeElementSTIX, 0, len(e.ExternalReferences))
+		for _, v := range e.ExternalReferences {
+			er = append(er, v.SanitizeStructExternalReferenceTypeElementSTIX())
+		}
+
+		e.ExternalReferences = er
+	}
+
+	return e
+}
+
+// GetID возвращает ID STIX объекта
+func (e MarkingDefinitionObjectSTIX) GetID() string {
+	return e.ID
+}
+
+// GetType возвращает Type STIX объекта
+func (e MarkingDefinitionObjectSTIX) GetType() string {
+	return e.Type
+}
+
+// ToStringBeautiful выполняет красивое представление информации содержащейся в типе
+func (e MarkingDefinitionObjectSTIX) ToStringBeautiful(num int) string {
+	str := strings.Builder{}
+	ws := commonlibs.GetWhitespace(num)
+
+	str.WriteString(fmt.Sprintf("%s'type': '%s'\n", ws, e.Type))
+	str.WriteString(fmt.Sprintf("%s'id': '%s'\n", ws, e.ID))
+	str.WriteString(fmt.Sprintf("%s'spec_version': '%s'\n", ws, e.SpecVersion))
+	str.WriteString(fmt.Sprintf("%s'created': '%v'\n", ws, e.Created))
+	str.WriteString(fmt.Sprintf("%s'created_by_ref': '%v'\n", ws, e.CreatedByRef))
+	str.WriteString(fmt.Sprintf("%s'name': '%s'\n", ws, e.Name))
+	str.WriteString(fmt.Sprintf("%s'definition_type': '%s'\n", ws, e.DefinitionType))
+	str.WriteString(fmt.Sprintf("%s'definition': \n%v", ws, func(l map[string]string, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'%s': '%s'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.Definition, num+1)))
+	str.WriteString(fmt.Sprintf("%s'external_references': \n%v", ws, func(l []ExternalReferenceTypeElementSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+		dubleWs := commonlibs.GetWhitespace(num + 1)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'external_references element '%d'':\n", ws, k))
+			str.WriteString(fmt.Sprintf("%s'source_name': '%s'\n", dubleWs, v.SourceName))
+			str.WriteString(fmt.Sprintf("%s'description': '%s'\n", dubleWs, v.Description))
+			str.WriteString(fmt.Sprintf("%s'url': '%s'\n", dubleWs, v.URL))
+			str.WriteString(fmt.Sprintf("%s'hashes': '%s'\n", dubleWs, v.Hashes))
+			str.WriteString(fmt.Sprintf("%s'external_id': '%s'\n", dubleWs, v.ExternalID))
+		}
+
+		return str.String()
+	}(e.ExternalReferences, num+1)))
+	str.WriteString(fmt.Sprintf("%s'object_marking_refs': \n%v", ws, func(l []IdentifierTypeSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'ref '%d'': '%v'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.ObjectMarkingRefs, num+1)))
+	str.WriteString(fmt.Sprintf("%s'granular_markings': \n%v", ws, func(l []GranularMarkingsTypeSTIX, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'granular_markings number %d.'\n", ws, k))
+			str.WriteString(fmt.Sprintf("%s'lang': '%s'\n", ws, v.Lang))
+			str.WriteString(fmt.Sprintf("%s'marking_ref': '%v'\n", ws, v.MarkingRef))
+			str.WriteString(fmt.Sprintf("%s'selectors': '%v'\n", ws, v.Selectors))
+		}
+
+		return str.String()
+	}(e.GranularMarkings, num+1)))
+	str.WriteString(fmt.Sprintf("%s'extensions': \n%v", ws, func(l map[string]interface{}, num int) string {
+		str := strings.Builder{}
+		ws := commonlibs.GetWhitespace(num)
+
+		for k, v := range l {
+			str.WriteString(fmt.Sprintf("%s'%s': '%v'\n", ws, k, v))
+		}
+
+		return str.String()
+	}(e.Extensions, num+1)))
+
+	return str.String()
+}
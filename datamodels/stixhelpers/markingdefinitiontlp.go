@@ -0,0 +1,89 @@
+package stixhelpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+/********** 			Предопределенные маркеры Traffic Light Protocol (TLP) 1.0			**********/
+
+const (
+	// DefinitionTypeStatement тип маркера содержащего текстовое утверждение, например, об авторских правах
+	DefinitionTypeStatement string = "statement"
+	// DefinitionTypeTLP тип маркера Traffic Light Protocol версии 1.0
+	DefinitionTypeTLP string = "tlp"
+)
+
+// Идентификаторы маркеров TLP 1.0 определенные спецификацией STIX 2.1, данные
+// идентификаторы ДОЛЖНЫ использоваться для ссылок на соответствующие маркеры
+const (
+	TLPWhiteMarkingDefinitionID IdentifierTypeSTIX = "marking-definition--613f2e26-407d-48c7-9eca-b8e91df99dc9"
+	TLPGreenMarkingDefinitionID IdentifierTypeSTIX = "marking-definition--34098fce-860f-48ae-8e50-ebd3cc5e41da"
+	TLPAmberMarkingDefinitionID IdentifierTypeSTIX = "marking-definition--f88d31f6-486f-44da-b317-01333bde0b82"
+	TLPRedMarkingDefinitionID   IdentifierTypeSTIX = "marking-definition--5e57c739-391a-4eb3-b6be-7d15ca92d5ed"
+)
+
+// tlpMarkingDefinitionCreated время создания маркеров TLP 1.0 определенное спецификацией STIX 2.1
+const tlpMarkingDefinitionCreated string = "2017-01-20T00:00:00.000Z"
+
+// tlpMarkingDefinitionIDs соответствие уровней TLP 1.0 идентификаторам маркеров
+var tlpMarkingDefinitionIDs = map[string]IdentifierTypeSTIX{
+	"white": TLPWhiteMarkingDefinitionID,
+	"green": TLPGreenMarkingDefinitionID,
+	"amber": TLPAmberMarkingDefinitionID,
+	"red":   TLPRedMarkingDefinitionID,
+}
+
+// GetTLPMarkingDefinitionSTIX возвращает предопределенный объект "marking-definition" для уровня TLP 1.0,
+// уровень должен быть одним из "white", "green", "amber", "red"
+func GetTLPMarkingDefinitionSTIX(tlp string) (MarkingDefinitionObjectSTIX, error) {
+	id, ok := tlpMarkingDefinitionIDs[tlp]
+	if !ok {
+		return MarkingDefinitionObjectSTIX{}, fmt.Errorf("invalid TLP value '%s', expected one of 'white', 'green', 'amber', 'red'", tlp)
+	}
+
+	return MarkingDefinitionObjectSTIX{
+		CommonDataMarkingsTypeSTIX: CommonDataMarkingsTypeSTIX{
+			SpecVersion: "2.1",
+			ID:          string(id),
+			Created:     tlpMarkingDefinitionCreated,
+		},
+		Type:           "marking-definition",
+		Name:           fmt.Sprintf("TLP:%s", strings.ToUpper(tlp)),
+		DefinitionType: DefinitionTypeTLP,
+		Definition:     map[string]string{DefinitionTypeTLP: tlp},
+	}, nil
+}
+
+// GetTLPMarkingDefinitionByIDSTIX возвращает предопределенный объект "marking-definition" TLP 1.0
+// по его идентификатору
+func GetTLPMarkingDefinitionByIDSTIX(id IdentifierTypeSTIX) (MarkingDefinitionObjectSTIX, bool) {
+	for tlp, v := range tlpMarkingDefinitionIDs {
+		if v == id {
+			md, err := GetTLPMarkingDefinitionSTIX(tlp)
+
+			return md, err == nil
+		}
+	}
+
+	return MarkingDefinitionObjectSTIX{}, false
+}
+
+// IsTLPMarkingDefinitionID проверяет является ли идентификатор идентификатором маркера TLP 1.0
+func IsTLPMarkingDefinitionID(id IdentifierTypeSTIX) bool {
+	_, ok := GetTLPMarkingDefinitionByIDSTIX(id)
+
+	return ok
+}
+
+// GetTLPMarkingDefinitionsSTIX возвращает список всех предопределенных маркеров TLP 1.0
+// в порядке возрастания ограничений
+func GetTLPMarkingDefinitionsSTIX() []MarkingDefinitionObjectSTIX {
+	list := make([]MarkingDefinitionObjectSTIX, 0, len(tlpMarkingDefinitionIDs))
+	for _, tlp := range []string{"white", "green", "amber", "red"} {
+		md, _ := GetTLPMarkingDefinitionSTIX(tlp)
+		list = append(list, md)
+	}
+
+	return list
+}
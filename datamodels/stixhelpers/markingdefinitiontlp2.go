@@ -0,0 +1,217 @@
+package stixhelpers
+
+import (
+	"fmt"
+	"strings"
+)
+
+/********** 			Предопределенные маркеры Traffic Light Protocol (TLP) 2.0			**********/
+
+// TLP20ExtensionDefinitionID идентификатор объекта "extension-definition" опубликованного OASIS, посредством
+// которого в STIX 2.1 выражаются маркеры TLP 2.0
+const TLP20ExtensionDefinitionID string = "extension-definition--60a3c5c5-0d10-413e-aab3-9e08dde9e88d"
+
+// Идентификаторы маркеров TLP 2.0 определенные OASIS
+const (
+	TLP20ClearMarkingDefinitionID       IdentifierTypeSTIX = "marking-definition--94868c89-83c2-464b-929b-a1a8aa3c8487"
+	TLP20GreenMarkingDefinitionID       IdentifierTypeSTIX = "marking-definition--bab4a63c-aed9-4cf5-a766-dfca5abac2bb"
+	TLP20AmberMarkingDefinitionID       IdentifierTypeSTIX = "marking-definition--55d920b0-5e8b-4f79-9ee9-91f868d9b421"
+	TLP20AmberStrictMarkingDefinitionID IdentifierTypeSTIX = "marking-definition--939a9414-2ddd-4d32-a0cd-375ea402b003"
+	TLP20RedMarkingDefinitionID         IdentifierTypeSTIX = "marking-definition--e828b379-4e03-4974-9ac4-e53a884c97c1"
+)
+
+// tlp20MarkingDefinitionCreated время создания маркеров TLP 2.0 определенное OASIS
+const tlp20MarkingDefinitionCreated string = "2022-10-01T00:00:00.000Z"
+
+// TLP20MarkingDefinitionCreatedByRef идентификатор объекта "identity" (FIRST), указанный OASIS в качестве
+// создателя маркеров TLP 2.0
+const TLP20MarkingDefinitionCreatedByRef IdentifierTypeSTIX = "identity--b3bca3c2-1f3d-4b54-b44f-dac42c3a8f01"
+
+// tlp20MarkingDefinitionIDs соответствие уровней TLP 2.0 идентификаторам маркеров
+var tlp20MarkingDefinitionIDs = map[string]IdentifierTypeSTIX{
+	"clear":        TLP20ClearMarkingDefinitionID,
+	"green":        TLP20GreenMarkingDefinitionID,
+	"amber":        TLP20AmberMarkingDefinitionID,
+	"amber+strict": TLP20AmberStrictMarkingDefinitionID,
+	"red":          TLP20RedMarkingDefinitionID,
+}
+
+// TLPLevelSTIX уровень Traffic Light Protocol, значения упорядочены по возрастанию ограничений.
+// Уровни TLP 1.0 и TLP 2.0 приводятся к общей шкале, при этом TLP:WHITE соответствует TLP:CLEAR
+type TLPLevelSTIX int
+
+const (
+	TLPLevelUndefined TLPLevelSTIX = iota
+	TLPLevelClear
+	TLPLevelGreen
+	TLPLevelAmber
+	TLPLevelAmberStrict
+	TLPLevelRed
+)
+
+// String возвращает наименование уровня в терминах TLP 2.0
+func (l TLPLevelSTIX) String() string {
+	switch l {
+	case TLPLevelClear:
+		return "clear"
+	case TLPLevelGreen:
+		return "green"
+	case TLPLevelAmber:
+		return "amber"
+	case TLPLevelAmberStrict:
+		return "amber+strict"
+	case TLPLevelRed:
+		return "red"
+	}
+
+	return ""
+}
+
+// GetTLPLevelSTIX возвращает уровень TLP по его наименованию, допускаются наименования как TLP 1.0,
+// так и TLP 2.0, в том числе с префиксом "TLP:", например, "TLP:AMBER+STRICT"
+func GetTLPLevelSTIX(name string) TLPLevelSTIX {
+	switch strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "tlp:") {
+	case "white", "clear":
+		return TLPLevelClear
+	case "green":
+		return TLPLevelGreen
+	case "amber":
+		return TLPLevelAmber
+	case "amber+strict":
+		return TLPLevelAmberStrict
+	case "red":
+		return TLPLevelRed
+	}
+
+	return TLPLevelUndefined
+}
+
+// GetTLP20MarkingDefinitionSTIX возвращает предопределенный объект "marking-definition" для уровня TLP 2.0,
+// уровень должен быть одним из "clear", "green", "amber", "amber+strict", "red"
+func GetTLP20MarkingDefinitionSTIX(tlp string) (MarkingDefinitionObjectSTIX, error) {
+	id, ok := tlp20MarkingDefinitionIDs[tlp]
+	if !ok {
+		return MarkingDefinitionObjectSTIX{}, fmt.Errorf("invalid TLP 2.0 value '%s', expected one of 'clear', 'green', 'amber', 'amber+strict', 'red'", tlp)
+	}
+
+	return MarkingDefinitionObjectSTIX{
+		CommonDataMarkingsTypeSTIX: CommonDataMarkingsTypeSTIX{
+			SpecVersion: "2.1",
+			ID:          string(id),
+			Created:     tlp20MarkingDefinitionCreated,
+		},
+		Type:         "marking-definition",
+		Name:         fmt.Sprintf("TLP:%s", strings.ToUpper(tlp)),
+		CreatedByRef: TLP20MarkingDefinitionCreatedByRef,
+		Extensions: map[string]interface{}{
+			TLP20ExtensionDefinitionID: map[string]interface{}{
+				"extension_type": "property-extension",
+				"tlp_2_0":        tlp,
+			},
+		},
+	}, nil
+}
+
+// GetTLP20MarkingDefinitionByIDSTIX возвращает предопределенный объект "marking-definition" TLP 2.0
+// по его идентификатору
+func GetTLP20MarkingDefinitionByIDSTIX(id IdentifierTypeSTIX) (MarkingDefinitionObjectSTIX, bool) {
+	for tlp, v := range tlp20MarkingDefinitionIDs {
+		if v == id {
+			md, err := GetTLP20MarkingDefinitionSTIX(tlp)
+
+			return md, err == nil
+		}
+	}
+
+	return MarkingDefinitionObjectSTIX{}, false
+}
+
+// IsTLP20MarkingDefinitionID проверяет является ли идентификатор идентификатором маркера TLP 2.0
+func IsTLP20MarkingDefinitionID(id IdentifierTypeSTIX) bool {
+	_, ok := GetTLP20MarkingDefinitionByIDSTIX(id)
+
+	return ok
+}
+
+// GetTLP20MarkingDefinitionsSTIX возвращает список всех предопределенных маркеров TLP 2.0
+// в порядке возрастания ограничений
+func GetTLP20MarkingDefinitionsSTIX() []MarkingDefinitionObjectSTIX {
+	list := make([]MarkingDefinitionObjectSTIX, 0, len(tlp20MarkingDefinitionIDs))
+	for _, tlp := range []string{"clear", "green", "amber", "amber+strict", "red"} {
+		md, _ := GetTLP20MarkingDefinitionSTIX(tlp)
+		list = append(list, md)
+	}
+
+	return list
+}
+
+// GetTLPLevelByMarkingID возвращает уровень TLP для идентификатора предопределенного маркера TLP 1.0
+// или TLP 2.0. Для прочих идентификаторов возвращается TLPLevelUndefined
+func GetTLPLevelByMarkingID(id IdentifierTypeSTIX) TLPLevelSTIX {
+	for tlp, v := range tlpMarkingDefinitionIDs {
+		if v == id {
+			return GetTLPLevelSTIX(tlp)
+		}
+	}
+
+	for tlp, v := range tlp20MarkingDefinitionIDs {
+		if v == id {
+			return GetTLPLevelSTIX(tlp)
+		}
+	}
+
+	return TLPLevelUndefined
+}
+
+// GetMostRestrictiveTLPLevel возвращает наиболее ограничивающий уровень TLP из списка ссылок на
+// объекты "marking-definition", например, из свойства ObjectMarkingRefs. Если ни одна из ссылок не
+// является маркером TLP возвращается TLPLevelUndefined
+func GetMostRestrictiveTLPLevel(refs []IdentifierTypeSTIX) TLPLevelSTIX {
+	level := TLPLevelUndefined
+	for _, v := range refs {
+		if l := GetTLPLevelByMarkingID(v); l > level {
+			level = l
+		}
+	}
+
+	return level
+}
+
+// ConvertTLP10ToTLP20 возвращает идентификатор маркера TLP 2.0 соответствующий маркеру TLP 1.0,
+// TLP:WHITE преобразуется в TLP:CLEAR, остальные уровни сохраняют свое наименование
+func ConvertTLP10ToTLP20(id IdentifierTypeSTIX) (IdentifierTypeSTIX, bool) {
+	for tlp, v := range tlpMarkingDefinitionIDs {
+		if v != id {
+			continue
+		}
+
+		if tlp == "white" {
+			tlp = "clear"
+		}
+
+		return tlp20MarkingDefinitionIDs[tlp], true
+	}
+
+	return "", false
+}
+
+// ConvertTLP20ToTLP10 возвращает идентификатор маркера TLP 1.0 соответствующий маркеру TLP 2.0,
+// TLP:CLEAR преобразуется в TLP:WHITE, а TLP:AMBER+STRICT, не имеющий аналога в TLP 1.0, в TLP:AMBER
+func ConvertTLP20ToTLP10(id IdentifierTypeSTIX) (IdentifierTypeSTIX, bool) {
+	for tlp, v := range tlp20MarkingDefinitionIDs {
+		if v != id {
+			continue
+		}
+
+		switch tlp {
+		case "clear":
+			tlp = "white"
+		case "amber+strict":
+			tlp = "amber"
+		}
+
+		return tlpMarkingDefinitionIDs[tlp], true
+	}
+
+	return "", false
+}
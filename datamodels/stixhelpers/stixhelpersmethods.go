@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/av-belyakov/methodstixobjects/commonlibs"
@@ -146,9 +148,11 @@ func (dtstix *DictionaryTypeSTIX) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("JSON message parsing error, undefined value found in the DictionaryTypeSTIX type")
 }
 
+// CheckGranularMarkingsTypeSTIX выполняет проверку полей типа GranularMarkingsTypeSTIX. Проверка того, что
+// селекторы указывают на существующие свойства объекта, выполняется пакетом granularmarkings
 func (gmtstix *GranularMarkingsTypeSTIX) CheckGranularMarkingsTypeSTIX() bool {
-	//для поля Lang
-	if gmtstix.Lang != "" && !(regexp.MustCompile(`^[a-zA-Z]+$`)).MatchString(gmtstix.Lang) {
+	//для поля Lang, тег языка RFC 5646
+	if gmtstix.Lang != "" && !commonlibs.IsLanguageTag(gmtstix.Lang) {
 		return false
 	}
 
@@ -156,11 +160,61 @@ func (gmtstix *GranularMarkingsTypeSTIX) CheckGranularMarkingsTypeSTIX() bool {
 		return false
 	}
 
+	//должен быть указан хотя бы один селектор
+	if len(gmtstix.Selectors) == 0 {
+		return false
+	}
+
 	selectorTmp := make([]string, 0, len(gmtstix.Selectors))
 	for _, v := range gmtstix.Selectors {
+		if v == "" || strings.Contains(v, "..") {
+			return false
+		}
+
 		selectorTmp = append(selectorTmp, commonlibs.StringSanitize(v))
 	}
 	gmtstix.Selectors = selectorTmp
 
 	return true
 }
+
+/**********			 Данные для индексации			 **********/
+
+// JoinForIndexing объединяет непустые значения списка через пробел
+func JoinForIndexing[T ~string](list []T) string {
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		if v != "" {
+			values = append(values, string(v))
+		}
+	}
+
+	return strings.Join(values, " ")
+}
+
+// GetValuesForIndexing возвращает значения хешей через пробел, упорядоченные по наименованию типа хеша
+func (htstix HashesTypeSTIX) GetValuesForIndexing() string {
+	keys := make([]string, 0, len(htstix))
+	for k := range htstix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, htstix[k])
+	}
+
+	return JoinForIndexing(values)
+}
+
+// GetExternalIDsForIndexing возвращает внешние идентификаторы (например, CVE или идентификаторы MITRE ATT&CK)
+// списка внешних ссылок через пробел
+func GetExternalIDsForIndexing(list []ExternalReferenceTypeElementSTIX) string {
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		values = append(values, v.ExternalID)
+	}
+
+	return JoinForIndexing(values)
+}
@@ -1,7 +1,5 @@
 package stixhelpers
 
-import "time"
-
 /**********			 Некоторые примитивные типы STIX			 **********/
 
 // EnumTypeSTIX тип "enum", по терминологии STIX, является жестко заданным списком терминов, который представлен в виде строки
@@ -47,6 +45,12 @@ type DictionaryTypeSTIX struct {
 	Dictionary interface{}
 }
 
+// ExtensionsTypeSTIX тип "extensions", по терминалогии STIX, содержащий расширения объекта, где ключ это идентификатор
+// объекта "extension-definition", а значение это содержимое расширения. При декодировании JSON содержимое расширений,
+// зарегистрированных с помощью RegisterExtensionSTIX, преобразуется в значения соответствующих им типов Go,
+// содержимое прочих расширений сохраняется в виде map[string]interface{}
+type ExtensionsTypeSTIX map[string]interface{}
+
 /********** 			Meta Object STIX 			**********/
 
 /***	 			Language Content STIX 				***/
@@ -58,9 +62,10 @@ type DictionaryTypeSTIX struct {
 // SpecVersion - версия спецификации STIX используемая для представления текущего объекта (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
 // Created - время создания объекта, в формате "2016-05-12T08:17:27.000Z" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
 // Modified - время создания объекта, в формате "2016-05-12T08:17:27.000Z" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
-// ObjectRef - определяет идентификатор объекта "language content" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// ObjectRef - определяет идентификатор объекта к которому относится перевод (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
 // ObjectModified - время изменения объекта, к которому применяется данное значение, в формате "2016-05-12T08:17:27.000Z"
-// Contents - (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Contents - переводы свойств объекта, где ключ это языковой тег по RFC 5646, например, "ru" или "en-US", а значение это
+// словарь содержащий переведенные свойства объекта (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
 // CreatedByRef - содержит идентификатор источника создавшего данный объект
 // Revoked - вернуть к текущему состоянию
 // Labels - определяет набор терминов, используемых для описания данного объекта
@@ -75,18 +80,18 @@ type LanguageContentTypeSTIX struct {
 	Type               string                             `json:"type" bson:"type" required:"true"`
 	ID                 string                             `json:"id" bson:"id" required:"true"`
 	SpecVersion        string                             `json:"spec_version" bson:"spec_version" required:"true"`
-	Created            time.Time                          `json:"created" bson:"created" required:"true"`
-	Modified           time.Time                          `json:"modified" bson:"modified" required:"true"`
+	Created            string                             `json:"created" bson:"created" required:"true" timestamp:"true"`
+	Modified           string                             `json:"modified" bson:"modified" required:"true" timestamp:"true"`
 	ObjectRef          IdentifierTypeSTIX                 `json:"object_ref" bson:"object_ref" required:"true"`
-	ObjectModified     time.Time                          `json:"object_modified" bson:"object_modified"`
-	Contents           map[string]string                  `json:"contents" bson:"contents" required:"true"`
+	ObjectModified     string                             `json:"object_modified" bson:"object_modified" timestamp:"true"`
+	Contents           map[string]map[string]interface{}  `json:"contents" bson:"contents" required:"true"`
 	CreatedByRef       IdentifierTypeSTIX                 `json:"created_by_ref" bson:"created_by_ref"`
 	Revoked            bool                               `json:"revoked" bson:"revoked"`
 	Labels             []string                           `json:"labels" bson:"labels"`
 	Сonfidence         int                                `json:"confidence" bson:"confidence"`
 	ExternalReferences []ExternalReferenceTypeElementSTIX `json:"external_references" bson:"external_references"`
 	ObjectMarkingRefs  []IdentifierTypeSTIX               `json:"object_marking_refs" bson:"object_marking_refs"`
-	GranularMarkings   GranularMarkingsTypeSTIX           `json:"granular_markings" bson:"granular_markings"`
+	GranularMarkings   []GranularMarkingsTypeSTIX         `json:"granular_markings" bson:"granular_markings"`
 }
 
 /***	 			Data Markings STIX 				***/
@@ -96,9 +101,9 @@ type LanguageContentTypeSTIX struct {
 // ID - уникальный идентификатор объекта (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
 // Created - время создания объекта, в формате "2016-05-12T08:17:27.000Z" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
 type CommonDataMarkingsTypeSTIX struct {
-	SpecVersion string    `json:"spec_version" bson:"spec_version" required:"true"`
-	ID          string    `json:"id" bson:"id" required:"true"`
-	Created     time.Time `json:"created" bson:"created" required:"true"`
+	SpecVersion string `json:"spec_version" bson:"spec_version" required:"true"`
+	ID          string `json:"id" bson:"id" required:"true"`
+	Created     string `json:"created" bson:"created" required:"true" timestamp:"true"`
 }
 
 // GranularMarkingsTypeSTIX тип "granular_markings", по терминалогии STIX, представляет собой набор маркеров ссылающихся на свойства "marking_ref" и "lang"
@@ -127,6 +132,7 @@ type GranularMarkingsTypeSTIX struct {
 //	хотя оно и является списком типа IdentifierTypeSTIX, но тот в свою очередь ССЫЛАЕТСЯ на объект типа MarkingDefinitionObjectSTIX (marking-definition)
 //
 // GranularMarkings - определяет список "гранулярных меток" (granular_markings) относящихся к этому объекту
+// Extensions - может содержать расширения, например, расширение TLP 2.0 (в этом случае DefinitionType и Definition не заполняются)
 type MarkingDefinitionObjectSTIX struct {
 	CommonDataMarkingsTypeSTIX
 	Type               string                             `json:"type" bson:"type" required:"true"`
@@ -136,7 +142,50 @@ type MarkingDefinitionObjectSTIX struct {
 	CreatedByRef       IdentifierTypeSTIX                 `json:"created_by_ref" bson:"created_by_ref"`
 	ExternalReferences []ExternalReferenceTypeElementSTIX `json:"external_references" bson:"external_references"`
 	ObjectMarkingRefs  []IdentifierTypeSTIX               `json:"object_marking_refs" bson:"object_marking_refs"`
-	GranularMarkings   GranularMarkingsTypeSTIX           `json:"granular_markings" bson:"granular_markings"`
+	GranularMarkings   []GranularMarkingsTypeSTIX         `json:"granular_markings" bson:"granular_markings"`
+	Extensions         ExtensionsTypeSTIX                 `json:"extensions" bson:"extensions"`
+}
+
+/***	 			Extension Definition STIX 				***/
+
+// ExtensionDefinitionObjectSTIX объект "extension-definition", по терминалогии STIX, описывает расширение спецификации STIX,
+// например, новый тип объекта или дополнительные свойства для уже существующих объектов
+// Type - наименование типа объекта, для этого типа это поле ДОЛЖНО содержать "extension-definition" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// ID - уникальный идентификатор объекта (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// SpecVersion - версия спецификации STIX используемая для представления текущего объекта (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Created - время создания объекта, в формате "2016-05-12T08:17:27.000Z" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Modified - время модификации объекта, в формате "2016-05-12T08:17:27.000Z" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// CreatedByRef - содержит идентификатор источника создавшего данный объект (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Revoked - вернуть к текущему состоянию
+// Labels - определяет набор терминов, используемых для описания данного объекта
+// ExternalReferences - список внешних ссылок не относящихся к STIX информации
+// ObjectMarkingRefs - определяет список ID ссылающиеся на объект "marking-definition"
+// GranularMarkings - определяет список "гранулярных меток" (granular_markings) относящихся к этому объекту
+// Name - наименование расширения (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Description - подробное описание расширения
+// Schema - схема расширения, как правило URL JSON схемы или ее текстовое описание (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// Version - версия расширения в формате major.minor.patch (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// ExtensionTypes - список типов расширения, значения "new-sdo", "new-sco", "new-sro", "property-extension",
+// "toplevel-property-extension" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// ExtensionProperties - список свойств добавляемых на верхний уровень объекта, только для типа "toplevel-property-extension"
+type ExtensionDefinitionObjectSTIX struct {
+	Type                string                             `json:"type" bson:"type" required:"true"`
+	ID                  string                             `json:"id" bson:"id" required:"true"`
+	SpecVersion         string                             `json:"spec_version" bson:"spec_version" required:"true"`
+	Created             string                             `json:"created" bson:"created" required:"true" timestamp:"true"`
+	Modified            string                             `json:"modified" bson:"modified" required:"true" timestamp:"true"`
+	CreatedByRef        IdentifierTypeSTIX                 `json:"created_by_ref" bson:"created_by_ref" required:"true"`
+	Revoked             bool                               `json:"revoked" bson:"revoked"`
+	Labels              []string                           `json:"labels" bson:"labels"`
+	ExternalReferences  []ExternalReferenceTypeElementSTIX `json:"external_references" bson:"external_references"`
+	ObjectMarkingRefs   []IdentifierTypeSTIX               `json:"object_marking_refs" bson:"object_marking_refs"`
+	GranularMarkings    []GranularMarkingsTypeSTIX         `json:"granular_markings" bson:"granular_markings"`
+	Name                string                             `json:"name" bson:"name" required:"true"`
+	Description         string                             `json:"description" bson:"description"`
+	Schema              string                             `json:"schema" bson:"schema" required:"true"`
+	Version             string                             `json:"version" bson:"version" required:"true"`
+	ExtensionTypes      []EnumTypeSTIX                     `json:"extension_types" bson:"extension_types" required:"true"`
+	ExtensionProperties []string                           `json:"extension_properties" bson:"extension_properties"`
 }
 
 /********** 			Bundle Object STIX 			**********/
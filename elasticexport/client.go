@@ -0,0 +1,199 @@
+package elasticexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ClientOptions параметры клиента
+// HTTPClient - клиент HTTP, по умолчанию http.DefaultClient
+// Username, Password - учетные данные для аутентификации Basic, если заданы
+type ClientOptions struct {
+	Options
+	HTTPClient *http.Client
+	Username   string
+	Password   string
+}
+
+// Client клиент Elasticsearch для загрузки шаблонов индексов и объектов STIX
+type Client struct {
+	url  string
+	opts ClientOptions
+}
+
+// BulkItem результат индексации одного документа
+type BulkItem struct {
+	Index  string
+	ID     string
+	Status int
+	Error  string
+}
+
+// BulkResult результат выполнения запроса _bulk
+type BulkResult struct {
+	Items []BulkItem
+}
+
+// GetFailed возвращает документы, которые не удалось проиндексировать
+func (r BulkResult) GetFailed() []BulkItem {
+	list := []BulkItem{}
+	for _, v := range r.Items {
+		if v.Status < 200 || v.Status > 299 {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// NewClient создает клиент Elasticsearch с адресом baseURL (например, "http://localhost:9200")
+func NewClient(baseURL string, opts ClientOptions) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("incorrect Elasticsearch URL '%s'", baseURL)
+	}
+
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	return &Client{url: strings.TrimRight(baseURL, "/"), opts: opts}, nil
+}
+
+// PutIndexTemplates создает или заменяет шаблоны индексов для всех предопределенных типов объектов STIX
+func (c *Client) PutIndexTemplates(ctx context.Context) error {
+	templates := GetIndexTemplates(c.opts.Options)
+
+	names := make([]string, 0, len(templates))
+	for k := range templates {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, err := json.Marshal(templates[name])
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.do(ctx, http.MethodPut, "/_index_template/"+url.PathEscape(name), "application/json", b); err != nil {
+			return fmt.Errorf("index template '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Bulk индексирует объекты с помощью запроса _bulk. Если часть документов не удалось проиндексировать,
+// возвращается результат и ошибка, сведения о документах можно получить с помощью BulkResult.GetFailed
+func (c *Client) Bulk(ctx context.Context, objects []interface{}) (BulkResult, error) {
+	result := BulkResult{Items: []BulkItem{}}
+	if len(objects) == 0 {
+		return result, nil
+	}
+
+	buf := bytes.Buffer{}
+	if err := WriteBulk(&buf, objects, c.opts.Options); err != nil {
+		return result, err
+	}
+
+	b, err := c.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", buf.Bytes())
+	if err != nil {
+		return result, err
+	}
+
+	response := struct {
+		Items []map[string]struct {
+			Index  string          `json:"_index"`
+			ID     string          `json:"_id"`
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return result, fmt.Errorf("incorrect response to the bulk request: %w", err)
+	}
+
+	for _, item := range response.Items {
+		for _, v := range item {
+			bi := BulkItem{Index: v.Index, ID: v.ID, Status: v.Status}
+			if len(v.Error) > 0 && string(v.Error) != "null" {
+				bi.Error = getErrorReason(v.Error)
+			}
+
+			result.Items = append(result.Items, bi)
+		}
+	}
+
+	if failed := result.GetFailed(); len(failed) > 0 {
+		return result, fmt.Errorf("%d of %d documents were not indexed, the first error for '%s': %s", len(failed), len(result.Items), failed[0].ID, failed[0].Error)
+	}
+
+	return result, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	if c.opts.Username != "" || c.opts.Password != "" {
+		req.SetBasicAuth(c.opts.Username, c.opts.Password)
+	}
+
+	res, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("%s %s: status %d: %s", method, path, res.StatusCode, getErrorReason(b))
+	}
+
+	return b, nil
+}
+
+// getErrorReason возвращает описание ошибки из ответа Elasticsearch
+func getErrorReason(b []byte) string {
+	e := struct {
+		Error  json.RawMessage `json:"error"`
+		Type   string          `json:"type"`
+		Reason string          `json:"reason"`
+	}{}
+	if err := json.Unmarshal(b, &e); err != nil {
+		return strings.TrimSpace(string(b))
+	}
+
+	if len(e.Error) > 0 {
+		return getErrorReason(e.Error)
+	}
+
+	switch {
+	case e.Type != "" && e.Reason != "":
+		return e.Type + ": " + e.Reason
+	case e.Type != "" || e.Reason != "":
+		return e.Type + e.Reason
+	}
+
+	return strings.TrimSpace(string(b))
+}
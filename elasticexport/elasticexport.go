@@ -0,0 +1,128 @@
+// Package elasticexport экспорт объектов STIX в Elasticsearch. Объекты записываются в формате NDJSON запроса
+// _bulk в индексы, соответствующие их типам, шаблоны индексов формируются на основании определений типов Go
+package elasticexport
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+)
+
+// DefaultIndexPrefix префикс наименований индексов по умолчанию
+const DefaultIndexPrefix = "stix-"
+
+// zeroTime значение time.Time по умолчанию
+const zeroTime = "0001-01-01T00:00:00Z"
+
+// Options параметры экспорта
+// IndexPrefix - префикс наименований индексов, по умолчанию DefaultIndexPrefix
+type Options struct {
+	IndexPrefix string
+}
+
+// GetIndexName возвращает наименование индекса для объектов типа objType
+func GetIndexName(objType string, opts Options) string {
+	if opts.IndexPrefix == "" {
+		return DefaultIndexPrefix + objType
+	}
+
+	return opts.IndexPrefix + objType
+}
+
+// GetDocumentID возвращает идентификатор документа, состоящий из идентификатора объекта и времени его изменения
+// в UTC, поэтому каждая версия объекта сохраняется в отдельный документ. Для объектов без времени изменения
+// (например, SCO) используется идентификатор объекта
+func GetDocumentID(info stixstore.ObjectInfo) string {
+	if info.Modified == "" {
+		return info.ID
+	}
+
+	modified := info.Modified
+	if t, err := commonlibs.ParseTimestamp(modified); err == nil {
+		modified = t.UTC().Format(time.RFC3339Nano)
+	}
+
+	return info.ID + "_" + modified
+}
+
+// WriteBulk записывает объекты (типы данного пакета или json.RawMessage) в w в формате NDJSON запроса _bulk.
+// Пустые значения свойств и значения времени по умолчанию в документ не включаются
+func WriteBulk(w io.Writer, objects []interface{}, opts Options) error {
+	bw := bufio.NewWriter(w)
+	for _, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return err
+		}
+
+		info, err := stixstore.GetObjectInfo(m)
+		if err != nil {
+			return err
+		}
+
+		action, err := json.Marshal(map[string]interface{}{
+			"index": map[string]string{"_index": GetIndexName(info.Type, opts), "_id": GetDocumentID(info)},
+		})
+		if err != nil {
+			return err
+		}
+
+		doc, err := json.Marshal(removeEmpty(m))
+		if err != nil {
+			return err
+		}
+
+		for _, b := range [][]byte{action, doc} {
+			bw.Write(b)
+			bw.WriteByte('\n')
+		}
+	}
+
+	return bw.Flush()
+}
+
+// removeEmpty удаляет пустые значения (пустые строки, списки и объекты) и значения времени по умолчанию,
+// которые типы данного пакета содержат для отсутствующих свойств
+func removeEmpty(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if v = removeEmptyValue(v); v == nil {
+			delete(m, k)
+		} else {
+			m[k] = v
+		}
+	}
+
+	return m
+}
+
+func removeEmptyValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		if value == "" || value == commonlibs.DefaultDateTime || value == zeroTime {
+			return nil
+		}
+	case []interface{}:
+		list := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			if item = removeEmptyValue(item); item != nil {
+				list = append(list, item)
+			}
+		}
+
+		if len(list) == 0 {
+			return nil
+		}
+
+		return list
+	case map[string]interface{}:
+		if len(removeEmpty(value)) == 0 {
+			return nil
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,161 @@
+package elasticexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+)
+
+// textProperties свойства объектов STIX, содержащие произвольный текст, для которых выполняется полнотекстовый поиск
+var textProperties = map[string]bool{
+	"description":         true,
+	"abstract":            true,
+	"content":             true,
+	"explanation":         true,
+	"body":                true,
+	"objective":           true,
+	"contact_information": true,
+	"comment":             true,
+	"summary":             true,
+}
+
+// ipTypes типы объектов STIX, свойство value которых содержит IP-адрес
+var ipTypes = map[string]bool{
+	"ipv4-addr": true,
+	"ipv6-addr": true,
+}
+
+var (
+	typeTime   = reflect.TypeOf(time.Time{})
+	typeHashes = reflect.TypeOf(stixhelpers.HashesTypeSTIX{})
+	typeDict   = reflect.TypeOf(stixhelpers.DictionaryTypeSTIX{})
+	typeRaw    = reflect.TypeOf(json.RawMessage{})
+)
+
+// GetMapping возвращает описание полей индекса Elasticsearch для объектов типа objType, сформированное на основании
+// определения соответствующего ему типа Go. Время отображается в тип date, IP-адреса в тип ip, хеши в тип keyword,
+// описания и прочий произвольный текст в тип text, остальные строковые значения в тип keyword
+func GetMapping(objType string) (map[string]interface{}, error) {
+	rt, ok := stixstore.GetObjectTypes()[objType]
+	if !ok {
+		return nil, fmt.Errorf("the type '%s' is not a predefined STIX object type", objType)
+	}
+
+	properties := getProperties(rt, map[reflect.Type]bool{})
+	if ipTypes[objType] {
+		//значения в нотации CIDR не индексируются, но сохраняются в исходном документе
+		properties["value"] = map[string]interface{}{"type": "ip", "ignore_malformed": true}
+	}
+
+	return map[string]interface{}{
+		"dynamic_templates": []interface{}{
+			map[string]interface{}{
+				"hashes": map[string]interface{}{
+					"path_match": "*hashes.*",
+					"mapping":    map[string]interface{}{"type": "keyword"},
+				},
+			},
+		},
+		"properties": properties,
+	}, nil
+}
+
+// GetIndexTemplate возвращает шаблон индекса (index template) для объектов типа objType
+func GetIndexTemplate(objType string, opts Options) (map[string]interface{}, error) {
+	mapping, err := GetMapping(objType)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"index_patterns": []string{GetIndexName(objType, opts)},
+		"template":       map[string]interface{}{"mappings": mapping},
+		"_meta":          map[string]interface{}{"stix_type": objType},
+	}, nil
+}
+
+// GetIndexTemplates возвращает шаблоны индексов для всех предопределенных типов объектов STIX,
+// где ключ это наименование шаблона
+func GetIndexTemplates(opts Options) map[string]map[string]interface{} {
+	list := map[string]map[string]interface{}{}
+	for objType := range stixstore.GetObjectTypes() {
+		if tmpl, err := GetIndexTemplate(objType, opts); err == nil {
+			list[GetIndexName(objType, opts)] = tmpl
+		}
+	}
+
+	return list
+}
+
+// getProperties возвращает описание полей структуры rt, visited содержит структуры, обрабатываемые
+// в данный момент, что исключает бесконечную рекурсию
+func getProperties(rt reflect.Type, visited map[reflect.Type]bool) map[string]interface{} {
+	properties := map[string]interface{}{}
+	if visited[rt] {
+		return properties
+	}
+
+	visited[rt] = true
+	defer delete(visited, rt)
+
+	for _, f := range stixstore.GetObjectFields(rt) {
+		properties[f.Name] = getFieldMapping(f, visited)
+	}
+
+	return properties
+}
+
+// getFieldMapping возвращает описание поля f, для списков используется тип элемента
+func getFieldMapping(f stixstore.ObjectField, visited map[reflect.Type]bool) map[string]interface{} {
+	name, rt := f.Name, f.Type
+	for rt.Kind() == reflect.Ptr || (rt.Kind() == reflect.Slice && rt != typeRaw) {
+		rt = rt.Elem()
+	}
+
+	switch {
+	case rt == typeTime || (rt.Kind() == reflect.String && f.IsTimestamp):
+		return map[string]interface{}{"type": "date"}
+	case rt == typeHashes:
+		//значения хешей отображаются в тип keyword с помощью динамического шаблона "hashes"
+		return map[string]interface{}{"type": "object"}
+	}
+
+	switch rt.Kind() {
+	case reflect.String:
+		if textProperties[name] {
+			return map[string]interface{}{"type": "text"}
+		}
+
+		if name == "name" {
+			return map[string]interface{}{"type": "keyword", "fields": map[string]interface{}{"text": map[string]interface{}{"type": "text"}}}
+		}
+
+		return map[string]interface{}{"type": "keyword"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "long"}
+	case reflect.Uint64:
+		return map[string]interface{}{"type": "unsigned_long"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "double"}
+	case reflect.Map:
+		if rt.Key().Kind() == reflect.String && rt.Elem().Kind() == reflect.String {
+			return map[string]interface{}{"type": "flattened"}
+		}
+	case reflect.Struct:
+		if properties := getProperties(rt, visited); len(properties) > 0 && rt != typeDict {
+			return map[string]interface{}{"properties": properties}
+		}
+	}
+
+	//значения произвольной структуры (расширения, словари) сохраняются в исходном документе без индексации
+	return map[string]interface{}{"type": "object", "enabled": false}
+}
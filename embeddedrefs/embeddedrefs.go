@@ -0,0 +1,409 @@
+package embeddedrefs
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/commonproperties"
+	"github.com/av-belyakov/methodstixobjects/datamodels/relationshipobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// embeddedRef встроенная ссылка
+// Property - наименование свойства
+// RelationshipType - наименование отношения
+// IsList - является ли свойство списком
+// IsRequired - является ли свойство обязательным, такое свойство не удаляется из объекта
+type embeddedRef struct {
+	Property         string
+	RelationshipType string
+	IsList           bool
+	IsRequired       bool
+}
+
+// commonRefs встроенные ссылки, которые могут содержаться в объекте любого типа
+var commonRefs = []embeddedRef{
+	{"created_by_ref", "created-by", false, false},
+}
+
+// embeddedRefs встроенные ссылки объектов STIX и их наименования отношений согласно спецификации STIX 2.1
+var embeddedRefs = map[string][]embeddedRef{
+	"directory":   {{"contains_refs", "contains", true, false}},
+	"domain-name": {{"resolves_to_refs", "resolves-to", true, false}},
+	"email-addr":  {{"belongs_to_ref", "belongs-to", false, false}},
+	"email-message": {
+		{"from_ref", "from", false, false},
+		{"sender_ref", "sender", false, false},
+		{"to_refs", "to", true, false},
+		{"cc_refs", "cc", true, false},
+		{"bcc_refs", "bcc", true, false},
+		{"raw_email_ref", "raw-email", false, false},
+	},
+	"file": {
+		{"parent_directory_ref", "parent-directory", false, false},
+		{"contains_refs", "contains", true, false},
+		{"content_ref", "content", false, false},
+	},
+	"ipv4-addr": {
+		{"resolves_to_refs", "resolves-to", true, false},
+		{"belongs_to_refs", "belongs-to", true, false},
+	},
+	"ipv6-addr": {
+		{"resolves_to_refs", "resolves-to", true, false},
+		{"belongs_to_refs", "belongs-to", true, false},
+	},
+	"network-traffic": {
+		{"src_ref", "src", false, false},
+		{"dst_ref", "dst", false, false},
+		{"src_payload_ref", "src-payload", false, false},
+		{"dst_payload_ref", "dst-payload", false, false},
+		{"encapsulates_refs", "encapsulates", true, false},
+		{"encapsulated_by_ref", "encapsulated-by", false, false},
+	},
+	"process": {
+		{"opened_connection_refs", "opened-connection", true, false},
+		{"creator_user_ref", "creator-user", false, false},
+		{"image_ref", "image", false, false},
+		{"parent_ref", "parent", false, false},
+		{"child_refs", "child", true, false},
+	},
+	"windows-registry-key": {{"creator_user_ref", "creator-user", false, false}},
+	"malware": {
+		{"sample_refs", "sample", true, false},
+		{"operating_system_refs", "operating-system", true, false},
+	},
+	"malware-analysis": {
+		{"host_vm_ref", "host-vm", false, false},
+		{"operating_system_ref", "operating-system", false, false},
+		{"installed_software_refs", "installed-software", true, false},
+		{"sample_ref", "sample", false, false},
+		{"analysis_sco_refs", "analysis-sco", true, false},
+	},
+	"grouping":      {{"object_refs", "object", true, true}},
+	"note":          {{"object_refs", "object", true, true}},
+	"observed-data": {{"object_refs", "object", true, true}},
+	"opinion":       {{"object_refs", "object", true, true}},
+	"report":        {{"object_refs", "object", true, true}},
+}
+
+// Options параметры преобразования встроенных ссылок
+// RemoveEmbeddedRefs - удалять из объектов встроенные ссылки, преобразованные в объекты "relationship",
+// обязательные свойства (например, object_refs объекта "report") не удаляются
+// Properties - перечень свойств подлежащих преобразованию, если не задан, преобразуются все встроенные ссылки
+type Options struct {
+	RemoveEmbeddedRefs bool
+	Properties         []string
+}
+
+func (o Options) match(property string) bool {
+	if len(o.Properties) == 0 {
+		return true
+	}
+
+	for _, v := range o.Properties {
+		if v == property {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetRelationshipType возвращает наименование отношения, соответствующее встроенной ссылке property объекта типа objectType
+func GetRelationshipType(objectType, property string) (string, bool) {
+	if ref, ok := getEmbeddedRef(objectType, func(r embeddedRef) bool { return r.Property == property }); ok {
+		return ref.RelationshipType, true
+	}
+
+	return "", false
+}
+
+// GetRelationshipID возвращает детерминированный идентификатор объекта "relationship", связывающего
+// объекты source и target отношением relationshipType
+func GetRelationshipID(source, relationshipType, target string) string {
+	id, _ := commonlibs.GetUUIDv5(commonlibs.STIXNamespaceUUID, strings.Join([]string{source, relationshipType, target}, "|"))
+
+	return "relationship--" + id
+}
+
+// ToRelationships преобразует встроенные ссылки объектов в объекты "relationship". Возвращает исходные объекты
+// (при установленном параметре RemoveEmbeddedRefs без преобразованных необязательных ссылок) и созданные объекты "relationship".
+// Идентификаторы созданных объектов детерминированы (см. GetRelationshipID), объекты, уже содержащиеся
+// в списке, повторно не создаются. Время создания и изменения объектов "relationship" берется из исходного объекта
+func ToRelationships(objects []interface{}, options Options) ([]interface{}, error) {
+	result := make([]interface{}, 0, len(objects))
+	relationships := []interface{}{}
+
+	exists := map[string]bool{}
+	for _, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if id, ok := m["id"].(string); ok {
+			exists[id] = true
+		}
+	}
+
+	for _, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		objectType, _ := m["type"].(string)
+		source, _ := m["id"].(string)
+		isChanged := false
+
+		for _, ref := range getEmbeddedRefs(objectType) {
+			if !options.match(ref.Property) {
+				continue
+			}
+
+			targets := getRefs(m[ref.Property])
+			if len(targets) == 0 {
+				continue
+			}
+
+			for _, target := range targets {
+				id := GetRelationshipID(source, ref.RelationshipType, target)
+				if exists[id] {
+					continue
+				}
+				exists[id] = true
+
+				relationships = append(relationships, newRelationship(id, ref.RelationshipType, source, target, m))
+			}
+
+			if options.RemoveEmbeddedRefs && !ref.IsRequired {
+				delete(m, ref.Property)
+				isChanged = true
+			}
+		}
+
+		if !isChanged {
+			result = append(result, obj)
+
+			continue
+		}
+
+		newObj, err := commonlibs.FromMap(m, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, newObj)
+	}
+
+	return append(result, relationships...), nil
+}
+
+// FromRelationships выполняет обратное преобразование, объекты "relationship", отношение которых соответствует
+// встроенной ссылке объекта-источника, заменяются встроенными ссылками. Преобразуются только объекты "relationship",
+// объект-источник которых содержится в списке, а сам объект "relationship" не содержит дополнительной информации
+// (описания, времени начала и окончания связи, расширений, маркеров, сведений об авторе, меток, внешних ссылок и уровня
+// уверенности). Если встроенная ссылка не является списком и уже содержит другое значение, объект "relationship" сохраняется
+func FromRelationships(objects []interface{}) ([]interface{}, error) {
+	maps := make([]map[string]interface{}, 0, len(objects))
+	index := map[string]int{}
+
+	for k, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		maps = append(maps, m)
+		if id, ok := m["id"].(string); ok {
+			index[id] = k
+		}
+	}
+
+	folded := map[int]bool{}
+	changed := map[int]bool{}
+	for k, m := range maps {
+		if m["type"] != "relationship" || !isFoldable(m) {
+			continue
+		}
+
+		relType, _ := m["relationship_type"].(string)
+		source, _ := m["source_ref"].(string)
+		target, _ := m["target_ref"].(string)
+
+		num, ok := index[source]
+		if !ok || target == "" {
+			continue
+		}
+
+		sourceObj := maps[num]
+		sourceType, _ := sourceObj["type"].(string)
+		ref, ok := getEmbeddedRef(sourceType, func(r embeddedRef) bool { return r.RelationshipType == relType })
+		if !ok {
+			continue
+		}
+
+		if ref.IsList {
+			list := getRefs(sourceObj[ref.Property])
+			if !contains(list, target) {
+				values := make([]interface{}, 0, len(list)+1)
+				for _, v := range list {
+					values = append(values, v)
+				}
+				sourceObj[ref.Property] = append(values, target)
+			}
+		} else {
+			current, _ := sourceObj[ref.Property].(string)
+			if current != "" && current != target {
+				continue
+			}
+
+			sourceObj[ref.Property] = target
+		}
+
+		folded[k] = true
+		changed[num] = true
+	}
+
+	result := make([]interface{}, 0, len(objects)-len(folded))
+	for k, obj := range objects {
+		if folded[k] {
+			continue
+		}
+
+		if !changed[k] {
+			result = append(result, obj)
+
+			continue
+		}
+
+		newObj, err := commonlibs.FromMap(maps[k], obj)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, newObj)
+	}
+
+	return result, nil
+}
+
+func newRelationship(id, relType, source, target string, m map[string]interface{}) relationshipobjectsstix.RelationshipObjectSTIX {
+	created, _ := m["created"].(string)
+	if created == "" {
+		created = commonlibs.DefaultDateTime
+	}
+
+	modified, _ := m["modified"].(string)
+	if modified == "" || modified == commonlibs.DefaultDateTime {
+		modified = created
+	}
+
+	return relationshipobjectsstix.RelationshipObjectSTIX{
+		CommonPropertiesObjectSTIX: commonproperties.CommonPropertiesObjectSTIX{
+			Type: "relationship",
+			ID:   id,
+		},
+		OptionalCommonPropertiesRelationshipObjectSTIX: relationshipobjectsstix.OptionalCommonPropertiesRelationshipObjectSTIX{
+			SpecVersion: "2.1",
+			Created:     created,
+			Modified:    modified,
+			Extensions:  stixhelpers.ExtensionsTypeSTIX{},
+		},
+		RelationshipType: relType,
+		SourceRef:        stixhelpers.IdentifierTypeSTIX(source),
+		TargetRef:        stixhelpers.IdentifierTypeSTIX(target),
+		StartTime:        commonlibs.DefaultDateTime,
+		StopTime:         commonlibs.DefaultDateTime,
+	}
+}
+
+// isFoldable проверяет что объект "relationship" не содержит информации, которая будет утеряна при его замене встроенной ссылкой,
+// в том числе маркеров, сведений об авторе, меток, внешних ссылок и уровня уверенности
+func isFoldable(m map[string]interface{}) bool {
+	for _, k := range []string{"description", "start_time", "stop_time", "created_by_ref"} {
+		if v, _ := m[k].(string); v != "" && v != commonlibs.DefaultDateTime {
+			return false
+		}
+	}
+
+	for _, k := range []string{"object_marking_refs", "granular_markings", "labels", "external_references"} {
+		if v, ok := m[k].([]interface{}); ok && len(v) > 0 {
+			return false
+		}
+	}
+
+	if v, ok := m["confidence"].(float64); ok && v != 0 {
+		return false
+	}
+
+	if ext, ok := m["extensions"].(map[string]interface{}); ok && len(ext) > 0 {
+		return false
+	}
+
+	return true
+}
+
+func getEmbeddedRefs(objectType string) []embeddedRef {
+	list := make([]embeddedRef, 0, len(commonRefs)+len(embeddedRefs[objectType]))
+	if objectType == "relationship" || objectType == "sighting" {
+		return list
+	}
+
+	list = append(list, commonRefs...)
+
+	return append(list, embeddedRefs[objectType]...)
+}
+
+func getEmbeddedRef(objectType string, f func(embeddedRef) bool) (embeddedRef, bool) {
+	for _, v := range getEmbeddedRefs(objectType) {
+		if f(v) {
+			return v, true
+		}
+	}
+
+	return embeddedRef{}, false
+}
+
+func getRefs(value interface{}) []string {
+	list := []string{}
+
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			list = append(list, v)
+		}
+
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" && !contains(list, s) {
+				list = append(list, s)
+			}
+		}
+	}
+
+	return list
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetEmbeddedRefProperties возвращает отсортированный список свойств объекта типа objectType,
+// являющихся встроенными ссылками
+func GetEmbeddedRefProperties(objectType string) []string {
+	list := []string{}
+	for _, v := range getEmbeddedRefs(objectType) {
+		list = append(list, v.Property)
+	}
+	sort.Strings(list)
+
+	return list
+}
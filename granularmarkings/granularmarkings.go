@@ -0,0 +1,351 @@
+package granularmarkings
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// selectorIndex шаблон элемента селектора указывающего на элемент списка, например, "[0]"
+var selectorIndex = regexp.MustCompile(`^\[([0-9]+)\]$`)
+
+// Resolver выполняет интерпретацию селекторов гранулярных маркеров (свойство granular_markings)
+// применительно к конкретному объекту STIX. Селектор это путь к свойству объекта, где имена свойств
+// разделены точкой, а индексы элементов списков указываются в квадратных скобках, например,
+// "description" или "external_references.[0].url"
+type Resolver struct {
+	object            map[string]interface{}
+	objectMarkingRefs []stixhelpers.IdentifierTypeSTIX
+	granularMarkings  []stixhelpers.GranularMarkingsTypeSTIX
+}
+
+// NewResolver создает новый обработчик селекторов для объекта STIX. В качестве объекта может быть
+// передан любой тип STIX (SDO, SCO, SRO, marking-definition) или его JSON представление
+// в виде json.RawMessage или []byte
+func NewResolver(obj interface{}) (*Resolver, error) {
+	if b, ok := obj.([]byte); ok {
+		obj = json.RawMessage(b)
+	}
+
+	object, err := commonlibs.ToMap(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	r := Resolver{object: object}
+
+	if v, ok := object["object_marking_refs"]; ok && v != nil {
+		if err = remarshal(v, &r.objectMarkingRefs); err != nil {
+			return nil, fmt.Errorf("invalid value of the 'object_marking_refs' property: %w", err)
+		}
+	}
+
+	if v, ok := object["granular_markings"]; ok && v != nil {
+		if err = remarshal(v, &r.granularMarkings); err != nil {
+			return nil, fmt.Errorf("invalid value of the 'granular_markings' property: %w", err)
+		}
+	}
+
+	return &r, nil
+}
+
+// GetObjectMarkingRefs возвращает маркеры уровня объекта (свойство object_marking_refs)
+func (r *Resolver) GetObjectMarkingRefs() []stixhelpers.IdentifierTypeSTIX {
+	return r.objectMarkingRefs
+}
+
+// GetGranularMarkings возвращает гранулярные маркеры объекта (свойство granular_markings)
+func (r *Resolver) GetGranularMarkings() []stixhelpers.GranularMarkingsTypeSTIX {
+	return r.granularMarkings
+}
+
+// ValidateSelector проверяет что селектор синтаксически корректен и указывает на свойство
+// присутствующее в объекте. Пустые значения (пустые строки, списки, словари) считаются отсутствующими
+func (r *Resolver) ValidateSelector(selector string) error {
+	path, err := ParseSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := lookup(r.object, path); !ok {
+		return fmt.Errorf("the selector '%s' does not refer to an existing property of the object", selector)
+	}
+
+	return nil
+}
+
+// Validate проверяет все селекторы всех гранулярных маркеров объекта. Возвращаемая ошибка
+// содержит перечень всех некорректных селекторов
+func (r *Resolver) Validate() error {
+	errs := []string{}
+
+	for k, gm := range r.granularMarkings {
+		if len(gm.Selectors) == 0 {
+			errs = append(errs, fmt.Sprintf("granular marking '%d' does not contain any selector", k))
+		}
+
+		for _, s := range gm.Selectors {
+			if err := r.ValidateSelector(s); err != nil {
+				errs = append(errs, fmt.Sprintf("granular marking '%d': %s", k, err.Error()))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// GetEffectiveMarkings возвращает список маркеров действующих в отношении свойства объекта, на
+// которое указывает selector. Результат объединяет маркеры уровня объекта и гранулярные маркеры,
+// селекторы которых указывают на само свойство или на одно из его родительских свойств.
+// Гранулярные маркеры языка (содержащие lang вместо marking_ref) не учитываются
+func (r *Resolver) GetEffectiveMarkings(selector string) ([]stixhelpers.IdentifierTypeSTIX, error) {
+	path, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := lookup(r.object, path); !ok {
+		return nil, fmt.Errorf("the selector '%s' does not refer to an existing property of the object", selector)
+	}
+
+	return r.getEffectiveMarkings(path), nil
+}
+
+// GetAllEffectiveMarkings возвращает действующие маркеры для каждого присутствующего в объекте
+// свойства, включая вложенные свойства и элементы списков. Ключом является селектор свойства
+func (r *Resolver) GetAllEffectiveMarkings() map[string][]stixhelpers.IdentifierTypeSTIX {
+	result := map[string][]stixhelpers.IdentifierTypeSTIX{}
+
+	walk(r.object, []string{}, func(path []string) {
+		result[strings.Join(path, ".")] = r.getEffectiveMarkings(path)
+	})
+
+	return result
+}
+
+// GetEffectiveLang возвращает язык свойства объекта на которое указывает selector. Используется
+// гранулярный маркер языка с наиболее точным селектором, а при его отсутствии значение свойства lang объекта
+func (r *Resolver) GetEffectiveLang(selector string) (string, error) {
+	path, err := ParseSelector(selector)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := lookup(r.object, path); !ok {
+		return "", fmt.Errorf("the selector '%s' does not refer to an existing property of the object", selector)
+	}
+
+	lang, depth := "", -1
+	for _, gm := range r.granularMarkings {
+		if gm.Lang == "" {
+			continue
+		}
+
+		for _, s := range gm.Selectors {
+			sp, err := ParseSelector(s)
+			if err != nil || !isPrefix(sp, path) {
+				continue
+			}
+
+			if len(sp) > depth {
+				lang, depth = gm.Lang, len(sp)
+			}
+		}
+	}
+
+	if depth >= 0 {
+		return lang, nil
+	}
+
+	if v, ok := r.object["lang"].(string); ok {
+		return v, nil
+	}
+
+	return "", nil
+}
+
+func (r *Resolver) getEffectiveMarkings(path []string) []stixhelpers.IdentifierTypeSTIX {
+	list := make([]stixhelpers.IdentifierTypeSTIX, 0, len(r.objectMarkingRefs))
+	list = appendUnique(list, r.objectMarkingRefs...)
+
+	for _, gm := range r.granularMarkings {
+		if gm.MarkingRef == "" {
+			continue
+		}
+
+		for _, s := range gm.Selectors {
+			sp, err := ParseSelector(s)
+			if err != nil || !isPrefix(sp, path) {
+				continue
+			}
+
+			list = appendUnique(list, gm.MarkingRef)
+
+			break
+		}
+	}
+
+	return list
+}
+
+// ParseSelector выполняет синтаксический разбор селектора и возвращает список его элементов,
+// например, для "external_references.[0].url" будет получено ["external_references", "[0]", "url"]
+func ParseSelector(selector string) ([]string, error) {
+	if selector == "" {
+		return nil, fmt.Errorf("the selector must not be empty")
+	}
+
+	path := strings.Split(selector, ".")
+	for _, v := range path {
+		if v == "" {
+			return nil, fmt.Errorf("invalid selector '%s', the selector contains an empty element", selector)
+		}
+
+		if strings.ContainsAny(v, "[]") && !selectorIndex.MatchString(v) {
+			return nil, fmt.Errorf("invalid selector '%s', incorrect list index '%s'", selector, v)
+		}
+	}
+
+	if selectorIndex.MatchString(path[0]) {
+		return nil, fmt.Errorf("invalid selector '%s', the selector cannot start with a list index", selector)
+	}
+
+	return path, nil
+}
+
+// lookup возвращает значение расположенное по указанному пути
+func lookup(obj interface{}, path []string) (interface{}, bool) {
+	current := obj
+	for _, p := range path {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			value, ok := v[p]
+			if !ok {
+				return nil, false
+			}
+
+			current = value
+
+		case []interface{}:
+			m := selectorIndex.FindStringSubmatch(p)
+			if m == nil {
+				return nil, false
+			}
+
+			i, err := strconv.Atoi(m[1])
+			if err != nil || i >= len(v) {
+				return nil, false
+			}
+
+			current = v[i]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return current, !isEmpty(current)
+}
+
+// walk обходит все присутствующие в объекте свойства и вызывает f для пути к каждому из них
+func walk(obj interface{}, path []string, f func(path []string)) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if isEmpty(v[k]) {
+				continue
+			}
+
+			p := append(append([]string{}, path...), k)
+			f(p)
+			walk(v[k], p, f)
+		}
+
+	case []interface{}:
+		for k, value := range v {
+			if isEmpty(value) {
+				continue
+			}
+
+			p := append(append([]string{}, path...), fmt.Sprintf("[%d]", k))
+			f(p)
+			walk(value, p, f)
+		}
+	}
+}
+
+// isEmpty проверяет является ли значение пустым, так как свойства типов STIX сериализуются в JSON
+// всегда, пустые значения рассматриваются как отсутствующие свойства
+func isEmpty(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+
+	return false
+}
+
+// isPrefix проверяет является ли prefix путем к самому свойству path или к одному из его родителей
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+
+	for k, v := range prefix {
+		if path[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func appendUnique(list []stixhelpers.IdentifierTypeSTIX, values ...stixhelpers.IdentifierTypeSTIX) []stixhelpers.IdentifierTypeSTIX {
+	for _, v := range values {
+		exist := false
+		for _, l := range list {
+			if l == v {
+				exist = true
+
+				break
+			}
+		}
+
+		if !exist {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+func remarshal(from, to interface{}) error {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, to)
+}
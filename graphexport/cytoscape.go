@@ -0,0 +1,108 @@
+package graphexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// cytoscapeShapes соответствие форм узлов формам Cytoscape.js
+var cytoscapeShapes = map[string]string{
+	"box": "rectangle",
+}
+
+// CytoscapeElement элемент (узел или ребро) графа Cytoscape.js
+type CytoscapeElement struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// CytoscapeStyle правило оформления Cytoscape.js
+type CytoscapeStyle struct {
+	Selector string            `json:"selector"`
+	Style    map[string]string `json:"style"`
+}
+
+// CytoscapeGraph граф в формате Cytoscape.js JSON
+type CytoscapeGraph struct {
+	Elements struct {
+		Nodes []CytoscapeElement `json:"nodes"`
+		Edges []CytoscapeElement `json:"edges"`
+	} `json:"elements"`
+	Style []CytoscapeStyle `json:"style"`
+}
+
+// NewCytoscapeGraph формирует граф Cytoscape.js из объектов STIX. Данные узла содержат свойства id, label,
+// type и свойства из Options.Attributes, данные ребра - id, source, target, label и embedded.
+// Оформление узлов задается правилами style по их типу
+func NewCytoscapeGraph(objects []interface{}, opts Options) (*CytoscapeGraph, error) {
+	g, err := newGraph(objects, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cg := &CytoscapeGraph{Style: []CytoscapeStyle{
+		{Selector: "node", Style: map[string]string{"label": "data(label)"}},
+		{Selector: "edge", Style: map[string]string{"label": "data(label)", "curve-style": "bezier", "target-arrow-shape": "triangle"}},
+		{Selector: "edge[?embedded]", Style: map[string]string{"line-style": "dashed"}},
+	}}
+	cg.Elements.Nodes = []CytoscapeElement{}
+	cg.Elements.Edges = []CytoscapeElement{}
+
+	styles := map[string]NodeStyle{}
+	for _, n := range g.Nodes {
+		data := map[string]interface{}{}
+		for k, v := range n.Attributes {
+			data[k] = v
+		}
+		data["id"] = n.ID
+		data["label"] = n.Label
+		data["type"] = n.Type
+
+		cg.Elements.Nodes = append(cg.Elements.Nodes, CytoscapeElement{Data: data})
+		styles[n.Type] = n.Style
+	}
+
+	types := make([]string, 0, len(styles))
+	for k := range styles {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+
+	for _, v := range types {
+		shape := styles[v].Shape
+		if s, ok := cytoscapeShapes[shape]; ok {
+			shape = s
+		}
+
+		cg.Style = append(cg.Style, CytoscapeStyle{
+			Selector: fmt.Sprintf("node[type = %q]", v),
+			Style:    map[string]string{"background-color": styles[v].Color, "shape": shape},
+		})
+	}
+
+	for _, e := range g.Edges {
+		cg.Elements.Edges = append(cg.Elements.Edges, CytoscapeElement{Data: map[string]interface{}{
+			"id":       e.ID,
+			"source":   e.Source,
+			"target":   e.Target,
+			"label":    e.Type,
+			"embedded": e.Embedded,
+		}})
+	}
+
+	return cg, nil
+}
+
+// WriteCytoscapeJSON записывает объекты STIX в формате Cytoscape.js JSON
+func WriteCytoscapeJSON(w io.Writer, objects []interface{}, opts Options) error {
+	cg, err := NewCytoscapeGraph(objects, opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(cg)
+}
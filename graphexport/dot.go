@@ -0,0 +1,60 @@
+package graphexport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT записывает объекты STIX в формате Graphviz DOT. Узлы подписываются наименованием или значением
+// объекта, цвет и форма узла определяются его типом, свойства из Options.Attributes добавляются
+// к подсказке (tooltip) узла
+func WriteDOT(w io.Writer, objects []interface{}, opts Options) error {
+	g, err := newGraph(objects, opts)
+	if err != nil {
+		return err
+	}
+
+	attributes := g.getAttributeNames()
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph stix {")
+	fmt.Fprintln(bw, "  node [style=filled];")
+
+	for _, n := range g.Nodes {
+		tooltip := []string{n.Type}
+		for _, v := range attributes {
+			if value, ok := n.Attributes[v]; ok {
+				tooltip = append(tooltip, v+": "+value)
+			}
+		}
+
+		fmt.Fprintf(bw, "  %s [label=%s, shape=%s, fillcolor=%s, tooltip=%s];\n",
+			quoteDOT(n.ID),
+			quoteDOT(n.Label),
+			quoteDOT(n.Style.Shape),
+			quoteDOT(n.Style.Color),
+			quoteDOT(strings.Join(tooltip, "\n")))
+	}
+
+	for _, e := range g.Edges {
+		style := "solid"
+		if e.Embedded {
+			style = "dashed"
+		}
+
+		fmt.Fprintf(bw, "  %s -> %s [label=%s, style=%s];\n", quoteDOT(e.Source), quoteDOT(e.Target), quoteDOT(e.Type), style)
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// quoteDOT возвращает строку в виде идентификатора DOT в двойных кавычках
+func quoteDOT(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,214 @@
+package graphexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/stixgraph"
+)
+
+// NodeStyle оформление узла
+// Color - цвет заливки узла в формате "#rrggbb"
+// Shape - форма узла ("ellipse", "box", "diamond", "hexagon", "octagon", "triangle")
+type NodeStyle struct {
+	Color string
+	Shape string
+}
+
+// DefaultNodeStyle оформление узлов, тип которых отсутствует в DefaultStyles и Options.Styles
+var DefaultNodeStyle = NodeStyle{Color: "#cccccc", Shape: "ellipse"}
+
+// DefaultStyles оформление узлов по типам объектов STIX
+var DefaultStyles = map[string]NodeStyle{
+	"attack-pattern":   {Color: "#e6550d", Shape: "hexagon"},
+	"campaign":         {Color: "#756bb1", Shape: "diamond"},
+	"course-of-action": {Color: "#31a354", Shape: "box"},
+	"grouping":         {Color: "#bdbdbd", Shape: "box"},
+	"identity":         {Color: "#3182bd", Shape: "ellipse"},
+	"incident":         {Color: "#de2d26", Shape: "octagon"},
+	"indicator":        {Color: "#fdae6b", Shape: "triangle"},
+	"infrastructure":   {Color: "#9ecae1", Shape: "box"},
+	"intrusion-set":    {Color: "#9e9ac8", Shape: "diamond"},
+	"location":         {Color: "#74c476", Shape: "ellipse"},
+	"malware":          {Color: "#e34a33", Shape: "octagon"},
+	"malware-analysis": {Color: "#fc9272", Shape: "box"},
+	"note":             {Color: "#fff7bc", Shape: "box"},
+	"observed-data":    {Color: "#c6dbef", Shape: "box"},
+	"opinion":          {Color: "#fff7bc", Shape: "box"},
+	"report":           {Color: "#d9d9d9", Shape: "box"},
+	"threat-actor":     {Color: "#54278f", Shape: "diamond"},
+	"tool":             {Color: "#fd8d3c", Shape: "hexagon"},
+	"vulnerability":    {Color: "#a50f15", Shape: "triangle"},
+}
+
+// Options параметры экспорта
+// IncludeEmbeddedRefs - строить ребра на основании встроенных ссылок (created_by_ref, resolves_to_refs и т.д.)
+// Attributes - перечень свойств объектов, которые будут добавлены к атрибутам узлов
+// Styles - оформление узлов по типам объектов, дополняет и переопределяет DefaultStyles
+type Options struct {
+	IncludeEmbeddedRefs bool
+	Attributes          []string
+	Styles              map[string]NodeStyle
+}
+
+func (o Options) getStyle(objType string) NodeStyle {
+	if s, ok := o.Styles[objType]; ok {
+		return s
+	}
+
+	if s, ok := DefaultStyles[objType]; ok {
+		return s
+	}
+
+	return DefaultNodeStyle
+}
+
+// node узел экспортируемого графа
+type node struct {
+	ID         string
+	Type       string
+	Label      string
+	Style      NodeStyle
+	Attributes map[string]string
+}
+
+// edge ребро экспортируемого графа
+type edge struct {
+	ID       string
+	Type     string
+	Source   string
+	Target   string
+	Embedded bool
+}
+
+// graph граф подготовленный к экспорту
+type graph struct {
+	Nodes []node
+	Edges []edge
+}
+
+// newGraph формирует граф для экспорта. В граф включаются только ребра, оба конца которых
+// присутствуют в списке объектов
+func newGraph(objects []interface{}, opts Options) (graph, error) {
+	g, err := stixgraph.NewGraph(objects)
+	if err != nil {
+		return graph{}, err
+	}
+
+	result := graph{Nodes: []node{}, Edges: []edge{}}
+	for _, id := range g.GetNodes() {
+		obj, _ := g.GetNode(id)
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return graph{}, err
+		}
+
+		objType, _ := m["type"].(string)
+		n := node{
+			ID:         id,
+			Type:       objType,
+			Label:      getLabel(m),
+			Style:      opts.getStyle(objType),
+			Attributes: map[string]string{},
+		}
+
+		for _, attr := range opts.Attributes {
+			if v, ok := m[attr]; ok {
+				n.Attributes[attr] = toString(v)
+			}
+		}
+
+		result.Nodes = append(result.Nodes, n)
+	}
+
+	for _, e := range g.GetEdges() {
+		if e.IsEmbedded() && !opts.IncludeEmbeddedRefs {
+			continue
+		}
+
+		_, okSource := g.GetNode(e.Source)
+		_, okTarget := g.GetNode(e.Target)
+		if !okSource || !okTarget {
+			continue
+		}
+
+		//встроенные ссылки не имеют идентификатора, а объект "sighting" может порождать несколько ребер
+		id := e.ID
+		if e.IsEmbedded() {
+			id = strings.Join([]string{e.Source, e.Property, e.Target}, "|")
+		} else if e.Type == stixgraph.EdgeTypeSighting {
+			id = strings.Join([]string{e.ID, e.Property, e.Target}, "|")
+		}
+
+		result.Edges = append(result.Edges, edge{
+			ID:       id,
+			Type:     e.Type,
+			Source:   e.Source,
+			Target:   e.Target,
+			Embedded: e.IsEmbedded(),
+		})
+	}
+
+	return result, nil
+}
+
+// getLabel возвращает подпись узла, наименование (name) или значение (value) объекта,
+// если они отсутствуют - идентификатор объекта
+func getLabel(m map[string]interface{}) string {
+	for _, k := range []string{"name", "value", "path", "key", "user_id", "pattern"} {
+		if v, ok := m[k].(string); ok && v != "" {
+			return v
+		}
+	}
+
+	id, _ := m["id"].(string)
+
+	return id
+}
+
+// toString преобразует значение свойства в строку, элементы списков разделяются запятой
+func toString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			list = append(list, toString(item))
+		}
+
+		return strings.Join(list, ",")
+
+	case nil:
+		return ""
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(b)
+}
+
+// getAttributeNames возвращает отсортированный список атрибутов узлов графа
+func (g graph) getAttributeNames() []string {
+	names := map[string]bool{}
+	for _, n := range g.Nodes {
+		for k := range n.Attributes {
+			names[k] = true
+		}
+	}
+
+	list := make([]string, 0, len(names))
+	for k := range names {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+
+	return list
+}
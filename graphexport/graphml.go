@@ -0,0 +1,125 @@
+package graphexport
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML записывает объекты STIX в формате GraphML (например, для Gephi). Подпись, тип и
+// оформление узла, а также свойства из Options.Attributes сохраняются в виде атрибутов узла,
+// тип ребра и признак встроенной ссылки в виде атрибутов ребра
+func WriteGraphML(w io.Writer, objects []interface{}, opts Options) error {
+	g, err := newGraph(objects, opts)
+	if err != nil {
+		return err
+	}
+
+	doc := graphMLDocument{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "type", For: "node", AttrName: "type", AttrType: "string"},
+			{ID: "color", For: "node", AttrName: "color", AttrType: "string"},
+			{ID: "shape", For: "node", AttrName: "shape", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "stix", EdgeDefault: "directed"},
+	}
+
+	attributes := g.getAttributeNames()
+	attrKeys := make(map[string]string, len(attributes))
+	for k, v := range attributes {
+		key := "attr" + strconv.Itoa(k)
+		attrKeys[v] = key
+		doc.Keys = append(doc.Keys, graphMLKey{ID: key, For: "node", AttrName: v, AttrType: "string"})
+	}
+
+	doc.Keys = append(doc.Keys,
+		graphMLKey{ID: "relationship_type", For: "edge", AttrName: "relationship_type", AttrType: "string"},
+		graphMLKey{ID: "embedded", For: "edge", AttrName: "embedded", AttrType: "boolean"},
+	)
+
+	for _, n := range g.Nodes {
+		gn := graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "label", Value: n.Label},
+				{Key: "type", Value: n.Type},
+				{Key: "color", Value: n.Style.Color},
+				{Key: "shape", Value: n.Style.Shape},
+			},
+		}
+
+		for _, v := range attributes {
+			if value, ok := n.Attributes[v]; ok {
+				gn.Data = append(gn.Data, graphMLData{Key: attrKeys[v], Value: value})
+			}
+		}
+
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gn)
+	}
+
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     e.ID,
+			Source: e.Source,
+			Target: e.Target,
+			Data: []graphMLData{
+				{Key: "relationship_type", Value: e.Type},
+				{Key: "embedded", Value: strconv.FormatBool(e.Embedded)},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+
+	return err
+}
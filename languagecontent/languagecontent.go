@@ -0,0 +1,233 @@
+package languagecontent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// ValidateLanguageContent проверяет объект "language-content" применительно к объекту STIX на который
+// он ссылается. Помимо общей проверки объекта, проверяется что свойство object_ref содержит идентификатор
+// объекта obj, а все переведенные свойства существуют в объекте obj и имеют совместимый тип. Возвращаемая
+// ошибка содержит перечень всех найденных несоответствий
+func ValidateLanguageContent(lc stixhelpers.LanguageContentTypeSTIX, obj interface{}) error {
+	if !lc.ValidateStruct() {
+		return fmt.Errorf("the language content object '%s' contains invalid values", lc.GetID())
+	}
+
+	object, err := commonlibs.ToMap(obj)
+	if err != nil {
+		return err
+	}
+
+	if id, _ := object["id"].(string); id != string(lc.GetObjectRef()) {
+		return fmt.Errorf("the language content object refers to '%s', not to '%s'", lc.GetObjectRef(), id)
+	}
+
+	errs := []string{}
+	for _, lang := range lc.GetLanguages() {
+		properties := lc.GetContentsLang(lang)
+
+		keys := make([]string, 0, len(properties))
+		for k := range properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			for _, path := range checkContent(object[k], properties[k], k) {
+				errs = append(errs, fmt.Sprintf("language '%s': the property '%s' does not exist in the object or has an incompatible type", lang, path))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// ApplyLanguageContent применяет объект "language-content" к объекту STIX (как правило SDO) и возвращает
+// его локализованную копию того же типа, что и obj. Язык выбирается по точному совпадению тега, без учета
+// регистра, а при его отсутствии по основному тегу, например, для "ru-RU" будет использован перевод "ru".
+// Пустые строки в переведенных списках означают отсутствие перевода и исходные значения сохраняются.
+// Если объект содержит свойство lang, ему присваивается язык перевода. Исходный объект не изменяется
+func ApplyLanguageContent(obj interface{}, lc stixhelpers.LanguageContentTypeSTIX, lang string) (interface{}, error) {
+	if err := ValidateLanguageContent(lc, obj); err != nil {
+		return nil, err
+	}
+
+	object, err := commonlibs.ToMap(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if om := lc.GetObjectModified(); om != "" && om != commonlibs.DefaultDateTime {
+		if modified, _ := object["modified"].(string); modified != "" && commonlibs.CompareTimestamps(modified, om) != 0 {
+			return nil, fmt.Errorf("the language content object applies to the version '%s' of the object, but the object version is '%s'", om, modified)
+		}
+	}
+
+	tag, ok := selectLanguage(lc, lang)
+	if !ok {
+		return nil, fmt.Errorf("the language content object does not contain a translation into the language '%s'", lang)
+	}
+
+	for k, v := range lc.GetContentsLang(tag) {
+		object[k] = applyContent(object[k], v)
+	}
+
+	if _, ok := object["lang"]; ok {
+		object["lang"] = tag
+	}
+
+	return commonlibs.FromMap(object, obj)
+}
+
+// GetTranslations возвращает список языков на которые переведен объект obj, среди указанных объектов "language-content"
+func GetTranslations(obj interface{}, list []stixhelpers.LanguageContentTypeSTIX) ([]string, error) {
+	object, err := commonlibs.ToMap(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := object["id"].(string)
+	languages := []string{}
+	for _, lc := range list {
+		if string(lc.GetObjectRef()) != id {
+			continue
+		}
+
+		for _, lang := range lc.GetLanguages() {
+			if !contains(languages, lang) {
+				languages = append(languages, lang)
+			}
+		}
+	}
+	sort.Strings(languages)
+
+	return languages, nil
+}
+
+// selectLanguage выбирает язык перевода наиболее подходящий запрошенному
+func selectLanguage(lc stixhelpers.LanguageContentTypeSTIX, lang string) (string, bool) {
+	languages := lc.GetLanguages()
+	for _, v := range languages {
+		if strings.EqualFold(v, lang) {
+			return v, true
+		}
+	}
+
+	primary := strings.SplitN(lang, "-", 2)[0]
+	for _, v := range languages {
+		if strings.EqualFold(v, primary) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// checkContent проверяет соответствие перевода translation исходному значению original,
+// возвращает список путей к свойствам для которых найдено несоответствие
+func checkContent(original, translation interface{}, path string) []string {
+	switch t := translation.(type) {
+	case string:
+		if o, ok := original.(string); !ok || o == "" {
+			return []string{path}
+		}
+
+	case []interface{}:
+		o, ok := original.([]interface{})
+		if !ok || len(t) > len(o) {
+			return []string{path}
+		}
+
+		errs := []string{}
+		for k, v := range t {
+			//пустое значение означает отсутствие перевода элемента
+			if v == nil || v == "" {
+				continue
+			}
+
+			errs = append(errs, checkContent(o[k], v, fmt.Sprintf("%s.[%d]", path, k))...)
+		}
+
+		return errs
+
+	case map[string]interface{}:
+		o, ok := original.(map[string]interface{})
+		if !ok {
+			return []string{path}
+		}
+
+		errs := []string{}
+		for k, v := range t {
+			errs = append(errs, checkContent(o[k], v, path+"."+k)...)
+		}
+		sort.Strings(errs)
+
+		return errs
+
+	default:
+		return []string{path}
+	}
+
+	return []string{}
+}
+
+// applyContent заменяет исходное значение original переводом translation
+func applyContent(original, translation interface{}) interface{} {
+	switch t := translation.(type) {
+	case []interface{}:
+		o, ok := original.([]interface{})
+		if !ok {
+			return original
+		}
+
+		list := make([]interface{}, len(o))
+		copy(list, o)
+		for k, v := range t {
+			if v == nil || v == "" {
+				continue
+			}
+
+			list[k] = applyContent(o[k], v)
+		}
+
+		return list
+
+	case map[string]interface{}:
+		o, ok := original.(map[string]interface{})
+		if !ok {
+			return original
+		}
+
+		m := make(map[string]interface{}, len(o))
+		for k, v := range o {
+			m[k] = v
+		}
+
+		for k, v := range t {
+			m[k] = applyContent(o[k], v)
+		}
+
+		return m
+	}
+
+	return translation
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,538 @@
+package misp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// attributeTypes соответствие типов объектов STIX CO типам атрибутов MISP
+var attributeTypes = map[string]string{
+	"ipv4-addr":   "ip-dst",
+	"ipv6-addr":   "ip-dst",
+	"domain-name": "domain",
+	"url":         "url",
+	"email-addr":  "email-src",
+}
+
+// attributeCategories категории атрибутов MISP, используемые при экспорте
+var attributeCategories = map[string]string{
+	"ip-dst":        "Network activity",
+	"domain":        "Network activity",
+	"url":           "Network activity",
+	"email-src":     "Payload delivery",
+	"filename":      "Payload delivery",
+	"size-in-bytes": "Other",
+	"mime-type":     "Payload delivery",
+	"md5":           "Payload delivery",
+	"sha1":          "Payload delivery",
+	"sha256":        "Payload delivery",
+	"sha512":        "Payload delivery",
+	"ssdeep":        "Payload delivery",
+	"tlsh":          "Payload delivery",
+}
+
+// comparisonPattern сравнение на равенство выражения шаблона STIX, например, "file:hashes.'SHA-256' = '...'"
+var comparisonPattern = regexp.MustCompile(`^\s*([a-z0-9-]+):([A-Za-z0-9_.'-]+)\s*=\s*'((?:[^'\\]|\\.)*)'\s*(?:AND\s|$)`)
+
+// ExportResult результат экспорта объектов STIX в событие MISP
+// Unmapped - объекты, на которые ссылается отчет или группа, а также маркеры и хеши объектов, не имеющие соответствия
+// в MISP, в виде "<идентификатор>: <причина>"
+type ExportResult struct {
+	Event    Event
+	Unmapped []string
+}
+
+// ExportEvent записывает в w документ MISP (см. EventDocument) в формате JSON, соответствующий отчету или группе
+// с идентификатором containerID (см. ConvertToEvent), и возвращает список объектов, не имеющих соответствия в MISP
+func ExportEvent(w io.Writer, containerID string, objects []interface{}) ([]string, error) {
+	result, err := ConvertToEvent(containerID, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.MarshalIndent(EventDocument{Event: result.Event}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+
+	return result.Unmapped, nil
+}
+
+// ConvertToEvent преобразует объект "report" или "grouping" с идентификатором containerID (при пустом значении
+// первый такой объект списка) и объекты, на которые он ссылается, в событие MISP:
+//   - свойство Labels и маркеры TLP 1.0 в метки события и атрибутов;
+//   - объект "identity", создавший отчет, в организацию события;
+//   - объекты "indicator" с шаблоном, состоящим из сравнений на равенство свойств одного объекта STIX CO,
+//     в атрибуты с признаком to_ids;
+//   - объекты STIX CO, в том числе входящие в объекты "observed-data", в атрибуты, объекты "file" с несколькими
+//     свойствами в объекты MISP "file";
+//   - объекты "attack-pattern", "threat-actor" и "malware" в кластеры галактик.
+//
+// Объекты списка objects могут быть типами объектов STIX или json.RawMessage, при наличии нескольких версий
+// объекта используется последняя
+func ConvertToEvent(containerID string, objects []interface{}) (ExportResult, error) {
+	ex := exporter{
+		objects:  map[string]map[string]interface{}{},
+		exported: map[string]bool{},
+		galaxies: map[string]int{},
+	}
+
+	for _, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return ExportResult{}, err
+		}
+
+		id, objType := getString(m, "id"), getString(m, "type")
+		if id == "" || objType == "" {
+			return ExportResult{}, fmt.Errorf("the object must contain the properties 'id' and 'type'")
+		}
+
+		if containerID == "" && (objType == "report" || objType == "grouping") {
+			containerID = id
+		}
+
+		if current, ok := ex.objects[id]; ok && commonlibs.CompareTimestamps(getString(current, "modified"), getString(m, "modified")) > 0 {
+			continue
+		}
+		ex.objects[id] = m
+	}
+
+	container, ok := ex.objects[containerID]
+	if !ok {
+		return ExportResult{}, fmt.Errorf("the object 'report' or 'grouping' '%s' is not found", containerID)
+	}
+
+	if objType := getString(container, "type"); objType != "report" && objType != "grouping" {
+		return ExportResult{}, fmt.Errorf("the object '%s' must be of the type 'report' or 'grouping'", containerID)
+	}
+
+	return ex.convert(container), nil
+}
+
+type exporter struct {
+	objects  map[string]map[string]interface{}
+	event    Event
+	unmapped []string
+	exported map[string]bool
+	galaxies map[string]int
+}
+
+func (ex *exporter) convert(container map[string]interface{}) ExportResult {
+	date := getString(container, "published")
+	if date == "" {
+		date = getString(container, "created")
+	}
+
+	ex.event = Event{
+		UUID:          getUUID(getString(container, "id")),
+		Info:          getString(container, "name"),
+		Date:          getDate(date),
+		Timestamp:     getUnixTimestamp(getString(container, "modified")),
+		ThreatLevelID: "4",
+		Analysis:      "2",
+		Tags:          ex.getTags(container),
+	}
+
+	if identity, ok := ex.objects[getString(container, "created_by_ref")]; ok && getString(identity, "name") != "" {
+		ex.event.Orgc = &Organisation{UUID: getUUID(getString(identity, "id")), Name: getString(identity, "name")}
+	}
+
+	for _, ref := range getStrings(container, "object_refs") {
+		ex.convertObject(ref)
+	}
+
+	return ExportResult{Event: ex.event, Unmapped: ex.unmapped}
+}
+
+// convertObject преобразует объект, на который ссылается отчет или группа
+func (ex *exporter) convertObject(id string) {
+	if ex.exported[id] {
+		return
+	}
+	ex.exported[id] = true
+
+	m, ok := ex.objects[id]
+	if !ok {
+		ex.unmapped = append(ex.unmapped, id+": the object is not found")
+
+		return
+	}
+
+	switch objType := getString(m, "type"); objType {
+	case "indicator":
+		ex.convertIndicator(m)
+
+	case "observed-data":
+		for _, ref := range getStrings(m, "object_refs") {
+			ex.convertObject(ref)
+		}
+
+	case "attack-pattern", "threat-actor", "malware":
+		ex.convertGalaxyCluster(m)
+
+	default:
+		obs, ok := getMapObservable(m)
+		if !ok {
+			ex.unmapped = append(ex.unmapped, fmt.Sprintf("%s: unsupported object type '%s'", id, objType))
+
+			return
+		}
+
+		ex.addAttributes(obs, m, false, "", "")
+	}
+}
+
+// convertIndicator преобразует объект "indicator" с простым шаблоном в атрибуты с признаком to_ids
+func (ex *exporter) convertIndicator(m map[string]interface{}) {
+	id := getString(m, "id")
+	if patternType := getString(m, "pattern_type"); patternType != "" && patternType != "stix" {
+		ex.unmapped = append(ex.unmapped, fmt.Sprintf("%s: unsupported pattern type '%s'", id, patternType))
+
+		return
+	}
+
+	obs, ok := parsePattern(getString(m, "pattern"))
+	if !ok {
+		ex.unmapped = append(ex.unmapped, id+": the pattern is not a simple equality pattern")
+
+		return
+	}
+
+	timestamp := getString(m, "modified")
+	if timestamp == "" {
+		timestamp = getString(m, "valid_from")
+	}
+
+	ex.addAttributes(obs, m, true, getString(m, "description"), timestamp)
+}
+
+// addAttributes добавляет в событие атрибуты, соответствующие объекту STIX CO, при нескольких атрибутах
+// они объединяются в объект MISP "file"
+func (ex *exporter) addAttributes(obs observable, m map[string]interface{}, toIDs bool, comment, timestamp string) {
+	attributes, algorithms := obs.getAttributes()
+	for _, v := range algorithms {
+		ex.unmapped = append(ex.unmapped, fmt.Sprintf("%s: unsupported hash algorithm '%s'", getString(m, "id"), v))
+	}
+
+	if len(attributes) == 0 {
+		ex.unmapped = append(ex.unmapped, getString(m, "id")+": the object does not contain supported properties")
+
+		return
+	}
+
+	uuid := getUUID(getString(m, "id"))
+	tags := ex.getTags(m)
+	unixTimestamp := getUnixTimestamp(timestamp)
+
+	for i := range attributes {
+		attributes[i].ToIDs = BoolValue(toIDs)
+		attributes[i].Timestamp = unixTimestamp
+		attributes[i].Tags = tags
+	}
+
+	if len(attributes) == 1 {
+		attributes[0].UUID = uuid
+		attributes[0].Comment = comment
+		attributes[0].ObjectRelation = ""
+		ex.event.Attributes = append(ex.event.Attributes, attributes[0])
+
+		return
+	}
+
+	ex.event.Objects = append(ex.event.Objects, Object{
+		UUID:         uuid,
+		Name:         "file",
+		MetaCategory: "file",
+		Comment:      comment,
+		Timestamp:    unixTimestamp,
+		Attributes:   attributes,
+	})
+}
+
+// convertGalaxyCluster преобразует объект "attack-pattern", "threat-actor" или "malware" в кластер галактики,
+// объекты со ссылками на источник "mitre-attack" преобразуются в кластеры галактик MITRE ATT&CK
+func (ex *exporter) convertGalaxyCluster(m map[string]interface{}) {
+	objType := getString(m, "type")
+	meta := map[string]interface{}{}
+	externalIDs := []string{}
+	refs := []string{}
+	isMITRE := false
+
+	list, _ := m["external_references"].([]interface{})
+	for _, item := range list {
+		reference, _ := item.(map[string]interface{})
+		if getString(reference, "source_name") == "mitre-attack" {
+			isMITRE = true
+		}
+
+		if v := getString(reference, "external_id"); v != "" {
+			externalIDs = append(externalIDs, v)
+		}
+
+		if v := getString(reference, "url"); v != "" {
+			refs = append(refs, v)
+		}
+	}
+
+	galaxyType := objType
+	if isMITRE && objType != "threat-actor" {
+		galaxyType = "mitre-" + objType
+	}
+
+	value := getString(m, "name")
+	if isMITRE && len(externalIDs) > 0 {
+		value += " - " + externalIDs[0]
+	}
+
+	if aliases := getStrings(m, "aliases"); len(aliases) > 0 {
+		meta["synonyms"] = aliases
+	}
+	if len(externalIDs) > 0 {
+		meta["external_id"] = externalIDs
+	}
+	if len(refs) > 0 {
+		meta["refs"] = refs
+	}
+
+	phases := []string{}
+	list, _ = m["kill_chain_phases"].([]interface{})
+	for _, item := range list {
+		phase, _ := item.(map[string]interface{})
+		phases = append(phases, getString(phase, "kill_chain_name")+":"+getString(phase, "phase_name"))
+	}
+	if len(phases) > 0 {
+		meta["kill_chain"] = phases
+	}
+
+	if len(meta) == 0 {
+		meta = nil
+	}
+
+	cluster := GalaxyCluster{
+		UUID:        getUUID(getString(m, "id")),
+		Type:        galaxyType,
+		Value:       value,
+		TagName:     fmt.Sprintf("misp-galaxy:%s=\"%s\"", galaxyType, value),
+		Description: getString(m, "description"),
+		Meta:        meta,
+	}
+
+	i, ok := ex.galaxies[galaxyType]
+	if !ok {
+		i = len(ex.event.Galaxies)
+		ex.galaxies[galaxyType] = i
+		ex.event.Galaxies = append(ex.event.Galaxies, Galaxy{Type: galaxyType})
+	}
+
+	ex.event.Galaxies[i].Clusters = append(ex.event.Galaxies[i].Clusters, cluster)
+	ex.event.Tags = append(ex.event.Tags, Tag{Name: cluster.TagName})
+}
+
+// getTags возвращает метки MISP, соответствующие свойству Labels и маркерам TLP 1.0 и TLP 2.0 объекта,
+// остальные маркеры перечисляются в unmapped
+func (ex *exporter) getTags(m map[string]interface{}) []Tag {
+	var tags []Tag
+	for _, ref := range getStrings(m, "object_marking_refs") {
+		md, ok := stixhelpers.GetTLPMarkingDefinitionByIDSTIX(stixhelpers.IdentifierTypeSTIX(ref))
+		if !ok {
+			md, ok = stixhelpers.GetTLP20MarkingDefinitionByIDSTIX(stixhelpers.IdentifierTypeSTIX(ref))
+		}
+
+		if !ok {
+			ex.unmapped = append(ex.unmapped, fmt.Sprintf("%s: unsupported marking definition '%s'", getString(m, "id"), ref))
+
+			continue
+		}
+
+		tags = append(tags, Tag{Name: strings.ToLower(md.Name)})
+	}
+
+	for _, label := range getStrings(m, "labels") {
+		tags = append(tags, Tag{Name: label})
+	}
+
+	return tags
+}
+
+// getMapObservable возвращает значение объекта STIX CO, представленного в виде map[string]interface{}
+func getMapObservable(m map[string]interface{}) (observable, bool) {
+	objType := getString(m, "type")
+	if _, ok := attributeTypes[objType]; ok {
+		value := getString(m, "value")
+
+		return observable{objType: objType, value: value}, value != ""
+	}
+
+	if objType != "file" {
+		return observable{}, false
+	}
+
+	obs := observable{objType: objType, name: getString(m, "name"), hashes: map[string]string{}, mimeType: getString(m, "mime_type")}
+	hashes, _ := m["hashes"].(map[string]interface{})
+	for k, v := range hashes {
+		if s, ok := v.(string); ok {
+			obs.hashes[k] = s
+		}
+	}
+
+	if size, ok := m["size"].(float64); ok && size > 0 {
+		obs.size = uint64(size)
+	}
+
+	return obs, obs.name != "" || len(obs.hashes) > 0
+}
+
+// parsePattern возвращает значение объекта STIX CO, соответствующее шаблону STIX вида
+// "[<тип>:<свойство> = '<значение>' AND ...]", все сравнения которого относятся к одному объекту
+func parsePattern(pattern string) (observable, bool) {
+	pattern = strings.TrimSpace(pattern)
+	if !strings.HasPrefix(pattern, "[") || !strings.HasSuffix(pattern, "]") {
+		return observable{}, false
+	}
+
+	obs := observable{hashes: map[string]string{}}
+	expression := strings.TrimSpace(pattern[1 : len(pattern)-1])
+	for expression != "" {
+		match := comparisonPattern.FindStringSubmatch(expression)
+		if match == nil {
+			return observable{}, false
+		}
+		expression = strings.TrimSpace(expression[len(match[0]):])
+
+		objType, path := match[1], match[2]
+		value := strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(match[3])
+		if obs.objType != "" && obs.objType != objType {
+			return observable{}, false
+		}
+		obs.objType = objType
+
+		switch {
+		case objType == "file" && path == "name":
+			obs.name = value
+		case objType == "file" && strings.HasPrefix(path, "hashes."):
+			obs.hashes[strings.Trim(strings.TrimPrefix(path, "hashes."), "'")] = value
+		case objType != "file" && path == "value" && attributeTypes[objType] != "":
+			obs.value = value
+		default:
+			return observable{}, false
+		}
+	}
+
+	return obs, obs.objType != ""
+}
+
+// getAttributes возвращает атрибуты MISP, соответствующие объекту STIX CO, для объекта "file" указываются
+// наименования свойств объекта MISP "file". Дополнительно возвращаются алгоритмы хеширования, не имеющие
+// соответствующих типов атрибутов MISP
+func (obs observable) getAttributes() ([]Attribute, []string) {
+	newAttribute := func(attrType, relation, value string) Attribute {
+		return Attribute{Type: attrType, Category: attributeCategories[attrType], Value: value, ObjectRelation: relation}
+	}
+
+	if obs.objType != "file" {
+		if attrType, ok := attributeTypes[obs.objType]; ok && obs.value != "" {
+			return []Attribute{newAttribute(attrType, "", obs.value)}, nil
+		}
+
+		return nil, nil
+	}
+
+	attributes := []Attribute{}
+	if obs.name != "" {
+		attributes = append(attributes, newAttribute("filename", "filename", obs.name))
+	}
+
+	algorithms := make([]string, 0, len(obs.hashes))
+	for k := range obs.hashes {
+		algorithms = append(algorithms, k)
+	}
+	sort.Strings(algorithms)
+
+	unsupported := []string{}
+	for _, algorithm := range algorithms {
+		supported := false
+		for attrType, v := range hashAlgorithms {
+			if strings.EqualFold(v, algorithm) {
+				attributes = append(attributes, newAttribute(attrType, attrType, obs.hashes[algorithm]))
+				supported = true
+			}
+		}
+
+		if !supported {
+			unsupported = append(unsupported, algorithm)
+		}
+	}
+
+	if obs.size > 0 {
+		attributes = append(attributes, newAttribute("size-in-bytes", "size-in-bytes", strconv.FormatUint(obs.size, 10)))
+	}
+
+	if obs.mimeType != "" {
+		attributes = append(attributes, newAttribute("mime-type", "mimetype", obs.mimeType))
+	}
+
+	return attributes, unsupported
+}
+
+// getString возвращает строковое свойство объекта
+func getString(m map[string]interface{}, name string) string {
+	v, _ := m[name].(string)
+
+	return v
+}
+
+// getStrings возвращает свойство объекта, являющееся списком строк
+func getStrings(m map[string]interface{}, name string) []string {
+	list, _ := m[name].([]interface{})
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// getUUID возвращает UUID идентификатора объекта STIX
+func getUUID(id string) string {
+	_, uuid, ok := strings.Cut(id, "--")
+	if !ok {
+		return id
+	}
+
+	return uuid
+}
+
+// getDate возвращает дату события MISP, соответствующую времени в формате RFC3339
+func getDate(v string) string {
+	t, err := commonlibs.ParseTimestamp(v)
+	if err != nil {
+		return ""
+	}
+
+	return t.UTC().Format(dateLayout)
+}
+
+// getUnixTimestamp возвращает время MISP (секунды Unix), соответствующее времени в формате RFC3339
+func getUnixTimestamp(v string) StringValue {
+	t, err := commonlibs.ParseTimestamp(v)
+	if err != nil || t.Unix() <= 0 {
+		return ""
+	}
+
+	return StringValue(strconv.FormatInt(t.Unix(), 10))
+}
@@ -0,0 +1,701 @@
+package misp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/commonproperties"
+	"github.com/av-belyakov/methodstixobjects/datamodels/commonpropertiesstixco"
+	"github.com/av-belyakov/methodstixobjects/datamodels/commonpropertiesstixdo"
+	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// galaxyTypes соответствие типов галактик MISP типам объектов STIX
+var galaxyTypes = map[string]string{
+	"threat-actor":                           "threat-actor",
+	"attack-pattern":                         "attack-pattern",
+	"mitre-attack-pattern":                   "attack-pattern",
+	"mitre-enterprise-attack-attack-pattern": "attack-pattern",
+	"mitre-mobile-attack-attack-pattern":     "attack-pattern",
+	"mitre-pre-attack-attack-pattern":        "attack-pattern",
+	"malware":                                "malware",
+	"mitre-malware":                          "malware",
+	"mitre-enterprise-attack-malware":        "malware",
+	"mitre-mobile-attack-malware":            "malware",
+	"malpedia":                               "malware",
+	"ransomware":                             "malware",
+	"rat":                                    "malware",
+	"banker":                                 "malware",
+	"stealer":                                "malware",
+	"backdoor":                               "malware",
+	"botnet":                                 "malware",
+}
+
+// hashAlgorithms соответствие типов атрибутов MISP наименованиям алгоритмов хеширования STIX
+var hashAlgorithms = map[string]string{
+	"md5":    "MD5",
+	"sha1":   "SHA-1",
+	"sha256": "SHA-256",
+	"sha512": "SHA-512",
+	"ssdeep": "SSDEEP",
+	"tlsh":   "TLSH",
+}
+
+// fileIDHashAlgorithms порядок выбора хеша, используемого при формировании идентификатора объекта "file"
+// (STIX 2.1, раздел 2.9)
+var fileIDHashAlgorithms = []string{"MD5", "SHA-1", "SHA-256", "SHA-512"}
+
+// tlpTags соответствие меток TLP MISP уровням TLP 1.0
+var tlpTags = map[string]string{
+	"tlp:white": "white",
+	"tlp:green": "green",
+	"tlp:amber": "amber",
+	"tlp:red":   "red",
+}
+
+// tlp20Tags соответствие меток TLP MISP, отсутствующих в TLP 1.0, уровням TLP 2.0
+var tlp20Tags = map[string]string{
+	"tlp:clear":        "clear",
+	"tlp:amber+strict": "amber+strict",
+}
+
+// ImportResult результат импорта события MISP
+// Report - объект "report", соответствующий событию, ссылающийся на все остальные объекты кроме "identity" и "marking-definition"
+// Objects - все созданные объекты STIX, включая Report (первый элемент списка)
+// Unmapped - описание атрибутов, объектов и кластеров галактик MISP, для которых нет соответствующих объектов STIX
+type ImportResult struct {
+	Report   domainobjectsstix.ReportDomainObjectsSTIX
+	Objects  []interface{}
+	Unmapped []string
+}
+
+// ImportEvent выполняет импорт события MISP в формате JSON (см. ParseEvent)
+func ImportEvent(data []byte) (ImportResult, error) {
+	event, err := ParseEvent(data)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return ConvertEvent(event)
+}
+
+// ConvertEvent преобразует событие MISP в объекты STIX:
+//   - событие в объект "report", организацию события в объект "identity";
+//   - атрибуты в объекты STIX CO ("ipv4-addr", "ipv6-addr", "domain-name", "url", "email-addr", "file"),
+//     а атрибуты с признаком to_ids в объекты "indicator";
+//   - объекты MISP в объекты "observed-data", группирующие объекты STIX CO их атрибутов, при наличии у атрибутов
+//     признака to_ids дополнительно создается объект "indicator";
+//   - кластеры галактик в объекты "threat-actor", "malware" и "attack-pattern";
+//   - метки TLP в ссылки на маркеры TLP 1.0 (метки tlp:clear и tlp:amber+strict в ссылки на маркеры TLP 2.0),
+//     остальные метки (кроме меток галактик) в свойство Labels.
+//
+// Идентификаторы объектов формируются из UUID соответствующих элементов события, идентификаторы объектов STIX CO
+// формируются детерминированно на основании их значений, поэтому повторный импорт события не создает новых объектов
+// Если ни один элемент события не преобразован в объект STIX, возвращается ошибка
+func ConvertEvent(event Event) (ImportResult, error) {
+	imp := importer{
+		event: event,
+		ids:   map[string]bool{},
+	}
+
+	return imp.convert()
+}
+
+type importer struct {
+	event        Event
+	timestamp    string
+	createdByRef stixhelpers.IdentifierTypeSTIX
+	markings     []stixhelpers.IdentifierTypeSTIX
+	objects      []interface{}
+	refs         []stixhelpers.IdentifierTypeSTIX
+	unmapped     []string
+	ids          map[string]bool
+}
+
+func (imp *importer) convert() (ImportResult, error) {
+	e := imp.event
+	if e.UUID == "" {
+		e.UUID = commonlibs.GetUUIDv4()
+	}
+
+	imp.timestamp = getTimestamp(string(e.Timestamp))
+	if imp.timestamp == "" {
+		imp.timestamp = getTimestamp(e.Date)
+	}
+	if imp.timestamp == "" {
+		imp.timestamp = commonlibs.TimeNowRFC3339()
+	}
+
+	if e.Orgc != nil && e.Orgc.Name != "" {
+		uuid := e.Orgc.UUID
+		if uuid == "" {
+			var err error
+			if uuid, err = commonlibs.GetUUIDv5(commonlibs.STIXNamespaceUUID, e.Orgc.Name); err != nil {
+				return ImportResult{}, err
+			}
+		}
+
+		identity := domainobjectsstix.IdentityDomainObjectsSTIX{
+			CommonPropertiesObjectSTIX:       commonproperties.CommonPropertiesObjectSTIX{Type: "identity", ID: "identity--" + uuid},
+			CommonPropertiesDomainObjectSTIX: commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX{SpecVersion: "2.1", Created: imp.timestamp, Modified: imp.timestamp},
+			Name:                             e.Orgc.Name,
+			IdentityClass:                    "organization",
+		}
+		imp.add(identity.ID, identity, false)
+		imp.createdByRef = stixhelpers.IdentifierTypeSTIX(identity.ID)
+	}
+
+	markings, labels := imp.getMarkings(e.Tags)
+	imp.markings = markings
+
+	for _, a := range e.Attributes {
+		if err := imp.convertAttribute(a); err != nil {
+			return ImportResult{}, err
+		}
+	}
+
+	for _, o := range e.Objects {
+		if err := imp.convertObject(o); err != nil {
+			return ImportResult{}, err
+		}
+	}
+
+	for _, g := range e.Galaxies {
+		for _, c := range g.Clusters {
+			imp.convertGalaxyCluster(g, c)
+		}
+	}
+
+	//отчет STIX должен ссылаться хотя бы на один объект
+	if len(imp.refs) == 0 {
+		return ImportResult{}, fmt.Errorf("the MISP event '%s' does not contain attributes, objects or galaxy clusters that can be converted to STIX objects", e.UUID)
+	}
+
+	published := getTimestamp(e.Date)
+	if published == "" {
+		published = getTimestamp(string(e.PublishTimestamp))
+	}
+	if published == "" {
+		published = imp.timestamp
+	}
+
+	report := domainobjectsstix.ReportDomainObjectsSTIX{
+		CommonPropertiesObjectSTIX:       commonproperties.CommonPropertiesObjectSTIX{Type: "report", ID: "report--" + e.UUID},
+		CommonPropertiesDomainObjectSTIX: imp.getCommonProperties(imp.timestamp, labels, markings),
+		Name:                             e.Info,
+		Published:                        published,
+		ReportTypes:                      []stixhelpers.OpenVocabTypeSTIX{"threat-report"},
+		ObjectRefs:                       imp.refs,
+	}
+
+	return ImportResult{
+		Report:   report,
+		Objects:  append([]interface{}{report}, imp.objects...),
+		Unmapped: imp.unmapped,
+	}, nil
+}
+
+// convertAttribute преобразует атрибут события в объект STIX CO или, при наличии признака to_ids, в объект "indicator"
+func (imp *importer) convertAttribute(a Attribute) error {
+	obs, ok := parseAttribute(a.Type, a.Value)
+	if !ok {
+		imp.unmapped = append(imp.unmapped, fmt.Sprintf("attribute %s '%s'", a.Type, a.Value))
+
+		return nil
+	}
+
+	markings, labels := imp.getMarkings(a.Tags)
+	if len(markings) == 0 {
+		markings = imp.markings
+	}
+
+	if bool(a.ToIDs) {
+		imp.addIndicator(a.UUID, a.Value, a.Comment, string(a.Timestamp), labels, markings, []observable{obs})
+
+		return nil
+	}
+
+	_, err := imp.addObservable(obs, markings)
+
+	return err
+}
+
+// convertObject преобразует объект события в объект "observed-data", атрибуты объекта "file" объединяются
+// в один объект "file"
+func (imp *importer) convertObject(o Object) error {
+	observables := []observable{}
+	toIDs := false
+	file := observable{objType: "file", hashes: map[string]string{}}
+
+	for _, a := range o.Attributes {
+		value := strings.TrimSpace(a.Value)
+		if o.Name == "file" {
+			switch a.Type {
+			case "size-in-bytes":
+				if size, err := strconv.ParseUint(value, 10, 64); err == nil {
+					file.size = size
+					toIDs = toIDs || bool(a.ToIDs)
+
+					continue
+				}
+			case "mime-type":
+				file.mimeType = value
+
+				continue
+			}
+		}
+
+		obs, ok := parseAttribute(a.Type, a.Value)
+		if !ok {
+			imp.unmapped = append(imp.unmapped, fmt.Sprintf("attribute %s '%s' of object %s", a.Type, a.Value, o.Name))
+
+			continue
+		}
+		toIDs = toIDs || bool(a.ToIDs)
+
+		if o.Name == "file" && obs.objType == "file" {
+			file.merge(obs)
+
+			continue
+		}
+
+		observables = append(observables, obs)
+	}
+
+	if file.name != "" || len(file.hashes) > 0 {
+		observables = append([]observable{file}, observables...)
+	}
+
+	if len(observables) == 0 {
+		imp.unmapped = append(imp.unmapped, fmt.Sprintf("object %s '%s'", o.Name, o.UUID))
+
+		return nil
+	}
+
+	uuid := o.UUID
+	if uuid == "" {
+		uuid = commonlibs.GetUUIDv4()
+	}
+
+	timestamp := getTimestamp(string(o.Timestamp))
+	if timestamp == "" {
+		timestamp = imp.timestamp
+	}
+
+	refs := []stixhelpers.IdentifierTypeSTIX{}
+	for _, obs := range observables {
+		id, err := imp.addObservable(obs, imp.markings)
+		if err != nil {
+			return err
+		}
+
+		refs = append(refs, id)
+	}
+
+	observedData := domainobjectsstix.ObservedDataDomainObjectsSTIX{
+		CommonPropertiesObjectSTIX:       commonproperties.CommonPropertiesObjectSTIX{Type: "observed-data", ID: "observed-data--" + uuid},
+		CommonPropertiesDomainObjectSTIX: imp.getCommonProperties(timestamp, nil, imp.markings),
+		NumberObserved:                   1,
+		FirstObserved:                    timestamp,
+		LastObserved:                     timestamp,
+		ObjectRefs:                       refs,
+	}
+	imp.add(observedData.ID, observedData, true)
+
+	if toIDs {
+		imp.addIndicator(uuid, o.Name, o.Comment, string(o.Timestamp), nil, imp.markings, observables)
+	}
+
+	return nil
+}
+
+// convertGalaxyCluster преобразует кластер галактики в объект "threat-actor", "malware" или "attack-pattern"
+func (imp *importer) convertGalaxyCluster(g Galaxy, c GalaxyCluster) {
+	galaxyType := c.Type
+	if galaxyType == "" {
+		galaxyType = g.Type
+	}
+
+	objType, ok := galaxyTypes[galaxyType]
+	if !ok || c.Value == "" {
+		imp.unmapped = append(imp.unmapped, fmt.Sprintf("galaxy cluster %s '%s'", galaxyType, c.Value))
+
+		return
+	}
+
+	uuid := c.UUID
+	if uuid == "" {
+		uuid = commonlibs.GetUUIDv4()
+	}
+
+	sourceName := galaxyType
+	if strings.HasPrefix(galaxyType, "mitre-") {
+		sourceName = "mitre-attack"
+	}
+
+	name := c.Value
+	references := []stixhelpers.ExternalReferenceTypeElementSTIX{}
+	for _, v := range c.getMetaValues("external_id") {
+		name = strings.TrimSuffix(name, " - "+v)
+		references = append(references, stixhelpers.ExternalReferenceTypeElementSTIX{SourceName: sourceName, ExternalID: v})
+	}
+	for _, v := range c.getMetaValues("refs") {
+		references = append(references, stixhelpers.ExternalReferenceTypeElementSTIX{SourceName: sourceName, URL: v})
+	}
+
+	common := imp.getCommonProperties(imp.timestamp, nil, imp.markings)
+	//кластеры галактик не создаются организацией события
+	common.CreatedByRef = ""
+	if len(references) > 0 {
+		common.ExternalReferences = references
+	}
+
+	aliases := c.getMetaValues("synonyms")
+	if len(aliases) == 0 {
+		aliases = nil
+	}
+
+	id := objType + "--" + uuid
+	properties := commonproperties.CommonPropertiesObjectSTIX{Type: objType, ID: id}
+
+	switch objType {
+	case "threat-actor":
+		imp.add(id, domainobjectsstix.ThreatActorDomainObjectsSTIX{
+			CommonPropertiesObjectSTIX:       properties,
+			CommonPropertiesDomainObjectSTIX: common,
+			Name:                             name,
+			Description:                      c.Description,
+			Aliases:                          aliases,
+		}, true)
+
+	case "malware":
+		imp.add(id, domainobjectsstix.MalwareDomainObjectsSTIX{
+			CommonPropertiesObjectSTIX:       properties,
+			CommonPropertiesDomainObjectSTIX: common,
+			IsFamily:                         true,
+			Name:                             name,
+			Description:                      c.Description,
+			Aliases:                          aliases,
+		}, true)
+
+	case "attack-pattern":
+		phases := []stixhelpers.KillChainPhasesTypeElementSTIX(nil)
+		for _, v := range c.getMetaValues("kill_chain") {
+			list := strings.Split(v, ":")
+			if len(list) < 2 {
+				continue
+			}
+
+			phases = append(phases, stixhelpers.KillChainPhasesTypeElementSTIX{KillChainName: list[0], PhaseName: list[len(list)-1]})
+		}
+
+		imp.add(id, domainobjectsstix.AttackPatternDomainObjectsSTIX{
+			CommonPropertiesObjectSTIX:       properties,
+			CommonPropertiesDomainObjectSTIX: common,
+			Name:                             name,
+			Description:                      c.Description,
+			Aliases:                          aliases,
+			KillChainPhases:                  phases,
+		}, true)
+	}
+}
+
+// addIndicator добавляет объект "indicator", шаблон которого соответствует объектам STIX CO observables
+func (imp *importer) addIndicator(uuid, name, description, timestamp string, labels []string, markings []stixhelpers.IdentifierTypeSTIX, observables []observable) {
+	if uuid == "" {
+		uuid = commonlibs.GetUUIDv4()
+	}
+
+	validFrom := getTimestamp(timestamp)
+	if validFrom == "" {
+		validFrom = imp.timestamp
+	}
+
+	patterns := make([]string, 0, len(observables))
+	for _, obs := range observables {
+		patterns = append(patterns, obs.getPattern())
+	}
+
+	indicator := domainobjectsstix.IndicatorDomainObjectsSTIX{
+		CommonPropertiesObjectSTIX:       commonproperties.CommonPropertiesObjectSTIX{Type: "indicator", ID: "indicator--" + uuid},
+		CommonPropertiesDomainObjectSTIX: imp.getCommonProperties(validFrom, labels, markings),
+		Name:                             name,
+		Description:                      description,
+		Pattern:                          strings.Join(patterns, " OR "),
+		PatternType:                      "stix",
+		PatternVersion:                   "2.1",
+		ValidFrom:                        validFrom,
+	}
+	imp.add(indicator.ID, indicator, true)
+}
+
+// addObservable добавляет объект STIX CO, соответствующий obs, если объект с таким же идентификатором
+// еще не был добавлен, и возвращает его идентификатор
+func (imp *importer) addObservable(obs observable, markings []stixhelpers.IdentifierTypeSTIX) (stixhelpers.IdentifierTypeSTIX, error) {
+	id, err := obs.getID()
+	if err != nil {
+		return "", err
+	}
+
+	properties := commonproperties.CommonPropertiesObjectSTIX{Type: obs.objType, ID: id}
+	optional := commonpropertiesstixco.OptionalCommonPropertiesCyberObservableObjectSTIX{SpecVersion: "2.1", ObjectMarkingRefs: markings}
+
+	var obj interface{}
+	switch obs.objType {
+	case "ipv4-addr":
+		obj = cyberobservableobjectsstix.IPv4AddressCyberObservableObjectSTIX{CommonPropertiesObjectSTIX: properties, OptionalCommonPropertiesCyberObservableObjectSTIX: optional, Value: obs.value}
+	case "ipv6-addr":
+		obj = cyberobservableobjectsstix.IPv6AddressCyberObservableObjectSTIX{CommonPropertiesObjectSTIX: properties, OptionalCommonPropertiesCyberObservableObjectSTIX: optional, Value: obs.value}
+	case "domain-name":
+		obj = cyberobservableobjectsstix.DomainNameCyberObservableObjectSTIX{CommonPropertiesObjectSTIX: properties, OptionalCommonPropertiesCyberObservableObjectSTIX: optional, Value: obs.value}
+	case "url":
+		obj = cyberobservableobjectsstix.URLCyberObservableObjectSTIX{CommonPropertiesObjectSTIX: properties, OptionalCommonPropertiesCyberObservableObjectSTIX: optional, Value: obs.value}
+	case "email-addr":
+		obj = cyberobservableobjectsstix.EmailAddressCyberObservableObjectSTIX{CommonPropertiesObjectSTIX: properties, OptionalCommonPropertiesCyberObservableObjectSTIX: optional, Value: obs.value}
+	case "file":
+		file := cyberobservableobjectsstix.FileCyberObservableObjectSTIX{
+			CommonPropertiesObjectSTIX:                        properties,
+			OptionalCommonPropertiesCyberObservableObjectSTIX: optional,
+			Name:     obs.name,
+			Size:     obs.size,
+			MimeType: obs.mimeType,
+		}
+		if len(obs.hashes) > 0 {
+			file.Hashes = stixhelpers.HashesTypeSTIX(obs.hashes)
+		}
+		obj = file
+	default:
+		return "", fmt.Errorf("unsupported type of the cyber observable object '%s'", obs.objType)
+	}
+
+	imp.add(id, obj, true)
+
+	return stixhelpers.IdentifierTypeSTIX(id), nil
+}
+
+// add добавляет объект в результат импорта, если объект с таким идентификатором еще не был добавлен,
+// при isRef равном true ссылка на объект добавляется в свойство object_refs объекта "report"
+func (imp *importer) add(id string, obj interface{}, isRef bool) {
+	if imp.ids[id] {
+		return
+	}
+
+	imp.ids[id] = true
+	imp.objects = append(imp.objects, obj)
+	if isRef {
+		imp.refs = append(imp.refs, stixhelpers.IdentifierTypeSTIX(id))
+	}
+}
+
+// getMarkings возвращает ссылки на маркеры TLP и метки, соответствующие меткам MISP, метки галактик пропускаются.
+// Объекты "marking-definition" используемых маркеров добавляются в результат импорта
+func (imp *importer) getMarkings(tags []Tag) ([]stixhelpers.IdentifierTypeSTIX, []string) {
+	var (
+		markings []stixhelpers.IdentifierTypeSTIX
+		labels   []string
+	)
+
+	for _, tag := range tags {
+		name := strings.TrimSpace(tag.Name)
+		if name == "" || strings.HasPrefix(name, "misp-galaxy:") {
+			continue
+		}
+
+		var (
+			md  stixhelpers.MarkingDefinitionObjectSTIX
+			err error
+		)
+
+		if tlp, ok := tlp20Tags[strings.ToLower(name)]; ok {
+			md, err = stixhelpers.GetTLP20MarkingDefinitionSTIX(tlp)
+		} else if tlp, ok := tlpTags[strings.ToLower(name)]; ok {
+			md, err = stixhelpers.GetTLPMarkingDefinitionSTIX(tlp)
+		} else {
+			labels = append(labels, name)
+
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		imp.add(md.ID, md, false)
+		markings = append(markings, stixhelpers.IdentifierTypeSTIX(md.ID))
+	}
+
+	return markings, labels
+}
+
+// getCommonProperties возвращает общие свойства объектов STIX DO
+func (imp *importer) getCommonProperties(timestamp string, labels []string, markings []stixhelpers.IdentifierTypeSTIX) commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX {
+	return commonpropertiesstixdo.CommonPropertiesDomainObjectSTIX{
+		SpecVersion:       "2.1",
+		Created:           timestamp,
+		Modified:          timestamp,
+		Labels:            labels,
+		CreatedByRef:      imp.createdByRef,
+		ObjectMarkingRefs: markings,
+	}
+}
+
+// observable значение атрибута MISP, соответствующее объекту STIX CO
+type observable struct {
+	objType  string
+	value    string
+	name     string
+	hashes   map[string]string
+	size     uint64
+	mimeType string
+}
+
+// parseAttribute возвращает значение атрибута MISP с типом attrType, соответствующее объекту STIX CO
+func parseAttribute(attrType, value string) (observable, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return observable{}, false
+	}
+
+	switch attrType {
+	case "ip-src", "ip-dst", "ip-src|port", "ip-dst|port":
+		ip, _, _ := strings.Cut(value, "|")
+		if strings.Contains(ip, ":") {
+			return observable{objType: "ipv6-addr", value: ip}, true
+		}
+
+		return observable{objType: "ipv4-addr", value: ip}, true
+
+	case "domain", "hostname":
+		return observable{objType: "domain-name", value: value}, true
+
+	case "url":
+		return observable{objType: "url", value: value}, true
+
+	case "email", "email-src", "email-dst":
+		return observable{objType: "email-addr", value: value}, true
+
+	case "filename":
+		return observable{objType: "file", name: value}, true
+	}
+
+	if algorithm, ok := hashAlgorithms[attrType]; ok {
+		return observable{objType: "file", hashes: map[string]string{algorithm: value}}, true
+	}
+
+	if hashType, ok := strings.CutPrefix(attrType, "filename|"); ok {
+		algorithm, ok := hashAlgorithms[hashType]
+		name, hash, found := strings.Cut(value, "|")
+		if !ok || !found {
+			return observable{}, false
+		}
+
+		return observable{objType: "file", name: name, hashes: map[string]string{algorithm: hash}}, true
+	}
+
+	return observable{}, false
+}
+
+// merge дополняет объект "file" именем и значениями хешей другого объекта "file"
+func (obs *observable) merge(other observable) {
+	if obs.name == "" {
+		obs.name = other.name
+	}
+
+	for k, v := range other.hashes {
+		obs.hashes[k] = v
+	}
+}
+
+// getID возвращает детерминированный идентификатор объекта STIX CO, UUID версии 5 которого формируется из
+// значений свойств, определяющих объект (одного хеша и имени для объекта "file", значения для остальных объектов)
+func (obs observable) getID() (string, error) {
+	properties := map[string]interface{}{}
+	if obs.objType == "file" {
+		if algorithm, ok := obs.getIDHashAlgorithm(); ok {
+			properties["hashes"] = map[string]string{algorithm: obs.hashes[algorithm]}
+		}
+		if obs.name != "" {
+			properties["name"] = obs.name
+		}
+	} else {
+		properties["value"] = obs.value
+	}
+
+	buf := bytes.Buffer{}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(properties); err != nil {
+		return "", err
+	}
+
+	uuid, err := commonlibs.GetUUIDv5(commonlibs.STIXNamespaceUUID, strings.TrimSpace(buf.String()))
+	if err != nil {
+		return "", err
+	}
+
+	return obs.objType + "--" + uuid, nil
+}
+
+// getIDHashAlgorithm возвращает алгоритм хеширования, значение хеша которого используется при формировании
+// идентификатора объекта "file". Если хеши алгоритмов fileIDHashAlgorithms отсутствуют, выбирается первый
+// по алфавиту алгоритм
+func (obs observable) getIDHashAlgorithm() (string, bool) {
+	for _, v := range fileIDHashAlgorithms {
+		if _, ok := obs.hashes[v]; ok {
+			return v, true
+		}
+	}
+
+	algorithms := make([]string, 0, len(obs.hashes))
+	for k := range obs.hashes {
+		algorithms = append(algorithms, k)
+	}
+	sort.Strings(algorithms)
+
+	if len(algorithms) == 0 {
+		return "", false
+	}
+
+	return algorithms[0], true
+}
+
+// getPattern возвращает выражение шаблона STIX, соответствующее объекту STIX CO
+func (obs observable) getPattern() string {
+	comparisons := []string{}
+	if obs.objType != "file" {
+		comparisons = append(comparisons, fmt.Sprintf("%s:value = '%s'", obs.objType, escapePatternString(obs.value)))
+	}
+
+	if obs.name != "" {
+		comparisons = append(comparisons, fmt.Sprintf("file:name = '%s'", escapePatternString(obs.name)))
+	}
+
+	algorithms := make([]string, 0, len(obs.hashes))
+	for k := range obs.hashes {
+		algorithms = append(algorithms, k)
+	}
+	sort.Strings(algorithms)
+
+	for _, k := range algorithms {
+		algorithm := k
+		if strings.Contains(k, "-") {
+			algorithm = "'" + k + "'"
+		}
+
+		comparisons = append(comparisons, fmt.Sprintf("file:hashes.%s = '%s'", algorithm, escapePatternString(obs.hashes[k])))
+	}
+
+	return "[" + strings.Join(comparisons, " AND ") + "]"
+}
+
+// escapePatternString экранирует символы "\" и "'" строкового значения шаблона STIX
+func escapePatternString(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v)
+}
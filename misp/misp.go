@@ -0,0 +1,219 @@
+// Package misp импорт событий MISP в объекты STIX и экспорт объектов STIX в события MISP
+package misp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout формат даты события MISP
+const dateLayout = "2006-01-02"
+
+// StringValue строковое значение MISP, при декодировании JSON допускается как строка, так и число
+// (MISP передает числовые значения, например, время в секундах Unix, в виде строк)
+type StringValue string
+
+// UnmarshalJSON декодирует строку или число
+func (v *StringValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*v = ""
+
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*v = StringValue(s)
+
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid value '%s', expected a string or a number", data)
+	}
+	*v = StringValue(n)
+
+	return nil
+}
+
+// BoolValue логическое значение MISP, при декодировании JSON допускается как логическое значение,
+// так и строка или число ("1", "0", "true", "false")
+type BoolValue bool
+
+// UnmarshalJSON декодирует логическое значение, строку или число
+func (v *BoolValue) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(bytes.TrimSpace(data)), `"`)
+	switch strings.ToLower(s) {
+	case "", "null", "0", "false":
+		*v = false
+
+		return nil
+	case "1", "true":
+		*v = true
+
+		return nil
+	}
+
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		*v = n != 0
+
+		return nil
+	}
+
+	return fmt.Errorf("invalid value '%s', expected a boolean", data)
+}
+
+// Organisation организация MISP
+type Organisation struct {
+	UUID string `json:"uuid,omitempty"`
+	Name string `json:"name"`
+}
+
+// Tag метка MISP, например, "tlp:amber" или "misp-galaxy:threat-actor=\"APT 28\""
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Attribute атрибут MISP
+// Type - тип атрибута, например, "ip-dst", "domain", "md5", "filename|sha256"
+// ToIDs - признак использования атрибута для обнаружения (индикатор)
+// ObjectRelation - наименование свойства объекта MISP, которое содержит атрибут
+type Attribute struct {
+	UUID           string      `json:"uuid,omitempty"`
+	Type           string      `json:"type"`
+	Category       string      `json:"category,omitempty"`
+	Value          string      `json:"value"`
+	ToIDs          BoolValue   `json:"to_ids"`
+	Comment        string      `json:"comment,omitempty"`
+	Timestamp      StringValue `json:"timestamp,omitempty"`
+	ObjectRelation string      `json:"object_relation,omitempty"`
+	Tags           []Tag       `json:"Tag,omitempty"`
+}
+
+// Object объект MISP, группа связанных атрибутов, например, "file" или "domain-ip"
+type Object struct {
+	UUID         string      `json:"uuid,omitempty"`
+	Name         string      `json:"name"`
+	MetaCategory string      `json:"meta-category,omitempty"`
+	Comment      string      `json:"comment,omitempty"`
+	Timestamp    StringValue `json:"timestamp,omitempty"`
+	Attributes   []Attribute `json:"Attribute,omitempty"`
+}
+
+// GalaxyCluster кластер галактики MISP, например, конкретная группировка или техника MITRE ATT&CK
+// Meta - дополнительные сведения, например, "synonyms", "external_id", "refs", "kill_chain"
+type GalaxyCluster struct {
+	UUID        string                 `json:"uuid,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Value       string                 `json:"value"`
+	TagName     string                 `json:"tag_name,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Meta        map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Galaxy галактика MISP, набор кластеров одного типа, например, "threat-actor" или "mitre-attack-pattern"
+type Galaxy struct {
+	UUID        string          `json:"uuid,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Type        string          `json:"type"`
+	Description string          `json:"description,omitempty"`
+	Clusters    []GalaxyCluster `json:"GalaxyCluster,omitempty"`
+}
+
+// Event событие MISP
+// Date - дата события в формате "2006-01-02"
+// Timestamp, PublishTimestamp - время изменения и публикации события в секундах Unix
+// Orgc - организация, создавшая событие
+type Event struct {
+	UUID             string        `json:"uuid"`
+	Info             string        `json:"info"`
+	Date             string        `json:"date,omitempty"`
+	Timestamp        StringValue   `json:"timestamp,omitempty"`
+	PublishTimestamp StringValue   `json:"publish_timestamp,omitempty"`
+	Published        BoolValue     `json:"published"`
+	ThreatLevelID    StringValue   `json:"threat_level_id,omitempty"`
+	Analysis         StringValue   `json:"analysis,omitempty"`
+	Distribution     StringValue   `json:"distribution,omitempty"`
+	Orgc             *Organisation `json:"Orgc,omitempty"`
+	Tags             []Tag         `json:"Tag,omitempty"`
+	Attributes       []Attribute   `json:"Attribute,omitempty"`
+	Objects          []Object      `json:"Object,omitempty"`
+	Galaxies         []Galaxy      `json:"Galaxy,omitempty"`
+}
+
+// EventDocument документ MISP, содержащий событие в свойстве "Event"
+type EventDocument struct {
+	Event Event `json:"Event"`
+}
+
+// ParseEvent декодирует событие MISP в формате JSON, событие может быть как вложено в свойство "Event", так и нет
+func ParseEvent(data []byte) (Event, error) {
+	doc := struct {
+		Event json.RawMessage `json:"Event"`
+	}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Event{}, err
+	}
+
+	if len(doc.Event) > 0 {
+		data = doc.Event
+	}
+
+	event := Event{}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return Event{}, err
+	}
+
+	return event, nil
+}
+
+// getTimestamp преобразует время MISP (секунды Unix или дату в формате "2006-01-02") во время в формате RFC3339,
+// при некорректном значении возвращается пустая строка
+func getTimestamp(v string) string {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return ""
+	}
+
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if n <= 0 {
+			return ""
+		}
+
+		return time.Unix(n, 0).UTC().Format(time.RFC3339)
+	}
+
+	if t, err := time.Parse(dateLayout, v); err == nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	return ""
+}
+
+// getMetaValues возвращает значения свойства name дополнительных сведений кластера галактики
+func (c GalaxyCluster) getMetaValues(name string) []string {
+	list := []string{}
+	switch v := c.Meta[name].(type) {
+	case string:
+		list = append(list, v)
+	case []string:
+		list = append(list, v...)
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,74 @@
+package neo4jexport
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CypherStatement параметризованный запрос Cypher
+// Query - текст запроса
+// Parameters - параметры запроса
+type CypherStatement struct {
+	Query      string
+	Parameters map[string]interface{}
+}
+
+// GetCypherStatements возвращает запросы Cypher для инкрементальной загрузки объектов STIX с помощью MERGE.
+// Первым запросом создается ограничение уникальности свойства id узлов с меткой NodeLabel, далее следуют
+// запросы создания (обновления) узлов и связей. Структура узлов и связей аналогична формируемой NewExport,
+// массивы передаются в виде списков, поэтому параметр ArrayDelimiter не используется
+func GetCypherStatements(objects []interface{}, opts Options) ([]CypherStatement, error) {
+	c, err := newCollection(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []CypherStatement{{
+		Query:      fmt.Sprintf("CREATE CONSTRAINT stix_object_id IF NOT EXISTS FOR (n:%s) REQUIRE n.id IS UNIQUE", NodeLabel),
+		Parameters: map[string]interface{}{},
+	}}
+
+	for _, n := range c.list {
+		objType, _ := n["type"].(string)
+		list = append(list, CypherStatement{
+			Query:      fmt.Sprintf("MERGE (n:%s {id: $id}) SET n:%s SET n += $properties", NodeLabel, quoteName(GetLabel(objType))),
+			Parameters: map[string]interface{}{"id": n["id"], "properties": n},
+		})
+	}
+
+	for _, r := range c.relationships {
+		relType, _ := r["relationship_type"].(string)
+		properties := map[string]interface{}{}
+		for k, v := range r {
+			if k != "source_ref" && k != "target_ref" {
+				properties[k] = v
+			}
+		}
+
+		list = append(list, CypherStatement{
+			Query: fmt.Sprintf("MATCH (s:%s {id: $source}), (t:%s {id: $target}) MERGE (s)-[r:%s {id: $id}]->(t) SET r += $properties",
+				NodeLabel, NodeLabel, quoteName(GetRelationshipType(relType))),
+			Parameters: map[string]interface{}{"source": r["source_ref"], "target": r["target_ref"], "id": r["id"], "properties": properties},
+		})
+	}
+
+	if !opts.IncludeEmbeddedRefs {
+		return list, nil
+	}
+
+	for _, v := range c.getEmbeddedRefs() {
+		list = append(list, CypherStatement{
+			Query: fmt.Sprintf("MATCH (s:%s {id: $source}), (t:%s {id: $target}) MERGE (s)-[r:%s]->(t) SET r.property = $property",
+				NodeLabel, NodeLabel, quoteName(GetRelationshipType(v.Property))),
+			Parameters: map[string]interface{}{"source": v.Source, "target": v.Target, "property": v.Property},
+		})
+	}
+
+	return list, nil
+}
+
+// quoteName возвращает метку или тип связи, заключенные в обратные кавычки. Метки и типы связей формируются
+// из значений свойств объектов, поэтому обратные кавычки внутри них экранируются удвоением
+func quoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,518 @@
+package neo4jexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+)
+
+// NodeLabel метка, которая присваивается всем узлам, созданным из объектов STIX
+const NodeLabel = "STIXObject"
+
+// DefaultArrayDelimiter разделитель элементов массивов, используемый neo4j-admin import по умолчанию
+const DefaultArrayDelimiter = ";"
+
+// Options параметры экспорта
+// IncludeEmbeddedRefs - формировать связи на основании встроенных ссылок (created_by_ref, resolves_to_refs и т.д.)
+// ArrayDelimiter - разделитель элементов массивов (параметр --array-delimiter утилиты neo4j-admin import),
+// если не задан используется DefaultArrayDelimiter
+type Options struct {
+	IncludeEmbeddedRefs bool
+	ArrayDelimiter      string
+}
+
+func (o Options) getArrayDelimiter() string {
+	if o.ArrayDelimiter == "" {
+		return DefaultArrayDelimiter
+	}
+
+	return o.ArrayDelimiter
+}
+
+// CSVFile файл CSV для импорта в Neo4j
+// Name - наименование файла
+// Header - заголовок файла с типами столбцов (например, "id:ID", "name", "is_family:boolean", ":LABEL")
+// Rows - строки файла
+type CSVFile struct {
+	Name   string
+	Header []string
+	Rows   [][]string
+}
+
+// Write записывает файл в формате CSV
+func (f CSVFile) Write(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(f.Header); err != nil {
+		return err
+	}
+
+	if err := cw.WriteAll(f.Rows); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// Export результат экспорта объектов STIX для утилиты neo4j-admin import
+// Nodes - файлы узлов, по одному на каждый тип объектов STIX
+// Relationships - файлы связей, построенных на основании объектов "relationship" и встроенных ссылок
+// ArrayDelimiter - разделитель элементов массивов
+type Export struct {
+	Nodes          []CSVFile
+	Relationships  []CSVFile
+	ArrayDelimiter string
+}
+
+// NewExport формирует файлы CSV для импорта объектов STIX в Neo4j. Объекты "relationship" преобразуются
+// в связи, остальные объекты (в том числе "sighting") в узлы с метками NodeLabel и метками, полученными из типа
+// объекта ("threat-actor" -> "ThreatActor"). Вложенные свойства объектов разворачиваются (например, "hashes.MD5"),
+// массивы простых значений преобразуются в массивы Neo4j, остальные сложные значения сохраняются в виде JSON.
+// Связи, ссылающиеся на отсутствующие объекты, не формируются. Если элемент массива содержит разделитель
+// ArrayDelimiter, возвращается ошибка
+func NewExport(objects []interface{}, opts Options) (*Export, error) {
+	c, err := newCollection(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter := opts.getArrayDelimiter()
+	export := &Export{Nodes: []CSVFile{}, Relationships: []CSVFile{}, ArrayDelimiter: delimiter}
+
+	types := make([]string, 0, len(c.nodes))
+	for k := range c.nodes {
+		types = append(types, k)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		columns := getColumns(c.nodes[t], map[string]bool{"id": true})
+
+		file := CSVFile{Name: "nodes_" + strings.ReplaceAll(t, "-", "_") + ".csv", Header: []string{"id:ID", ":LABEL"}}
+		for _, v := range columns {
+			file.Header = append(file.Header, v.header())
+		}
+
+		for _, n := range c.nodes[t] {
+			label := GetLabel(t)
+			if strings.Contains(label, delimiter) {
+				return nil, fmt.Errorf("the label '%s' contains the array delimiter '%s'", label, delimiter)
+			}
+
+			row := []string{n["id"].(string), NodeLabel + delimiter + label}
+			for _, v := range columns {
+				value, err := v.format(n[v.Name], delimiter)
+				if err != nil {
+					return nil, fmt.Errorf("object '%s': %w", n["id"], err)
+				}
+
+				row = append(row, value)
+			}
+
+			file.Rows = append(file.Rows, row)
+		}
+
+		export.Nodes = append(export.Nodes, file)
+	}
+
+	if len(c.relationships) > 0 {
+		columns := getColumns(c.relationships, map[string]bool{"source_ref": true, "target_ref": true})
+
+		file := CSVFile{Name: "relationships.csv", Header: []string{":START_ID", ":END_ID", ":TYPE"}}
+		for _, v := range columns {
+			file.Header = append(file.Header, v.header())
+		}
+
+		for _, r := range c.relationships {
+			relType, _ := r["relationship_type"].(string)
+			row := []string{r["source_ref"].(string), r["target_ref"].(string), GetRelationshipType(relType)}
+			for _, v := range columns {
+				value, err := v.format(r[v.Name], delimiter)
+				if err != nil {
+					return nil, fmt.Errorf("object '%s': %w", r["id"], err)
+				}
+
+				row = append(row, value)
+			}
+
+			file.Rows = append(file.Rows, row)
+		}
+
+		export.Relationships = append(export.Relationships, file)
+	}
+
+	if refs := c.getEmbeddedRefs(); opts.IncludeEmbeddedRefs && len(refs) > 0 {
+		file := CSVFile{Name: "embedded_refs.csv", Header: []string{":START_ID", ":END_ID", ":TYPE", "property"}}
+		for _, v := range refs {
+			file.Rows = append(file.Rows, []string{v.Source, v.Target, GetRelationshipType(v.Property), v.Property})
+		}
+
+		export.Relationships = append(export.Relationships, file)
+	}
+
+	return export, nil
+}
+
+// WriteDir записывает файлы CSV в директорию dir
+func (e *Export) WriteDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, list := range [][]CSVFile{e.Nodes, e.Relationships} {
+		for _, f := range list {
+			if err := writeFile(filepath.Join(dir, f.Name), f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetImportArguments возвращает аргументы утилиты neo4j-admin import для файлов, записанных в директорию dir
+func (e *Export) GetImportArguments(dir string) []string {
+	args := []string{"--array-delimiter=" + e.ArrayDelimiter}
+	for _, f := range e.Nodes {
+		args = append(args, "--nodes="+filepath.Join(dir, f.Name))
+	}
+
+	for _, f := range e.Relationships {
+		args = append(args, "--relationships="+filepath.Join(dir, f.Name))
+	}
+
+	return args
+}
+
+func writeFile(path string, f CSVFile) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Write(file); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+// GetLabel возвращает метку Neo4j, соответствующую типу объекта STIX ("threat-actor" -> "ThreatActor")
+func GetLabel(objectType string) string {
+	parts := strings.Split(objectType, "-")
+	for k, v := range parts {
+		if v != "" {
+			parts[k] = strings.ToUpper(v[:1]) + v[1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+// GetRelationshipType возвращает тип связи Neo4j, соответствующий типу отношения STIX
+// или наименованию встроенной ссылки ("attributed-to" -> "ATTRIBUTED_TO")
+func GetRelationshipType(relationshipType string) string {
+	return strings.ToUpper(strings.ReplaceAll(relationshipType, "-", "_"))
+}
+
+// column столбец файла CSV
+type column struct {
+	Name string
+	Type string
+}
+
+func (c column) header() string {
+	if c.Type == "string" {
+		return c.Name
+	}
+
+	return c.Name + ":" + c.Type
+}
+
+// format возвращает значение столбца в виде строки. Утилита neo4j-admin import не поддерживает экранирование
+// разделителя элементов массивов, поэтому для элементов, содержащих разделитель, возвращается ошибка
+func (c column) format(value interface{}, delimiter string) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			str := formatScalar(item)
+			if strings.HasSuffix(c.Type, "[]") && strings.Contains(str, delimiter) {
+				return "", fmt.Errorf("the element '%s' of the property '%s' contains the array delimiter '%s', set another ArrayDelimiter", str, c.Name, delimiter)
+			}
+
+			list = append(list, str)
+		}
+
+		return strings.Join(list, delimiter), nil
+	}
+
+	return formatScalar(value), nil
+}
+
+func formatScalar(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// getColumns возвращает отсортированный список столбцов для свойств объектов list. Тип столбца определяется
+// по значениям свойства, при несовпадении типов используется "string"
+func getColumns(list []map[string]interface{}, exclude map[string]bool) []column {
+	types := map[string]string{}
+	for _, m := range list {
+		for k, v := range m {
+			if exclude[k] {
+				continue
+			}
+
+			t := getType(v)
+			current, ok := types[k]
+			switch {
+			case !ok || current == t:
+				types[k] = t
+			case (current == "long" && t == "double") || (current == "double" && t == "long"):
+				types[k] = "double"
+			case (current == "long[]" && t == "double[]") || (current == "double[]" && t == "long[]"):
+				types[k] = "double[]"
+			case strings.HasSuffix(current, "[]") && strings.HasSuffix(t, "[]"):
+				types[k] = "string[]"
+			default:
+				types[k] = "string"
+			}
+		}
+	}
+
+	columns := make([]column, 0, len(types))
+	for k, v := range types {
+		columns = append(columns, column{Name: k, Type: v})
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i].Name < columns[j].Name })
+
+	return columns
+}
+
+// getType возвращает тип Neo4j для значения развернутого свойства
+func getType(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return "boolean"
+
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return "long"
+		}
+
+		return "double"
+
+	case []interface{}:
+		t := ""
+		for _, item := range v {
+			it := getType(item)
+			switch {
+			case t == "" || t == it:
+				t = it
+			case (t == "long" && it == "double") || (t == "double" && it == "long"):
+				t = "double"
+			default:
+				t = "string"
+			}
+		}
+
+		if t == "" {
+			t = "string"
+		}
+
+		return t + "[]"
+	}
+
+	return "string"
+}
+
+// embeddedRef встроенная ссылка
+type embeddedRef struct {
+	Source   string
+	Target   string
+	Property string
+}
+
+// collection объекты STIX, подготовленные к экспорту
+type collection struct {
+	nodes         map[string][]map[string]interface{}
+	list          []map[string]interface{}
+	relationships []map[string]interface{}
+	refs          map[string][]embeddedRef
+	order         []string
+	exists        map[string]bool
+}
+
+func newCollection(objects []interface{}) (*collection, error) {
+	c := &collection{
+		nodes:  map[string][]map[string]interface{}{},
+		refs:   map[string][]embeddedRef{},
+		exists: map[string]bool{},
+	}
+
+	relationships := []map[string]interface{}{}
+	for _, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		id, _ := m["id"].(string)
+		objType, _ := m["type"].(string)
+		if id == "" || objType == "" {
+			return nil, fmt.Errorf("the object must contain the properties 'id' and 'type'")
+		}
+
+		if objType == "relationship" {
+			relationships = append(relationships, flatten(m))
+
+			continue
+		}
+
+		c.exists[id] = true
+		c.order = append(c.order, id)
+		c.refs[id] = getRefs(id, m)
+		n := flatten(m)
+		c.list = append(c.list, n)
+		c.nodes[objType] = append(c.nodes[objType], n)
+	}
+
+	for _, r := range relationships {
+		source, _ := r["source_ref"].(string)
+		target, _ := r["target_ref"].(string)
+		if c.exists[source] && c.exists[target] {
+			c.relationships = append(c.relationships, r)
+		}
+	}
+
+	return c, nil
+}
+
+// getEmbeddedRefs возвращает встроенные ссылки между объектами коллекции
+func (c *collection) getEmbeddedRefs() []embeddedRef {
+	list := []embeddedRef{}
+	for _, id := range c.order {
+		for _, v := range c.refs[id] {
+			if c.exists[v.Target] {
+				list = append(list, v)
+			}
+		}
+	}
+
+	return list
+}
+
+// getRefs возвращает встроенные ссылки объекта (свойства оканчивающиеся на "_ref" и "_refs", кроме "object_marking_refs")
+func getRefs(id string, m map[string]interface{}) []embeddedRef {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := []embeddedRef{}
+	for _, k := range keys {
+		if k == "object_marking_refs" || !(strings.HasSuffix(k, "_ref") || strings.HasSuffix(k, "_refs")) {
+			continue
+		}
+
+		switch v := m[k].(type) {
+		case string:
+			if v != "" {
+				list = append(list, embeddedRef{Source: id, Target: v, Property: k})
+			}
+
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok && s != "" {
+					list = append(list, embeddedRef{Source: id, Target: s, Property: k})
+				}
+			}
+		}
+	}
+
+	return list
+}
+
+// flatten разворачивает вложенные свойства объекта, наименования свойств объединяются через точку.
+// Массивы простых значений сохраняются, массивы объектов и вложенные массивы преобразуются в JSON,
+// пустые значения отбрасываются
+func flatten(m map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	flattenTo(result, "", m)
+
+	return result
+}
+
+func flattenTo(result map[string]interface{}, prefix string, m map[string]interface{}) {
+	for k, v := range m {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+
+		switch value := v.(type) {
+		case nil:
+
+		case string:
+			if value != "" {
+				result[name] = value
+			}
+
+		case map[string]interface{}:
+			flattenTo(result, name, value)
+
+		case []interface{}:
+			if len(value) == 0 {
+				continue
+			}
+
+			if isScalarList(value) {
+				result[name] = value
+
+				continue
+			}
+
+			b, _ := json.Marshal(value)
+			result[name] = string(b)
+
+		default:
+			result[name] = value
+		}
+	}
+}
+
+func isScalarList(list []interface{}) bool {
+	for _, v := range list {
+		switch v.(type) {
+		case string, bool, float64:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
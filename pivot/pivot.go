@@ -0,0 +1,292 @@
+package pivot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/stixgraph"
+)
+
+// Step шаг перехода по объектам "relationship"
+// RelationshipType - тип отношения (relationship_type)
+// Direction - направление перехода, DirectionOutgoing от source_ref к target_ref, DirectionIncoming в обратном направлении
+// TargetTypes - типы объектов, к которым выполняется переход, если не заданы, переход выполняется к объектам любого типа
+// Transitive - переход выполняется повторно, пока находятся новые объекты (например, цепочка "attributed-to")
+type Step struct {
+	RelationshipType string
+	Direction        stixgraph.Direction
+	TargetTypes      []string
+	Transitive       bool
+}
+
+func (s Step) matchType(id string) bool {
+	if len(s.TargetTypes) == 0 {
+		return true
+	}
+
+	for _, v := range s.TargetTypes {
+		if strings.HasPrefix(id, v+"--") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match найденный объект
+// ID - идентификатор объекта
+// Path - путь от исходного объекта к найденному, ребра пути соответствуют объектам "relationship"
+type Match struct {
+	ID   string
+	Path stixgraph.Path
+}
+
+// Result найденный объект STIX с путем, обосновывающим результат
+type Result[T any] struct {
+	Object *T
+	Path   stixgraph.Path
+}
+
+// Pivot поиск связанных объектов STIX по объектам "relationship". Не безопасен для использования из нескольких горутин.
+type Pivot struct {
+	graph *stixgraph.Graph
+}
+
+// NewPivot создает Pivot для списка объектов STIX. Объектами могут быть как типы данного пакета,
+// так и объекты в виде json.RawMessage
+func NewPivot(objects []interface{}) (*Pivot, error) {
+	g, err := stixgraph.NewGraph(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pivot{graph: g}, nil
+}
+
+// Follow выполняет последовательность переходов steps начиная с объекта id и возвращает объекты, найденные
+// на последнем шаге. Для каждого объекта возвращается кратчайший из найденных путей, объекты упорядочены
+// в порядке их обнаружения, исходный объект в результат не включается
+func (p *Pivot) Follow(id string, steps ...Step) ([]Match, error) {
+	if _, ok := p.graph.GetNode(id); !ok {
+		return nil, fmt.Errorf("the object '%s' does not exist", id)
+	}
+
+	current := []Match{{ID: id, Path: stixgraph.Path{Nodes: []string{id}, Edges: []stixgraph.Edge{}}}}
+	for _, s := range steps {
+		current = p.step(current, s)
+	}
+
+	result := make([]Match, 0, len(current))
+	for _, v := range current {
+		if v.ID != id {
+			result = append(result, v)
+		}
+	}
+
+	return result, nil
+}
+
+// step выполняет один шаг перехода для списка объектов
+func (p *Pivot) step(from []Match, s Step) []Match {
+	filter := stixgraph.EdgeFilter{Direction: s.Direction, Types: []string{s.RelationshipType}}
+
+	result := []Match{}
+	visited := map[string]bool{}
+	queue := from
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+
+		for _, n := range p.graph.GetNeighbours(m.ID, filter) {
+			if n.Edge.IsEmbedded() || visited[n.ID] || !s.matchType(n.ID) || contains(m.Path.Nodes, n.ID) {
+				continue
+			}
+			visited[n.ID] = true
+
+			next := Match{
+				ID: n.ID,
+				Path: stixgraph.Path{
+					Nodes: append(append([]string{}, m.Path.Nodes...), n.ID),
+					Edges: append(append([]stixgraph.Edge{}, m.Path.Edges...), n.Edge),
+				},
+			}
+			result = append(result, next)
+
+			if s.Transitive {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return result
+}
+
+// GetAttributedThreatActors возвращает субъектов угроз ("threat-actor"), которым приписывается объект id
+// (например, "campaign" или "intrusion-set"), в том числе через цепочку отношений "attributed-to"
+func (p *Pivot) GetAttributedThreatActors(id string) ([]Result[domainobjectsstix.ThreatActorDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "attributed-to", Direction: stixgraph.DirectionOutgoing, Transitive: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.ThreatActorDomainObjectsSTIX](p, matches, "threat-actor")
+}
+
+// GetAttributedIntrusionSets возвращает наборы вторжений ("intrusion-set"), которым приписывается объект id,
+// в том числе через цепочку отношений "attributed-to"
+func (p *Pivot) GetAttributedIntrusionSets(id string) ([]Result[domainobjectsstix.IntrusionSetDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "attributed-to", Direction: stixgraph.DirectionOutgoing, Transitive: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.IntrusionSetDomainObjectsSTIX](p, matches, "intrusion-set")
+}
+
+// GetAttributedCampaigns возвращает кампании ("campaign"), приписываемые объекту id
+// ("intrusion-set" или "threat-actor"), в том числе через цепочку отношений "attributed-to"
+func (p *Pivot) GetAttributedCampaigns(id string) ([]Result[domainobjectsstix.CampaignDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "attributed-to", Direction: stixgraph.DirectionIncoming, Transitive: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.CampaignDomainObjectsSTIX](p, matches, "campaign")
+}
+
+// GetUsedMalware возвращает вредоносное ПО ("malware"), используемое объектом id
+func (p *Pivot) GetUsedMalware(id string) ([]Result[domainobjectsstix.MalwareDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "uses", Direction: stixgraph.DirectionOutgoing, TargetTypes: []string{"malware"}})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.MalwareDomainObjectsSTIX](p, matches, "malware")
+}
+
+// GetUsedTools возвращает инструменты ("tool"), используемые объектом id
+func (p *Pivot) GetUsedTools(id string) ([]Result[domainobjectsstix.ToolDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "uses", Direction: stixgraph.DirectionOutgoing, TargetTypes: []string{"tool"}})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.ToolDomainObjectsSTIX](p, matches, "tool")
+}
+
+// GetUsedAttackPatterns возвращает шаблоны атак ("attack-pattern"), используемые объектом id
+func (p *Pivot) GetUsedAttackPatterns(id string) ([]Result[domainobjectsstix.AttackPatternDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "uses", Direction: stixgraph.DirectionOutgoing, TargetTypes: []string{"attack-pattern"}})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.AttackPatternDomainObjectsSTIX](p, matches, "attack-pattern")
+}
+
+// GetIndicators возвращает индикаторы ("indicator"), указывающие на объект id
+func (p *Pivot) GetIndicators(id string) ([]Result[domainobjectsstix.IndicatorDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "indicates", Direction: stixgraph.DirectionIncoming, TargetTypes: []string{"indicator"}})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.IndicatorDomainObjectsSTIX](p, matches, "indicator")
+}
+
+// GetIndicatorsOfUsed возвращает индикаторы ("indicator"), указывающие на любые объекты, используемые объектом id
+// (например, индикаторы вредоносного ПО и инструментов, используемых субъектом угроз)
+func (p *Pivot) GetIndicatorsOfUsed(id string) ([]Result[domainobjectsstix.IndicatorDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id,
+		Step{RelationshipType: "uses", Direction: stixgraph.DirectionOutgoing},
+		Step{RelationshipType: "indicates", Direction: stixgraph.DirectionIncoming, TargetTypes: []string{"indicator"}})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.IndicatorDomainObjectsSTIX](p, matches, "indicator")
+}
+
+// GetTargetedIdentities возвращает субъекты ("identity"), являющиеся целями объекта id
+func (p *Pivot) GetTargetedIdentities(id string) ([]Result[domainobjectsstix.IdentityDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "targets", Direction: stixgraph.DirectionOutgoing, TargetTypes: []string{"identity"}})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.IdentityDomainObjectsSTIX](p, matches, "identity")
+}
+
+// GetTargetedLocations возвращает местоположения ("location"), являющиеся целями объекта id
+func (p *Pivot) GetTargetedLocations(id string) ([]Result[domainobjectsstix.LocationDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "targets", Direction: stixgraph.DirectionOutgoing, TargetTypes: []string{"location"}})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.LocationDomainObjectsSTIX](p, matches, "location")
+}
+
+// GetTargetedVulnerabilities возвращает уязвимости ("vulnerability"), являющиеся целями объекта id
+func (p *Pivot) GetTargetedVulnerabilities(id string) ([]Result[domainobjectsstix.VulnerabilityDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "targets", Direction: stixgraph.DirectionOutgoing, TargetTypes: []string{"vulnerability"}})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.VulnerabilityDomainObjectsSTIX](p, matches, "vulnerability")
+}
+
+// GetMalwareVariants возвращает вредоносное ПО ("malware"), являющееся вариантами объекта id,
+// в том числе через цепочку отношений "variant-of"
+func (p *Pivot) GetMalwareVariants(id string) ([]Result[domainobjectsstix.MalwareDomainObjectsSTIX], error) {
+	matches, err := p.Follow(id, Step{RelationshipType: "variant-of", Direction: stixgraph.DirectionIncoming, TargetTypes: []string{"malware"}, Transitive: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return getResults[domainobjectsstix.MalwareDomainObjectsSTIX](p, matches, "malware")
+}
+
+// GetRelated возвращает идентификаторы объектов, связанных с объектом id отношением "related-to" в любом направлении
+func (p *Pivot) GetRelated(id string) ([]Match, error) {
+	return p.Follow(id, Step{RelationshipType: "related-to", Direction: stixgraph.DirectionBoth})
+}
+
+// getResults преобразует найденные объекты типа objectType к типу T
+func getResults[T any](p *Pivot, matches []Match, objectType string) ([]Result[T], error) {
+	list := []Result[T]{}
+	for _, m := range matches {
+		if !strings.HasPrefix(m.ID, objectType+"--") {
+			continue
+		}
+
+		obj, _ := p.graph.GetNode(m.ID)
+
+		object, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := commonlibs.FromMap(object, new(T))
+		if err != nil {
+			return nil, fmt.Errorf("object '%s': %w", m.ID, err)
+		}
+
+		list = append(list, Result[T]{Object: value.(*T), Path: m.Path})
+	}
+
+	return list, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,538 @@
+package redaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/granularmarkings"
+)
+
+// Причины удаления объектов
+const (
+	// ReasonObjectMarking объект помечен маркером не разрешенным политикой получателя
+	ReasonObjectMarking string = "object marking is not allowed by the policy"
+	// ReasonMarkingDefinition объект "marking-definition" не разрешен политикой получателя
+	ReasonMarkingDefinition string = "marking definition is not allowed by the policy"
+	// ReasonReference объект отношения ссылается на удаленный объект или все объекты, на которые ссылается
+	// объект (свойство object_refs), удалены
+	ReasonReference string = "object refers to a removed object"
+	// ReasonMalformedGranularMarking гранулярные маркеры объекта некорректны, поэтому определить какие свойства
+	// требуется удалить невозможно и объект удаляется целиком
+	ReasonMalformedGranularMarking string = "granular markings of the object are malformed"
+)
+
+// Policy политика получателя данных
+// MaxTLP - максимальный уровень TLP который может быть передан получателю, объекты и свойства
+// помеченные более ограничивающим уровнем удаляются
+// AllowedStatements - список идентификаторов объектов "marking-definition" (statement или любых других,
+// отличных от TLP), разрешенных для передачи получателю. Объекты и свойства помеченные маркерами
+// отсутствующими в данном списке удаляются
+type Policy struct {
+	MaxTLP            stixhelpers.TLPLevelSTIX
+	AllowedStatements []stixhelpers.IdentifierTypeSTIX
+}
+
+// RemovedObject сведения об удаленном объекте
+type RemovedObject struct {
+	ID     string
+	Type   string
+	Reason string
+}
+
+// RedactedProperty сведения об удаленном свойстве объекта
+// ID - идентификатор объекта
+// Selector - селектор удаленного свойства, в терминах исходного объекта
+// MarkingRef - маркер, по причине которого свойство было удалено
+type RedactedProperty struct {
+	ID         string
+	Selector   string
+	MarkingRef stixhelpers.IdentifierTypeSTIX
+}
+
+// RemovedRef сведения об удаленной ссылке свойства object_refs на удаленный объект
+// ID - идентификатор объекта, содержащего ссылку
+// Ref - идентификатор удаленного объекта
+type RemovedRef struct {
+	ID  string
+	Ref string
+}
+
+// Report отчет о выполненной фильтрации
+type Report struct {
+	RemovedObjects     []RemovedObject
+	RedactedProperties []RedactedProperty
+	RemovedRefs        []RemovedRef
+}
+
+// Redact выполняет фильтрацию набора объектов STIX в соответствии с политикой получателя и возвращает
+// отфильтрованную копию набора и отчет об удаленных данных. Исходные объекты не изменяются.
+// Объекты, маркеры уровня объекта которых не разрешены политикой, удаляются. Свойства помеченные
+// не разрешенными гранулярными маркерами очищаются, элементы списков удаляются, объекты с некорректными
+// гранулярными маркерами удаляются целиком. Объекты "relationship" и "sighting" ссылающиеся на удаленные
+// объекты также удаляются. Ссылки на удаленные объекты исключаются из свойства object_refs (объекты "report",
+// "grouping", "observed-data", "note", "opinion"), если удалены все объекты на которые ссылается объект, он
+// также удаляется. Объекты возвращаются в виде значений тех же типов, что и исходные
+func Redact(objects []interface{}, policy Policy) ([]interface{}, Report, error) {
+	report := Report{
+		RemovedObjects:     []RemovedObject{},
+		RedactedProperties: []RedactedProperty{},
+		RemovedRefs:        []RemovedRef{},
+	}
+
+	list := make([]map[string]interface{}, 0, len(objects))
+	for k, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return nil, report, fmt.Errorf("object '%d': %w", k, err)
+		}
+
+		list = append(list, m)
+	}
+
+	checker := newMarkingChecker(list, policy)
+	removed := map[string]bool{}
+	remove := func(m map[string]interface{}, reason string) {
+		id := getString(m, "id")
+		removed[id] = true
+		report.RemovedObjects = append(report.RemovedObjects, RemovedObject{ID: id, Type: getString(m, "type"), Reason: reason})
+	}
+
+	//удаление объектов по маркерам уровня объекта
+	for _, m := range list {
+		id, t := getString(m, "id"), getString(m, "type")
+
+		if t == "marking-definition" && !checker.isAllowed(stixhelpers.IdentifierTypeSTIX(id)) {
+			remove(m, ReasonMarkingDefinition)
+
+			continue
+		}
+
+		for _, ref := range getStrings(m, "object_marking_refs") {
+			if !checker.isAllowed(stixhelpers.IdentifierTypeSTIX(ref)) {
+				remove(m, ReasonObjectMarking)
+
+				break
+			}
+		}
+	}
+
+	//очистка свойств по гранулярным маркерам, объекты с некорректными гранулярными маркерами удаляются
+	redacted := map[string][]RedactedProperty{}
+	for _, m := range list {
+		id := getString(m, "id")
+		if removed[id] {
+			continue
+		}
+
+		props, err := redactProperties(m, checker)
+		if err != nil {
+			remove(m, ReasonMalformedGranularMarking)
+
+			continue
+		}
+		redacted[id] = props
+	}
+
+	//удаление объектов отношений ссылающихся на удаленные объекты и объектов, все объекты свойства object_refs
+	//которых удалены. Объекты могут ссылаться друг на друга, поэтому проверка повторяется пока удаляется хотя
+	//бы один объект
+	for isChanged := true; isChanged; {
+		isChanged = false
+
+		for _, m := range list {
+			if removed[getString(m, "id")] {
+				continue
+			}
+
+			if isRefsRemoved(m, removed) {
+				remove(m, ReasonReference)
+				isChanged = true
+			}
+		}
+	}
+
+	result := make([]interface{}, 0, len(list))
+	for k, m := range list {
+		id := getString(m, "id")
+		if removed[id] {
+			continue
+		}
+		report.RedactedProperties = append(report.RedactedProperties, redacted[id]...)
+
+		//исключение ссылок на удаленные объекты
+		if refs, ok := m["object_refs"].([]interface{}); ok {
+			kept := make([]interface{}, 0, len(refs))
+			for _, ref := range refs {
+				if s, ok := ref.(string); ok && removed[s] {
+					report.RemovedRefs = append(report.RemovedRefs, RemovedRef{ID: id, Ref: s})
+
+					continue
+				}
+
+				kept = append(kept, ref)
+			}
+			m["object_refs"] = kept
+		}
+
+		obj, err := commonlibs.FromMap(m, objects[k])
+		if err != nil {
+			return nil, report, fmt.Errorf("object '%s': %w", id, err)
+		}
+
+		result = append(result, obj)
+	}
+
+	return result, report, nil
+}
+
+// isRefsRemoved проверяет ссылается ли объект "relationship" или "sighting" на удаленный объект, либо
+// удалены ли все объекты, на которые ссылается свойство object_refs объекта
+func isRefsRemoved(m map[string]interface{}, removed map[string]bool) bool {
+	for _, ref := range getRelationshipRefs(m) {
+		if removed[ref] {
+			return true
+		}
+	}
+
+	refs := getStrings(m, "object_refs")
+	if len(refs) == 0 {
+		return false
+	}
+
+	for _, ref := range refs {
+		if !removed[ref] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// redactProperties очищает свойства объекта помеченные не разрешенными гранулярными маркерами
+// и удаляет такие гранулярные маркеры из объекта
+func redactProperties(m map[string]interface{}, checker markingChecker) ([]RedactedProperty, error) {
+	r, err := granularmarkings.NewResolver(m)
+	if err != nil {
+		return nil, err
+	}
+
+	gms := r.GetGranularMarkings()
+	if len(gms) == 0 {
+		return []RedactedProperty{}, nil
+	}
+
+	id := getString(m, "id")
+	props := []RedactedProperty{}
+	paths := [][]string{}
+	allowed := make([]stixhelpers.GranularMarkingsTypeSTIX, 0, len(gms))
+
+	for _, gm := range gms {
+		if gm.MarkingRef == "" || checker.isAllowed(gm.MarkingRef) {
+			allowed = append(allowed, gm)
+
+			continue
+		}
+
+		for _, s := range gm.Selectors {
+			path, err := granularmarkings.ParseSelector(s)
+			if err != nil {
+				return nil, err
+			}
+
+			if r.ValidateSelector(s) != nil {
+				continue
+			}
+
+			paths = append(paths, path)
+			props = append(props, RedactedProperty{ID: id, Selector: s, MarkingRef: gm.MarkingRef})
+		}
+	}
+
+	if len(allowed) == len(gms) {
+		return props, nil
+	}
+
+	//сначала все свойства помечаются как удаленные, а затем удаляются помеченные элементы списков,
+	//это позволяет не учитывать смещение индексов при обработке селекторов
+	for _, p := range paths {
+		markRemoved(m, p)
+	}
+	compactLists := compact(m, []string{})
+
+	//селекторы оставшихся гранулярных маркеров приводятся в соответствие с новыми индексами
+	granular := make([]stixhelpers.GranularMarkingsTypeSTIX, 0, len(allowed))
+	for _, gm := range allowed {
+		selectors := make([]string, 0, len(gm.Selectors))
+		for _, s := range gm.Selectors {
+			if ns, ok := remapSelector(s, paths, compactLists); ok {
+				selectors = append(selectors, ns)
+			}
+		}
+
+		if len(selectors) == 0 {
+			continue
+		}
+
+		gm.Selectors = selectors
+		granular = append(granular, gm)
+	}
+
+	m["granular_markings"] = granular
+
+	return props, nil
+}
+
+// removedValue значение которым помечаются удаляемые свойства
+type removedValue struct{}
+
+func markRemoved(obj interface{}, path []string) {
+	current := obj
+	for k, p := range path {
+		isLast := k == len(path)-1
+
+		switch v := current.(type) {
+		case map[string]interface{}:
+			if isLast {
+				v[p] = removedValue{}
+
+				return
+			}
+
+			current = v[p]
+
+		case []interface{}:
+			i, ok := parseIndex(p)
+			if !ok || i >= len(v) {
+				return
+			}
+
+			if isLast {
+				v[i] = removedValue{}
+
+				return
+			}
+
+			current = v[i]
+
+		default:
+			return
+		}
+	}
+}
+
+// compact удаляет помеченные свойства и элементы списков, возвращает для каждого списка (ключ - путь к нему)
+// индексы удаленных элементов
+func compact(obj interface{}, path []string) map[string][]int {
+	result := map[string][]int{}
+
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if _, ok := value.(removedValue); ok {
+				delete(v, k)
+
+				continue
+			}
+
+			p := append(append([]string{}, path...), k)
+			for lk, lv := range compact(value, p) {
+				result[lk] = lv
+			}
+
+			if l, ok := value.([]interface{}); ok {
+				v[k] = compactList(l, p, result)
+			}
+		}
+
+	case []interface{}:
+		for k, value := range v {
+			p := append(append([]string{}, path...), fmt.Sprintf("[%d]", k))
+			for lk, lv := range compact(value, p) {
+				result[lk] = lv
+			}
+
+			if l, ok := value.([]interface{}); ok {
+				v[k] = compactList(l, p, result)
+			}
+		}
+	}
+
+	return result
+}
+
+func compactList(l []interface{}, path []string, removed map[string][]int) []interface{} {
+	list := make([]interface{}, 0, len(l))
+	for k, value := range l {
+		if _, ok := value.(removedValue); ok {
+			key := strings.Join(path, ".")
+			removed[key] = append(removed[key], k)
+
+			continue
+		}
+
+		list = append(list, value)
+	}
+
+	return list
+}
+
+// remapSelector пересчитывает индексы списков в селекторе с учетом удаленных элементов. Если селектор
+// указывает на удаленное свойство или на одно из его вложенных свойств возвращается false
+func remapSelector(selector string, removedPaths [][]string, removedItems map[string][]int) (string, bool) {
+	path, err := granularmarkings.ParseSelector(selector)
+	if err != nil {
+		return "", false
+	}
+
+	for _, rp := range removedPaths {
+		if isPrefix(rp, path) {
+			return "", false
+		}
+	}
+
+	result := make([]string, len(path))
+	for k, p := range path {
+		result[k] = p
+
+		i, ok := parseIndex(p)
+		if !ok {
+			continue
+		}
+
+		shift := 0
+		for _, ri := range removedItems[strings.Join(path[:k], ".")] {
+			if ri < i {
+				shift++
+			}
+		}
+
+		result[k] = fmt.Sprintf("[%d]", i-shift)
+	}
+
+	return strings.Join(result, "."), true
+}
+
+// markingChecker проверяет разрешен ли маркер политикой получателя
+type markingChecker struct {
+	policy     Policy
+	tlpLevels  map[stixhelpers.IdentifierTypeSTIX]stixhelpers.TLPLevelSTIX
+	statements map[stixhelpers.IdentifierTypeSTIX]bool
+}
+
+// newMarkingChecker создает проверку маркеров, уровни TLP определяются как по предопределенным
+// идентификаторам TLP 1.0 и TLP 2.0, так и по объектам "marking-definition" из набора
+func newMarkingChecker(list []map[string]interface{}, policy Policy) markingChecker {
+	mc := markingChecker{
+		policy:     policy,
+		tlpLevels:  map[stixhelpers.IdentifierTypeSTIX]stixhelpers.TLPLevelSTIX{},
+		statements: map[stixhelpers.IdentifierTypeSTIX]bool{},
+	}
+
+	for _, v := range policy.AllowedStatements {
+		mc.statements[v] = true
+	}
+
+	for _, m := range list {
+		if getString(m, "type") != "marking-definition" {
+			continue
+		}
+
+		md := stixhelpers.MarkingDefinitionObjectSTIX{}
+		if err := remarshal(m, &md); err != nil {
+			continue
+		}
+
+		if l := md.GetTLPLevel(); l != stixhelpers.TLPLevelUndefined {
+			mc.tlpLevels[stixhelpers.IdentifierTypeSTIX(md.ID)] = l
+		}
+	}
+
+	return mc
+}
+
+func (mc markingChecker) isAllowed(ref stixhelpers.IdentifierTypeSTIX) bool {
+	level := stixhelpers.GetTLPLevelByMarkingID(ref)
+	if level == stixhelpers.TLPLevelUndefined {
+		level = mc.tlpLevels[ref]
+	}
+
+	if level != stixhelpers.TLPLevelUndefined {
+		return level <= mc.policy.MaxTLP
+	}
+
+	return mc.statements[ref]
+}
+
+// getRelationshipRefs возвращает ссылки объектов "relationship" и "sighting" на другие объекты
+func getRelationshipRefs(m map[string]interface{}) []string {
+	switch getString(m, "type") {
+	case "relationship":
+		return []string{getString(m, "source_ref"), getString(m, "target_ref")}
+
+	case "sighting":
+		refs := []string{getString(m, "sighting_of_ref")}
+		refs = append(refs, getStrings(m, "observed_data_refs")...)
+
+		return append(refs, getStrings(m, "where_sighted_refs")...)
+	}
+
+	return []string{}
+}
+
+func getString(m map[string]interface{}, key string) string {
+	if v, ok := m[key].(string); ok {
+		return v
+	}
+
+	return ""
+}
+
+func getStrings(m map[string]interface{}, key string) []string {
+	list := []string{}
+	if l, ok := m[key].([]interface{}); ok {
+		for _, v := range l {
+			if s, ok := v.(string); ok {
+				list = append(list, s)
+			}
+		}
+	}
+
+	return list
+}
+
+func parseIndex(p string) (int, bool) {
+	if !strings.HasPrefix(p, "[") || !strings.HasSuffix(p, "]") {
+		return 0, false
+	}
+
+	i, err := strconv.Atoi(p[1 : len(p)-1])
+
+	return i, err == nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+
+	for k, v := range prefix {
+		if path[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func remarshal(from, to interface{}) error {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, to)
+}
@@ -0,0 +1,249 @@
+package sightinggenerator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/relationshipobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// MatchResult результат совпадения индикатора с данными телеметрии
+// IndicatorID - идентификатор сработавшего объекта "indicator", должен начинаться с "indicator--" (ОБЯЗАТЕЛЬНОЕ ЗНАЧЕНИЕ)
+// ObservedDataRefs - список идентификаторов объектов "observed-data" содержащих данные на которых сработал индикатор
+// WhereSightedRefs - список идентификаторов объектов "identity" или "location" описывающих где было сделано наблюдение
+// FirstSeen - время первого совпадения, в формате RFC3339, если не задано используется текущее время
+// LastSeen - время последнего совпадения, в формате RFC3339, если не задано используется значение FirstSeen,
+// не может быть раньше FirstSeen
+// Count - количество совпадений, если значение меньше 1 считается что совпадение было одно
+type MatchResult struct {
+	IndicatorID      stixhelpers.IdentifierTypeSTIX
+	ObservedDataRefs []stixhelpers.IdentifierTypeSTIX
+	WhereSightedRefs []stixhelpers.IdentifierTypeSTIX
+	FirstSeen        string
+	LastSeen         string
+	Count            int
+}
+
+// SightingGenerator выполняет создание новых или обновление уже существующих объектов "sighting"
+// на основе результатов совпадений индикаторов. Для каждого индикатора (свойство sighting_of_ref)
+// ведется один объект "sighting". Безопасен для использования из нескольких горутин.
+type SightingGenerator struct {
+	mutex     sync.RWMutex
+	sightings map[stixhelpers.IdentifierTypeSTIX]*relationshipobjectsstix.SightingObjectSTIX
+}
+
+// NewSightingGenerator создает новый генератор объектов "sighting"
+func NewSightingGenerator() *SightingGenerator {
+	return &SightingGenerator{
+		sightings: map[stixhelpers.IdentifierTypeSTIX]*relationshipobjectsstix.SightingObjectSTIX{},
+	}
+}
+
+// AddSighting добавляет в генератор ранее созданный объект "sighting", последующие совпадения
+// по индикатору указанному в SightingOfRef будут обновлять этот объект
+func (sg *SightingGenerator) AddSighting(s relationshipobjectsstix.SightingObjectSTIX) error {
+	if _, err := s.Get(); err != nil {
+		return err
+	}
+
+	sg.mutex.Lock()
+	defer sg.mutex.Unlock()
+
+	tmp := copySighting(s)
+	sg.sightings[s.SightingOfRef] = &tmp
+
+	return nil
+}
+
+// HandleMatch обрабатывает результат совпадения индикатора. Если объект "sighting" для индикатора
+// отсутствует он создается, в противном случае увеличивается значение Count, расширяется временное
+// окно FirstSeen/LastSeen, добавляются ссылки ObservedDataRefs и WhereSightedRefs (без повторов).
+// Возвращает копию созданного или обновленного объекта и признак того что объект был создан.
+func (sg *SightingGenerator) HandleMatch(m MatchResult) (relationshipobjectsstix.SightingObjectSTIX, bool, error) {
+	if m.IndicatorID == "" {
+		return relationshipobjectsstix.SightingObjectSTIX{}, false, fmt.Errorf("the required value 'IndicatorID' must not be empty")
+	}
+
+	if !m.IndicatorID.CheckIdentifierTypeSTIX() || !strings.HasPrefix(string(m.IndicatorID), "indicator--") {
+		return relationshipobjectsstix.SightingObjectSTIX{}, false, fmt.Errorf("invalid indicator identifier '%s', expected an identifier of the object 'indicator'", m.IndicatorID)
+	}
+
+	now := commonlibs.TimeNowRFC3339()
+
+	firstSeen := m.FirstSeen
+	if firstSeen == "" {
+		firstSeen = now
+	}
+
+	lastSeen := m.LastSeen
+	if lastSeen == "" {
+		lastSeen = firstSeen
+	}
+
+	if _, err := time.Parse(time.RFC3339, firstSeen); err != nil {
+		return relationshipobjectsstix.SightingObjectSTIX{}, false, err
+	}
+
+	if _, err := time.Parse(time.RFC3339, lastSeen); err != nil {
+		return relationshipobjectsstix.SightingObjectSTIX{}, false, err
+	}
+
+	if commonlibs.CompareTimestamps(lastSeen, firstSeen) < 0 {
+		return relationshipobjectsstix.SightingObjectSTIX{}, false, fmt.Errorf("the value 'LastSeen' (%s) must not be earlier than the value 'FirstSeen' (%s)", lastSeen, firstSeen)
+	}
+
+	count := m.Count
+	if count < 1 {
+		count = 1
+	}
+
+	sg.mutex.Lock()
+	defer sg.mutex.Unlock()
+
+	s, ok := sg.sightings[m.IndicatorID]
+	if !ok {
+		s = &relationshipobjectsstix.SightingObjectSTIX{
+			OptionalCommonPropertiesRelationshipObjectSTIX: relationshipobjectsstix.OptionalCommonPropertiesRelationshipObjectSTIX{
+				SpecVersion: "2.1",
+				Created:     now,
+				Modified:    now,
+			},
+			FirstSeen:     firstSeen,
+			LastSeen:      lastSeen,
+			SightingOfRef: m.IndicatorID,
+		}
+		s.SetValueType("sighting")
+		s.SetValueID("sighting--" + commonlibs.GetUUIDv4())
+
+		sg.sightings[m.IndicatorID] = s
+	} else {
+		s.FirstSeen = earliestDateTime(s.FirstSeen, firstSeen)
+		s.LastSeen = latestDateTime(s.LastSeen, lastSeen)
+		s.Modified = now
+	}
+
+	s.Count += count
+	s.ObservedDataRefs = appendUniqueRefs(s.ObservedDataRefs, m.ObservedDataRefs, false)
+	s.WhereSightedRefs = appendUniqueRefs(s.WhereSightedRefs, m.WhereSightedRefs, true)
+
+	return copySighting(*s), !ok, nil
+}
+
+// GetSighting возвращает копию объекта "sighting" для указанного индикатора
+func (sg *SightingGenerator) GetSighting(indicatorID stixhelpers.IdentifierTypeSTIX) (relationshipobjectsstix.SightingObjectSTIX, bool) {
+	sg.mutex.RLock()
+	defer sg.mutex.RUnlock()
+
+	s, ok := sg.sightings[indicatorID]
+	if !ok {
+		return relationshipobjectsstix.SightingObjectSTIX{}, false
+	}
+
+	return copySighting(*s), true
+}
+
+// GetSightings возвращает копии всех объектов "sighting", отсортированные по sighting_of_ref
+func (sg *SightingGenerator) GetSightings() []relationshipobjectsstix.SightingObjectSTIX {
+	sg.mutex.RLock()
+	defer sg.mutex.RUnlock()
+
+	list := make([]relationshipobjectsstix.SightingObjectSTIX, 0, len(sg.sightings))
+	for _, s := range sg.sightings {
+		list = append(list, copySighting(*s))
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].SightingOfRef < list[j].SightingOfRef
+	})
+
+	return list
+}
+
+// copySighting создает копию объекта, не разделяющую с оригиналом списки ссылок
+func copySighting(s relationshipobjectsstix.SightingObjectSTIX) relationshipobjectsstix.SightingObjectSTIX {
+	if s.ObservedDataRefs != nil {
+		s.ObservedDataRefs = append([]stixhelpers.IdentifierTypeSTIX(nil), s.ObservedDataRefs...)
+	}
+
+	if s.WhereSightedRefs != nil {
+		s.WhereSightedRefs = append([]stixhelpers.IdentifierTypeSTIX(nil), s.WhereSightedRefs...)
+	}
+
+	if s.Extensions != nil {
+		ext := make(stixhelpers.ExtensionsTypeSTIX, len(s.Extensions))
+		for k, v := range s.Extensions {
+			ext[k] = v
+		}
+
+		s.Extensions = ext
+	}
+
+	return s
+}
+
+// appendUniqueRefs добавляет ссылки в список, при unique равном true повторяющиеся ссылки пропускаются
+func appendUniqueRefs(list, refs []stixhelpers.IdentifierTypeSTIX, unique bool) []stixhelpers.IdentifierTypeSTIX {
+	exist := make(map[stixhelpers.IdentifierTypeSTIX]struct{}, len(list))
+	for _, v := range list {
+		exist[v] = struct{}{}
+	}
+
+	for _, v := range refs {
+		if v == "" {
+			continue
+		}
+
+		if _, ok := exist[v]; ok && unique {
+			continue
+		}
+
+		exist[v] = struct{}{}
+		list = append(list, v)
+	}
+
+	return list
+}
+
+// earliestDateTime возвращает наиболее раннее из двух значений времени, значение
+// по умолчанию или некорректное значение во внимание не принимаются
+func earliestDateTime(current, v string) string {
+	if !isSetDateTime(current) {
+		return v
+	}
+
+	if isSetDateTime(v) && commonlibs.CompareTimestamps(v, current) < 0 {
+		return v
+	}
+
+	return current
+}
+
+// latestDateTime возвращает наиболее позднее из двух значений времени, значение
+// по умолчанию или некорректное значение во внимание не принимаются
+func latestDateTime(current, v string) string {
+	if !isSetDateTime(current) {
+		return v
+	}
+
+	if isSetDateTime(v) && commonlibs.CompareTimestamps(v, current) > 0 {
+		return v
+	}
+
+	return current
+}
+
+// isSetDateTime проверяет что значение времени корректно и не является значением по умолчанию
+func isSetDateTime(v string) bool {
+	if v == commonlibs.DefaultDateTime {
+		return false
+	}
+
+	_, err := commonlibs.ParseTimestamp(v)
+
+	return err == nil
+}
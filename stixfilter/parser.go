@@ -0,0 +1,340 @@
+package stixfilter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Parse разбирает условие, записанное на языке запросов, например:
+//
+//	type = 'indicator' AND pattern_type = 'stix' AND (valid_until > '2024-01-01T00:00:00Z' OR valid_until exists false)
+//	labels contains 'apt' OR external_references.source_name in ('mitre-attack', 'capec')
+//
+// Условие имеет вид <путь к свойству> <оператор> <значение>, операторы: =, !=, <, >, in, contains, exists.
+// Значения: строки в одинарных или двойных кавычках, числа, true, false, списки значений в скобках (для in).
+// Для оператора exists значение необязательно (по умолчанию true). Условия объединяются с помощью AND и OR
+// (AND имеет больший приоритет) и группируются скобками
+func Parse(query string) (Expression, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", t.text, t.pos)
+	}
+
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(query string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: i})
+			i++
+
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+
+		case r == '=' || r == '<' || r == '>':
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), pos: i})
+			i++
+
+		case r == '!':
+			if i+1 >= len(runes) || runes[i+1] != '=' {
+				return nil, fmt.Errorf("unexpected '!' at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: "!=", pos: i})
+			i += 2
+
+		case r == '\'' || r == '"':
+			start := i
+			sb := strings.Builder{}
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), pos: start})
+
+		case r == '-' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || strings.ContainsRune(".eE+-", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || strings.ContainsRune("_-.", runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+
+		default:
+			return nil, fmt.Errorf("unexpected '%c' at position %d", r, i)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, pos: len(runes)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	list := Or{expr}
+	for p.isKeyword("OR") {
+		p.next()
+
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, expr)
+	}
+
+	if len(list) == 1 {
+		return list[0], nil
+	}
+
+	return list, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	list := And{expr}
+	for p.isKeyword("AND") {
+		p.next()
+
+		expr, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, expr)
+	}
+
+	if len(list) == 1 {
+		return list[0], nil
+	}
+
+	return list, nil
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	t := p.next()
+
+	if t.kind == tokenLeftParen {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if t := p.next(); t.kind != tokenRightParen {
+			return nil, fmt.Errorf("expected ')' at position %d", t.pos)
+		}
+
+		return expr, nil
+	}
+
+	if t.kind != tokenWord {
+		return nil, fmt.Errorf("expected property path at position %d", t.pos)
+	}
+
+	f := Filter{Property: t.text}
+
+	op := p.next()
+	switch {
+	case op.kind == tokenOperator:
+		f.Operator = Operator(op.text)
+
+	case op.kind == tokenWord && (strings.EqualFold(op.text, "in") || strings.EqualFold(op.text, "contains") || strings.EqualFold(op.text, "exists")):
+		f.Operator = Operator(strings.ToLower(op.text))
+
+	default:
+		return nil, fmt.Errorf("expected operator at position %d", op.pos)
+	}
+
+	var err error
+	switch f.Operator {
+	case OperatorExists:
+		if p.isKeyword("true") || p.isKeyword("false") {
+			f.Value = strings.EqualFold(p.next().text, "true")
+		}
+
+	case OperatorIn:
+		f.Value, err = p.parseList()
+
+	default:
+		f.Value, err = p.parseValue()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f, f.Validate()
+}
+
+func (p *parser) parseList() ([]interface{}, error) {
+	if t := p.next(); t.kind != tokenLeftParen {
+		return nil, fmt.Errorf("expected '(' at position %d", t.pos)
+	}
+
+	list := []interface{}{}
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+
+		t := p.next()
+		if t.kind == tokenRightParen {
+			return list, nil
+		}
+
+		if t.kind != tokenComma {
+			return nil, fmt.Errorf("expected ',' or ')' at position %d", t.pos)
+		}
+	}
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenString:
+		return t.text, nil
+
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s' at position %d", t.text, t.pos)
+		}
+
+		return f, nil
+
+	case tokenWord:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+
+	return nil, fmt.Errorf("expected value at position %d", t.pos)
+}
+
+// formatValue возвращает значение в виде литерала языка запросов
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+
+	case time.Time:
+		return "'" + value.UTC().Format(time.RFC3339Nano) + "'"
+
+	case bool:
+		return strconv.FormatBool(value)
+	}
+
+	if f, ok := toFloat(v); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	if list, ok := toList(v); ok {
+		parts := make([]string, 0, len(list))
+		for _, item := range list {
+			parts = append(parts, formatValue(item))
+		}
+
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+
+	return fmt.Sprintf("'%v'", v)
+}
@@ -0,0 +1,463 @@
+package stixfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+)
+
+// Operator оператор сравнения
+type Operator string
+
+const (
+	// OperatorEqual значение свойства равно заданному
+	OperatorEqual Operator = "="
+	// OperatorNotEqual ни одно из значений свойства не равно заданному
+	OperatorNotEqual Operator = "!="
+	// OperatorLess значение свойства меньше заданного
+	OperatorLess Operator = "<"
+	// OperatorGreater значение свойства больше заданного
+	OperatorGreater Operator = ">"
+	// OperatorIn значение свойства содержится в заданном списке
+	OperatorIn Operator = "in"
+	// OperatorContains список значений свойства содержит заданное значение, а строковое значение заданную подстроку
+	OperatorContains Operator = "contains"
+	// OperatorExists свойство присутствует (при значении false - отсутствует) в объекте
+	OperatorExists Operator = "exists"
+)
+
+var operators = map[Operator]bool{
+	OperatorEqual:    true,
+	OperatorNotEqual: true,
+	OperatorLess:     true,
+	OperatorGreater:  true,
+	OperatorIn:       true,
+	OperatorContains: true,
+	OperatorExists:   true,
+}
+
+// Expression условие отбора объектов. Объект передается в виде результата декодирования JSON в map[string]interface{},
+// поэтому любое Expression может использоваться в качестве условия отбора хранилищ stixstore
+type Expression interface {
+	Match(obj map[string]interface{}) bool
+}
+
+// Filter условие вида (путь к свойству, оператор, значение).
+// Property - путь к свойству, элементы пути разделяются точкой, например, "external_references.source_name"
+// или "hashes.SHA-256". Если элемент пути является списком, условие проверяется для каждого его элемента,
+// числовой элемент пути обозначает индекс в списке ("kill_chain_phases.0.phase_name")
+// Operator - оператор сравнения
+// Value - значение, для OperatorIn список значений, для OperatorExists значение типа bool (по умолчанию true).
+// Значения типа time.Time, а также строки в формате RFC3339 сравниваются как время
+type Filter struct {
+	Property string
+	Operator Operator
+	Value    interface{}
+}
+
+// NewFilter создает условие, проверяя корректность оператора и значения
+func NewFilter(property string, operator Operator, value interface{}) (Filter, error) {
+	f := Filter{Property: property, Operator: operator, Value: value}
+
+	return f, f.Validate()
+}
+
+// Validate проверяет корректность условия
+func (f Filter) Validate() error {
+	if f.Property == "" {
+		return fmt.Errorf("the property of the filter must not be empty")
+	}
+
+	if !operators[f.Operator] {
+		return fmt.Errorf("unknown filter operator '%s'", f.Operator)
+	}
+
+	switch f.Operator {
+	case OperatorIn:
+		if _, ok := toList(f.Value); !ok {
+			return fmt.Errorf("the value of the operator 'in' must be a list")
+		}
+
+	case OperatorExists:
+		if _, ok := f.Value.(bool); !ok && f.Value != nil {
+			return fmt.Errorf("the value of the operator 'exists' must be a boolean")
+		}
+	}
+
+	return nil
+}
+
+// Match проверяет соответствие объекта условию. Условия с операторами, кроме OperatorExists,
+// для объектов не имеющих свойства не выполняются
+func (f Filter) Match(obj map[string]interface{}) bool {
+	values := Resolve(obj, f.Property)
+
+	if f.Operator == OperatorExists {
+		want, ok := f.Value.(bool)
+
+		return (len(values) > 0) == (want || !ok)
+	}
+
+	if len(values) == 0 {
+		return false
+	}
+
+	switch f.Operator {
+	case OperatorNotEqual:
+		for _, v := range flattenValues(values) {
+			if equal(v, f.Value) {
+				return false
+			}
+		}
+
+		return true
+
+	case OperatorContains:
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				if sub, ok := f.Value.(string); ok && strings.Contains(s, sub) {
+					return true
+				}
+
+				continue
+			}
+
+			if list, ok := v.([]interface{}); ok {
+				for _, item := range list {
+					if equal(item, f.Value) {
+						return true
+					}
+				}
+			}
+		}
+
+		return false
+	}
+
+	for _, v := range flattenValues(values) {
+		switch f.Operator {
+		case OperatorEqual:
+			if equal(v, f.Value) {
+				return true
+			}
+
+		case OperatorLess:
+			if c, ok := compare(v, f.Value); ok && c < 0 {
+				return true
+			}
+
+		case OperatorGreater:
+			if c, ok := compare(v, f.Value); ok && c > 0 {
+				return true
+			}
+
+		case OperatorIn:
+			list, _ := toList(f.Value)
+			for _, item := range list {
+				if equal(v, item) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// String возвращает условие в виде строки языка запросов (см. Parse)
+func (f Filter) String() string {
+	if f.Operator == OperatorExists {
+		if want, ok := f.Value.(bool); ok && !want {
+			return fmt.Sprintf("%s exists false", f.Property)
+		}
+
+		return fmt.Sprintf("%s exists", f.Property)
+	}
+
+	return fmt.Sprintf("%s %s %s", f.Property, f.Operator, formatValue(f.Value))
+}
+
+// And логическое И условий, пустой список условий выполняется для любого объекта
+type And []Expression
+
+// Match проверяет выполнение всех условий
+func (a And) Match(obj map[string]interface{}) bool {
+	for _, e := range a {
+		if !e.Match(obj) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String возвращает условие в виде строки языка запросов
+func (a And) String() string {
+	return join([]Expression(a), " AND ")
+}
+
+// Or логическое ИЛИ условий, пустой список условий не выполняется ни для одного объекта
+type Or []Expression
+
+// Match проверяет выполнение хотя бы одного из условий
+func (o Or) Match(obj map[string]interface{}) bool {
+	for _, e := range o {
+		if e.Match(obj) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String возвращает условие в виде строки языка запросов
+func (o Or) String() string {
+	return join([]Expression(o), " OR ")
+}
+
+func join(list []Expression, sep string) string {
+	parts := make([]string, 0, len(list))
+	for _, e := range list {
+		parts = append(parts, "("+fmt.Sprint(e)+")")
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// Apply возвращает объекты из списка objects, удовлетворяющие условию expr. Объектами могут быть как типы
+// данного пакета, так и объекты в виде json.RawMessage
+func Apply(objects []interface{}, expr Expression) ([]interface{}, error) {
+	list := []interface{}{}
+	for _, obj := range objects {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			var err error
+			if m, err = commonlibs.ToMap(obj); err != nil {
+				return nil, err
+			}
+		}
+
+		if expr == nil || expr.Match(m) {
+			list = append(list, obj)
+		}
+	}
+
+	return list, nil
+}
+
+// Resolve возвращает значения свойства объекта по пути path. Если промежуточный элемент пути является списком,
+// путь разрешается для каждого его элемента. Пустые значения (null, пустые строки, списки и объекты, а также время
+// по умолчанию commonlibs.DefaultDateTime) не возвращаются, так как типы данного пакета кодируют незаполненные
+// свойства такими значениями
+func Resolve(obj map[string]interface{}, path string) []interface{} {
+	current := []interface{}{obj}
+	for _, name := range strings.Split(path, ".") {
+		next := []interface{}{}
+		for _, v := range current {
+			next = append(next, resolveElement(v, name)...)
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+func resolveElement(v interface{}, name string) []interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if item, ok := value[name]; ok && !isEmpty(item) {
+			return []interface{}{item}
+		}
+
+	case []interface{}:
+		if num, err := strconv.Atoi(name); err == nil {
+			if num >= 0 && num < len(value) && !isEmpty(value[num]) {
+				return []interface{}{value[num]}
+			}
+
+			return nil
+		}
+
+		list := []interface{}{}
+		for _, item := range value {
+			list = append(list, resolveElement(item, name)...)
+		}
+
+		return list
+	}
+
+	return nil
+}
+
+func isEmpty(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case string:
+		return value == "" || value == commonlibs.DefaultDateTime
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+
+	return false
+}
+
+// flattenValues раскрывает списки значений
+func flattenValues(values []interface{}) []interface{} {
+	list := []interface{}{}
+	for _, v := range values {
+		if items, ok := v.([]interface{}); ok {
+			list = append(list, items...)
+
+			continue
+		}
+
+		list = append(list, v)
+	}
+
+	return list
+}
+
+// equal сравнивает значение свойства со значением условия
+func equal(a, b interface{}) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+
+	return reflect.DeepEqual(a, normalize(b))
+}
+
+// compare сравнивает значение свойства со значением условия, возвращает false если значения несравнимы
+func compare(a, b interface{}) (int, bool) {
+	if t, ok := b.(time.Time); ok {
+		s, ok := a.(string)
+		if !ok {
+			return 0, false
+		}
+
+		ta, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return 0, false
+		}
+
+		return compareTime(ta, t), true
+	}
+
+	if fb, ok := toFloat(b); ok {
+		fa, ok := toFloat(a)
+		if !ok {
+			return 0, false
+		}
+
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+
+		return 0, true
+	}
+
+	sa, okA := a.(string)
+	sb, okB := b.(string)
+	if !okA || !okB {
+		return 0, false
+	}
+
+	ta, errA := time.Parse(time.RFC3339Nano, sa)
+	tb, errB := time.Parse(time.RFC3339Nano, sb)
+	if errA == nil && errB == nil {
+		return compareTime(ta, tb), true
+	}
+
+	return strings.Compare(sa, sb), true
+}
+
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+
+	return 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch value := v.(type) {
+	case float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int8:
+		return float64(value), true
+	case int16:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint:
+		return float64(value), true
+	case uint8:
+		return float64(value), true
+	case uint16:
+		return float64(value), true
+	case uint32:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case json.Number:
+		f, err := value.Float64()
+
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+// normalize приводит значение условия к виду, получаемому при декодировании JSON
+func normalize(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return v
+	}
+
+	return result
+}
+
+// toList преобразует значение условия в список
+func toList(v interface{}) ([]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	list := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		list = append(list, rv.Index(i).Interface())
+	}
+
+	return list, true
+}
@@ -0,0 +1,467 @@
+package stixgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// Direction направление ребра относительно узла
+type Direction int
+
+const (
+	// DirectionBoth входящие и исходящие ребра
+	DirectionBoth Direction = iota
+	// DirectionOutgoing ребра, исходящие из узла
+	DirectionOutgoing
+	// DirectionIncoming ребра, входящие в узел
+	DirectionIncoming
+)
+
+// EdgeTypeSighting тип ребер, построенных на основании объектов "sighting"
+const EdgeTypeSighting = "sighting"
+
+// Edge ребро графа
+// ID - идентификатор объекта "relationship" или "sighting" на основании которого построено ребро, для встроенных ссылок пустой
+// Type - для "relationship" значение свойства relationship_type, для "sighting" значение EdgeTypeSighting,
+// для встроенных ссылок наименование свойства, например, "created_by_ref"
+// Property - наименование свойства содержащего ссылку на Target (для "relationship" - "target_ref")
+// Source - идентификатор исходного узла
+// Target - идентификатор конечного узла
+type Edge struct {
+	ID       string
+	Type     string
+	Property string
+	Source   string
+	Target   string
+}
+
+// IsEmbedded является ли ребро встроенной ссылкой
+func (e Edge) IsEmbedded() bool {
+	return e.ID == ""
+}
+
+// EdgeFilter параметры отбора ребер
+// Direction - направление ребер
+// Types - типы ребер (relationship_type для "relationship"), если не заданы, используются все ребра
+type EdgeFilter struct {
+	Direction Direction
+	Types     []string
+}
+
+func (f EdgeFilter) match(e Edge) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+
+	for _, v := range f.Types {
+		if v == e.Type {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Neighbour соседний узел
+// ID - идентификатор соседнего узла
+// Direction - направление ребра относительно исходного узла
+// Edge - ребро связывающее узлы
+type Neighbour struct {
+	ID        string
+	Direction Direction
+	Edge      Edge
+}
+
+// Path путь между двумя узлами
+// Nodes - идентификаторы узлов пути, начиная с исходного
+// Edges - ребра пути, Edges[i] связывает Nodes[i] и Nodes[i+1]
+type Path struct {
+	Nodes []string
+	Edges []Edge
+}
+
+// Graph граф объектов STIX. Узлами графа являются объекты STIX DO, STIX CO и прочие объекты, кроме
+// "relationship" и "sighting", ребра строятся на основании объектов "relationship" (source_ref -> target_ref),
+// "sighting" (sighting_of_ref -> where_sighted_refs и observed_data_refs) и встроенных ссылок узлов (свойства
+// оканчивающиеся на "_ref" и "_refs", кроме "object_marking_refs"). Ребра к отсутствующим в графе узлам
+// сохраняются, но не учитываются при обходе графа. Не безопасен для использования из нескольких горутин.
+type Graph struct {
+	nodes    map[string]interface{}
+	sros     map[string]interface{}
+	order    []string
+	modified map[string]string
+	edges    []Edge
+	out      map[string][]int
+	in       map[string][]int
+}
+
+// NewGraph создает граф из списка объектов STIX. Объектами могут быть как типы данного пакета,
+// так и объекты в виде json.RawMessage
+func NewGraph(objects []interface{}) (*Graph, error) {
+	g := &Graph{
+		nodes:    map[string]interface{}{},
+		sros:     map[string]interface{}{},
+		modified: map[string]string{},
+		out:      map[string][]int{},
+		in:       map[string][]int{},
+	}
+
+	for _, obj := range objects {
+		if err := g.Add(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// Add добавляет объект в граф. Если объект с таким идентификатором уже содержится в графе,
+// он заменяется, при условии что добавляемый объект не старше (по свойству modified)
+func (g *Graph) Add(obj interface{}) error {
+	m, err := commonlibs.ToMap(obj)
+	if err != nil {
+		return err
+	}
+
+	id, _ := m["id"].(string)
+	objType, _ := m["type"].(string)
+	if id == "" || objType == "" {
+		return fmt.Errorf("the object must contain the properties 'id' and 'type'")
+	}
+
+	modified, _ := m["modified"].(string)
+	if _, ok := g.getObject(id); ok {
+		if commonlibs.CompareTimestamps(modified, g.modified[id]) < 0 {
+			return nil
+		}
+
+		g.replace(id, obj, modified)
+
+		return nil
+	}
+
+	g.order = append(g.order, id)
+	g.modified[id] = modified
+
+	if objType == "relationship" || objType == "sighting" {
+		g.sros[id] = obj
+	} else {
+		g.nodes[id] = obj
+	}
+
+	for _, e := range getEdges(m) {
+		g.addEdge(e)
+	}
+
+	return nil
+}
+
+// replace заменяет объект и перестраивает ребра графа
+func (g *Graph) replace(id string, obj interface{}, modified string) {
+	if _, ok := g.sros[id]; ok {
+		g.sros[id] = obj
+	} else {
+		g.nodes[id] = obj
+	}
+	g.modified[id] = modified
+
+	g.edges = nil
+	g.out = map[string][]int{}
+	g.in = map[string][]int{}
+
+	for _, v := range g.order {
+		o, _ := g.getObject(v)
+		m, err := commonlibs.ToMap(o)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range getEdges(m) {
+			g.addEdge(e)
+		}
+	}
+}
+
+func (g *Graph) addEdge(e Edge) {
+	g.edges = append(g.edges, e)
+	num := len(g.edges) - 1
+
+	g.out[e.Source] = append(g.out[e.Source], num)
+	g.in[e.Target] = append(g.in[e.Target], num)
+}
+
+func (g *Graph) getObject(id string) (interface{}, bool) {
+	if obj, ok := g.nodes[id]; ok {
+		return obj, true
+	}
+
+	obj, ok := g.sros[id]
+
+	return obj, ok
+}
+
+// GetNode возвращает объект узла графа
+func (g *Graph) GetNode(id string) (interface{}, bool) {
+	obj, ok := g.nodes[id]
+
+	return obj, ok
+}
+
+// GetNodes возвращает отсортированный список идентификаторов узлов графа
+func (g *Graph) GetNodes() []string {
+	list := make([]string, 0, len(g.nodes))
+	for k := range g.nodes {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// GetEdges возвращает все ребра графа, в том числе ребра к отсутствующим в графе узлам
+func (g *Graph) GetEdges() []Edge {
+	list := make([]Edge, len(g.edges))
+	copy(list, g.edges)
+
+	return list
+}
+
+// GetNeighbours возвращает соседей узла id с учетом направления и типов ребер
+func (g *Graph) GetNeighbours(id string, filter EdgeFilter) []Neighbour {
+	list := []Neighbour{}
+
+	if filter.Direction != DirectionIncoming {
+		for _, num := range g.out[id] {
+			e := g.edges[num]
+			if _, ok := g.nodes[e.Target]; ok && filter.match(e) {
+				list = append(list, Neighbour{ID: e.Target, Direction: DirectionOutgoing, Edge: e})
+			}
+		}
+	}
+
+	if filter.Direction != DirectionOutgoing {
+		for _, num := range g.in[id] {
+			e := g.edges[num]
+			if _, ok := g.nodes[e.Source]; ok && filter.match(e) {
+				list = append(list, Neighbour{ID: e.Source, Direction: DirectionIncoming, Edge: e})
+			}
+		}
+	}
+
+	return list
+}
+
+// GetShortestPath возвращает кратчайший путь (по количеству ребер) между узлами from и to
+func (g *Graph) GetShortestPath(from, to string, filter EdgeFilter) (Path, error) {
+	for _, id := range []string{from, to} {
+		if _, ok := g.nodes[id]; !ok {
+			return Path{}, fmt.Errorf("the node '%s' does not exist in the graph", id)
+		}
+	}
+
+	type step struct {
+		prev string
+		edge Edge
+	}
+
+	visited := map[string]step{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 && queue[0] != to {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, n := range g.GetNeighbours(current, filter) {
+			if _, ok := visited[n.ID]; ok {
+				continue
+			}
+
+			visited[n.ID] = step{prev: current, edge: n.Edge}
+			queue = append(queue, n.ID)
+		}
+	}
+
+	if _, ok := visited[to]; !ok {
+		return Path{}, fmt.Errorf("there is no path between the nodes '%s' and '%s'", from, to)
+	}
+
+	path := Path{Nodes: []string{to}, Edges: []Edge{}}
+	for current := to; current != from; current = visited[current].prev {
+		path.Nodes = append([]string{visited[current].prev}, path.Nodes...)
+		path.Edges = append([]Edge{visited[current].edge}, path.Edges...)
+	}
+
+	return path, nil
+}
+
+// GetConnectedComponents возвращает компоненты связности графа (без учета направления ребер).
+// Идентификаторы узлов внутри компонента отсортированы, компоненты упорядочены по убыванию размера
+func (g *Graph) GetConnectedComponents() [][]string {
+	visited := map[string]bool{}
+	components := [][]string{}
+
+	for _, id := range g.GetNodes() {
+		if visited[id] {
+			continue
+		}
+
+		component := []string{}
+		visited[id] = true
+		queue := []string{id}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			component = append(component, current)
+
+			for _, n := range g.GetNeighbours(current, EdgeFilter{}) {
+				if !visited[n.ID] {
+					visited[n.ID] = true
+					queue = append(queue, n.ID)
+				}
+			}
+		}
+
+		sort.Strings(component)
+		components = append(components, component)
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return len(components[i]) > len(components[j])
+	})
+
+	return components
+}
+
+// GetSubgraph возвращает объект "bundle" содержащий узлы, находящиеся на расстоянии не более depth ребер
+// от узла id, а также объекты "relationship" и "sighting", связывающие эти узлы
+func (g *Graph) GetSubgraph(id string, depth int, filter EdgeFilter) (*stixhelpers.BundleObjectSTIX, error) {
+	if _, ok := g.nodes[id]; !ok {
+		return nil, fmt.Errorf("the node '%s' does not exist in the graph", id)
+	}
+
+	if depth < 0 {
+		return nil, fmt.Errorf("the depth must not be negative")
+	}
+
+	distance := map[string]int{id: 0}
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if distance[current] == depth {
+			continue
+		}
+
+		for _, n := range g.GetNeighbours(current, filter) {
+			if _, ok := distance[n.ID]; !ok {
+				distance[n.ID] = distance[current] + 1
+				queue = append(queue, n.ID)
+			}
+		}
+	}
+
+	sros := map[string]bool{}
+	for _, e := range g.edges {
+		if e.IsEmbedded() || !filter.match(e) {
+			continue
+		}
+
+		_, okSource := distance[e.Source]
+		_, okTarget := distance[e.Target]
+		if okSource && okTarget {
+			sros[e.ID] = true
+		}
+	}
+
+	bundle := &stixhelpers.BundleObjectSTIX{
+		Type:    "bundle",
+		ID:      "bundle--" + commonlibs.GetUUIDv4(),
+		Objects: []interface{}{},
+	}
+
+	for _, v := range g.order {
+		if _, ok := distance[v]; ok {
+			bundle.Objects = append(bundle.Objects, g.nodes[v])
+
+			continue
+		}
+
+		if sros[v] {
+			bundle.Objects = append(bundle.Objects, g.sros[v])
+		}
+	}
+
+	return bundle, nil
+}
+
+// getEdges возвращает ребра построенные на основании объекта
+func getEdges(m map[string]interface{}) []Edge {
+	id, _ := m["id"].(string)
+	edges := []Edge{}
+
+	switch m["type"] {
+	case "relationship":
+		relType, _ := m["relationship_type"].(string)
+		source, _ := m["source_ref"].(string)
+		target, _ := m["target_ref"].(string)
+
+		if source != "" && target != "" {
+			edges = append(edges, Edge{ID: id, Type: relType, Property: "target_ref", Source: source, Target: target})
+		}
+
+	case "sighting":
+		source, _ := m["sighting_of_ref"].(string)
+		if source == "" {
+			return edges
+		}
+
+		for _, property := range []string{"where_sighted_refs", "observed_data_refs"} {
+			for _, target := range getRefs(m[property]) {
+				edges = append(edges, Edge{ID: id, Type: EdgeTypeSighting, Property: property, Source: source, Target: target})
+			}
+		}
+
+	default:
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if k == "object_marking_refs" || !(strings.HasSuffix(k, "_ref") || strings.HasSuffix(k, "_refs")) {
+				continue
+			}
+
+			for _, target := range getRefs(m[k]) {
+				edges = append(edges, Edge{Type: k, Property: k, Source: id, Target: target})
+			}
+		}
+	}
+
+	return edges
+}
+
+func getRefs(value interface{}) []string {
+	list := []string{}
+
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			list = append(list, v)
+		}
+
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				list = append(list, s)
+			}
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,437 @@
+// Package stixindex инвертированный индекс объектов STIX для полнотекстового поиска. Данные для индексации
+// формируются методом GeneratingDataForIndexing объектов, поиск выполняется по лексемам и их префиксам
+// с ранжированием результатов по весу свойства, частоте лексемы и её редкости среди объектов
+package stixindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+)
+
+// Indexer объект, формирующий данные для индексации в виде "наименование свойства - текст"
+type Indexer interface {
+	GeneratingDataForIndexing() map[string]string
+}
+
+// DefaultFieldWeights веса свойств по умолчанию, свойства отсутствующие в списке имеют вес 1
+var DefaultFieldWeights = map[string]float64{
+	"name":        4,
+	"value":       4,
+	"aliases":     3,
+	"external_id": 3,
+	"hashes":      3,
+	"key":         3,
+	"path":        3,
+	"subject":     2,
+	"labels":      2,
+	"pattern":     2,
+	"id":          2,
+}
+
+// prefixFactor коэффициент, применяемый к лексемам найденным по префиксу
+const prefixFactor = 0.5
+
+// Options параметры индекса
+// FieldWeights - веса свойств, при отсутствии используются DefaultFieldWeights
+type Options struct {
+	FieldWeights map[string]float64
+}
+
+// SearchOptions параметры поиска
+// Types - типы объектов, среди которых выполняется поиск, если не заданы, поиск выполняется среди всех объектов
+// Fields - свойства, по которым выполняется поиск, если не заданы, поиск выполняется по всем свойствам
+// Limit - максимальное количество результатов, при значении 0 возвращаются все результаты
+// ExactOnly - лексемы запроса сравниваются только целиком (без поиска по префиксу)
+type SearchOptions struct {
+	Types     []string
+	Fields    []string
+	Limit     int
+	ExactOnly bool
+}
+
+// Result результат поиска
+// ID - идентификатор объекта
+// Type - тип объекта
+// Score - оценка соответствия запросу, результаты упорядочены по её убыванию
+// Fields - свойства, в которых найдены лексемы запроса
+type Result struct {
+	ID     string
+	Type   string
+	Score  float64
+	Fields []string
+}
+
+// document проиндексированный объект
+type document struct {
+	objType string
+	// terms количество вхождений лексемы в каждое свойство
+	terms map[string]map[string]int
+}
+
+// Index инвертированный индекс объектов STIX. Безопасен для использования из нескольких горутин
+type Index struct {
+	mu       sync.RWMutex
+	weights  map[string]float64
+	docs     map[string]document
+	postings map[string]map[string]bool
+	// sorted упорядоченный список лексем для поиска по префиксу, формируется при первом поиске после изменения индекса
+	sorted []string
+	dirty  bool
+}
+
+// NewIndex создает пустой индекс
+func NewIndex(opts Options) *Index {
+	weights := opts.FieldWeights
+	if weights == nil {
+		weights = DefaultFieldWeights
+	}
+
+	return &Index{
+		weights:  weights,
+		docs:     map[string]document{},
+		postings: map[string]map[string]bool{},
+	}
+}
+
+// Add добавляет объекты в индекс, ранее добавленный объект с тем же идентификатором заменяется.
+// Объекты, не реализующие интерфейс Indexer, индексируются с помощью GetDataForIndexing
+func (idx *Index) Add(objects ...interface{}) error {
+	list := make([]map[string]string, 0, len(objects))
+	for _, obj := range objects {
+		data, err := GetDataForIndexing(obj)
+		if err != nil {
+			return err
+		}
+
+		if data["id"] == "" {
+			return fmt.Errorf("the object must contain the property 'id'")
+		}
+
+		list = append(list, data)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, data := range list {
+		id := data["id"]
+		idx.remove(id)
+
+		doc := document{objType: data["type"], terms: map[string]map[string]int{}}
+		for field, text := range data {
+			if field == "type" {
+				continue
+			}
+
+			terms := tokenizeAll(text)
+			//идентификатор индексируется только целиком, иначе тип объекта в его составе совпадал бы с любым запросом по типу
+			if field == "id" {
+				terms = []string{strings.ToLower(text)}
+			}
+
+			for _, term := range terms {
+				if doc.terms[term] == nil {
+					doc.terms[term] = map[string]int{}
+				}
+				doc.terms[term][field]++
+			}
+		}
+
+		for term := range doc.terms {
+			if idx.postings[term] == nil {
+				idx.postings[term] = map[string]bool{}
+				idx.dirty = true
+			}
+			idx.postings[term][id] = true
+		}
+
+		idx.docs[id] = doc
+	}
+
+	return nil
+}
+
+// Remove удаляет объект из индекса, возвращает false если объект отсутствует
+func (idx *Index) Remove(id string) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	return idx.remove(id)
+}
+
+func (idx *Index) remove(id string) bool {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return false
+	}
+
+	for term := range doc.terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+			idx.dirty = true
+		}
+	}
+	delete(idx.docs, id)
+
+	return true
+}
+
+// Len возвращает количество объектов в индексе
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.docs)
+}
+
+// Search выполняет поиск объектов, содержащих все лексемы запроса query (лексема запроса совпадает с лексемой
+// объекта целиком или является её префиксом). Совпадение целиком оценивается выше совпадения по префиксу,
+// идентификатор объекта сравнивается только целиком
+func (idx *Index) Search(query string, opts SearchOptions) []Result {
+	terms := getCompounds(query)
+	if len(terms) == 0 {
+		return []Result{}
+	}
+
+	idx.mu.Lock()
+	if idx.dirty {
+		idx.sorted = make([]string, 0, len(idx.postings))
+		for term := range idx.postings {
+			idx.sorted = append(idx.sorted, term)
+		}
+		sort.Strings(idx.sorted)
+		idx.dirty = false
+	}
+	idx.mu.Unlock()
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	types := toSet(opts.Types)
+	fields := toSet(opts.Fields)
+
+	var (
+		scores  map[string]float64
+		matched = map[string]map[string]bool{}
+	)
+	for _, term := range terms {
+		indexedTerms := idx.getTerms(term, opts.ExactOnly)
+
+		//редкость лексемы запроса определяется по всем объектам, содержащим соответствующие ей лексемы, поэтому
+		//совпадение по префиксу с редкой лексемой не оценивается выше совпадения целиком
+		docs := map[string]bool{}
+		for _, indexed := range indexedTerms {
+			for id := range idx.postings[indexed] {
+				docs[id] = true
+			}
+		}
+		idf := math.Log(1 + (float64(len(idx.docs))-float64(len(docs))+0.5)/(float64(len(docs))+0.5))
+
+		current := map[string]float64{}
+		for _, indexed := range indexedTerms {
+			factor := 1.0
+			if indexed != term {
+				factor = prefixFactor
+			}
+
+			for id := range idx.postings[indexed] {
+				doc := idx.docs[id]
+				if len(types) > 0 && !types[doc.objType] {
+					continue
+				}
+
+				score := 0.0
+				for field, tf := range doc.terms[indexed] {
+					if (len(fields) > 0 && !fields[field]) || (field == "id" && indexed != term) {
+						continue
+					}
+
+					if matched[id] == nil {
+						matched[id] = map[string]bool{}
+					}
+					matched[id][field] = true
+
+					score += idx.getWeight(field) * idf * float64(tf) / float64(tf+1) * factor
+				}
+
+				//для каждой лексемы запроса учитывается лучшая из соответствующих ей лексем объекта
+				if score > current[id] {
+					current[id] = score
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = current
+
+			continue
+		}
+
+		for id, score := range scores {
+			if v, ok := current[id]; ok {
+				scores[id] = score + v
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	list := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		result := Result{ID: id, Type: idx.docs[id].objType, Score: score}
+		for field := range matched[id] {
+			result.Fields = append(result.Fields, field)
+		}
+		sort.Strings(result.Fields)
+
+		list = append(list, result)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+
+		return list[i].ID < list[j].ID
+	})
+
+	if opts.Limit > 0 && len(list) > opts.Limit {
+		list = list[:opts.Limit]
+	}
+
+	return list
+}
+
+// getTerms возвращает лексемы индекса, совпадающие с term или начинающиеся с него
+func (idx *Index) getTerms(term string, exactOnly bool) []string {
+	if exactOnly {
+		if _, ok := idx.postings[term]; ok {
+			return []string{term}
+		}
+
+		return nil
+	}
+
+	list := []string{}
+	for i := sort.SearchStrings(idx.sorted, term); i < len(idx.sorted) && strings.HasPrefix(idx.sorted[i], term); i++ {
+		list = append(list, idx.sorted[i])
+	}
+
+	return list
+}
+
+func (idx *Index) getWeight(field string) float64 {
+	if w, ok := idx.weights[field]; ok {
+		return w
+	}
+
+	return 1
+}
+
+// GetDataForIndexing возвращает данные для индексации объекта. Для объектов, реализующих интерфейс Indexer,
+// используется метод GeneratingDataForIndexing, объекты в виде JSON предварительно декодируются в типы данного
+// пакета. Для остальных объектов (например, пользовательских) индексируются строковые свойства верхнего уровня,
+// значения хешей и внешние идентификаторы
+func GetDataForIndexing(obj interface{}) (map[string]string, error) {
+	switch v := obj.(type) {
+	case Indexer:
+		return v.GeneratingDataForIndexing(), nil
+	case json.RawMessage:
+		decoded, err := stixstore.DecodeObject(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if indexer, ok := decoded.(Indexer); ok {
+			return indexer.GeneratingDataForIndexing(), nil
+		}
+	case *json.RawMessage:
+		if v != nil {
+			return GetDataForIndexing(*v)
+		}
+	}
+
+	m, err := commonlibs.ToMap(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]string{}
+	for k, v := range m {
+		if k == "spec_version" || k == "created" || k == "modified" || k == "lang" ||
+			strings.HasSuffix(k, "_ref") || strings.HasSuffix(k, "_refs") {
+			continue
+		}
+
+		switch value := v.(type) {
+		case string:
+			if value != "" {
+				data[k] = value
+			}
+
+		case []interface{}:
+			values := []string{}
+			for _, item := range value {
+				if s, ok := item.(string); ok && s != "" {
+					values = append(values, s)
+				}
+
+				if ref, ok := item.(map[string]interface{}); ok && k == "external_references" {
+					if s, ok := ref["external_id"].(string); ok && s != "" {
+						data["external_id"] = strings.TrimSpace(data["external_id"] + " " + s)
+					}
+				}
+			}
+
+			if len(values) > 0 {
+				data[k] = strings.Join(values, " ")
+			}
+
+		case map[string]interface{}:
+			if k != "hashes" {
+				continue
+			}
+
+			values := []string{}
+			for _, item := range value {
+				if s, ok := item.(string); ok && s != "" {
+					values = append(values, s)
+				}
+			}
+			sort.Strings(values)
+
+			if len(values) > 0 {
+				data[k] = strings.Join(values, " ")
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// tokenizeAll возвращает лексемы текста с повторениями, необходимыми для подсчета частоты
+func tokenizeAll(text string) []string {
+	list := []string{}
+	for _, compound := range getCompounds(text) {
+		list = append(list, Tokenize(compound)...)
+	}
+
+	return list
+}
+
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, v := range list {
+		set[v] = true
+	}
+
+	return set
+}
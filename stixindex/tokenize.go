@@ -0,0 +1,51 @@
+package stixindex
+
+import (
+	"strings"
+	"unicode"
+)
+
+// compoundSeparators символы, разделяющие составные лексемы (помимо пробельных символов)
+const compoundSeparators = ",;()[]{}<>\"'`|!?*"
+
+// compoundTrim символы, удаляемые по краям составной лексемы
+const compoundTrim = ".:/-_\\=+#@"
+
+// Tokenize разбивает текст на лексемы в нижнем регистре. Помимо слов, состоящих из букв и цифр, возвращаются
+// составные лексемы, разделенные пробелами, например, для "evil-domain.com" будут получены лексемы
+// "evil-domain.com", "evil", "domain" и "com". Поэтому поиск находит как значения целиком (IP-адреса, домены,
+// идентификаторы CVE), так и отдельные слова
+func Tokenize(text string) []string {
+	list := []string{}
+	seen := map[string]bool{}
+	add := func(v string) {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+
+	for _, compound := range getCompounds(text) {
+		add(compound)
+
+		for _, word := range strings.FieldsFunc(compound, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			add(word)
+		}
+	}
+
+	return list
+}
+
+// getCompounds возвращает составные лексемы текста в нижнем регистре
+func getCompounds(text string) []string {
+	list := []string{}
+	for _, v := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(compoundSeparators, r)
+	}) {
+		if v = strings.Trim(v, compoundTrim); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,210 @@
+package stixstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/relationshipobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+)
+
+// Decoder декодирует объект STIX из JSON
+type Decoder func(raw *json.RawMessage) (interface{}, error)
+
+type decoderJSON interface {
+	DecodeJSON(raw *json.RawMessage) (interface{}, error)
+}
+
+type encoderJSON interface {
+	EncodeJSON(interface{}) (*[]byte, error)
+}
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{}
+	types      = map[string]reflect.Type{}
+)
+
+func init() {
+	for k, v := range map[string]decoderJSON{
+		"attack-pattern":       domainobjectsstix.AttackPatternDomainObjectsSTIX{},
+		"campaign":             domainobjectsstix.CampaignDomainObjectsSTIX{},
+		"course-of-action":     domainobjectsstix.CourseOfActionDomainObjectsSTIX{},
+		"event":                domainobjectsstix.EventDomainObjectsSTIX{},
+		"grouping":             domainobjectsstix.GroupingDomainObjectsSTIX{},
+		"identity":             domainobjectsstix.IdentityDomainObjectsSTIX{},
+		"impact":               domainobjectsstix.ImpactDomainObjectsSTIX{},
+		"incident":             domainobjectsstix.IncidentDomainObjectsSTIX{},
+		"indicator":            domainobjectsstix.IndicatorDomainObjectsSTIX{},
+		"infrastructure":       domainobjectsstix.InfrastructureDomainObjectsSTIX{},
+		"intrusion-set":        domainobjectsstix.IntrusionSetDomainObjectsSTIX{},
+		"location":             domainobjectsstix.LocationDomainObjectsSTIX{},
+		"malware":              domainobjectsstix.MalwareDomainObjectsSTIX{},
+		"malware-analysis":     domainobjectsstix.MalwareAnalysisDomainObjectsSTIX{},
+		"note":                 domainobjectsstix.NoteDomainObjectsSTIX{},
+		"observed-data":        domainobjectsstix.ObservedDataDomainObjectsSTIX{},
+		"opinion":              domainobjectsstix.OpinionDomainObjectsSTIX{},
+		"report":               domainobjectsstix.ReportDomainObjectsSTIX{},
+		"task":                 domainobjectsstix.TaskDomainObjectsSTIX{},
+		"threat-actor":         domainobjectsstix.ThreatActorDomainObjectsSTIX{},
+		"tool":                 domainobjectsstix.ToolDomainObjectsSTIX{},
+		"vulnerability":        domainobjectsstix.VulnerabilityDomainObjectsSTIX{},
+		"artifact":             cyberobservableobjectsstix.ArtifactCyberObservableObjectSTIX{},
+		"autonomous-system":    cyberobservableobjectsstix.AutonomousSystemCyberObservableObjectSTIX{},
+		"directory":            cyberobservableobjectsstix.DirectoryCyberObservableObjectSTIX{},
+		"domain-name":          cyberobservableobjectsstix.DomainNameCyberObservableObjectSTIX{},
+		"email-addr":           cyberobservableobjectsstix.EmailAddressCyberObservableObjectSTIX{},
+		"email-message":        cyberobservableobjectsstix.EmailMessageCyberObservableObjectSTIX{},
+		"file":                 cyberobservableobjectsstix.FileCyberObservableObjectSTIX{},
+		"ipv4-addr":            cyberobservableobjectsstix.IPv4AddressCyberObservableObjectSTIX{},
+		"ipv6-addr":            cyberobservableobjectsstix.IPv6AddressCyberObservableObjectSTIX{},
+		"mac-addr":             cyberobservableobjectsstix.MACAddressCyberObservableObjectSTIX{},
+		"mutex":                cyberobservableobjectsstix.MutexCyberObservableObjectSTIX{},
+		"network-traffic":      cyberobservableobjectsstix.NetworkTrafficCyberObservableObjectSTIX{},
+		"process":              cyberobservableobjectsstix.ProcessCyberObservableObjectSTIX{},
+		"software":             cyberobservableobjectsstix.SoftwareCyberObservableObjectSTIX{},
+		"url":                  cyberobservableobjectsstix.URLCyberObservableObjectSTIX{},
+		"user-account":         cyberobservableobjectsstix.UserAccountCyberObservableObjectSTIX{},
+		"windows-registry-key": cyberobservableobjectsstix.WindowsRegistryKeyCyberObservableObjectSTIX{},
+		"x509-certificate":     cyberobservableobjectsstix.X509CertificateCyberObservableObjectSTIX{},
+		"relationship":         relationshipobjectsstix.RelationshipObjectSTIX{},
+		"sighting":             relationshipobjectsstix.SightingObjectSTIX{},
+		"marking-definition":   stixhelpers.MarkingDefinitionObjectSTIX{},
+		"language-content":     stixhelpers.LanguageContentTypeSTIX{},
+		"extension-definition": stixhelpers.ExtensionDefinitionObjectSTIX{},
+	} {
+		decoders[k] = v.DecodeJSON
+		types[k] = reflect.TypeOf(v)
+	}
+}
+
+// GetObjectTypes возвращает типы данного пакета, соответствующие предопределенным типам объектов STIX
+func GetObjectTypes() map[string]reflect.Type {
+	list := make(map[string]reflect.Type, len(types))
+	for k, v := range types {
+		list[k] = v
+	}
+
+	return list
+}
+
+// ObjectField свойство JSON структуры, IsTimestamp - строковое свойство содержит время
+// (отмечено тегом timestamp:"true")
+type ObjectField struct {
+	Name        string
+	Type        reflect.Type
+	IsTimestamp bool
+}
+
+// GetObjectFields возвращает свойства JSON структуры rt (например, полученной с помощью GetObjectTypes)
+// с учетом встроенных структур
+func GetObjectFields(rt reflect.Type) []ObjectField {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+
+	list := []ObjectField{}
+	if rt.Kind() != reflect.Struct {
+		return list
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			for _, v := range GetObjectFields(f.Type) {
+				if !seen[v.Name] {
+					seen[v.Name] = true
+					list = append(list, v)
+				}
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		if !seen[name] {
+			seen[name] = true
+			list = append(list, ObjectField{Name: name, Type: f.Type, IsTimestamp: f.Tag.Get("timestamp") == "true"})
+		}
+	}
+
+	return list
+}
+
+// RegisterDecoder регистрирует декодер объектов типа objType (например, для пользовательских объектов),
+// декодер ранее зарегистрированный для этого типа заменяется
+func RegisterDecoder(objType string, decoder Decoder) error {
+	if decoder == nil {
+		return fmt.Errorf("the decoder for the type '%s' must not be empty", objType)
+	}
+
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders[objType] = decoder
+
+	return nil
+}
+
+// DecodeObject декодирует объект STIX из JSON с помощью метода DecodeJSON типа, соответствующего
+// свойству type объекта. Объекты неизвестных типов возвращаются в виде json.RawMessage
+func DecodeObject(raw json.RawMessage) (interface{}, error) {
+	obj := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+
+	decodersMu.RLock()
+	decoder, ok := decoders[obj.Type]
+	decodersMu.RUnlock()
+
+	if !ok {
+		return append(json.RawMessage{}, raw...), nil
+	}
+
+	return decoder(&raw)
+}
+
+// EncodeObject кодирует объект STIX в JSON с помощью его метода EncodeJSON, при его отсутствии с помощью json.Marshal
+func EncodeObject(obj interface{}) ([]byte, error) {
+	switch v := obj.(type) {
+	case json.RawMessage:
+		return v, nil
+	case *json.RawMessage:
+		if v == nil {
+			return nil, fmt.Errorf("the object must not be empty")
+		}
+
+		return *v, nil
+	case encoderJSON:
+		b, err := v.EncodeJSON(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return *b, nil
+	}
+
+	return json.Marshal(obj)
+}
@@ -0,0 +1,468 @@
+package stixstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+)
+
+// FileSystemStore хранилище объектов STIX в файловой системе. Версионируемые объекты (имеющие свойство modified)
+// хранятся в файлах <root>/<type>/<id>/<modified>.json, где <modified> время изменения объекта в UTC в формате
+// YYYYMMDDhhmmssffffff, неверсионируемые объекты (STIX CO, "marking-definition") в файлах <root>/<type>/<id>.json.
+// Такая структура директорий совместима с FileSystemStore библиотеки python-stix2. При чтении также поддерживаются
+// файлы, содержащие объект "bundle".
+//
+// Запись файлов атомарна (через временный файл и переименование), поэтому читатели, в том числе из других процессов,
+// никогда не получают частично записанный объект. Безопасно для использования из нескольких горутин
+type FileSystemStore struct {
+	mu   sync.RWMutex
+	root string
+}
+
+// storedObject объект, прочитанный из файла
+type storedObject struct {
+	object interface{}
+	data   map[string]interface{}
+	info   ObjectInfo
+}
+
+// NewFileSystemStore создает хранилище объектов STIX в директории root, при отсутствии директория создается
+func NewFileSystemStore(root string) (*FileSystemStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileSystemStore{root: root}, nil
+}
+
+// GetRoot возвращает корневую директорию хранилища
+func (s *FileSystemStore) GetRoot() string {
+	return s.root
+}
+
+// Add добавляет объекты. Новые версии отозванного объекта не добавляются
+func (s *FileSystemStore) Add(objects ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, obj := range objects {
+		b, err := EncodeObject(obj)
+		if err != nil {
+			return err
+		}
+
+		m := map[string]interface{}{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return err
+		}
+
+		info, err := GetObjectInfo(m)
+		if err != nil {
+			return err
+		}
+
+		if err := s.add(info, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *FileSystemStore) add(info ObjectInfo, b []byte) error {
+	path, err := s.getPath(info)
+	if err != nil {
+		return err
+	}
+
+	if info.Modified != "" {
+		versions, err := s.getVersions(info.ID)
+		if err != nil {
+			return err
+		}
+
+		if n := len(versions); n > 0 && versions[n-1].info.Revoked && commonlibs.CompareTimestamps(info.Modified, versions[n-1].info.Modified) > 0 {
+			return fmt.Errorf("object '%s': %w", info.ID, ErrRevoked)
+		}
+	}
+
+	return writeFileAtomic(path, b)
+}
+
+// getPath возвращает путь к файлу объекта
+func (s *FileSystemStore) getPath(info ObjectInfo) (string, error) {
+	if err := checkPathElement(info.Type); err != nil {
+		return "", err
+	}
+
+	if err := checkPathElement(info.ID); err != nil {
+		return "", err
+	}
+
+	if info.Modified == "" {
+		return filepath.Join(s.root, info.Type, info.ID+".json"), nil
+	}
+
+	name, err := timestampToFileName(info.Modified)
+	if err != nil {
+		return "", fmt.Errorf("object '%s': %w", info.ID, err)
+	}
+
+	return filepath.Join(s.root, info.Type, info.ID, name+".json"), nil
+}
+
+// Get возвращает последнюю версию объекта
+func (s *FileSystemStore) Get(id string) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, err := s.getVersions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("object '%s': %w", id, ErrNotFound)
+	}
+
+	return versions[len(versions)-1].object, nil
+}
+
+// GetVersion возвращает версию объекта с заданным значением свойства modified
+func (s *FileSystemStore) GetVersion(id, modified string) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, err := s.getVersions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range versions {
+		if commonlibs.CompareTimestamps(v.info.Modified, modified) == 0 {
+			return v.object, nil
+		}
+	}
+
+	return nil, fmt.Errorf("object '%s' version '%s': %w", id, modified, ErrNotFound)
+}
+
+// GetAllVersions возвращает все версии объекта, упорядоченные по возрастанию modified
+func (s *FileSystemStore) GetAllVersions(id string) ([]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, err := s.getVersions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("object '%s': %w", id, ErrNotFound)
+	}
+
+	list := make([]interface{}, 0, len(versions))
+	for _, v := range versions {
+		list = append(list, v.object)
+	}
+
+	return list, nil
+}
+
+// Query возвращает последние версии объектов, удовлетворяющие условию filter
+func (s *FileSystemStore) Query(filter Filter) ([]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids, err := s.getIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	list := []interface{}{}
+	for _, id := range ids {
+		versions, err := s.getVersions(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(versions) == 0 {
+			continue
+		}
+
+		last := versions[len(versions)-1]
+		if filter == nil || filter.Match(last.data) {
+			list = append(list, last.object)
+		}
+	}
+
+	return list, nil
+}
+
+// Delete удаляет все версии объекта
+func (s *FileSystemStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	objType, err := getTypeFromID(id)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.root, objType, id)
+	file := dir + ".json"
+
+	_, errDir := os.Stat(dir)
+	_, errFile := os.Stat(file)
+	if errors.Is(errDir, os.ErrNotExist) && errors.Is(errFile, os.ErrNotExist) {
+		return fmt.Errorf("object '%s': %w", id, ErrNotFound)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// Revoke отзывает объект, добавляя его новую версию со свойством revoked равным true
+func (s *FileSystemStore) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.getVersions(id)
+	if err != nil {
+		return err
+	}
+
+	if len(versions) == 0 {
+		return fmt.Errorf("object '%s': %w", id, ErrNotFound)
+	}
+
+	obj, m, err := NewRevokedVersion(versions[len(versions)-1].object)
+	if err != nil {
+		return err
+	}
+
+	info, err := GetObjectInfo(m)
+	if err != nil {
+		return err
+	}
+
+	b, err := EncodeObject(obj)
+	if err != nil {
+		return err
+	}
+
+	return s.add(info, b)
+}
+
+// getVersions возвращает все версии объекта, упорядоченные по возрастанию modified
+func (s *FileSystemStore) getVersions(id string) ([]storedObject, error) {
+	objType, err := getTypeFromID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(s.root, objType, id)
+	files := []string{dir + ".json"}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		//временные файлы, записываемые в данный момент, пропускаются
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".json" && !strings.HasPrefix(e.Name(), ".") {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	list := []storedObject{}
+	for _, f := range files {
+		objects, err := readFile(f)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range objects {
+			if v.info.ID == id {
+				list = append(list, v)
+			}
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return commonlibs.CompareTimestamps(list[i].info.Modified, list[j].info.Modified) < 0
+	})
+
+	return list, nil
+}
+
+// getIDs возвращает отсортированный список идентификаторов объектов, содержащихся в хранилище
+func (s *FileSystemStore) getIDs() ([]string, error) {
+	types, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, t := range types {
+		if !t.IsDir() {
+			continue
+		}
+
+		entries, err := os.ReadDir(filepath.Join(s.root, t.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			name := strings.TrimSuffix(e.Name(), ".json")
+			if strings.HasPrefix(name, t.Name()+"--") && (e.IsDir() || name != e.Name()) {
+				ids[name] = true
+			}
+		}
+	}
+
+	list := make([]string, 0, len(ids))
+	for k := range ids {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// readFile читает объект или объекты, содержащиеся в объекте "bundle", из файла
+func readFile(path string) ([]storedObject, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []json.RawMessage
+	bundle := struct {
+		Type    string            `json:"type"`
+		Objects []json.RawMessage `json:"objects"`
+	}{}
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return nil, fmt.Errorf("file '%s': %w", path, err)
+	}
+
+	if bundle.Type == "bundle" {
+		raw = bundle.Objects
+	} else {
+		raw = []json.RawMessage{b}
+	}
+
+	list := make([]storedObject, 0, len(raw))
+	for _, v := range raw {
+		m := map[string]interface{}{}
+		if err := json.Unmarshal(v, &m); err != nil {
+			return nil, fmt.Errorf("file '%s': %w", path, err)
+		}
+
+		info, err := GetObjectInfo(m)
+		if err != nil {
+			return nil, fmt.Errorf("file '%s': %w", path, err)
+		}
+
+		obj, err := DecodeObject(v)
+		if err != nil {
+			return nil, fmt.Errorf("file '%s': %w", path, err)
+		}
+
+		list = append(list, storedObject{object: obj, data: m, info: info})
+	}
+
+	return list, nil
+}
+
+// writeFileAtomic записывает файл через временный файл в той же директории с последующим переименованием
+func writeFileAtomic(path string, b []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*.json")
+	if err != nil {
+		return err
+	}
+
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpName, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, path)
+}
+
+// timestampToFileName преобразует время в наименование файла в формате python-stix2 (YYYYMMDDhhmmssffffff, UTC)
+func timestampToFileName(v string) (string, error) {
+	t, err := commonlibs.ParseTimestamp(v)
+	if err != nil {
+		return "", err
+	}
+
+	t = t.UTC()
+
+	return fmt.Sprintf("%s%06d", t.Format("20060102150405"), t.Nanosecond()/1000), nil
+}
+
+func getTypeFromID(id string) (string, error) {
+	if err := checkPathElement(id); err != nil {
+		return "", err
+	}
+
+	objType, _, ok := strings.Cut(id, "--")
+	if !ok || objType == "" {
+		return "", fmt.Errorf("invalid object identifier '%s'", id)
+	}
+
+	return objType, nil
+}
+
+// checkPathElement проверяет что значение может использоваться в качестве элемента пути
+func checkPathElement(v string) error {
+	if v == "" || v == "." || v == ".." || strings.ContainsAny(v, `/\`) || strings.ContainsRune(v, 0) {
+		return fmt.Errorf("the value '%s' cannot be used as a path element", v)
+	}
+
+	return nil
+}
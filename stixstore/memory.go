@@ -0,0 +1,189 @@
+package stixstore
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+)
+
+// version версия объекта
+type version struct {
+	object interface{}
+	data   map[string]interface{}
+	info   ObjectInfo
+}
+
+// MemoryStore хранилище объектов STIX в памяти. Безопасно для использования из нескольких горутин
+type MemoryStore struct {
+	mu      sync.RWMutex
+	objects map[string][]version
+}
+
+// NewMemoryStore создает хранилище объектов STIX в памяти и добавляет в него объекты objects
+func NewMemoryStore(objects ...interface{}) (*MemoryStore, error) {
+	s := &MemoryStore{objects: map[string][]version{}}
+	if err := s.Add(objects...); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Add добавляет объекты. Новые версии отозванного объекта не добавляются
+func (s *MemoryStore) Add(objects ...interface{}) error {
+	list := make([]version, 0, len(objects))
+	for _, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return err
+		}
+
+		info, err := GetObjectInfo(m)
+		if err != nil {
+			return err
+		}
+
+		list = append(list, version{object: obj, data: m, info: info})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range list {
+		if err := s.add(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) add(v version) error {
+	versions := s.objects[v.info.ID]
+	for k, current := range versions {
+		if commonlibs.CompareTimestamps(current.info.Modified, v.info.Modified) == 0 {
+			versions[k] = v
+
+			return nil
+		}
+	}
+
+	if n := len(versions); n > 0 && versions[n-1].info.Revoked && commonlibs.CompareTimestamps(v.info.Modified, versions[n-1].info.Modified) > 0 {
+		return fmt.Errorf("object '%s': %w", v.info.ID, ErrRevoked)
+	}
+
+	versions = append(versions, v)
+	sort.SliceStable(versions, func(i, j int) bool {
+		return commonlibs.CompareTimestamps(versions[i].info.Modified, versions[j].info.Modified) < 0
+	})
+	s.objects[v.info.ID] = versions
+
+	return nil
+}
+
+// Get возвращает последнюю версию объекта
+func (s *MemoryStore) Get(id string) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, ok := s.objects[id]
+	if !ok {
+		return nil, fmt.Errorf("object '%s': %w", id, ErrNotFound)
+	}
+
+	return versions[len(versions)-1].object, nil
+}
+
+// GetVersion возвращает версию объекта с заданным значением свойства modified
+func (s *MemoryStore) GetVersion(id, modified string) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, v := range s.objects[id] {
+		if commonlibs.CompareTimestamps(v.info.Modified, modified) == 0 {
+			return v.object, nil
+		}
+	}
+
+	return nil, fmt.Errorf("object '%s' version '%s': %w", id, modified, ErrNotFound)
+}
+
+// GetAllVersions возвращает все версии объекта, упорядоченные по возрастанию modified
+func (s *MemoryStore) GetAllVersions(id string) ([]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions, ok := s.objects[id]
+	if !ok {
+		return nil, fmt.Errorf("object '%s': %w", id, ErrNotFound)
+	}
+
+	list := make([]interface{}, 0, len(versions))
+	for _, v := range versions {
+		list = append(list, v.object)
+	}
+
+	return list, nil
+}
+
+// Query возвращает последние версии объектов, удовлетворяющие условию filter
+func (s *MemoryStore) Query(filter Filter) ([]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+
+	list := []interface{}{}
+	for _, id := range ids {
+		versions := s.objects[id]
+		last := versions[len(versions)-1]
+		if filter == nil || filter.Match(last.data) {
+			list = append(list, last.object)
+		}
+	}
+
+	return list, nil
+}
+
+// Delete удаляет все версии объекта
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[id]; !ok {
+		return fmt.Errorf("object '%s': %w", id, ErrNotFound)
+	}
+
+	delete(s.objects, id)
+
+	return nil
+}
+
+// Revoke отзывает объект, добавляя его новую версию со свойством revoked равным true
+func (s *MemoryStore) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, ok := s.objects[id]
+	if !ok {
+		return fmt.Errorf("object '%s': %w", id, ErrNotFound)
+	}
+
+	obj, m, err := NewRevokedVersion(versions[len(versions)-1].object)
+	if err != nil {
+		return err
+	}
+
+	info, err := GetObjectInfo(m)
+	if err != nil {
+		return err
+	}
+
+	return s.add(version{object: obj, data: m, info: info})
+}
@@ -0,0 +1,485 @@
+package sqlitestore
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/stixfilter"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+)
+
+const (
+	sqlTrue  = "1"
+	sqlFalse = "0"
+)
+
+// timestampPattern шаблон GLOB значений в формате timestampLayout
+const timestampPattern = "[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]T[0-9][0-9]:[0-9][0-9]:[0-9][0-9].[0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9][0-9]Z"
+
+// translator преобразует условия stixfilter в условие SQL для таблицы версий (псевдоним v).
+// Полученное условие отбирает все объекты, удовлетворяющие исходному (и, возможно, некоторые другие),
+// поэтому непреобразуемые условия заменяются значением "истина" в составе And, а результат запроса
+// проверяется исходным условием
+type translator struct {
+	tables []typeTable
+	args   []interface{}
+}
+
+// newTranslator создает translator. Если условие filter ограничивает тип объектов (type = '...'),
+// используются только таблицы этого типа
+func newTranslator(tables map[string]typeTable, filter stixstore.Filter) *translator {
+	names := make([]string, 0, len(tables))
+	for k := range tables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	if objType, ok := getType(filter); ok {
+		names = []string{objType}
+	}
+
+	tr := &translator{}
+	for _, name := range names {
+		if t, ok := tables[name]; ok {
+			tr.tables = append(tr.tables, t)
+		}
+	}
+
+	return tr
+}
+
+// getType возвращает тип объектов, заданный условием верхнего уровня type = '...'
+func getType(filter stixstore.Filter) (string, bool) {
+	switch f := filter.(type) {
+	case stixfilter.Filter:
+		if v, ok := f.Value.(string); ok && f.Property == "type" && f.Operator == stixfilter.OperatorEqual {
+			return v, true
+		}
+
+	case stixfilter.And:
+		for _, v := range f {
+			if objType, ok := getType(v); ok {
+				return objType, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// translate возвращает условие SQL и его параметры
+func (tr *translator) translate(filter stixstore.Filter) (string, []interface{}) {
+	if filter == nil {
+		return sqlTrue, nil
+	}
+
+	where, ok := tr.expression(filter)
+	if !ok {
+		return sqlTrue, nil
+	}
+
+	return where, tr.args
+}
+
+func (tr *translator) expression(filter stixstore.Filter) (string, bool) {
+	switch f := filter.(type) {
+	case stixfilter.Filter:
+		return tr.filter(f)
+
+	case stixfilter.And:
+		list := []string{}
+		for _, v := range f {
+			if where, ok := tr.expression(v); ok {
+				list = append(list, where)
+			}
+		}
+
+		if len(list) == 0 {
+			return sqlTrue, true
+		}
+
+		return "(" + strings.Join(list, " AND ") + ")", true
+
+	case stixfilter.Or:
+		n := len(tr.args)
+		list := []string{}
+		for _, v := range f {
+			where, ok := tr.expression(v)
+			if !ok {
+				tr.args = tr.args[:n]
+
+				return "", false
+			}
+			list = append(list, where)
+		}
+
+		if len(list) == 0 {
+			return sqlFalse, true
+		}
+
+		return "(" + strings.Join(list, " OR ") + ")", true
+	}
+
+	return "", false
+}
+
+func (tr *translator) filter(f stixfilter.Filter) (string, bool) {
+	if f.Validate() != nil {
+		return "", false
+	}
+
+	if strings.Contains(f.Property, ".") {
+		return tr.nested(f)
+	}
+
+	//свойства id и type обязательны и хранятся в таблице версий
+	if f.Property == "id" || f.Property == "type" {
+		if f.Operator == stixfilter.OperatorExists {
+			if want, ok := f.Value.(bool); ok && !want {
+				return sqlFalse, true
+			}
+
+			return sqlTrue, true
+		}
+
+		return tr.scalar("v."+f.Property, valueString, f)
+	}
+
+	if (f.Property == "created" || f.Property == "modified") && f.Operator != stixfilter.OperatorExists {
+		return tr.timestamp("v."+f.Property, f)
+	}
+
+	if f.Operator == stixfilter.OperatorExists {
+		if want, ok := f.Value.(bool); ok && !want {
+			where, _ := tr.property(f.Property, func(name string, kind valueKind) (string, bool) { return name + " IS NOT NULL", true },
+				func(name string) (string, bool) { return "EXISTS (SELECT 1 FROM " + name + ")", true })
+
+			return "(v.conforming = 0 OR NOT " + where + ")", true
+		}
+	}
+
+	n := len(tr.args)
+	where, ok := tr.property(f.Property,
+		func(name string, kind valueKind) (string, bool) {
+			if f.Operator == stixfilter.OperatorExists {
+				return name + " IS NOT NULL", true
+			}
+
+			if kind == valueJSON {
+				return "", false
+			}
+
+			return tr.scalar(name, kind, f)
+		},
+		func(name string) (string, bool) { return tr.list(name, f) })
+	if !ok {
+		tr.args = tr.args[:n]
+
+		return "", false
+	}
+
+	//объекты, не соответствующие схеме, проверяются только исходным условием
+	return "(v.conforming = 0 OR " + where + ")", true
+}
+
+// property формирует условие для свойства name по всем таблицам, содержащим его в виде столбца или дочерней таблицы
+func (tr *translator) property(name string, column func(name string, kind valueKind) (string, bool), list func(name string) (string, bool)) (string, bool) {
+	conditions := []string{}
+	for _, t := range tr.tables {
+		if c, ok := t.getColumn(name); ok {
+			where, ok := column("t."+quote(c.Name), c.Kind)
+			if !ok {
+				return "", false
+			}
+
+			conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM %s t WHERE t.id = v.id AND t.object_version = v.object_version AND %s)", quote(t.Name), where))
+
+			continue
+		}
+
+		if l, ok := t.getList(name); ok {
+			where, ok := list(fmt.Sprintf("%s c WHERE c.id = v.id AND c.object_version = v.object_version", quote(l.Name)))
+			if !ok {
+				return "", false
+			}
+
+			conditions = append(conditions, where)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return sqlFalse, true
+	}
+
+	return "(" + strings.Join(conditions, " OR ") + ")", true
+}
+
+// scalar условие для столбца name, содержащего простое значение
+func (tr *translator) scalar(name string, kind valueKind, f stixfilter.Filter) (string, bool) {
+	switch f.Operator {
+	case stixfilter.OperatorEqual:
+		return tr.compare(name, "=", f.Value, false)
+
+	case stixfilter.OperatorNotEqual:
+		where, ok := tr.compare(name, "!=", f.Value, false)
+		if !ok {
+			return "", false
+		}
+
+		return "(" + name + " IS NOT NULL AND " + where + ")", true
+
+	case stixfilter.OperatorLess:
+		return tr.compare(name, "<", f.Value, true)
+
+	case stixfilter.OperatorGreater:
+		return tr.compare(name, ">", f.Value, true)
+
+	case stixfilter.OperatorIn:
+		return tr.in(name, f.Value)
+
+	case stixfilter.OperatorContains:
+		sub, ok := f.Value.(string)
+		if !ok || kind != valueString {
+			return "", false
+		}
+		tr.args = append(tr.args, sub)
+
+		//время хранится в формате timestampLayout, поэтому подстрока исходного значения проверяется после выборки
+		return "(instr(" + name + ", ?) > 0 OR " + name + " GLOB '" + timestampPattern + "')", true
+	}
+
+	return "", false
+}
+
+// nested условие для вложенного свойства, значения которого хранятся в таблице propertiesTable. Пути, содержащие
+// номера элементов списков, а также условия, выполняющиеся для свойств без простых значений, не преобразуются
+func (tr *translator) nested(f stixfilter.Filter) (string, bool) {
+	for _, name := range strings.Split(f.Property, ".") {
+		if _, err := strconv.Atoi(name); err == nil {
+			return "", false
+		}
+	}
+
+	n := len(tr.args)
+	tr.args = append(tr.args, f.Property)
+	from := propertiesTable + " c WHERE c.id = v.id AND c.object_version = v.object_version AND c.path = ?"
+
+	switch f.Operator {
+	case stixfilter.OperatorEqual, stixfilter.OperatorLess, stixfilter.OperatorGreater, stixfilter.OperatorIn:
+		if where, ok := tr.list(from, f); ok {
+			return where, true
+		}
+
+	case stixfilter.OperatorContains:
+		if sub, ok := f.Value.(string); ok {
+			tr.args = append(tr.args, sub)
+
+			return "EXISTS (SELECT 1 FROM " + from + " AND (instr(c.value, ?) > 0 OR c.value GLOB '" + timestampPattern + "'))", true
+		}
+	}
+
+	tr.args = tr.args[:n]
+
+	return "", false
+}
+
+// list условие для дочерней таблицы from, содержащей элементы списка
+func (tr *translator) list(from string, f stixfilter.Filter) (string, bool) {
+	switch f.Operator {
+	case stixfilter.OperatorExists:
+		return "EXISTS (SELECT 1 FROM " + from + ")", true
+
+	case stixfilter.OperatorEqual, stixfilter.OperatorContains:
+		where, ok := tr.compare("c.value", "=", f.Value, false)
+		if !ok {
+			return "", false
+		}
+
+		return "EXISTS (SELECT 1 FROM " + from + " AND " + where + ")", true
+
+	case stixfilter.OperatorNotEqual:
+		where, ok := tr.compare("c.value", "=", f.Value, false)
+		if !ok {
+			return "", false
+		}
+
+		return "(EXISTS (SELECT 1 FROM " + from + ") AND NOT EXISTS (SELECT 1 FROM " + from + " AND " + where + "))", true
+
+	case stixfilter.OperatorLess, stixfilter.OperatorGreater:
+		where, ok := tr.compare("c.value", string(f.Operator), f.Value, true)
+		if !ok {
+			return "", false
+		}
+
+		return "EXISTS (SELECT 1 FROM " + from + " AND " + where + ")", true
+
+	case stixfilter.OperatorIn:
+		where, ok := tr.in("c.value", f.Value)
+		if !ok {
+			return "", false
+		}
+
+		return "EXISTS (SELECT 1 FROM " + from + " AND " + where + ")", true
+	}
+
+	return "", false
+}
+
+// compare условие сравнения столбца name со значением value. Время хранится в формате timestampLayout
+// и упорядочивается как время, остальные строки в исходном виде, поэтому упорядочивающее сравнение
+// значений разного вида проверяется после выборки
+func (tr *translator) compare(name, operator string, value interface{}, ordered bool) (string, bool) {
+	v, ok := getArgument(value, ordered)
+	if !ok {
+		return "", false
+	}
+	tr.args = append(tr.args, v)
+
+	where := name + " " + operator + " ?"
+	if _, isString := v.(string); !ordered || !isString {
+		return where, true
+	}
+
+	if isTime(value) {
+		return "(" + where + " OR NOT " + name + " GLOB '" + timestampPattern + "')", true
+	}
+
+	return "(" + where + " OR " + name + " GLOB '" + timestampPattern + "')", true
+}
+
+func (tr *translator) in(name string, value interface{}) (string, bool) {
+	list, ok := toList(value)
+	if !ok {
+		return "", false
+	}
+
+	if len(list) == 0 {
+		return sqlFalse, true
+	}
+
+	n := len(tr.args)
+	marks := make([]string, 0, len(list))
+	for _, item := range list {
+		v, ok := getArgument(item, false)
+		if !ok {
+			tr.args = tr.args[:n]
+
+			return "", false
+		}
+
+		tr.args = append(tr.args, v)
+		marks = append(marks, "?")
+	}
+
+	return name + " IN (" + strings.Join(marks, ", ") + ")", true
+}
+
+// timestamp условие для свойств created и modified, хранящихся в таблице версий в формате timestampLayout
+func (tr *translator) timestamp(name string, f stixfilter.Filter) (string, bool) {
+	operators := map[stixfilter.Operator]string{
+		stixfilter.OperatorEqual:    "=",
+		stixfilter.OperatorNotEqual: "!=",
+		stixfilter.OperatorLess:     "<",
+		stixfilter.OperatorGreater:  ">",
+	}
+
+	values := []interface{}{f.Value}
+	if f.Operator == stixfilter.OperatorIn {
+		list, ok := toList(f.Value)
+		if !ok {
+			return "", false
+		}
+		values = list
+	} else if _, ok := operators[f.Operator]; !ok {
+		return "", false
+	}
+
+	args := make([]interface{}, 0, len(values))
+	marks := make([]string, 0, len(values))
+	for _, v := range values {
+		ts, ok := getTimeArgument(v)
+		if !ok {
+			return "", false
+		}
+
+		args = append(args, ts)
+		marks = append(marks, "?")
+	}
+	tr.args = append(tr.args, args...)
+
+	if f.Operator == stixfilter.OperatorIn {
+		if len(marks) == 0 {
+			return sqlFalse, true
+		}
+
+		return "(v.conforming = 0 OR " + name + " IN (" + strings.Join(marks, ", ") + "))", true
+	}
+
+	return "(v.conforming = 0 OR " + name + " " + operators[f.Operator] + " ?)", true
+}
+
+// getArgument возвращает параметр запроса для значения условия, время приводится к формату timestampLayout.
+// Для упорядочивающих операторов логические значения не используются, так как не сравниваются
+func getArgument(value interface{}, ordered bool) (interface{}, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return getTimeArgument(v)
+
+	case string:
+		if isTime(v) {
+			return getTimeArgument(v)
+		}
+
+		return v, true
+
+	case bool:
+		return v, !ordered
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+
+	return nil, false
+}
+
+// getTimeArgument возвращает параметр запроса для значения условия, сравниваемого как время
+func getTimeArgument(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(timestampLayout), true
+	case string:
+		ts, ok := getTimestamp(v)
+		if !ok {
+			return "", false
+		}
+
+		return ts.(string), true
+	}
+
+	return "", false
+}
+
+func toList(v interface{}) ([]interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	if v == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return nil, false
+	}
+
+	list := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		list = append(list, rv.Index(i).Interface())
+	}
+
+	return list, true
+}
@@ -0,0 +1,200 @@
+package sqlitestore
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+)
+
+// versionsTable общая таблица версий объектов, содержит исходный JSON каждой версии. Столбец conforming
+// принимает значение 1, если все свойства объекта соответствуют столбцам и дочерним таблицам его типа
+const versionsTable = "stix_versions"
+
+// propertiesTable общая таблица простых значений вложенных свойств объектов (например, "hashes.SHA-256"
+// или "external_references.source_name"), номера элементов списков в путь свойства не включаются
+const propertiesTable = "stix_properties"
+
+// valueKind вид значения свойства
+type valueKind int
+
+const (
+	valueString valueKind = iota
+	valueBool
+	valueNumber
+	// valueJSON сложное значение (объект, список объектов), хранится в виде JSON
+	valueJSON
+)
+
+// column столбец таблицы типа
+type column struct {
+	Name    string
+	SQLType string
+	Kind    valueKind
+}
+
+// listTable дочерняя таблица для свойства, являющегося списком простых значений (в том числе ссылок "_refs")
+type listTable struct {
+	Property string
+	Name     string
+	SQLType  string
+	Kind     valueKind
+}
+
+// typeTable таблица объектов одного типа
+type typeTable struct {
+	Type    string
+	Name    string
+	Columns []column
+	Lists   []listTable
+}
+
+func (t typeTable) getColumn(name string) (column, bool) {
+	for _, v := range t.Columns {
+		if v.Name == name {
+			return v, true
+		}
+	}
+
+	return column{}, false
+}
+
+func (t typeTable) getList(property string) (listTable, bool) {
+	for _, v := range t.Lists {
+		if v.Property == property {
+			return v, true
+		}
+	}
+
+	return listTable{}, false
+}
+
+// newTypeTables формирует описание таблиц на основании определений типов объектов STIX
+func newTypeTables() map[string]typeTable {
+	tables := map[string]typeTable{}
+	for objType, rt := range stixstore.GetObjectTypes() {
+		t := typeTable{Type: objType, Name: "stix_" + strings.ReplaceAll(objType, "-", "_")}
+
+		for _, f := range stixstore.GetObjectFields(rt) {
+			if f.Name == "id" || f.Name == "type" {
+				continue
+			}
+
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Slice && isScalar(ft.Elem()) {
+				t.Lists = append(t.Lists, listTable{Property: f.Name, Name: t.Name + "__" + f.Name, SQLType: getSQLType(ft.Elem()), Kind: getValueKind(ft.Elem())})
+
+				continue
+			}
+
+			if isScalar(ft) {
+				t.Columns = append(t.Columns, column{Name: f.Name, SQLType: getSQLType(ft), Kind: getValueKind(ft)})
+
+				continue
+			}
+
+			t.Columns = append(t.Columns, column{Name: f.Name, SQLType: "TEXT", Kind: valueJSON})
+		}
+
+		sort.Slice(t.Columns, func(i, j int) bool { return t.Columns[i].Name < t.Columns[j].Name })
+		sort.Slice(t.Lists, func(i, j int) bool { return t.Lists[i].Property < t.Lists[j].Property })
+
+		tables[objType] = t
+	}
+
+	return tables
+}
+
+func isScalar(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func getValueKind(rt reflect.Type) valueKind {
+	switch rt.Kind() {
+	case reflect.String:
+		return valueString
+	case reflect.Bool:
+		return valueBool
+	}
+
+	return valueNumber
+}
+
+func getSQLType(rt reflect.Type) string {
+	switch rt.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	}
+
+	return "TEXT"
+}
+
+// getSchema возвращает инструкции создания таблиц
+func getSchema(tables map[string]typeTable) []string {
+	list := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id TEXT NOT NULL,
+	object_version TEXT NOT NULL,
+	type TEXT NOT NULL,
+	created TEXT,
+	modified TEXT,
+	revoked INTEGER NOT NULL DEFAULT 0,
+	conforming INTEGER NOT NULL DEFAULT 0,
+	data TEXT NOT NULL,
+	PRIMARY KEY (id, object_version)
+)`, versionsTable),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_type ON %s (type)", versionsTable, versionsTable),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\tid TEXT NOT NULL,\n\tobject_version TEXT NOT NULL,\n\tpath TEXT NOT NULL,\n\tvalue\n)", propertiesTable),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_object ON %s (id, object_version)", propertiesTable, propertiesTable),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_value ON %s (path, value)", propertiesTable, propertiesTable),
+	}
+
+	names := make([]string, 0, len(tables))
+	for k := range tables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t := tables[name]
+
+		columns := []string{"id TEXT NOT NULL", "object_version TEXT NOT NULL"}
+		for _, c := range t.Columns {
+			columns = append(columns, quote(c.Name)+" "+c.SQLType)
+		}
+		columns = append(columns, "PRIMARY KEY (id, object_version)")
+
+		list = append(list, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", quote(t.Name), strings.Join(columns, ",\n\t")))
+
+		for _, l := range t.Lists {
+			list = append(list, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\tid TEXT NOT NULL,\n\tobject_version TEXT NOT NULL,\n\tposition INTEGER NOT NULL,\n\tvalue %s,\n\tPRIMARY KEY (id, object_version, position)\n)",
+				quote(l.Name), l.SQLType))
+			list = append(list, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (value)", quote(l.Name+"_value"), quote(l.Name)))
+		}
+	}
+
+	return list
+}
+
+// quote возвращает идентификатор SQL в двойных кавычках
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
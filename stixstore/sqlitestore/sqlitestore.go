@@ -0,0 +1,561 @@
+// Package sqlitestore хранилище объектов STIX на основе встраиваемой СУБД SQLite (реализация на Go, без cgo).
+// Схема базы данных формируется на основании определений типов объектов STIX: общая таблица версий
+// с исходным JSON каждой версии, по одной таблице на тип объекта, дочерние таблицы для свойств, являющихся
+// списками простых значений (в том числе ссылок "_refs"), и общая таблица значений вложенных свойств.
+// Значения, являющиеся временем в формате RFC3339, хранятся в формате timestampLayout. Условия отбора
+// stixfilter преобразуются в SQL.
+//
+// Пакет использует драйвер modernc.org/sqlite и требует Go 1.21, поэтому вынесен в отдельный модуль,
+// что исключает эти зависимости из основного модуля
+package sqlitestore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+)
+
+// SQLiteStore хранилище объектов STIX в базе данных SQLite. Безопасно для использования из нескольких горутин
+type SQLiteStore struct {
+	db     *sql.DB
+	tables map[string]typeTable
+}
+
+// NewSQLiteStore открывает (при отсутствии создает) базу данных SQLite по строке подключения dsn,
+// например, "file:/var/lib/stix.db" или ":memory:", и создает необходимые таблицы
+func NewSQLiteStore(dsn string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	//одно соединение исключает конфликты блокировок между транзакциями и сохраняет базу данных ":memory:"
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStore{db: db, tables: newTypeTables()}
+	for _, v := range getSchema(s.tables) {
+		if _, err := db.Exec(v); err != nil {
+			db.Close()
+
+			return nil, fmt.Errorf("schema creation error: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Close закрывает базу данных
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// GetSchema возвращает инструкции создания таблиц базы данных
+func (s *SQLiteStore) GetSchema() string {
+	return strings.Join(getSchema(s.tables), ";\n") + ";\n"
+}
+
+// Add добавляет объекты. Новые версии отозванного объекта не добавляются
+func (s *SQLiteStore) Add(objects ...interface{}) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, obj := range objects {
+		b, err := stixstore.EncodeObject(obj)
+		if err != nil {
+			return err
+		}
+
+		if err := s.add(tx, b); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) add(tx *sql.Tx, b []byte) error {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	info, err := stixstore.GetObjectInfo(m)
+	if err != nil {
+		return err
+	}
+
+	version := getVersion(info.Modified)
+
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM "+versionsTable+" WHERE id = ? AND object_version = ?)", info.ID, version).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		var (
+			last    string
+			revoked bool
+		)
+		err := tx.QueryRow("SELECT object_version, revoked FROM "+versionsTable+" WHERE id = ? ORDER BY object_version DESC LIMIT 1", info.ID).Scan(&last, &revoked)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if err == nil && revoked && version > last {
+			return fmt.Errorf("object '%s': %w", info.ID, stixstore.ErrRevoked)
+		}
+	}
+
+	if err := s.deleteRows(tx, info.ID, version); err != nil {
+		return err
+	}
+
+	t, ok := s.tables[info.Type]
+	row, lists, conforming := getRow(t, m)
+	if !ok {
+		conforming = false
+	}
+
+	created, okCreated := getTimestamp(info.Created)
+	modified, okModified := getTimestamp(info.Modified)
+	if (info.Created != "" && !okCreated) || (info.Modified != "" && !okModified) {
+		conforming = false
+	}
+
+	_, err = tx.Exec("INSERT INTO "+versionsTable+" (id, object_version, type, created, modified, revoked, conforming, data) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		info.ID, version, info.Type, created, modified, info.Revoked, conforming, string(b))
+	if err != nil {
+		return err
+	}
+
+	for _, v := range getNestedValues(m) {
+		query := "INSERT INTO " + propertiesTable + " (id, object_version, path, value) VALUES (?, ?, ?, ?)"
+		if _, err := tx.Exec(query, info.ID, version, v.Path, v.Value); err != nil {
+			return err
+		}
+	}
+
+	//строки таблиц типа используются только для отбора, поэтому для несоответствующих схеме объектов не создаются
+	if !conforming {
+		return nil
+	}
+
+	names := []string{"id", "object_version"}
+	marks := []string{"?", "?"}
+	args := []interface{}{info.ID, version}
+	for _, c := range t.Columns {
+		names = append(names, quote(c.Name))
+		marks = append(marks, "?")
+		args = append(args, row[c.Name])
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quote(t.Name), strings.Join(names, ", "), strings.Join(marks, ", "))
+	if _, err := tx.Exec(query, args...); err != nil {
+		return err
+	}
+
+	for _, l := range t.Lists {
+		for position, value := range lists[l.Property] {
+			query := fmt.Sprintf("INSERT INTO %s (id, object_version, position, value) VALUES (?, ?, ?, ?)", quote(l.Name))
+			if _, err := tx.Exec(query, info.ID, version, position, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deleteRows удаляет строки всех таблиц, относящиеся к объекту id (при version отличном от nil только к его версии)
+func (s *SQLiteStore) deleteRows(tx *sql.Tx, id string, version interface{}) error {
+	var objType string
+	err := tx.QueryRow("SELECT type FROM "+versionsTable+" WHERE id = ? LIMIT 1", id).Scan(&objType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	names := []string{versionsTable, propertiesTable}
+	if t, ok := s.tables[objType]; ok {
+		names = append(names, quote(t.Name))
+		for _, l := range t.Lists {
+			names = append(names, quote(l.Name))
+		}
+	}
+
+	for _, name := range names {
+		query, args := "DELETE FROM "+name+" WHERE id = ?", []interface{}{id}
+		if version != nil {
+			query, args = query+" AND object_version = ?", append(args, version)
+		}
+
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get возвращает последнюю версию объекта
+func (s *SQLiteStore) Get(id string) (interface{}, error) {
+	var data string
+	err := s.db.QueryRow("SELECT data FROM "+versionsTable+" WHERE id = ? ORDER BY object_version DESC LIMIT 1", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("object '%s': %w", id, stixstore.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stixstore.DecodeObject(json.RawMessage(data))
+}
+
+// GetVersion возвращает версию объекта с заданным значением свойства modified
+func (s *SQLiteStore) GetVersion(id, modified string) (interface{}, error) {
+	var data string
+	err := s.db.QueryRow("SELECT data FROM "+versionsTable+" WHERE id = ? AND object_version = ?", id, getVersion(modified)).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("object '%s' version '%s': %w", id, modified, stixstore.ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return stixstore.DecodeObject(json.RawMessage(data))
+}
+
+// GetAllVersions возвращает все версии объекта, упорядоченные по возрастанию modified
+func (s *SQLiteStore) GetAllVersions(id string) ([]interface{}, error) {
+	rows, err := s.db.Query("SELECT data FROM "+versionsTable+" WHERE id = ? ORDER BY object_version", id)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := decodeRows(rows, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, fmt.Errorf("object '%s': %w", id, stixstore.ErrNotFound)
+	}
+
+	return list, nil
+}
+
+// Query возвращает последние версии объектов, удовлетворяющие условию filter. Условия stixfilter
+// (в том числе stixfilter.And и stixfilter.Or) преобразуются в SQL, после чего выбранные объекты
+// дополнительно проверяются условием filter, поэтому результат совпадает с результатом stixfilter.Apply
+func (s *SQLiteStore) Query(filter stixstore.Filter) ([]interface{}, error) {
+	query, args := s.Explain(filter)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeRows(rows, filter)
+}
+
+// Explain возвращает запрос SQL и его параметры, используемые при отборе объектов условием filter
+func (s *SQLiteStore) Explain(filter stixstore.Filter) (string, []interface{}) {
+	where, args := newTranslator(s.tables, filter).translate(filter)
+
+	query := fmt.Sprintf("SELECT v.data FROM %s v WHERE v.object_version = (SELECT MAX(x.object_version) FROM %s x WHERE x.id = v.id) AND %s ORDER BY v.id",
+		versionsTable, versionsTable, where)
+
+	return query, args
+}
+
+// Delete удаляет все версии объекта
+func (s *SQLiteStore) Delete(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM "+versionsTable+" WHERE id = ?)", id).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("object '%s': %w", id, stixstore.ErrNotFound)
+	}
+
+	if err := s.deleteRows(tx, id, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Revoke отзывает объект, добавляя его новую версию со свойством revoked равным true
+func (s *SQLiteStore) Revoke(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var data string
+	err = tx.QueryRow("SELECT data FROM "+versionsTable+" WHERE id = ? ORDER BY object_version DESC LIMIT 1", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("object '%s': %w", id, stixstore.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	obj, _, err := stixstore.NewRevokedVersion(json.RawMessage(data))
+	if err != nil {
+		return err
+	}
+
+	if err := s.add(tx, obj.(json.RawMessage)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// decodeRows декодирует объекты, проверяя их условием filter
+func decodeRows(rows *sql.Rows, filter stixstore.Filter) ([]interface{}, error) {
+	defer rows.Close()
+
+	list := []interface{}{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		if filter != nil {
+			m := map[string]interface{}{}
+			if err := json.Unmarshal([]byte(data), &m); err != nil {
+				return nil, err
+			}
+
+			if !filter.Match(m) {
+				continue
+			}
+		}
+
+		obj, err := stixstore.DecodeObject(json.RawMessage(data))
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, obj)
+	}
+
+	return list, rows.Err()
+}
+
+// timestampLayout формат времени фиксированной длины в UTC, строковый порядок которого совпадает с порядком времени
+const timestampLayout = "2006-01-02T15:04:05.000000000Z"
+
+// getTimestamp приводит время в формате RFC3339 к формату timestampLayout
+func getTimestamp(v string) (interface{}, bool) {
+	t, err := commonlibs.ParseTimestamp(v)
+	if err != nil {
+		return nil, false
+	}
+
+	return t.UTC().Format(timestampLayout), true
+}
+
+// getVersion возвращает ключ версии объекта, для неверсионируемых объектов пустую строку
+func getVersion(modified string) string {
+	if modified == "" {
+		return ""
+	}
+
+	if v, ok := getTimestamp(modified); ok {
+		return v.(string)
+	}
+
+	return modified
+}
+
+// getRow возвращает значения столбцов и дочерних таблиц типа t, а также признак соответствия объекта схеме
+func getRow(t typeTable, m map[string]interface{}) (map[string]interface{}, map[string][]interface{}, bool) {
+	row := map[string]interface{}{}
+	lists := map[string][]interface{}{}
+	conforming := true
+
+	for name, value := range m {
+		if name == "id" || name == "type" {
+			continue
+		}
+
+		if c, ok := t.getColumn(name); ok {
+			v, ok := getColumnValue(c.Kind, value)
+			if !ok {
+				conforming = false
+			}
+			row[name] = v
+
+			continue
+		}
+
+		if l, ok := t.getList(name); ok {
+			items, isList := value.([]interface{})
+			if value != nil && !isList {
+				conforming = false
+
+				continue
+			}
+
+			for _, item := range items {
+				v, ok := getColumnValue(l.Kind, item)
+				if !ok || v == nil {
+					conforming = false
+				}
+				lists[name] = append(lists[name], v)
+			}
+
+			continue
+		}
+
+		conforming = false
+	}
+
+	return row, lists, conforming
+}
+
+// getColumnValue возвращает значение столбца. Пустые значения (null, пустые строки, списки и объекты, а также
+// время по умолчанию commonlibs.DefaultDateTime) хранятся как NULL, так как считаются отсутствующими при отборе объектов
+func getColumnValue(kind valueKind, value interface{}) (interface{}, bool) {
+	if value == nil {
+		return nil, true
+	}
+
+	switch kind {
+	case valueString:
+		v, ok := value.(string)
+		if !ok || v == "" || v == commonlibs.DefaultDateTime {
+			return nil, ok
+		}
+
+		if ts, ok := getTimestamp(v); ok {
+			return ts, true
+		}
+
+		return v, true
+
+	case valueBool:
+		v, ok := value.(bool)
+
+		return v, ok
+
+	case valueNumber:
+		v, ok := value.(float64)
+
+		return v, ok
+	}
+
+	switch v := value.(type) {
+	case string:
+		if v == "" || v == commonlibs.DefaultDateTime {
+			return nil, true
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, true
+		}
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil, true
+		}
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+
+	return string(b), true
+}
+
+// nestedValue простое значение вложенного свойства
+type nestedValue struct {
+	Path  string
+	Value interface{}
+}
+
+// getNestedValues возвращает простые значения вложенных свойств объекта m. Пустые значения не возвращаются,
+// время приводится к формату timestampLayout
+func getNestedValues(m map[string]interface{}) []nestedValue {
+	list := []nestedValue{}
+	for name, value := range m {
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			list = addNestedValues(list, name, value, false)
+		}
+	}
+
+	return list
+}
+
+func addNestedValues(list []nestedValue, path string, value interface{}, nested bool) []nestedValue {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, item := range v {
+			list = addNestedValues(list, path+"."+name, item, true)
+		}
+
+	case []interface{}:
+		for _, item := range v {
+			list = addNestedValues(list, path, item, nested)
+		}
+
+	case string:
+		if !nested || v == "" || v == commonlibs.DefaultDateTime {
+			break
+		}
+
+		if ts, ok := getTimestamp(v); ok {
+			list = append(list, nestedValue{Path: path, Value: ts})
+
+			break
+		}
+
+		list = append(list, nestedValue{Path: path, Value: v})
+
+	case bool, float64:
+		if nested {
+			list = append(list, nestedValue{Path: path, Value: v})
+		}
+	}
+
+	return list
+}
+
+// isTime проверяет, сравнивается ли значение условия как время
+func isTime(v interface{}) bool {
+	switch value := v.(type) {
+	case time.Time:
+		return true
+	case string:
+		_, err := commonlibs.ParseTimestamp(value)
+
+		return err == nil
+	}
+
+	return false
+}
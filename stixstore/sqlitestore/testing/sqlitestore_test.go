@@ -0,0 +1,216 @@
+package testing
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/stixfilter"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+	"github.com/av-belyakov/methodstixobjects/stixstore/sqlitestore"
+	"github.com/av-belyakov/methodstixobjects/stixstore/storetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteStore(t *testing.T, path string) *sqlitestore.SQLiteStore {
+	s, err := sqlitestore.NewSQLiteStore("file:" + path)
+	require.NoError(t, err)
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func TestSQLiteStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) stixstore.Store {
+		return newSQLiteStore(t, filepath.Join(t.TempDir(), "stix.db"))
+	})
+}
+
+func TestSQLiteStoreFidelity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stix.db")
+	raw := json.RawMessage(`{"type": "x-custom-object", "spec_version": "2.1", "id": "x-custom-object--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f", "created": "2023-01-05T00:00:00.123456Z", "modified": "2023-01-05T03:00:00.123456+03:00", "name": "APT hash", "x_custom": {"a": [1, 2.5, null]}}`)
+
+	m := methodstixobjects.NewMalwareDomainObjectsSTIX()
+	m.SetValueID(storetest.MalwareID)
+	m.SetValueName("Poison Ivy")
+	m.SetValueCreated("2023-01-01T10:00:00+00:00")
+	m.SetValueModified("2023-01-01T10:00:00+00:00")
+	m.SetValueIsFamily(true)
+
+	s := newSQLiteStore(t, path)
+	assert.NoError(t, s.Add(raw, *m))
+	assert.NoError(t, s.Close())
+
+	//после повторного открытия объекты не изменяются, JSON объектов неизвестных типов сохраняется без изменений
+	s = newSQLiteStore(t, path)
+
+	obj, err := s.Get("x-custom-object--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f")
+	assert.NoError(t, err)
+	b, err := stixstore.EncodeObject(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, string(b), string(raw))
+
+	//версия находится по времени изменения в любом часовом поясе
+	_, err = s.GetVersion("x-custom-object--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f", "2023-01-05T00:00:00.123456Z")
+	assert.NoError(t, err)
+
+	obj, err = s.Get(storetest.MalwareID)
+	assert.NoError(t, err)
+	malware, ok := obj.(domainobjectsstix.MalwareDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, malware.GetName(), "Poison Ivy")
+	assert.True(t, malware.GetIsFamily())
+
+	schema := s.GetSchema()
+	assert.Contains(t, schema, `CREATE TABLE IF NOT EXISTS "stix_malware" (`)
+	assert.Contains(t, schema, `CREATE TABLE IF NOT EXISTS "stix_malware__malware_types" (`)
+	assert.Contains(t, schema, `CREATE TABLE IF NOT EXISTS "stix_relationship" (`)
+}
+
+func TestSQLiteStoreQuery(t *testing.T) {
+	objects := []interface{}{
+		json.RawMessage(`{"type": "indicator", "id": "indicator--1", "created": "2023-01-01T00:00:00Z", "modified": "2023-01-01T00:00:00Z", "name": "APT hash", "pattern_type": "stix", "valid_until": "2099-01-01T00:00:00Z", "labels": ["apt", "malicious-activity"], "confidence": 80,
+			"external_references": [{"source_name": "capec", "external_id": "CAPEC-163"}]}`),
+		json.RawMessage(`{"type": "indicator", "id": "indicator--2", "created": "2023-06-01T00:00:00+03:00", "modified": "2023-06-01T00:00:00+03:00", "name": "Old rule", "pattern_type": "snort", "valid_until": "2000-01-01T00:00:00+03:00", "labels": ["benign"], "confidence": 20}`),
+		//объект со свойством, отсутствующим в схеме
+		json.RawMessage(`{"type": "indicator", "id": "indicator--3", "created": "2023-01-01T00:00:00Z", "modified": "2023-01-01T00:00:00Z", "name": "Custom", "confidence": 50, "x_labels": ["apt"]}`),
+		json.RawMessage(`{"type": "file", "id": "file--4", "name": "a.exe", "hashes": {"SHA-256": "4bac27393bdd9777ce02453256c5577cd02275510b2227f473d03f533924f877"}, "ctime": "2023-01-01T03:00:00+03:00"}`),
+		json.RawMessage(`{"type": "x-custom", "id": "x-custom--5", "name": "APT hash", "labels": "apt"}`),
+		//время по умолчанию считается отсутствующим значением
+		json.RawMessage(`{"type": "indicator", "id": "indicator--6", "created": "2023-03-01T00:00:00Z", "modified": "2023-03-01T00:00:00Z", "name": "Default", "valid_until": "1970-01-01T00:00:00+00:00",
+			"external_references": [{"source_name": "capec", "external_id": "CAPEC-1", "x_time": "1970-01-01T00:00:00+00:00"}]}`),
+	}
+
+	s := newSQLiteStore(t, filepath.Join(t.TempDir(), "stix.db"))
+	assert.NoError(t, s.Add(objects...))
+
+	for _, query := range []string{
+		`type = 'indicator'`,
+		`name = 'APT hash'`,
+		`name != 'APT hash'`,
+		`name contains 'rule'`,
+		`labels contains 'apt'`,
+		`labels = 'benign'`,
+		`labels != 'apt'`,
+		`labels exists`,
+		`labels exists false`,
+		`hashes exists`,
+		`confidence > 30`,
+		`confidence < 30 OR name in ('a.exe', 'Custom')`,
+		`type = 'indicator' AND pattern_type in ('stix', 'snort')`,
+		`created > '2023-05-31T22:00:00Z'`,
+		`modified = '2023-05-31T21:00:00Z'`,
+		`valid_until < '2024-01-01T00:00:00Z'`,
+		`external_references.source_name = 'capec'`,
+		`external_references.source_name != 'capec'`,
+		`external_references.external_id in ('CAPEC-163', 'CAPEC-1')`,
+		`external_references.external_id contains '163'`,
+		`external_references.0.source_name = 'capec'`,
+		`hashes.SHA-256 = '4bac27393bdd9777ce02453256c5577cd02275510b2227f473d03f533924f877'`,
+		`hashes.SHA-256 > '1'`,
+		`valid_until > '2024-01-01T00:00:00Z'`,
+		`valid_until = '2000-01-01T00:00:00+03:00'`,
+		`valid_until = '1999-12-31T21:00:00Z'`,
+		`valid_until contains '+03:00'`,
+		`valid_until < 'A'`,
+		`ctime = '2023-01-01T00:00:00Z'`,
+		`ctime < '2023-01-01T00:00:00.5Z'`,
+		`type = 'indicator' AND (labels contains 'apt' OR confidence > 30)`,
+		`id in ('file--4', 'x-custom--5')`,
+		`valid_until exists`,
+		`valid_until exists false`,
+		`external_references.x_time exists`,
+	} {
+		expr, err := stixfilter.Parse(query)
+		assert.NoError(t, err, query)
+
+		expected, err := stixfilter.Apply(objects, expr)
+		assert.NoError(t, err, query)
+
+		//хранилище упорядочивает объекты по идентификатору
+		ids := getIDs(t, expected)
+		sort.Strings(ids)
+
+		list, err := s.Query(expr)
+		assert.NoError(t, err, query)
+		assert.Equal(t, getIDs(t, list), ids, query)
+	}
+
+	list, err := s.Query(stixfilter.Filter{Property: "created", Operator: stixfilter.OperatorLess, Value: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)})
+	assert.NoError(t, err)
+	assert.Equal(t, getIDs(t, list), []string{"indicator--1", "indicator--3"})
+
+	list, err = s.Query(stixfilter.Filter{Property: "valid_until", Operator: stixfilter.OperatorGreater, Value: time.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, getIDs(t, list), []string{"indicator--1"})
+
+	list, err = s.Query(stixstore.FilterFunc(func(obj map[string]interface{}) bool { return obj["name"] == "a.exe" }))
+	assert.NoError(t, err)
+	assert.Equal(t, getIDs(t, list), []string{"file--4"})
+}
+
+func TestSQLiteStoreExplain(t *testing.T) {
+	s := newSQLiteStore(t, filepath.Join(t.TempDir(), "stix.db"))
+
+	//условия по свойствам типа используют таблицы этого типа
+	query, args := s.Explain(stixfilter.And{
+		stixfilter.Filter{Property: "type", Operator: stixfilter.OperatorEqual, Value: "indicator"},
+		stixfilter.Filter{Property: "labels", Operator: stixfilter.OperatorContains, Value: "apt"},
+		stixfilter.Filter{Property: "confidence", Operator: stixfilter.OperatorGreater, Value: 50},
+	})
+	assert.Contains(t, query, `v.type = ?`)
+	assert.Contains(t, query, `"stix_indicator__labels" c`)
+	assert.Contains(t, query, `"stix_indicator" t`)
+	assert.NotContains(t, query, `"stix_malware`)
+	assert.Equal(t, args, []interface{}{"indicator", "apt", float64(50)})
+
+	//время создания и изменения сравнивается в UTC
+	query, args = s.Explain(stixfilter.Filter{Property: "modified", Operator: stixfilter.OperatorGreater, Value: "2023-01-01T03:00:00+03:00"})
+	assert.Contains(t, query, `v.modified > ?`)
+	assert.Equal(t, args, []interface{}{"2023-01-01T00:00:00.000000000Z"})
+
+	//без ограничения типа используются все таблицы, содержащие свойство
+	query, _ = s.Explain(stixfilter.Filter{Property: "name", Operator: stixfilter.OperatorEqual, Value: "Poison Ivy"})
+	assert.Contains(t, query, `"stix_malware" t`)
+	assert.Contains(t, query, `"stix_tool" t`)
+
+	//время в любых свойствах сравнивается в UTC
+	query, args = s.Explain(stixfilter.And{
+		stixfilter.Filter{Property: "type", Operator: stixfilter.OperatorEqual, Value: "indicator"},
+		stixfilter.Filter{Property: "valid_until", Operator: stixfilter.OperatorGreater, Value: time.Date(2024, 1, 1, 3, 0, 0, 0, time.FixedZone("MSK", 3*60*60))},
+	})
+	assert.Contains(t, query, `t."valid_until" > ?`)
+	assert.Equal(t, args, []interface{}{"indicator", "2024-01-01T00:00:00.000000000Z"})
+
+	//вложенные свойства отбираются по таблице значений вложенных свойств
+	query, args = s.Explain(stixfilter.Or{
+		stixfilter.Filter{Property: "hashes.SHA-256", Operator: stixfilter.OperatorEqual, Value: "4bac27393bdd9777ce02453256c5577cd02275510b2227f473d03f533924f877"},
+		stixfilter.Filter{Property: "external_references.source_name", Operator: stixfilter.OperatorEqual, Value: "capec"},
+	})
+	assert.Contains(t, query, `stix_properties c WHERE c.id = v.id AND c.object_version = v.object_version AND c.path = ?`)
+	assert.False(t, strings.HasSuffix(query, "AND 1 ORDER BY v.id"))
+	assert.Equal(t, args, []interface{}{"hashes.SHA-256", "4bac27393bdd9777ce02453256c5577cd02275510b2227f473d03f533924f877", "external_references.source_name", "capec"})
+
+	//непреобразуемые условия проверяются после выборки
+	query, args = s.Explain(stixfilter.Or{
+		stixfilter.Filter{Property: "name", Operator: stixfilter.OperatorEqual, Value: "Poison Ivy"},
+		stixfilter.Filter{Property: "external_references.0.source_name", Operator: stixfilter.OperatorEqual, Value: "capec"},
+	})
+	assert.True(t, strings.HasSuffix(query, "AND 1 ORDER BY v.id"))
+	assert.Len(t, args, 0)
+}
+
+func getIDs(t *testing.T, list []interface{}) []string {
+	ids := []string{}
+	for _, v := range list {
+		ids = append(ids, storetest.GetProperty(t, v, "id").(string))
+	}
+
+	return ids
+}
@@ -0,0 +1,126 @@
+package stixstore
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+)
+
+var (
+	// ErrNotFound объект (версия объекта) отсутствует в хранилище
+	ErrNotFound = errors.New("the object was not found")
+	// ErrRevoked объект отозван и не может быть изменен
+	ErrRevoked = errors.New("the object has been revoked")
+	// ErrNotVersioned объект не имеет версий (свойство modified отсутствует)
+	ErrNotVersioned = errors.New("the object is not versioned")
+)
+
+// Filter условие отбора объектов. Объект передается в виде результата декодирования JSON в map[string]interface{}
+type Filter interface {
+	Match(obj map[string]interface{}) bool
+}
+
+// FilterFunc функция, реализующая интерфейс Filter
+type FilterFunc func(obj map[string]interface{}) bool
+
+// Match вызывает f(obj)
+func (f FilterFunc) Match(obj map[string]interface{}) bool {
+	return f(obj)
+}
+
+// DataSource источник объектов STIX
+type DataSource interface {
+	// Get возвращает последнюю (по свойству modified) версию объекта
+	Get(id string) (interface{}, error)
+	// GetVersion возвращает версию объекта с заданным значением свойства modified
+	GetVersion(id, modified string) (interface{}, error)
+	// GetAllVersions возвращает все версии объекта, упорядоченные по возрастанию modified
+	GetAllVersions(id string) ([]interface{}, error)
+	// Query возвращает последние версии объектов, удовлетворяющие условию filter (при filter равном nil все объекты),
+	// объекты упорядочены по идентификатору
+	Query(filter Filter) ([]interface{}, error)
+}
+
+// DataSink приемник объектов STIX
+type DataSink interface {
+	// Add добавляет объекты, добавление уже существующей версии объекта заменяет её
+	Add(objects ...interface{}) error
+	// Delete удаляет все версии объекта
+	Delete(id string) error
+	// Revoke отзывает объект, добавляя его новую версию со свойством revoked равным true
+	Revoke(id string) error
+}
+
+// Store хранилище объектов STIX
+type Store interface {
+	DataSource
+	DataSink
+}
+
+// ObjectInfo сведения об объекте, необходимые для хранения его версий
+type ObjectInfo struct {
+	ID       string
+	Type     string
+	Created  string
+	Modified string
+	Revoked  bool
+}
+
+// GetObjectInfo возвращает сведения об объекте, проверяя наличие обязательных свойств и
+// корректность времени создания и изменения
+func GetObjectInfo(m map[string]interface{}) (ObjectInfo, error) {
+	info := ObjectInfo{}
+	info.ID, _ = m["id"].(string)
+	info.Type, _ = m["type"].(string)
+	info.Created, _ = m["created"].(string)
+	info.Modified, _ = m["modified"].(string)
+	info.Revoked, _ = m["revoked"].(bool)
+
+	if info.ID == "" || info.Type == "" {
+		return info, fmt.Errorf("the object must contain the properties 'id' and 'type'")
+	}
+
+	if info.Modified != "" && info.Created != "" && commonlibs.CompareTimestamps(info.Modified, info.Created) < 0 {
+		return info, fmt.Errorf("object '%s': the property 'modified' must not be earlier than 'created'", info.ID)
+	}
+
+	return info, nil
+}
+
+// NewRevokedVersion возвращает новую версию объекта со свойством revoked равным true
+func NewRevokedVersion(obj interface{}) (interface{}, map[string]interface{}, error) {
+	m, err := commonlibs.ToMap(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := GetObjectInfo(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if info.Modified == "" {
+		return nil, nil, fmt.Errorf("object '%s': %w", info.ID, ErrNotVersioned)
+	}
+
+	if info.Revoked {
+		return nil, nil, fmt.Errorf("object '%s': %w", info.ID, ErrRevoked)
+	}
+
+	modified := time.Now().UTC()
+	if last, err := commonlibs.ParseTimestamp(info.Modified); err == nil && !modified.After(last) {
+		modified = last.Add(time.Millisecond)
+	}
+
+	m["revoked"] = true
+	m["modified"] = modified.Format("2006-01-02T15:04:05.000Z07:00")
+
+	revoked, err := commonlibs.FromMap(m, obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return revoked, m, nil
+}
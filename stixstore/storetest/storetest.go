@@ -0,0 +1,207 @@
+// Package storetest содержит общий набор тестов, которому должна удовлетворять любая реализация stixstore.Store
+package storetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	MalwareID   = "malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b"
+	IndicatorID = "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	IdentityID  = "identity--f431f809-377b-45e0-aa1c-6a4751cae5ff"
+	DomainID    = "domain-name--3c10e93f-798e-5a26-a0c1-08156efab7f5"
+
+	FirstVersion  = "2023-01-01T10:00:00.000Z"
+	SecondVersion = "2023-02-01T10:00:00.000Z"
+	ThirdVersion  = "2023-03-01T10:00:00.000Z"
+)
+
+// NewMalware возвращает объект "malware" в виде JSON с заданными версией и наименованием
+func NewMalware(modified, name string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"type": "malware", "spec_version": "2.1", "id": "%s", "created": "%s", "modified": "%s", "name": "%s", "is_family": true, "labels": ["apt", "rat"]}`,
+		MalwareID, FirstVersion, modified, name))
+}
+
+// GetObjects возвращает набор объектов, используемый тестами
+func GetObjects() []interface{} {
+	return []interface{}{
+		NewMalware(FirstVersion, "Poison Ivy"),
+		NewMalware(SecondVersion, "Poison Ivy v2"),
+		json.RawMessage(`{"type": "indicator", "spec_version": "2.1", "id": "` + IndicatorID + `", "created": "2023-01-05T00:00:00Z", "modified": "2023-01-05T00:00:00Z", "name": "hash", "pattern": "[file:hashes.MD5 = '1717b7fff97d37a1e1a0029d83492de1']", "pattern_type": "stix", "valid_from": "2023-01-05T00:00:00Z"}`),
+		json.RawMessage(`{"type": "identity", "spec_version": "2.1", "id": "` + IdentityID + `", "created": "2023-01-05T00:00:00Z", "modified": "2023-01-05T00:00:00Z", "name": "ACME", "identity_class": "organization"}`),
+		json.RawMessage(`{"type": "domain-name", "spec_version": "2.1", "id": "` + DomainID + `", "value": "example.com"}`),
+	}
+}
+
+// GetProperty возвращает значение свойства объекта
+func GetProperty(t *testing.T, obj interface{}, name string) interface{} {
+	m, err := commonlibs.ToMap(obj)
+	require.NoError(t, err)
+
+	return m[name]
+}
+
+// Run выполняет набор тестов для реализации хранилища. Функция newStore должна возвращать новое пустое хранилище
+func Run(t *testing.T, newStore func(t *testing.T) stixstore.Store) {
+	t.Run("Add and Get", func(t *testing.T) {
+		s := newStore(t)
+		require.NoError(t, s.Add(GetObjects()...))
+
+		obj, err := s.Get(MalwareID)
+		require.NoError(t, err)
+		assert.Equal(t, GetProperty(t, obj, "name"), "Poison Ivy v2")
+		assert.Equal(t, GetProperty(t, obj, "is_family"), true)
+		assert.Equal(t, GetProperty(t, obj, "labels"), []interface{}{"apt", "rat"})
+
+		obj, err = s.Get(DomainID)
+		require.NoError(t, err)
+		assert.Equal(t, GetProperty(t, obj, "value"), "example.com")
+
+		_, err = s.Get("malware--00000000-0000-0000-0000-000000000000")
+		assert.True(t, errors.Is(err, stixstore.ErrNotFound))
+
+		assert.Error(t, s.Add(json.RawMessage(`{"name": "without id"}`)))
+		assert.Error(t, s.Add(json.RawMessage(`{"type": "malware", "id": "`+MalwareID+`", "created": "`+SecondVersion+`", "modified": "`+FirstVersion+`"}`)))
+	})
+
+	t.Run("Versions", func(t *testing.T) {
+		s := newStore(t)
+		require.NoError(t, s.Add(NewMalware(SecondVersion, "Poison Ivy v2"), NewMalware(FirstVersion, "Poison Ivy")))
+
+		obj, err := s.GetVersion(MalwareID, FirstVersion)
+		require.NoError(t, err)
+		assert.Equal(t, GetProperty(t, obj, "name"), "Poison Ivy")
+
+		//версия может быть задана в другом представлении того же момента времени
+		obj, err = s.GetVersion(MalwareID, "2023-01-01T13:00:00+03:00")
+		require.NoError(t, err)
+		assert.Equal(t, GetProperty(t, obj, "name"), "Poison Ivy")
+
+		_, err = s.GetVersion(MalwareID, ThirdVersion)
+		assert.True(t, errors.Is(err, stixstore.ErrNotFound))
+
+		list, err := s.GetAllVersions(MalwareID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, GetProperty(t, list[0], "name"), "Poison Ivy")
+		assert.Equal(t, GetProperty(t, list[1], "name"), "Poison Ivy v2")
+
+		//повторное добавление версии заменяет её
+		require.NoError(t, s.Add(NewMalware(SecondVersion, "Poison Ivy v2.1")))
+		list, err = s.GetAllVersions(MalwareID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, GetProperty(t, list[1], "name"), "Poison Ivy v2.1")
+
+		_, err = s.GetAllVersions(IdentityID)
+		assert.True(t, errors.Is(err, stixstore.ErrNotFound))
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		s := newStore(t)
+		require.NoError(t, s.Add(GetObjects()...))
+
+		list, err := s.Query(nil)
+		require.NoError(t, err)
+		require.Len(t, list, 4)
+		assert.Equal(t, GetProperty(t, list[0], "id"), DomainID)
+		assert.Equal(t, GetProperty(t, list[3], "id"), MalwareID)
+		assert.Equal(t, GetProperty(t, list[3], "name"), "Poison Ivy v2")
+
+		list, err = s.Query(stixstore.FilterFunc(func(obj map[string]interface{}) bool {
+			return obj["type"] == "malware" || obj["type"] == "identity"
+		}))
+		require.NoError(t, err)
+		assert.Len(t, list, 2)
+
+		//отбор выполняется только по последним версиям
+		list, err = s.Query(stixstore.FilterFunc(func(obj map[string]interface{}) bool {
+			return obj["name"] == "Poison Ivy"
+		}))
+		require.NoError(t, err)
+		assert.Len(t, list, 0)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		s := newStore(t)
+		require.NoError(t, s.Add(GetObjects()...))
+
+		require.NoError(t, s.Delete(MalwareID))
+		_, err := s.Get(MalwareID)
+		assert.True(t, errors.Is(err, stixstore.ErrNotFound))
+		_, err = s.GetAllVersions(MalwareID)
+		assert.True(t, errors.Is(err, stixstore.ErrNotFound))
+
+		require.NoError(t, s.Delete(DomainID))
+		assert.True(t, errors.Is(s.Delete(MalwareID), stixstore.ErrNotFound))
+
+		list, err := s.Query(nil)
+		require.NoError(t, err)
+		assert.Len(t, list, 2)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		s := newStore(t)
+		require.NoError(t, s.Add(GetObjects()...))
+
+		require.NoError(t, s.Revoke(MalwareID))
+		obj, err := s.Get(MalwareID)
+		require.NoError(t, err)
+		assert.Equal(t, GetProperty(t, obj, "revoked"), true)
+		assert.Equal(t, GetProperty(t, obj, "name"), "Poison Ivy v2")
+
+		list, err := s.GetAllVersions(MalwareID)
+		require.NoError(t, err)
+		assert.Len(t, list, 3)
+
+		//отозванный объект не может быть изменен
+		assert.True(t, errors.Is(s.Revoke(MalwareID), stixstore.ErrRevoked))
+		assert.True(t, errors.Is(s.Add(NewMalware("2099-01-01T00:00:00.000Z", "Poison Ivy v3")), stixstore.ErrRevoked))
+
+		assert.True(t, errors.Is(s.Revoke(DomainID), stixstore.ErrNotVersioned))
+		assert.True(t, errors.Is(s.Revoke("malware--00000000-0000-0000-0000-000000000000"), stixstore.ErrNotFound))
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		s := newStore(t)
+		require.NoError(t, s.Add(GetObjects()...))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(2)
+
+			go func(i int) {
+				defer wg.Done()
+
+				assert.NoError(t, s.Add(NewMalware(fmt.Sprintf("2023-04-%02dT00:00:00.000Z", i+1), fmt.Sprintf("version %d", i))))
+			}(i)
+
+			go func() {
+				defer wg.Done()
+
+				_, err := s.Get(MalwareID)
+				assert.NoError(t, err)
+				_, err = s.Query(nil)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		list, err := s.GetAllVersions(MalwareID)
+		require.NoError(t, err)
+		assert.Len(t, list, 10)
+
+		obj, err := s.Get(MalwareID)
+		require.NoError(t, err)
+		assert.Equal(t, GetProperty(t, obj, "name"), "version 7")
+	})
+}
@@ -0,0 +1,360 @@
+package taxii
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+)
+
+// DefaultPollInterval интервал запроса состояния обработки добавленных объектов по умолчанию
+const DefaultPollInterval = time.Second
+
+// ClientOptions параметры клиента
+// HTTPClient - клиент HTTP, по умолчанию http.DefaultClient
+// Username, Password - учетные данные для аутентификации Basic, если заданы
+// PollInterval - интервал запроса состояния обработки добавленных объектов, по умолчанию DefaultPollInterval
+type ClientOptions struct {
+	HTTPClient   *http.Client
+	Username     string
+	Password     string
+	PollInterval time.Duration
+}
+
+// Client клиент TAXII 2.1
+type Client struct {
+	opts ClientOptions
+}
+
+// ObjectsQuery параметры запроса объектов коллекции
+// AddedAfter - время, после которого объекты были добавлены в коллекцию (например, DateAddedLast предыдущего запроса)
+// IDs, Types, Versions, SpecVersions - значения параметров match[id], match[type], match[version] и match[spec_version]
+// Limit - количество объектов на странице, если не задано, определяется сервером
+type ObjectsQuery struct {
+	AddedAfter   string
+	IDs          []string
+	Types        []string
+	Versions     []string
+	SpecVersions []string
+	Limit        int
+}
+
+// ObjectsResult результат запроса объектов коллекции
+// Objects - объекты, декодированные в типы пакета stixstore.DecodeObject (объекты неизвестных типов в виде json.RawMessage)
+// DateAddedLast - время добавления последнего полученного объекта, используется в качестве ObjectsQuery.AddedAfter
+// следующего запроса для получения только новых объектов, если объекты не получены, равно AddedAfter запроса
+type ObjectsResult struct {
+	Objects       []interface{}
+	DateAddedLast string
+}
+
+// NewClient создает клиент TAXII 2.1
+func NewClient(opts ClientOptions) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+
+	return &Client{opts: opts}
+}
+
+// GetDiscovery возвращает ресурс "discovery" сервера, относительные адреса корней API преобразуются в абсолютные
+func (c *Client) GetDiscovery(ctx context.Context, discoveryURL string) (Discovery, error) {
+	discovery := Discovery{}
+	if _, err := c.do(ctx, http.MethodGet, discoveryURL, nil, &discovery); err != nil {
+		return discovery, err
+	}
+
+	base, err := url.Parse(discoveryURL)
+	if err != nil {
+		return discovery, err
+	}
+
+	resolve := func(v string) string {
+		u, err := base.Parse(v)
+		if err != nil {
+			return v
+		}
+
+		return u.String()
+	}
+
+	for i, v := range discovery.APIRoots {
+		discovery.APIRoots[i] = resolve(v)
+	}
+
+	if discovery.Default != "" {
+		discovery.Default = resolve(discovery.Default)
+	}
+
+	return discovery, nil
+}
+
+// GetAPIRoot возвращает описание корня API
+func (c *Client) GetAPIRoot(ctx context.Context, apiRootURL string) (APIRoot, error) {
+	root := APIRoot{}
+	_, err := c.do(ctx, http.MethodGet, getURL(apiRootURL), nil, &root)
+
+	return root, err
+}
+
+// GetCollections возвращает коллекции корня API
+func (c *Client) GetCollections(ctx context.Context, apiRootURL string) ([]Collection, error) {
+	collections := Collections{}
+	if _, err := c.do(ctx, http.MethodGet, getURL(apiRootURL, "collections"), nil, &collections); err != nil {
+		return nil, err
+	}
+
+	if collections.Collections == nil {
+		return []Collection{}, nil
+	}
+
+	return collections.Collections, nil
+}
+
+// GetCollection возвращает описание коллекции
+func (c *Client) GetCollection(ctx context.Context, apiRootURL, collectionID string) (Collection, error) {
+	collection := Collection{}
+	_, err := c.do(ctx, http.MethodGet, getURL(apiRootURL, "collections", collectionID), nil, &collection)
+
+	return collection, err
+}
+
+// GetObjects возвращает все объекты коллекции, удовлетворяющие запросу q, последовательно запрашивая страницы
+// объектов. Для перехода к следующей странице используется параметр next, а если сервер его не поддерживает,
+// параметр added_after со значением заголовка X-TAXII-Date-Added-Last (в этом случае объекты, имеющие то же время
+// добавления, что и последний объект страницы, но не вошедшие в неё, сервером не возвращаются)
+func (c *Client) GetObjects(ctx context.Context, apiRootURL, collectionID string, q ObjectsQuery) (ObjectsResult, error) {
+	result := ObjectsResult{Objects: []interface{}{}, DateAddedLast: q.AddedAfter}
+	next := ""
+
+	for {
+		values := q.getValues()
+		if next != "" {
+			values.Set("next", next)
+		} else if result.DateAddedLast != "" {
+			values.Set("added_after", result.DateAddedLast)
+		}
+
+		envelope := Envelope{}
+		header, err := c.do(ctx, http.MethodGet, getURL(apiRootURL, "collections", collectionID, "objects")+"?"+values.Encode(), nil, &envelope)
+		if err != nil {
+			return result, err
+		}
+
+		for _, raw := range envelope.Objects {
+			obj, err := stixstore.DecodeObject(raw)
+			if err != nil {
+				return result, err
+			}
+
+			result.Objects = append(result.Objects, obj)
+		}
+
+		last := header.Get(HeaderDateAddedLast)
+		if !envelope.More || len(envelope.Objects) == 0 {
+			if last != "" {
+				result.DateAddedLast = last
+			}
+
+			return result, nil
+		}
+
+		switch {
+		case envelope.Next != "":
+			if envelope.Next == next {
+				return result, fmt.Errorf("the server returned the same value of the parameter 'next' '%s'", next)
+			}
+			next = envelope.Next
+
+		case last != "" && last != result.DateAddedLast:
+			next = ""
+
+		default:
+			return result, fmt.Errorf("the server does not support pagination: the response contains neither 'next' nor a new value of the header '%s'", HeaderDateAddedLast)
+		}
+
+		if last != "" {
+			result.DateAddedLast = last
+		}
+	}
+}
+
+// GetManifest возвращает сведения о версиях объектов коллекции, удовлетворяющих запросу q (первую страницу)
+func (c *Client) GetManifest(ctx context.Context, apiRootURL, collectionID string, q ObjectsQuery) (Manifest, error) {
+	manifest := Manifest{}
+	_, err := c.do(ctx, http.MethodGet, getURL(apiRootURL, "collections", collectionID, "manifest")+"?"+q.getValues().Encode(), nil, &manifest)
+
+	return manifest, err
+}
+
+// GetVersions возвращает версии объекта коллекции (первую страницу)
+func (c *Client) GetVersions(ctx context.Context, apiRootURL, collectionID, objectID string) ([]string, error) {
+	versions := Versions{}
+	if _, err := c.do(ctx, http.MethodGet, getURL(apiRootURL, "collections", collectionID, "objects", objectID, "versions"), nil, &versions); err != nil {
+		return nil, err
+	}
+
+	if versions.Versions == nil {
+		return []string{}, nil
+	}
+
+	return versions.Versions, nil
+}
+
+// AddObjects добавляет объекты (типы объектов STIX или json.RawMessage) в коллекцию и ожидает завершения обработки
+// запроса сервером, периодически запрашивая его состояние. Возвращается итоговое состояние, сведения об объектах,
+// которые не удалось добавить, содержатся в Status.Failures
+func (c *Client) AddObjects(ctx context.Context, apiRootURL, collectionID string, objects []interface{}) (Status, error) {
+	envelope := Envelope{Objects: make([]json.RawMessage, 0, len(objects))}
+	for _, obj := range objects {
+		b, err := stixstore.EncodeObject(obj)
+		if err != nil {
+			return Status{}, err
+		}
+
+		envelope.Objects = append(envelope.Objects, b)
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{}
+	if _, err := c.do(ctx, http.MethodPost, getURL(apiRootURL, "collections", collectionID, "objects"), body, &status); err != nil {
+		return status, err
+	}
+
+	return c.WaitStatus(ctx, apiRootURL, status)
+}
+
+// GetStatus возвращает состояние обработки запроса на добавление объектов
+func (c *Client) GetStatus(ctx context.Context, apiRootURL, statusID string) (Status, error) {
+	status := Status{}
+	_, err := c.do(ctx, http.MethodGet, getURL(apiRootURL, "status", statusID), nil, &status)
+
+	return status, err
+}
+
+// WaitStatus запрашивает состояние обработки запроса до его завершения или отмены ctx
+func (c *Client) WaitStatus(ctx context.Context, apiRootURL string, status Status) (Status, error) {
+	ticker := time.NewTicker(c.opts.PollInterval)
+	defer ticker.Stop()
+
+	for status.Status == StatusPending {
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := c.GetStatus(ctx, apiRootURL, status.ID)
+		if err != nil {
+			return status, err
+		}
+		status = current
+	}
+
+	return status, nil
+}
+
+func (c *Client) do(ctx context.Context, method, u string, body []byte, result interface{}) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", MediaType)
+	if body != nil {
+		req.Header.Set("Content-Type", MediaType)
+	}
+
+	if c.opts.Username != "" || c.opts.Password != "" {
+		req.SetBasicAuth(c.opts.Username, c.opts.Password)
+	}
+
+	res, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		e := Error{}
+		if err := json.Unmarshal(b, &e); err != nil || e.Title == "" {
+			e = Error{Title: http.StatusText(res.StatusCode), Description: strings.TrimSpace(string(b))}
+		}
+
+		if e.HTTPStatus == "" {
+			e.HTTPStatus = strconv.Itoa(res.StatusCode)
+		}
+
+		return res.Header, fmt.Errorf("%s %s: %w", method, u, e)
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		return res.Header, nil
+	}
+
+	if err := json.Unmarshal(b, result); err != nil {
+		return res.Header, fmt.Errorf("%s %s: incorrect response: %w", method, u, err)
+	}
+
+	return res.Header, nil
+}
+
+func (q ObjectsQuery) getValues() url.Values {
+	values := url.Values{}
+	for name, list := range map[string][]string{
+		"match[id]":           q.IDs,
+		"match[type]":         q.Types,
+		"match[version]":      q.Versions,
+		"match[spec_version]": q.SpecVersions,
+	} {
+		if len(list) > 0 {
+			values.Set(name, strings.Join(list, ","))
+		}
+	}
+
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	if q.AddedAfter != "" {
+		values.Set("added_after", q.AddedAfter)
+	}
+
+	return values
+}
+
+// getURL возвращает адрес ресурса, состоящий из адреса корня API и элементов пути, адрес завершается символом "/"
+func getURL(apiRootURL string, segments ...string) string {
+	u := strings.TrimRight(apiRootURL, "/") + "/"
+	for _, v := range segments {
+		u += url.PathEscape(v) + "/"
+	}
+
+	return u
+}
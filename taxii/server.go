@@ -0,0 +1,921 @@
+package taxii
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/stixfilter"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+)
+
+const (
+	// DefaultPageSize максимальное количество объектов на странице по умолчанию
+	DefaultPageSize = 100
+	// DefaultMaxContentLength максимальный размер тела запроса по умолчанию
+	DefaultMaxContentLength = 10 << 20
+)
+
+// discoveryPath путь ресурса "discovery"
+const discoveryPath = "taxii2"
+
+// versionLayout формат времени для упорядочивания версий объектов
+const versionLayout = "2006-01-02T15:04:05.000000000Z"
+
+// ServerOptions параметры сервера
+// Title, Description, Contact - описание сервера в ресурсе "discovery"
+// Default - наименование корня API по умолчанию
+// PageSize - максимальное количество объектов на странице, по умолчанию DefaultPageSize
+// MaxContentLength - максимальный размер тела запроса в байтах, по умолчанию DefaultMaxContentLength
+// Authenticate - проверка учетных данных аутентификации Basic, если не задана, аутентификация не выполняется
+// Authorize - проверка доступа пользователя к коллекции на чтение (write равно false) или на запись, если не задана,
+// доступ определяется свойствами коллекции CanRead и CanWrite
+type ServerOptions struct {
+	Title            string
+	Description      string
+	Contact          string
+	Default          string
+	PageSize         int
+	MaxContentLength int64
+	Authenticate     func(username, password string) bool
+	Authorize        func(username string, collection Collection, write bool) bool
+}
+
+// Server сервер TAXII 2.1, реализует интерфейс http.Handler. Ресурс "discovery" доступен по пути "/taxii2/",
+// корни API по путям "/<наименование корня API>/"
+type Server struct {
+	opts ServerOptions
+
+	mu        sync.RWMutex
+	roots     map[string]*apiRoot
+	rootNames []string
+	lastAdded time.Time
+}
+
+// apiRoot корень API
+type apiRoot struct {
+	info        APIRoot
+	collections []*collection
+	statuses    map[string]Status
+}
+
+// collection коллекция, added содержит время добавления версий объектов
+type collection struct {
+	info  Collection
+	store stixstore.Store
+	added map[string]time.Time
+}
+
+// record версия объекта коллекции
+type record struct {
+	id        string
+	version   string
+	sortKey   string
+	dateAdded time.Time
+	object    interface{}
+}
+
+// query параметры запроса объектов
+type query struct {
+	addedAfter   time.Time
+	ids          []string
+	types        []string
+	versions     []string
+	specVersions []string
+	limit        int
+	next         string
+}
+
+// requestError ошибка обработки запроса, возвращаемая клиенту в виде ресурса "error"
+type requestError struct {
+	status      int
+	title       string
+	description string
+}
+
+func (e requestError) Error() string {
+	return e.title
+}
+
+func newRequestError(status int, title, description string) requestError {
+	return requestError{status: status, title: title, description: description}
+}
+
+// NewServer создает сервер TAXII без корней API
+func NewServer(opts ServerOptions) *Server {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+
+	if opts.MaxContentLength <= 0 {
+		opts.MaxContentLength = DefaultMaxContentLength
+	}
+
+	return &Server{opts: opts, roots: map[string]*apiRoot{}}
+}
+
+// AddAPIRoot добавляет корень API с наименованием name, являющимся первым элементом его пути
+func (s *Server) AddAPIRoot(name string, info APIRoot) error {
+	if name == "" || name == discoveryPath || strings.ContainsAny(name, "/?#") {
+		return fmt.Errorf("incorrect API root name '%s'", name)
+	}
+
+	if len(info.Versions) == 0 {
+		info.Versions = []string{MediaType}
+	}
+
+	if info.MaxContentLength <= 0 {
+		info.MaxContentLength = s.opts.MaxContentLength
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.roots[name]; ok {
+		return fmt.Errorf("the API root '%s' already exists", name)
+	}
+
+	s.roots[name] = &apiRoot{info: info, statuses: map[string]Status{}}
+	s.rootNames = append(s.rootNames, name)
+
+	return nil
+}
+
+// AddCollection добавляет в корень API коллекцию, объекты которой хранятся в store. Если идентификатор коллекции
+// не задан, он формируется автоматически. Время добавления объектов, уже имеющихся в хранилище, считается равным
+// времени их версии (но не позднее времени добавления коллекции), объектов, добавленных в хранилище в обход сервера,
+// - времени, когда сервер впервые их обнаружил
+func (s *Server) AddCollection(apiRootName string, info Collection, store stixstore.Store) (Collection, error) {
+	if store == nil {
+		return info, fmt.Errorf("the store of the collection must not be empty")
+	}
+
+	if info.ID == "" {
+		info.ID = commonlibs.GetUUIDv4()
+	}
+
+	if len(info.MediaTypes) == 0 {
+		info.MediaTypes = []string{STIXMediaType}
+	}
+
+	c := &collection{info: info, store: store, added: map[string]time.Time{}}
+
+	objects, err := store.Query(nil)
+	if err != nil {
+		return info, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	root, ok := s.roots[apiRootName]
+	if !ok {
+		return info, fmt.Errorf("the API root '%s' was not found", apiRootName)
+	}
+
+	for _, v := range root.collections {
+		if v.info.ID == info.ID || (info.Alias != "" && v.info.Alias == info.Alias) {
+			return info, fmt.Errorf("the collection '%s' already exists", info.ID)
+		}
+	}
+
+	now := s.getDateAdded()
+	for _, obj := range objects {
+		m, err := commonlibs.ToMap(obj)
+		if err != nil {
+			return info, err
+		}
+
+		objectInfo, err := stixstore.GetObjectInfo(m)
+		if err != nil {
+			return info, err
+		}
+
+		versions, err := store.GetAllVersions(objectInfo.ID)
+		if err != nil {
+			return info, err
+		}
+
+		for _, v := range versions {
+			id, version, _ := getVersion(v)
+
+			dateAdded := now
+			if t, err := commonlibs.ParseTimestamp(version); err == nil && t.Before(now) {
+				dateAdded = t.UTC()
+			}
+			c.added[getVersionKey(id, version)] = dateAdded
+		}
+	}
+
+	root.collections = append(root.collections, c)
+
+	return info, nil
+}
+
+// getDateAdded возвращает время добавления объектов, время строго возрастает, поэтому объекты, добавленные
+// разными запросами, имеют разное время добавления. Вызывается при заблокированном s.mu
+func (s *Server) getDateAdded() time.Time {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	if !now.After(s.lastAdded) {
+		now = s.lastAdded.Add(time.Microsecond)
+	}
+	s.lastAdded = now
+
+	return now
+}
+
+// ServeHTTP обрабатывает запросы TAXII
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	username, password, _ := r.BasicAuth()
+	if s.opts.Authenticate != nil && !s.opts.Authenticate(username, password) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="TAXII"`)
+		writeError(w, newRequestError(http.StatusUnauthorized, "Unauthorized", "the request requires valid credentials"))
+
+		return
+	}
+
+	if !isAcceptable(r.Header.Get("Accept")) {
+		writeError(w, newRequestError(http.StatusNotAcceptable, "Not Acceptable", "the media type of the response must be "+MediaType))
+
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(segments) == 1 && segments[0] == discoveryPath {
+		if r.Method != http.MethodGet {
+			writeError(w, newRequestError(http.StatusMethodNotAllowed, "Method Not Allowed", ""))
+
+			return
+		}
+
+		writeResponse(w, http.StatusOK, s.getDiscovery(r))
+
+		return
+	}
+
+	s.mu.RLock()
+	root, ok := s.roots[segments[0]]
+	s.mu.RUnlock()
+	if !ok {
+		writeError(w, newRequestError(http.StatusNotFound, "Not Found", "the API root was not found"))
+
+		return
+	}
+
+	if err := s.handleAPIRoot(w, r, root, username, segments[1:]); err != nil {
+		var reqErr requestError
+		if !errors.As(err, &reqErr) {
+			reqErr = newRequestError(http.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+
+		writeError(w, reqErr)
+	}
+}
+
+func (s *Server) getDiscovery(r *http.Request) Discovery {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	baseURL := scheme + "://" + r.Host + "/"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	discovery := Discovery{Title: s.opts.Title, Description: s.opts.Description, Contact: s.opts.Contact}
+	for _, name := range s.rootNames {
+		discovery.APIRoots = append(discovery.APIRoots, baseURL+name+"/")
+	}
+
+	if _, ok := s.roots[s.opts.Default]; ok {
+		discovery.Default = baseURL + s.opts.Default + "/"
+	}
+
+	return discovery
+}
+
+// handleAPIRoot обрабатывает запросы к ресурсам корня API, segments - элементы пути после наименования корня API
+func (s *Server) handleAPIRoot(w http.ResponseWriter, r *http.Request, root *apiRoot, username string, segments []string) error {
+	method := http.MethodGet
+	switch {
+	case len(segments) == 3 && segments[0] == "collections" && segments[2] == "objects":
+		if r.Method == http.MethodPost {
+			method = http.MethodPost
+		}
+	case len(segments) == 4 && segments[0] == "collections" && segments[2] == "objects":
+		if r.Method == http.MethodDelete {
+			method = http.MethodDelete
+		}
+	}
+
+	if r.Method != method {
+		return newRequestError(http.StatusMethodNotAllowed, "Method Not Allowed", "")
+	}
+
+	switch {
+	case len(segments) == 0:
+		s.mu.RLock()
+		info := root.info
+		s.mu.RUnlock()
+
+		writeResponse(w, http.StatusOK, info)
+
+		return nil
+
+	case len(segments) == 2 && segments[0] == "status":
+		s.mu.RLock()
+		status, ok := root.statuses[segments[1]]
+		s.mu.RUnlock()
+		if !ok {
+			return newRequestError(http.StatusNotFound, "Not Found", "the status was not found")
+		}
+
+		writeResponse(w, http.StatusOK, status)
+
+		return nil
+
+	case len(segments) == 1 && segments[0] == "collections":
+		list := Collections{}
+		s.mu.RLock()
+		for _, c := range root.collections {
+			info := s.getAccess(c, username)
+			if info.CanRead || info.CanWrite {
+				list.Collections = append(list.Collections, info)
+			}
+		}
+		s.mu.RUnlock()
+
+		writeResponse(w, http.StatusOK, list)
+
+		return nil
+
+	case len(segments) < 2 || segments[0] != "collections":
+		return newRequestError(http.StatusNotFound, "Not Found", "the resource was not found")
+	}
+
+	s.mu.RLock()
+	var c *collection
+	for _, v := range root.collections {
+		if v.info.ID == segments[1] || (v.info.Alias != "" && v.info.Alias == segments[1]) {
+			c = v
+		}
+	}
+	s.mu.RUnlock()
+
+	if c == nil {
+		return newRequestError(http.StatusNotFound, "Not Found", "the collection was not found")
+	}
+
+	access := s.getAccess(c, username)
+	if !access.CanRead && !access.CanWrite {
+		return newRequestError(http.StatusForbidden, "Forbidden", "access to the collection is denied")
+	}
+
+	switch {
+	case len(segments) == 2:
+		writeResponse(w, http.StatusOK, access)
+
+		return nil
+
+	case len(segments) == 3 && segments[2] == "objects" && r.Method == http.MethodPost:
+		if !access.CanWrite {
+			return newRequestError(http.StatusForbidden, "Forbidden", "the collection is not writable")
+		}
+
+		status, err := s.addObjects(r, root, c)
+		if err != nil {
+			return err
+		}
+
+		writeResponse(w, http.StatusAccepted, status)
+
+		return nil
+
+	case len(segments) == 4 && segments[2] == "objects" && r.Method == http.MethodDelete:
+		if !access.CanRead || !access.CanWrite {
+			return newRequestError(http.StatusForbidden, "Forbidden", "the collection is not writable")
+		}
+
+		if err := c.store.Delete(segments[3]); err != nil {
+			if errors.Is(err, stixstore.ErrNotFound) {
+				return newRequestError(http.StatusNotFound, "Not Found", "the object was not found")
+			}
+
+			return err
+		}
+
+		s.mu.Lock()
+		for k := range c.added {
+			if strings.HasPrefix(k, segments[3]+"|") {
+				delete(c.added, k)
+			}
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", MediaType)
+		w.WriteHeader(http.StatusOK)
+
+		return nil
+	}
+
+	if !access.CanRead {
+		return newRequestError(http.StatusForbidden, "Forbidden", "the collection is not readable")
+	}
+
+	switch {
+	case len(segments) == 3 && segments[2] == "manifest":
+		q, err := s.parseQuery(r, "last", true)
+		if err != nil {
+			return err
+		}
+
+		page, more, next, err := s.getPage(c, q, "")
+		if err != nil {
+			return err
+		}
+
+		manifest := Manifest{More: more, Next: next}
+		for _, v := range page {
+			manifest.Objects = append(manifest.Objects, ManifestRecord{ID: v.id, DateAdded: FormatTimestamp(v.dateAdded), Version: v.version, MediaType: STIXMediaType})
+		}
+
+		setDateAddedHeaders(w, page)
+		writeResponse(w, http.StatusOK, manifest)
+
+		return nil
+
+	case len(segments) == 3 && segments[2] == "objects":
+		q, err := s.parseQuery(r, "last", true)
+		if err != nil {
+			return err
+		}
+
+		page, more, next, err := s.getPage(c, q, "")
+		if err != nil {
+			return err
+		}
+
+		return writeEnvelope(w, page, more, next)
+
+	case len(segments) == 4 && segments[2] == "objects":
+		q, err := s.parseQuery(r, "last", false)
+		if err != nil {
+			return err
+		}
+
+		page, more, next, err := s.getPage(c, q, segments[3])
+		if err != nil {
+			return err
+		}
+
+		return writeEnvelope(w, page, more, next)
+
+	case len(segments) == 5 && segments[2] == "objects" && segments[4] == "versions":
+		q, err := s.parseQuery(r, "all", false)
+		if err != nil {
+			return err
+		}
+
+		page, more, next, err := s.getPage(c, q, segments[3])
+		if err != nil {
+			return err
+		}
+
+		versions := Versions{More: more, Next: next}
+		for _, v := range page {
+			versions.Versions = append(versions.Versions, v.version)
+		}
+
+		setDateAddedHeaders(w, page)
+		writeResponse(w, http.StatusOK, versions)
+
+		return nil
+	}
+
+	return newRequestError(http.StatusNotFound, "Not Found", "the resource was not found")
+}
+
+// getAccess возвращает описание коллекции с учетом прав доступа пользователя username
+func (s *Server) getAccess(c *collection, username string) Collection {
+	info := c.info
+	if s.opts.Authorize != nil {
+		info.CanRead = info.CanRead && s.opts.Authorize(username, c.info, false)
+		info.CanWrite = info.CanWrite && s.opts.Authorize(username, c.info, true)
+	}
+
+	return info
+}
+
+// parseQuery разбирает параметры запроса объектов, defaultVersion - значение match[version] по умолчанию,
+// allowMatch определяет допустимость параметров match[id] и match[type]
+func (s *Server) parseQuery(r *http.Request, defaultVersion string, allowMatch bool) (query, error) {
+	values := r.URL.Query()
+	q := query{limit: s.opts.PageSize, next: values.Get("next")}
+
+	getList := func(name string) []string {
+		list := []string{}
+		for _, value := range values[name] {
+			for _, v := range strings.Split(value, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					list = append(list, v)
+				}
+			}
+		}
+
+		return list
+	}
+
+	if v := values.Get("added_after"); v != "" {
+		t, err := commonlibs.ParseTimestamp(v)
+		if err != nil {
+			return q, newRequestError(http.StatusBadRequest, "Bad Request", "incorrect value of the parameter 'added_after'")
+		}
+		q.addedAfter = t
+	}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return q, newRequestError(http.StatusBadRequest, "Bad Request", "incorrect value of the parameter 'limit'")
+		}
+
+		if limit < q.limit {
+			q.limit = limit
+		}
+	}
+
+	if allowMatch {
+		q.ids = getList("match[id]")
+		q.types = getList("match[type]")
+	}
+
+	q.specVersions = getList("match[spec_version]")
+	q.versions = getList("match[version]")
+	if len(q.versions) == 0 {
+		q.versions = []string{defaultVersion}
+	}
+
+	for _, v := range q.versions {
+		if v == "first" || v == "last" || v == "all" {
+			continue
+		}
+
+		if _, err := commonlibs.ParseTimestamp(v); err != nil {
+			return q, newRequestError(http.StatusBadRequest, "Bad Request", fmt.Sprintf("incorrect value '%s' of the parameter 'match[version]'", v))
+		}
+	}
+
+	return q, nil
+}
+
+// getPage возвращает страницу версий объектов коллекции, удовлетворяющих запросу q,
+// если id не пустой, возвращаются только версии объекта с этим идентификатором
+func (s *Server) getPage(c *collection, q query, id string) ([]record, bool, string, error) {
+	list, err := s.getRecords(c, q, id)
+	if err != nil {
+		return nil, false, "", err
+	}
+
+	start := 0
+	if q.next != "" {
+		b, err := base64.RawURLEncoding.DecodeString(q.next)
+		if err != nil {
+			return nil, false, "", newRequestError(http.StatusBadRequest, "Bad Request", "incorrect value of the parameter 'next'")
+		}
+
+		key := string(b)
+		start = sort.Search(len(list), func(i int) bool { return list[i].sortKey > key })
+	}
+
+	list = list[start:]
+	if len(list) <= q.limit {
+		return list, false, "", nil
+	}
+
+	list = list[:q.limit]
+
+	return list, true, base64.RawURLEncoding.EncodeToString([]byte(list[len(list)-1].sortKey)), nil
+}
+
+// getRecords возвращает версии объектов коллекции, удовлетворяющих запросу q, упорядоченные по времени добавления
+func (s *Server) getRecords(c *collection, q query, id string) ([]record, error) {
+	expr := stixfilter.And{}
+	if id != "" {
+		expr = append(expr, stixfilter.Filter{Property: "id", Operator: stixfilter.OperatorEqual, Value: id})
+	}
+
+	if len(q.ids) > 0 {
+		expr = append(expr, stixfilter.Filter{Property: "id", Operator: stixfilter.OperatorIn, Value: q.ids})
+	}
+
+	if len(q.types) > 0 {
+		expr = append(expr, stixfilter.Filter{Property: "type", Operator: stixfilter.OperatorIn, Value: q.types})
+	}
+
+	var filter stixstore.Filter
+	if len(expr) > 0 {
+		filter = expr
+	}
+
+	objects, err := c.store.Query(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if id != "" && len(objects) == 0 {
+		return nil, newRequestError(http.StatusNotFound, "Not Found", "the object was not found")
+	}
+
+	onlyLast := len(q.versions) == 1 && q.versions[0] == "last"
+
+	list := []record{}
+	for _, obj := range objects {
+		versions := []interface{}{obj}
+		if !onlyLast {
+			objectID, _, err := getVersion(obj)
+			if err != nil {
+				return nil, err
+			}
+
+			if versions, err = c.store.GetAllVersions(objectID); err != nil {
+				return nil, err
+			}
+		}
+
+		for i, v := range versions {
+			m, err := commonlibs.ToMap(v)
+			if err != nil {
+				return nil, err
+			}
+
+			objectID, version, err := getMapVersion(m)
+			if err != nil {
+				return nil, err
+			}
+
+			if !matchVersion(q.versions, version, i == 0, i == len(versions)-1) || !matchSpecVersion(q.specVersions, m) {
+				continue
+			}
+
+			r := s.newRecord(c, v, objectID, version)
+
+			if !q.addedAfter.IsZero() && !r.dateAdded.After(q.addedAfter) {
+				continue
+			}
+
+			list = append(list, r)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].sortKey < list[j].sortKey })
+
+	return list, nil
+}
+
+// newRecord возвращает сведения о версии version объекта коллекции, время добавления версий, ранее неизвестных
+// серверу, считается равным текущему времени
+func (s *Server) newRecord(c *collection, obj interface{}, id, version string) record {
+	key := getVersionKey(id, version)
+
+	s.mu.Lock()
+	dateAdded, ok := c.added[key]
+	if !ok {
+		dateAdded = s.getDateAdded()
+		c.added[key] = dateAdded
+	}
+	s.mu.Unlock()
+
+	if version == "" {
+		version = FormatTimestamp(dateAdded)
+	}
+
+	sortVersion := version
+	if t, err := commonlibs.ParseTimestamp(version); err == nil {
+		sortVersion = t.UTC().Format(versionLayout)
+	}
+
+	return record{
+		id:        id,
+		version:   version,
+		sortKey:   dateAdded.Format(versionLayout) + "|" + id + "|" + sortVersion,
+		dateAdded: dateAdded,
+		object:    obj,
+	}
+}
+
+// addObjects добавляет в коллекцию объекты из тела запроса и возвращает состояние обработки запроса
+func (s *Server) addObjects(r *http.Request, root *apiRoot, c *collection) (Status, error) {
+	if !isTAXIIMediaType(r.Header.Get("Content-Type")) {
+		return Status{}, newRequestError(http.StatusUnsupportedMediaType, "Unsupported Media Type", "the media type of the request must be "+MediaType)
+	}
+
+	s.mu.RLock()
+	maxContentLength := root.info.MaxContentLength
+	s.mu.RUnlock()
+
+	if r.ContentLength > maxContentLength {
+		return Status{}, newRequestError(http.StatusRequestEntityTooLarge, "Request Entity Too Large", fmt.Sprintf("the request must not exceed %d bytes", maxContentLength))
+	}
+
+	b, err := io.ReadAll(io.LimitReader(r.Body, maxContentLength+1))
+	if err != nil {
+		return Status{}, err
+	}
+
+	if int64(len(b)) > maxContentLength {
+		return Status{}, newRequestError(http.StatusRequestEntityTooLarge, "Request Entity Too Large", fmt.Sprintf("the request must not exceed %d bytes", maxContentLength))
+	}
+
+	envelope := Envelope{}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return Status{}, newRequestError(http.StatusBadRequest, "Bad Request", "the request must contain an envelope: "+err.Error())
+	}
+
+	status := Status{
+		ID:               commonlibs.GetUUIDv4(),
+		Status:           StatusComplete,
+		RequestTimestamp: FormatTimestamp(time.Now()),
+		TotalCount:       len(envelope.Objects),
+	}
+
+	s.mu.Lock()
+	dateAdded := s.getDateAdded()
+	s.mu.Unlock()
+
+	for _, raw := range envelope.Objects {
+		id, version, err := getVersion(raw)
+		if err == nil {
+			err = c.store.Add(raw)
+		}
+
+		if err != nil {
+			status.Failures = append(status.Failures, StatusDetails{ID: id, Version: version, Message: err.Error()})
+
+			continue
+		}
+
+		s.mu.Lock()
+		if _, ok := c.added[getVersionKey(id, version)]; !ok {
+			c.added[getVersionKey(id, version)] = dateAdded
+		}
+		s.mu.Unlock()
+
+		if version == "" {
+			version = FormatTimestamp(dateAdded)
+		}
+		status.Successes = append(status.Successes, StatusDetails{ID: id, Version: version})
+	}
+
+	status.SuccessCount = len(status.Successes)
+	status.FailureCount = len(status.Failures)
+
+	s.mu.Lock()
+	root.statuses[status.ID] = status
+	s.mu.Unlock()
+
+	return status, nil
+}
+
+// getVersion возвращает идентификатор объекта и его версию (время изменения или, при его отсутствии, время создания)
+func getVersion(obj interface{}) (string, string, error) {
+	m, err := commonlibs.ToMap(obj)
+	if err != nil {
+		return "", "", err
+	}
+
+	return getMapVersion(m)
+}
+
+func getMapVersion(m map[string]interface{}) (string, string, error) {
+	info, err := stixstore.GetObjectInfo(m)
+	if err != nil {
+		return info.ID, "", err
+	}
+
+	if info.Modified != "" {
+		return info.ID, info.Modified, nil
+	}
+
+	return info.ID, info.Created, nil
+}
+
+// getVersionKey возвращает ключ версии объекта, не зависящий от часового пояса времени версии
+func getVersionKey(id, version string) string {
+	if t, err := commonlibs.ParseTimestamp(version); err == nil {
+		version = t.UTC().Format(versionLayout)
+	}
+
+	return id + "|" + version
+}
+
+// matchVersion проверяет соответствие версии объекта значениям параметра match[version]
+func matchVersion(versions []string, version string, first, last bool) bool {
+	for _, v := range versions {
+		switch v {
+		case "all":
+			return true
+		case "first":
+			if first {
+				return true
+			}
+		case "last":
+			if last {
+				return true
+			}
+		default:
+			if version != "" && commonlibs.CompareTimestamps(version, v) == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchSpecVersion проверяет соответствие версии спецификации объекта значениям параметра match[spec_version],
+// объекты без свойства spec_version (например, SCO) считаются соответствующими версии 2.1
+func matchSpecVersion(specVersions []string, m map[string]interface{}) bool {
+	if len(specVersions) == 0 {
+		return true
+	}
+
+	specVersion := "2.1"
+	if v, ok := m["spec_version"].(string); ok && v != "" {
+		specVersion = v
+	}
+
+	for _, v := range specVersions {
+		if v == specVersion {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAcceptable проверяет, что заголовок Accept допускает ответ в формате TAXII 2.1
+func isAcceptable(accept string) bool {
+	for _, v := range strings.Split(accept, ",") {
+		if isTAXIIMediaType(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setDateAddedHeaders(w http.ResponseWriter, page []record) {
+	if len(page) == 0 {
+		return
+	}
+
+	first, last := page[0].dateAdded, page[0].dateAdded
+	for _, v := range page {
+		if v.dateAdded.Before(first) {
+			first = v.dateAdded
+		}
+
+		if v.dateAdded.After(last) {
+			last = v.dateAdded
+		}
+	}
+
+	w.Header().Set(HeaderDateAddedFirst, FormatTimestamp(first))
+	w.Header().Set(HeaderDateAddedLast, FormatTimestamp(last))
+}
+
+func writeEnvelope(w http.ResponseWriter, page []record, more bool, next string) error {
+	envelope := Envelope{More: more, Next: next}
+	for _, v := range page {
+		b, err := stixstore.EncodeObject(v.object)
+		if err != nil {
+			return err
+		}
+
+		envelope.Objects = append(envelope.Objects, b)
+	}
+
+	setDateAddedHeaders(w, page)
+	writeResponse(w, http.StatusOK, envelope)
+
+	return nil
+}
+
+func writeResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err requestError) {
+	writeResponse(w, err.status, Error{Title: err.title, Description: err.description, HTTPStatus: strconv.Itoa(err.status)})
+}
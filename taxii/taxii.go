@@ -0,0 +1,157 @@
+// Package taxii сервер и клиент TAXII 2.1 для обмена объектами STIX. Сервер публикует коллекции,
+// объекты которых хранятся в хранилищах stixstore
+package taxii
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	// MediaType тип содержимого запросов и ответов TAXII 2.1
+	MediaType = "application/taxii+json;version=2.1"
+	// STIXMediaType тип содержимого объектов STIX 2.1
+	STIXMediaType = "application/stix+json;version=2.1"
+)
+
+const (
+	// HeaderDateAddedFirst заголовок, содержащий время добавления первого объекта в ответе
+	HeaderDateAddedFirst = "X-TAXII-Date-Added-First"
+	// HeaderDateAddedLast заголовок, содержащий время добавления последнего объекта в ответе
+	HeaderDateAddedLast = "X-TAXII-Date-Added-Last"
+)
+
+// timestampLayout формат времени TAXII, время добавления объектов указывается с точностью до микросекунды
+const timestampLayout = "2006-01-02T15:04:05.000000Z"
+
+// Discovery ресурс "discovery", описание сервера и список его корней API
+type Discovery struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Contact     string   `json:"contact,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	APIRoots    []string `json:"api_roots,omitempty"`
+}
+
+// APIRoot ресурс "api-root", описание корня API
+type APIRoot struct {
+	Title            string   `json:"title"`
+	Description      string   `json:"description,omitempty"`
+	Versions         []string `json:"versions"`
+	MaxContentLength int64    `json:"max_content_length"`
+}
+
+// Collection ресурс "collection", описание коллекции
+type Collection struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Alias       string   `json:"alias,omitempty"`
+	CanRead     bool     `json:"can_read"`
+	CanWrite    bool     `json:"can_write"`
+	MediaTypes  []string `json:"media_types,omitempty"`
+}
+
+// Collections ресурс "collections", список коллекций корня API
+type Collections struct {
+	Collections []Collection `json:"collections,omitempty"`
+}
+
+// Envelope ресурс "envelope", страница объектов коллекции
+type Envelope struct {
+	More    bool              `json:"more,omitempty"`
+	Next    string            `json:"next,omitempty"`
+	Objects []json.RawMessage `json:"objects,omitempty"`
+}
+
+// ManifestRecord сведения о версии объекта коллекции
+type ManifestRecord struct {
+	ID        string `json:"id"`
+	DateAdded string `json:"date_added"`
+	Version   string `json:"version"`
+	MediaType string `json:"media_type,omitempty"`
+}
+
+// Manifest ресурс "manifest", страница сведений о версиях объектов коллекции
+type Manifest struct {
+	More    bool             `json:"more,omitempty"`
+	Next    string           `json:"next,omitempty"`
+	Objects []ManifestRecord `json:"objects,omitempty"`
+}
+
+// Versions ресурс "versions", страница версий объекта
+type Versions struct {
+	More     bool     `json:"more,omitempty"`
+	Next     string   `json:"next,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+}
+
+const (
+	// StatusComplete обработка запроса завершена
+	StatusComplete = "complete"
+	// StatusPending запрос обрабатывается
+	StatusPending = "pending"
+)
+
+// StatusDetails сведения о результате добавления объекта
+type StatusDetails struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	Message string `json:"message,omitempty"`
+}
+
+// Status ресурс "status", состояние обработки запроса на добавление объектов
+type Status struct {
+	ID               string          `json:"id"`
+	Status           string          `json:"status"`
+	RequestTimestamp string          `json:"request_timestamp,omitempty"`
+	TotalCount       int             `json:"total_count"`
+	SuccessCount     int             `json:"success_count"`
+	Successes        []StatusDetails `json:"successes,omitempty"`
+	FailureCount     int             `json:"failure_count"`
+	Failures         []StatusDetails `json:"failures,omitempty"`
+	PendingCount     int             `json:"pending_count"`
+	Pendings         []StatusDetails `json:"pendings,omitempty"`
+}
+
+// Error ресурс "error", описание ошибки
+type Error struct {
+	Title           string            `json:"title"`
+	Description     string            `json:"description,omitempty"`
+	ErrorID         string            `json:"error_id,omitempty"`
+	ErrorCode       string            `json:"error_code,omitempty"`
+	HTTPStatus      string            `json:"http_status,omitempty"`
+	ExternalDetails string            `json:"external_details,omitempty"`
+	Details         map[string]string `json:"details,omitempty"`
+}
+
+// Error возвращает описание ошибки
+func (e Error) Error() string {
+	if e.Description == "" {
+		return e.Title
+	}
+
+	return e.Title + ": " + e.Description
+}
+
+// FormatTimestamp возвращает время в формате TAXII
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+// isTAXIIMediaType проверяет, что тип содержимого является типом TAXII 2.1 (версия может не указываться)
+func isTAXIIMediaType(v string) bool {
+	mediaType, params, _ := strings.Cut(strings.ReplaceAll(strings.ToLower(v), " ", ""), ";")
+	if mediaType != "application/taxii+json" {
+		return false
+	}
+
+	for _, param := range strings.Split(params, ";") {
+		if version, ok := strings.CutPrefix(param, "version="); ok && version != "2.1" {
+			return false
+		}
+	}
+
+	return true
+}
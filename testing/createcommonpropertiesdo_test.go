@@ -47,6 +47,10 @@ func TestCommonPropertiesDomainObjectSTIX(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, key1, "123")
 
+	//значение незарегистрированного расширения сохраняется без преобразования в строку
+	ncpdo.SetAnyTypedExtensions("key_3", 345)
+	assert.Equal(t, ncpdo.GetExtensions()["key_3"], 345)
+
 	ncpdo.SetValueCreatedByRef("full_extension")
 	assert.Equal(t, ncpdo.GetCreatedByRef(), stixhelpers.IdentifierTypeSTIX("full_extension"))
 
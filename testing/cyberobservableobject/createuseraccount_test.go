@@ -1,9 +1,11 @@
 package cyberobservableobject
 
 import (
+	"encoding/json"
 	"testing"
 
 	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
 	"github.com/av-belyakov/methodstixobjects/datamodels/someextensionsstixco"
 	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
 	"github.com/stretchr/testify/assert"
@@ -116,6 +118,58 @@ func TestUserAccountCyberObservableObjectSTIX(t *testing.T) {
 	nua.SetValueExtensions("key_1", someextensionsstixco.UNIXAccountExtensionSTIX{GID: 3215, HomeDir: "/home/user_1"})
 	nua.SetValueExtensions("key_2", someextensionsstixco.UNIXAccountExtensionSTIX{GID: 2241, HomeDir: "/home/user_2"})
 	assert.Equal(t, len(nua.GetExtensions()), 3)
+
+	nua.SetFullValueAnyExtensions(map[string]interface{}{
+		"unix-account-ext": someextensionsstixco.UNIXAccountExtensionSTIX{GID: 734},
+		"x-vendor-ext":     map[string]interface{}{"engine": "abc"},
+	})
+	assert.Equal(t, len(nua.GetExtensions()), 2)
+}
+
+func TestUserAccountExtensionsRoundTrip(t *testing.T) {
+	raw := json.RawMessage(`{
+		"type": "user-account",
+		"spec_version": "2.1",
+		"id": "user-account--0d5b424b-93b8-5cd8-ac36-306e1789d63c",
+		"user_id": "1001",
+		"account_login": "jdoe",
+		"account_type": "unix",
+		"extensions": {
+			"unix-account-ext": {"gid": 1001, "groups": ["wheel"], "home_dir": "/home/jdoe", "shell": "/bin/bash"},
+			"x-vendor-ext": {"engine": "abc"}
+		}
+	}`)
+
+	obj, err := cyberobservableobjectsstix.UserAccountCyberObservableObjectSTIX{}.DecodeJSON(&raw)
+	assert.NoError(t, err)
+
+	ua := obj.(cyberobservableobjectsstix.UserAccountCyberObservableObjectSTIX)
+	assert.Equal(t, ua.UserID, "1001")
+
+	unix, ok := ua.Extensions["unix-account-ext"].(someextensionsstixco.UNIXAccountExtensionSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, unix.GID, 1001)
+	assert.Equal(t, unix.Groups, []string{"wheel"})
+	assert.Equal(t, unix.HomeDir, "/home/jdoe")
+
+	//неизвестное расширение сохраняется без изменений
+	_, ok = ua.Extensions["x-vendor-ext"].(json.RawMessage)
+	assert.True(t, ok)
+	assert.True(t, ua.ValidateStruct())
+
+	b, err := ua.EncodeJSON(nil)
+	assert.NoError(t, err)
+
+	obj, err = cyberobservableobjectsstix.UserAccountCyberObservableObjectSTIX{}.DecodeJSON((*json.RawMessage)(b))
+	assert.NoError(t, err)
+	result := obj.(cyberobservableobjectsstix.UserAccountCyberObservableObjectSTIX)
+	assert.Equal(t, result.Extensions["unix-account-ext"], unix)
+	assert.JSONEq(t, string(result.Extensions["x-vendor-ext"].(json.RawMessage)), `{"engine": "abc"}`)
+
+	//ошибка декодирования встроенного расширения
+	raw = json.RawMessage(`{"type": "user-account", "id": "user-account--0d5b424b-93b8-5cd8-ac36-306e1789d63c", "extensions": {"unix-account-ext": {"gid": "wheel"}}}`)
+	_, err = cyberobservableobjectsstix.UserAccountCyberObservableObjectSTIX{}.DecodeJSON(&raw)
+	assert.Error(t, err)
 }
 
 /*
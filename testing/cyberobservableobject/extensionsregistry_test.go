@@ -0,0 +1,110 @@
+package cyberobservableobject
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/datamodels"
+	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/someextensionsstixco"
+	"github.com/stretchr/testify/assert"
+)
+
+type customScanExtension struct {
+	Scanner string `json:"scanner"`
+	Score   int    `json:"score"`
+}
+
+func TestExtensionsRegistry(t *testing.T) {
+	rawFile := json.RawMessage(`{
+		"type": "file",
+		"spec_version": "2.1",
+		"id": "file--73c4cd13-7206-5100-88ee-822c42d3f02a",
+		"name": "example.pdf",
+		"extensions": {
+			"pdf-ext": {"version": "1.7", "is_optimized": true, "pdfid0": "DFCE52BD827ECF765649852119D"},
+			"x-vendor-ext": {"engine": "abc", "verdicts": [1, 2, 3]}
+		}
+	}`)
+
+	t.Run("Встроенные и неизвестные расширения", func(t *testing.T) {
+		obj, err := cyberobservableobjectsstix.FileCyberObservableObjectSTIX{}.DecodeJSON(&rawFile)
+		assert.NoError(t, err)
+
+		f := obj.(cyberobservableobjectsstix.FileCyberObservableObjectSTIX)
+		pdf, ok := f.Extensions["pdf-ext"].(someextensionsstixco.PDFFileExtensionSTIX)
+		assert.True(t, ok)
+		assert.Equal(t, pdf.Version, "1.7")
+		assert.True(t, pdf.IsOptimized)
+
+		_, ok = f.Extensions["x-vendor-ext"].(json.RawMessage)
+		assert.True(t, ok)
+		assert.True(t, f.ValidateStruct())
+
+		b, err := f.EncodeJSON(nil)
+		assert.NoError(t, err)
+
+		result := map[string]map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(*b, &struct {
+			Extensions *map[string]map[string]interface{} `json:"extensions"`
+		}{Extensions: &result}))
+		assert.Equal(t, result["x-vendor-ext"]["engine"], "abc")
+		assert.Equal(t, result["x-vendor-ext"]["verdicts"], []interface{}{float64(1), float64(2), float64(3)})
+	})
+
+	t.Run("Ошибка декодирования расширения", func(t *testing.T) {
+		raw := json.RawMessage(`{"type": "file", "id": "file--73c4cd13-7206-5100-88ee-822c42d3f02a", "extensions": {"pdf-ext": {"version": 17}}}`)
+
+		_, err := cyberobservableobjectsstix.FileCyberObservableObjectSTIX{}.DecodeJSON(&raw)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "pdf-ext")
+	})
+
+	t.Run("Регистрация стороннего расширения", func(t *testing.T) {
+		err := datamodels.RegisterExtensionHandlerSTIX("x-scan-ext", datamodels.ExtensionHandlerSTIX{})
+		assert.Error(t, err)
+
+		err = datamodels.RegisterExtensionHandlerSTIX("x-scan-ext", datamodels.ExtensionHandlerSTIX{
+			Decoder: datamodels.NewExtensionDecoderSTIX(customScanExtension{}),
+			Validator: func(ext interface{}) bool {
+				v, ok := ext.(customScanExtension)
+
+				return ok && v.Score >= 0 && v.Score <= 100
+			},
+			Sanitizer: func(ext interface{}) interface{} {
+				if v, ok := ext.(customScanExtension); ok {
+					v.Scanner = fmt.Sprintf("[%s]", v.Scanner)
+
+					return v
+				}
+
+				return ext
+			},
+		})
+		assert.NoError(t, err)
+		defer datamodels.UnregisterExtensionHandlerSTIX("x-scan-ext")
+
+		raw := json.RawMessage(`{"type": "process", "spec_version": "2.1", "id": "process--f52a906a-0dfc-40bd-92f1-e7778ead38a9", "extensions": {"x-scan-ext": {"scanner": "av", "score": 150}}}`)
+		obj, err := cyberobservableobjectsstix.ProcessCyberObservableObjectSTIX{}.DecodeJSON(&raw)
+		assert.NoError(t, err)
+
+		p := obj.(cyberobservableobjectsstix.ProcessCyberObservableObjectSTIX)
+		ext, ok := p.Extensions["x-scan-ext"].(customScanExtension)
+		assert.True(t, ok)
+		assert.Equal(t, ext.Score, 150)
+		assert.False(t, p.ValidateStruct())
+
+		p.Extensions["x-scan-ext"] = customScanExtension{Scanner: "av", Score: 50}
+		assert.True(t, p.ValidateStruct())
+		assert.Equal(t, p.SanitizeStruct().Extensions["x-scan-ext"], customScanExtension{Scanner: "[av]", Score: 50})
+	})
+
+	t.Run("Очистка встроенного расширения", func(t *testing.T) {
+		ext := datamodels.SanitizeExtensionSTIX("archive-ext", someextensionsstixco.ArchiveFileExtensionSTIX{Comment: "<b>comment</b>"})
+
+		archive, ok := ext.(someextensionsstixco.ArchiveFileExtensionSTIX)
+		assert.True(t, ok)
+		assert.NotContains(t, archive.Comment, "<b>")
+	})
+}
@@ -0,0 +1,161 @@
+package cyberobservableobject
+
+import (
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/datamodels"
+	"github.com/av-belyakov/methodstixobjects/datamodels/somecomplextypesstixco"
+	"github.com/av-belyakov/methodstixobjects/datamodels/someextensionsstixco"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func getProperties(errs []datamodels.ExtensionPropertyErrorSTIX) []string {
+	list := make([]string, 0, len(errs))
+	for _, v := range errs {
+		list = append(list, v.Property)
+	}
+
+	return list
+}
+
+func TestExtensionsValidation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		ext        interface{}
+		properties []string
+	}{
+		{
+			name:       "archive-ext",
+			ext:        someextensionsstixco.ArchiveFileExtensionSTIX{ContainsRefs: []stixhelpers.IdentifierTypeSTIX{"file--9a1f834d-2506-5367-baec-7aa63996ac43", "url--0b1f4b5b-1de1-5e3a-9c8f-6e8d3c4b0a33"}},
+			properties: []string{"contains_refs.[1]"},
+		},
+		{
+			name:       "ntfs-ext",
+			ext:        someextensionsstixco.NTFSFileExtensionSTIX{AlternateDataStreams: []somecomplextypesstixco.AlternateDataStreamTypeSTIX{{Size: 12}}},
+			properties: []string{"alternate_data_streams.[0].name"},
+		},
+		{
+			name:       "pdf-ext",
+			ext:        someextensionsstixco.PDFFileExtensionSTIX{Version: "1.7", DocumentInfoDict: map[string]string{"Title": "Sample"}},
+			properties: []string{},
+		},
+		{
+			name:       "raster-image-ext",
+			ext:        someextensionsstixco.RasterImageFileExtensionSTIX{ImageHeight: 768, ImageWidth: -1},
+			properties: []string{"image_width"},
+		},
+		{
+			name: "windows-pebinary-ext",
+			ext: someextensionsstixco.WindowsPEBinaryFileExtensionSTIX{
+				MachineHex: "014c",
+				OptionalHeader: somecomplextypesstixco.WindowsPEOptionalHeaderTypeSTIX{
+					MagicHex: "x10b",
+				},
+				Sections: []somecomplextypesstixco.WindowsPESectionTypeSTIX{
+					{Name: ".text", Entropy: 6.2},
+					{Name: ".data", Entropy: 9.1},
+				},
+			},
+			properties: []string{"pe_type", "optional_header.magic_hex", "sections.[1].entropy"},
+		},
+		{
+			name:       "http-request-ext",
+			ext:        someextensionsstixco.HTTPRequestExtensionSTIX{RequestMethod: "get", MessageBodyDataRef: "file--9a1f834d-2506-5367-baec-7aa63996ac43"},
+			properties: []string{"request_value", "message_body_data_ref"},
+		},
+		{
+			name:       "icmp-ext",
+			ext:        someextensionsstixco.ICMPExtensionSTIX{ICMPTypeHex: "08", ICMPCodeHex: "0z"},
+			properties: []string{"icmp_code_hex"},
+		},
+		{
+			name: "socket-ext",
+			ext: someextensionsstixco.NetworkSocketExtensionSTIX{
+				AddressFamily: "AF_INET7",
+				SocketType:    "SOCK_STREAM",
+				Options:       map[string]int{"SO_REUSEADDR": 1, "reuse": 1},
+			},
+			properties: []string{"address_family", "options.reuse"},
+		},
+		{
+			name:       "tcp-ext",
+			ext:        someextensionsstixco.TCPExtensionSTIX{SrcFlagsHex: "00000002", DstFlagsHex: "12a"},
+			properties: []string{"dst_flags_hex"},
+		},
+		{
+			name:       "windows-process-ext",
+			ext:        someextensionsstixco.WindowsProcessExtensionSTIX{Priority: "HIGH_PRIORITY_CLASS", IntegrityLevel: "critical"},
+			properties: []string{"integrity_level"},
+		},
+		{
+			name: "windows-service-ext",
+			ext: someextensionsstixco.WindowsServiceExtensionSTIX{
+				ServiceName:   "sirvizio",
+				StartType:     "SERVICE_AUTO_START",
+				ServiceType:   "SERVICE_WIN32_OWN",
+				ServiceStatus: "SERVICE_STARTED",
+			},
+			properties: []string{"service_type", "service_status"},
+		},
+		{
+			name:       "unix-account-ext",
+			ext:        someextensionsstixco.UNIXAccountExtensionSTIX{GID: 1001, Groups: []string{"wheel", ""}, HomeDir: "/home/jdoe"},
+			properties: []string{"groups.[1]"},
+		},
+		//расширения, которые должны содержать хотя бы одно свойство
+		{
+			name:       "pdf-ext без свойств",
+			ext:        someextensionsstixco.PDFFileExtensionSTIX{DocumentInfoDict: map[string]string{}},
+			properties: []string{"version"},
+		},
+		{
+			name:       "raster-image-ext без свойств",
+			ext:        someextensionsstixco.RasterImageFileExtensionSTIX{},
+			properties: []string{"image_height"},
+		},
+		{
+			name:       "raster-image-ext только с тегами EXIF",
+			ext:        someextensionsstixco.RasterImageFileExtensionSTIX{ExifTags: somecomplextypesstixco.ExifTags{Make: "Nikon"}},
+			properties: []string{},
+		},
+		{
+			name:       "windows-process-ext без свойств",
+			ext:        someextensionsstixco.WindowsProcessExtensionSTIX{},
+			properties: []string{"aslr_enabled"},
+		},
+		{
+			name:       "windows-service-ext без свойств",
+			ext:        someextensionsstixco.WindowsServiceExtensionSTIX{Descriptions: []string{}},
+			properties: []string{"service_name"},
+		},
+		{
+			name:       "unix-account-ext без свойств",
+			ext:        someextensionsstixco.UNIXAccountExtensionSTIX{},
+			properties: []string{"gid"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := datamodels.ValidatingExtensionsSTIX(tc.ext)
+
+			assert.Equal(t, tc.properties, getProperties(errs))
+			assert.Equal(t, len(tc.properties) == 0, datamodels.CheckingExtensionsSTIX(tc.ext))
+		})
+	}
+
+	t.Run("Описание ошибки", func(t *testing.T) {
+		errs := datamodels.ValidatingExtensionsSTIX(someextensionsstixco.ICMPExtensionSTIX{ICMPCodeHex: "00"})
+
+		assert.Len(t, errs, 1)
+		assert.Equal(t, errs[0].Error(), "the property 'icmp_type_hex' is required")
+	})
+
+	t.Run("Описание ошибки отсутствия свойств", func(t *testing.T) {
+		errs := datamodels.ValidatingExtensionsSTIX(someextensionsstixco.UNIXAccountExtensionSTIX{})
+
+		assert.Len(t, errs, 1)
+		assert.Equal(t, errs[0].Error(), "the property 'gid' or one of the properties 'groups', 'home_dir', 'shell' must be present")
+	})
+}
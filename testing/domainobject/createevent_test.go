@@ -0,0 +1,100 @@
+package domainobject
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventDomainObjectsSTIX(t *testing.T) {
+	ne := methodstixobjects.NewEventDomainObjectsSTIX()
+
+	assert.Equal(t, ne.GetType(), "event")
+	ne.SetValueID("event--1d0c3c4e-2a3b-4c5d-8e9f-0a1b2c3d4e5f")
+	assert.True(t, strings.HasPrefix(ne.GetID(), "event--"))
+	_, err := ne.Get()
+	assert.Error(t, err)
+	//обязательное поле
+	assert.False(t, ne.ValidateStruct())
+
+	ne.SetValueStatus("occurred")
+	_, err = ne.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, ne.GetStatus(), stixhelpers.EnumTypeSTIX("occurred"))
+
+	ne.SetAnyName("<phishing email>")
+	assert.Equal(t, ne.GetName(), "<phishing email>")
+	ne.SetValueDescription("event description")
+	assert.Equal(t, ne.GetDescription(), "event description")
+	ne.SetValueGoal("initial access")
+	assert.Equal(t, ne.GetGoal(), "initial access")
+	ne.SetValueEventTypes("email-delivery")
+	assert.Equal(t, len(ne.GetEventTypes()), 1)
+
+	assert.NoError(t, ne.SetValueStartTime("2024-02-14T12:03:06+00:00"))
+	assert.Error(t, ne.SetValueEndTime("14.02.2024"))
+	assert.NoError(t, ne.SetAnyEndTime("2024-02-14T13:03:06+00:00"))
+	ne.SetValueStartTimeFidelity("minute")
+	ne.SetValueEndTimeFidelity("hour")
+
+	ne.SetValueSightingRefs("sighting--ee20065d-2555-424f-ad9e-0f8428623c75")
+	ne.SetValueChangedObjects(domainobjectsstix.StateChangeTypeSTIX{ResultRef: "user-account--0d5b424b-93b8-5cd8-ac36-306e1789d63c"})
+	assert.True(t, ne.ValidateStruct())
+	assert.Equal(t, ne.GetExtensions()[domainobjectsstix.EventExtensionDefinitionID], domainobjectsstix.NewSDOExtensionSTIX{ExtensionType: "new-sdo"})
+
+	t.Run("Декодирование", func(t *testing.T) {
+		b, err := ne.EncodeJSON(nil)
+		assert.NoError(t, err)
+
+		raw := json.RawMessage(*b)
+		obj, err := domainobjectsstix.EventDomainObjectsSTIX{}.DecodeJSON(&raw)
+		assert.NoError(t, err)
+
+		event := obj.(domainobjectsstix.EventDomainObjectsSTIX)
+		assert.Equal(t, event.GetSightingRefs(), ne.GetSightingRefs())
+		assert.True(t, event.ValidateStruct())
+
+		//признак "new-sdo" передается в виде {"extension_type": "new-sdo"}
+		var object struct {
+			Extensions map[string]map[string]interface{} `json:"extensions"`
+		}
+		assert.NoError(t, json.Unmarshal(*b, &object))
+		assert.Equal(t, object.Extensions, map[string]map[string]interface{}{
+			domainobjectsstix.EventExtensionDefinitionID: {"extension_type": "new-sdo"},
+		})
+	})
+
+	t.Run("Проверка", func(t *testing.T) {
+		invalid := *ne
+		invalid.Status = "finished"
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid = *ne
+		invalid.StartTimeFidelity = "week"
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid = *ne
+		invalid.SightingRefs = []stixhelpers.IdentifierTypeSTIX{"indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"}
+		assert.False(t, invalid.ValidateStruct())
+
+		//объект без признака расширения "new-sdo"
+		invalid = *ne
+		invalid.Extensions = stixhelpers.ExtensionsTypeSTIX{}
+		assert.False(t, invalid.ValidateStruct())
+
+		//признак "new-sdo" должен содержаться под идентификатором расширения объекта "event"
+		invalid.SetValueExtensions(domainobjectsstix.IncidentCoreExtensionDefinitionID, domainobjectsstix.NewSDOExtensionSTIX{ExtensionType: stixhelpers.ExtensionTypeNewSDO})
+		assert.False(t, invalid.ValidateStruct())
+	})
+
+	t.Run("Очистка и вывод", func(t *testing.T) {
+		assert.NotContains(t, ne.SanitizeStruct().Name, "<")
+		assert.Contains(t, ne.ToStringBeautiful(0), "'status': 'occurred'")
+		assert.Equal(t, ne.GeneratingDataForIndexing()["status"], "occurred")
+	})
+}
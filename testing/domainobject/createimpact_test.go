@@ -0,0 +1,141 @@
+package domainobject
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpactDomainObjectsSTIX(t *testing.T) {
+	ni := methodstixobjects.NewImpactDomainObjectsSTIX()
+
+	assert.Equal(t, ni.GetType(), "impact")
+	ni.SetValueID("impact--7f1e4c2a-5b3d-4e6f-8a9b-0c1d2e3f4a5b")
+	assert.True(t, strings.HasPrefix(ni.GetID(), "impact--"))
+	_, err := ni.Get()
+	assert.Error(t, err)
+
+	ni.SetValueImpactCategory("confidentiality")
+	_, err = ni.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, ni.GetImpactCategory(), stixhelpers.EnumTypeSTIX("confidentiality"))
+
+	ni.SetAnyDescription("<b>leak</b>")
+	assert.Equal(t, ni.GetDescription(), "<b>leak</b>")
+	ni.SetAnyCriticality(80)
+	assert.Equal(t, ni.GetCriticality(), 80)
+	ni.SetValueRecoverability("regular")
+	ni.SetValueImpactedEntityCounts("user-account", 25)
+	assert.Equal(t, ni.GetImpactedEntityCounts(), map[string]int{"user-account": 25})
+	ni.SetValueImpactedRefs("identity--b38dfe21-7477-40d1-aa90-5c8671ce51ca")
+	ni.SetValueSupersededByRef("impact--2a0c6b1d-3e4f-4a5b-9c6d-7e8f9a0b1c2d")
+	assert.NoError(t, ni.SetValueStartTime("2024-02-14T12:03:06+00:00"))
+	assert.True(t, ni.ValidateStruct())
+
+	//расширение категории последствий
+	_, ok := ni.GetImpactCategoryExtension()
+	assert.False(t, ok)
+	assert.Error(t, ni.SetValueImpactCategoryExtension(domainobjectsstix.AvailabilityImpactExtensionSTIX{AvailabilityImpact: 50}))
+	assert.Error(t, ni.SetValueImpactCategoryExtension(map[string]interface{}{"loss_type": "confirmed-loss"}))
+	assert.NoError(t, ni.SetValueImpactCategoryExtension(domainobjectsstix.ConfidentialityImpactExtensionSTIX{
+		InformationType: "credentials-user",
+		LossType:        "confirmed-loss",
+		RecordCount:     25,
+	}))
+	ext, ok := ni.GetImpactCategoryExtension()
+	assert.True(t, ok)
+	assert.Equal(t, ext.(domainobjectsstix.ConfidentialityImpactExtensionSTIX).RecordCount, 25)
+	assert.True(t, ni.ValidateStruct())
+
+	t.Run("Декодирование", func(t *testing.T) {
+		b, err := ni.EncodeJSON(nil)
+		assert.NoError(t, err)
+
+		raw := json.RawMessage(*b)
+		obj, err := domainobjectsstix.ImpactDomainObjectsSTIX{}.DecodeJSON(&raw)
+		assert.NoError(t, err)
+
+		impact := obj.(domainobjectsstix.ImpactDomainObjectsSTIX)
+		assert.Equal(t, impact.GetImpactedEntityCounts(), ni.GetImpactedEntityCounts())
+		assert.True(t, impact.ValidateStruct())
+
+		ext, ok := impact.GetImpactCategoryExtension()
+		assert.True(t, ok)
+		assert.Equal(t, ext, domainobjectsstix.ConfidentialityImpactExtensionSTIX{InformationType: "credentials-user", LossType: "confirmed-loss", RecordCount: 25})
+
+		//расширения категорий декодируются в зарегистрированные типы
+		raw = json.RawMessage(`{
+			"type": "impact",
+			"spec_version": "2.1",
+			"id": "impact--7f1e4c2a-5b3d-4e6f-8a9b-0c1d2e3f4a5b",
+			"created": "2024-02-14T12:03:06Z",
+			"modified": "2024-02-14T12:03:06Z",
+			"impact_category": "monetary",
+			"extensions": {
+				"` + domainobjectsstix.ImpactExtensionDefinitionID + `": {"extension_type": "new-sdo"},
+				"monetary-ext": {"variety": "ransom-payment", "currency": "USD", "min_amount": 1000, "max_amount": 5000}
+			}
+		}`)
+		obj, err = domainobjectsstix.ImpactDomainObjectsSTIX{}.DecodeJSON(&raw)
+		assert.NoError(t, err)
+
+		impact = obj.(domainobjectsstix.ImpactDomainObjectsSTIX)
+		ext, ok = impact.GetImpactCategoryExtension()
+		assert.True(t, ok)
+		assert.Equal(t, ext.(domainobjectsstix.MonetaryImpactExtensionSTIX).MaxAmount, 5000.0)
+		assert.True(t, impact.ValidateStruct())
+	})
+
+	t.Run("Проверка", func(t *testing.T) {
+		invalid := *ni
+		invalid.ImpactCategory = "reputation"
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid = *ni
+		invalid.Criticality = 101
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid = *ni
+		invalid.ImpactedEntityCounts = map[string]int{"user-account": -1}
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid = *ni
+		invalid.SupersededByRef = "task--3b6a4b5e-7f2d-4ad1-9a7b-0bbd6cf5f1a2"
+		assert.False(t, invalid.ValidateStruct())
+
+		//некорректное содержимое расширения категории последствий
+		invalid = *ni
+		invalid.Extensions = stixhelpers.ExtensionsTypeSTIX{}
+		invalid.SetValueExtensions(domainobjectsstix.ImpactExtensionDefinitionID, domainobjectsstix.NewSDOExtensionSTIX{ExtensionType: stixhelpers.ExtensionTypeNewSDO})
+		invalid.SetValueExtensions("confidentiality-ext", map[string]interface{}{"loss_type": "stolen"})
+		assert.False(t, invalid.ValidateStruct())
+
+		//расширение другой категории последствий
+		invalid = *ni
+		invalid.Extensions = stixhelpers.ExtensionsTypeSTIX{}
+		invalid.SetValueExtensions(domainobjectsstix.ImpactExtensionDefinitionID, domainobjectsstix.NewSDOExtensionSTIX{ExtensionType: stixhelpers.ExtensionTypeNewSDO})
+		invalid.SetValueExtensions("availability-ext", domainobjectsstix.AvailabilityImpactExtensionSTIX{AvailabilityImpact: 50})
+		assert.False(t, invalid.ValidateStruct())
+
+		for _, v := range []interface{ ValidateStruct() bool }{
+			domainobjectsstix.AvailabilityImpactExtensionSTIX{AvailabilityImpact: 101},
+			domainobjectsstix.ExternalImpactExtensionSTIX{},
+			domainobjectsstix.IntegrityImpactExtensionSTIX{Alteration: "deleted"},
+			domainobjectsstix.MonetaryImpactExtensionSTIX{Variety: "fine", MinAmount: 10, MaxAmount: 5},
+			domainobjectsstix.PhysicalImpactExtensionSTIX{ImpactType: "burned"},
+			domainobjectsstix.TraceabilityImpactExtensionSTIX{},
+		} {
+			assert.False(t, v.ValidateStruct())
+		}
+	})
+
+	t.Run("Очистка и вывод", func(t *testing.T) {
+		assert.NotContains(t, ni.SanitizeStruct().Description, "<")
+		assert.Contains(t, ni.ToStringBeautiful(0), "'impact_category': 'confidentiality'")
+	})
+}
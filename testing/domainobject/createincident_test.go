@@ -0,0 +1,105 @@
+package domainobject
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncidentDomainObjectsSTIX(t *testing.T) {
+	ni := methodstixobjects.NewIncidentDomainObjectsSTIX()
+
+	assert.Equal(t, ni.GetType(), "incident")
+	ni.SetValueID("incident--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f")
+	assert.True(t, strings.HasPrefix(ni.GetID(), "incident--"))
+	_, err := ni.Get()
+	assert.Error(t, err)
+
+	ni.SetAnyName("incident name")
+	_, err = ni.Get()
+	assert.NoError(t, err)
+	ni.SetValueName("phishing incident")
+	assert.Equal(t, ni.GetName(), "phishing incident")
+
+	ni.SetAnyDescription("example_description")
+	assert.Equal(t, ni.GetDescription(), "example_description")
+	ni.SetValueDescription("exm_description")
+	assert.Equal(t, ni.GetDescription(), "exm_description")
+
+	ni.SetValueKillChainPhases(stixhelpers.KillChainPhasesTypeElementSTIX{KillChainName: "mitre-attack", PhaseName: "initial-access"})
+	assert.Equal(t, len(ni.GetKillChainPhases()), 1)
+
+	assert.True(t, ni.ValidateStruct())
+
+	_, ok := ni.GetIncidentCoreExtension()
+	assert.False(t, ok)
+
+	ni.SetValueIncidentCoreExtension(domainobjectsstix.IncidentCoreExtensionSTIX{
+		Determination:       "successful-attempt",
+		InvestigationStatus: "open",
+		Criticality:         75,
+		IncidentTypes:       []stixhelpers.OpenVocabTypeSTIX{"phishing"},
+		Scores:              []domainobjectsstix.IncidentScoreTypeSTIX{{Name: "<cvss>", Value: 7.5}},
+		TaskRefs:            []stixhelpers.IdentifierTypeSTIX{"task--3b6a4b5e-7f2d-4ad1-9a7b-0bbd6cf5f1a2"},
+		EventRefs:           []stixhelpers.IdentifierTypeSTIX{"event--1d0c3c4e-2a3b-4c5d-8e9f-0a1b2c3d4e5f"},
+	})
+
+	ext, ok := ni.GetIncidentCoreExtension()
+	assert.True(t, ok)
+	assert.Equal(t, ext.ExtensionType, stixhelpers.ExtensionTypePropertyExtension)
+	assert.Equal(t, ext.InvestigationStatus, stixhelpers.EnumTypeSTIX("open"))
+	assert.True(t, ni.ValidateStruct())
+
+	t.Run("Декодирование расширения", func(t *testing.T) {
+		b, err := ni.EncodeJSON(nil)
+		assert.NoError(t, err)
+
+		raw := json.RawMessage(*b)
+		obj, err := domainobjectsstix.IncidentDomainObjectsSTIX{}.DecodeJSON(&raw)
+		assert.NoError(t, err)
+
+		incident := obj.(domainobjectsstix.IncidentDomainObjectsSTIX)
+		ext, ok := incident.GetIncidentCoreExtension()
+		assert.True(t, ok)
+		assert.Equal(t, ext.Determination, stixhelpers.EnumTypeSTIX("successful-attempt"))
+		assert.Equal(t, ext.TaskRefs, []stixhelpers.IdentifierTypeSTIX{"task--3b6a4b5e-7f2d-4ad1-9a7b-0bbd6cf5f1a2"})
+		assert.True(t, incident.ValidateStruct())
+	})
+
+	t.Run("Проверка расширения", func(t *testing.T) {
+		invalid := *ni
+		invalid.Extensions = stixhelpers.ExtensionsTypeSTIX{}
+
+		invalid.SetValueIncidentCoreExtension(domainobjectsstix.IncidentCoreExtensionSTIX{Determination: "unknown", InvestigationStatus: "open"})
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid.SetValueIncidentCoreExtension(domainobjectsstix.IncidentCoreExtensionSTIX{Determination: "blocked", InvestigationStatus: "open", Criticality: 101})
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid.SetValueIncidentCoreExtension(domainobjectsstix.IncidentCoreExtensionSTIX{
+			Determination:       "blocked",
+			InvestigationStatus: "closed",
+			ImpactRefs:          []stixhelpers.IdentifierTypeSTIX{"task--3b6a4b5e-7f2d-4ad1-9a7b-0bbd6cf5f1a2"},
+		})
+		assert.False(t, invalid.ValidateStruct())
+
+		//признак объекта определяемого расширением не допускается в объекте "incident"
+		invalid.Extensions = stixhelpers.ExtensionsTypeSTIX{}
+		invalid.SetValueExtensions(domainobjectsstix.IncidentCoreExtensionDefinitionID, domainobjectsstix.IncidentCoreExtensionSTIX{ExtensionType: stixhelpers.ExtensionTypeNewSDO})
+		assert.False(t, invalid.ValidateStruct())
+	})
+
+	t.Run("Очистка и вывод", func(t *testing.T) {
+		sanitized := ni.SanitizeStruct()
+		ext, ok := sanitized.GetIncidentCoreExtension()
+		assert.True(t, ok)
+		assert.NotContains(t, ext.Scores[0].Name, "<")
+
+		assert.Contains(t, ni.ToStringBeautiful(0), "'investigation_status': 'open'")
+	})
+}
@@ -0,0 +1,85 @@
+package domainobject
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskDomainObjectsSTIX(t *testing.T) {
+	nt := methodstixobjects.NewTaskDomainObjectsSTIX()
+
+	assert.Equal(t, nt.GetType(), "task")
+	nt.SetValueID("task--3b6a4b5e-7f2d-4ad1-9a7b-0bbd6cf5f1a2")
+	assert.True(t, strings.HasPrefix(nt.GetID(), "task--"))
+	_, err := nt.Get()
+	assert.Error(t, err)
+
+	nt.SetAnyName("block <sender>")
+	_, err = nt.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, nt.GetName(), "block <sender>")
+
+	nt.SetValueDescription("task description")
+	nt.SetValueTaskTypes("containment")
+	assert.Equal(t, len(nt.GetTaskTypes()), 1)
+	nt.SetValueOutcome("successful")
+	assert.Equal(t, nt.GetOutcome(), stixhelpers.EnumTypeSTIX("successful"))
+	nt.SetAnyPriority(60)
+	assert.Equal(t, nt.GetPriority(), 60)
+	nt.SetValueOwner("identity--b38dfe21-7477-40d1-aa90-5c8671ce51ca")
+	nt.SetValueImpactedEntityCounts("email-addr", 3)
+	assert.NoError(t, nt.SetAnyStartTime("2024-02-14T12:03:06+00:00"))
+	nt.SetValueChangedObjects(domainobjectsstix.StateChangeTypeSTIX{
+		InitialRef: "user-account--0d5b424b-93b8-5cd8-ac36-306e1789d63c",
+		ResultRef:  "user-account--9a7e1c3b-2d4f-5a6b-8c9d-0e1f2a3b4c5d",
+	})
+	assert.True(t, nt.ValidateStruct())
+
+	t.Run("Декодирование", func(t *testing.T) {
+		b, err := nt.EncodeJSON(nil)
+		assert.NoError(t, err)
+
+		raw := json.RawMessage(*b)
+		obj, err := domainobjectsstix.TaskDomainObjectsSTIX{}.DecodeJSON(&raw)
+		assert.NoError(t, err)
+
+		task := obj.(domainobjectsstix.TaskDomainObjectsSTIX)
+		assert.Equal(t, task.GetChangedObjects(), nt.GetChangedObjects())
+		assert.True(t, task.ValidateStruct())
+	})
+
+	t.Run("Проверка", func(t *testing.T) {
+		invalid := *nt
+		invalid.Name = ""
+		assert.False(t, invalid.ValidateStruct())
+
+		//признак "new-sdo" расширения объекта "event" не подходит для объекта "task"
+		invalid = *nt
+		invalid.Extensions = stixhelpers.ExtensionsTypeSTIX{}
+		invalid.SetValueExtensions(domainobjectsstix.EventExtensionDefinitionID, domainobjectsstix.NewSDOExtensionSTIX{ExtensionType: stixhelpers.ExtensionTypeNewSDO})
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid = *nt
+		invalid.Outcome = "done"
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid = *nt
+		invalid.Owner = "malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b"
+		assert.False(t, invalid.ValidateStruct())
+
+		invalid = *nt
+		invalid.ChangedObjects = []domainobjectsstix.StateChangeTypeSTIX{{}}
+		assert.False(t, invalid.ValidateStruct())
+	})
+
+	t.Run("Очистка и вывод", func(t *testing.T) {
+		assert.NotContains(t, nt.SanitizeStruct().Name, "<")
+		assert.Contains(t, nt.ToStringBeautiful(0), "'outcome': 'successful'")
+	})
+}
@@ -0,0 +1,288 @@
+package elasticexport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/elasticexport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	indicatorID = "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	fileID      = "file--5a27d487-c542-5f97-a131-a8866b477b46"
+	ipv4ID      = "ipv4-addr--ff26c055-6336-5bc5-b98d-13d6226742dd"
+)
+
+func getObjects() []interface{} {
+	indicator := methodstixobjects.NewIndicatorDomainObjectsSTIX()
+	indicator.SetValueID(indicatorID)
+	indicator.SetValueName("Malicious site")
+	indicator.SetValuePattern("[ipv4-addr:value = '198.51.100.3']")
+	indicator.SetValueCreated("2023-01-01T10:00:00+03:00")
+	indicator.SetValueModified("2023-01-01T10:00:00.123+03:00")
+
+	file := methodstixobjects.NewFileCyberObservableObjectSTIX()
+	file.SetValueID(fileID)
+	file.SetValueName("beacon.exe")
+	file.SetValueHashes(stixhelpers.HashesTypeSTIX{"SHA-256": "4bac27393bdd9777ce02453256c5577cd02275510b2227f473d03f533924f877"})
+
+	ipv4 := methodstixobjects.NewIPv4AddressCyberObservableObjectSTIX()
+	ipv4.SetValueID(ipv4ID)
+	ipv4.SetValueValue("198.51.100.3")
+
+	return []interface{}{
+		*indicator,
+		*file,
+		*ipv4,
+		json.RawMessage(`{"type": "x-custom-object", "id": "x-custom-object--1", "created": "2023-01-01T00:00:00Z", "modified": "2023-01-02T00:00:00Z", "title": "notes", "x_empty": ""}`),
+	}
+}
+
+func parseBulk(r io.Reader) ([]map[string]map[string]string, []map[string]interface{}, error) {
+	actions := []map[string]map[string]string{}
+	docs := []map[string]interface{}{}
+
+	scanner := bufio.NewScanner(r)
+	for i := 0; scanner.Scan(); i++ {
+		if i%2 == 0 {
+			action := map[string]map[string]string{}
+			if err := json.Unmarshal(scanner.Bytes(), &action); err != nil {
+				return nil, nil, err
+			}
+			actions = append(actions, action)
+
+			continue
+		}
+
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			return nil, nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	return actions, docs, scanner.Err()
+}
+
+func readBulk(t *testing.T, r io.Reader) ([]map[string]map[string]string, []map[string]interface{}) {
+	actions, docs, err := parseBulk(r)
+	require.NoError(t, err)
+	require.Len(t, docs, len(actions))
+
+	return actions, docs
+}
+
+func getProperties(t *testing.T, objType string) map[string]interface{} {
+	mapping, err := elasticexport.GetMapping(objType)
+	require.NoError(t, err)
+
+	return mapping["properties"].(map[string]interface{})
+}
+
+func TestWriteBulk(t *testing.T) {
+	buf := bytes.Buffer{}
+	assert.NoError(t, elasticexport.WriteBulk(&buf, getObjects(), elasticexport.Options{}))
+	assert.True(t, strings.HasSuffix(buf.String(), "}\n"))
+
+	actions, docs := readBulk(t, &buf)
+	require.Len(t, actions, 4)
+	require.Len(t, docs, 4)
+
+	//идентификатор документа содержит время изменения в UTC, объекты без времени изменения индексируются по идентификатору
+	assert.Equal(t, actions[0]["index"], map[string]string{"_index": "stix-indicator", "_id": indicatorID + "_2023-01-01T07:00:00.123Z"})
+	assert.Equal(t, actions[1]["index"], map[string]string{"_index": "stix-file", "_id": fileID})
+	assert.Equal(t, actions[3]["index"], map[string]string{"_index": "stix-x-custom-object", "_id": "x-custom-object--1_2023-01-02T00:00:00Z"})
+
+	//пустые значения в документ не включаются
+	assert.Equal(t, docs[0]["name"], "Malicious site")
+	assert.NotContains(t, docs[0], "valid_until")
+	assert.NotContains(t, docs[0], "labels")
+	assert.Equal(t, docs[1]["hashes"], map[string]interface{}{"SHA-256": "4bac27393bdd9777ce02453256c5577cd02275510b2227f473d03f533924f877"})
+	assert.Equal(t, docs[3], map[string]interface{}{
+		"type": "x-custom-object", "id": "x-custom-object--1", "created": "2023-01-01T00:00:00Z", "modified": "2023-01-02T00:00:00Z", "title": "notes",
+	})
+
+	buf.Reset()
+	assert.NoError(t, elasticexport.WriteBulk(&buf, getObjects()[2:3], elasticexport.Options{IndexPrefix: "cti-"}))
+	actions, _ = readBulk(t, &buf)
+	assert.Equal(t, actions[0]["index"]["_index"], "cti-ipv4-addr")
+
+	assert.Error(t, elasticexport.WriteBulk(&buf, []interface{}{json.RawMessage(`{"type": "x-custom-object"}`)}, elasticexport.Options{}))
+}
+
+func TestMapping(t *testing.T) {
+	properties := getProperties(t, "indicator")
+	assert.Equal(t, properties["created"], map[string]interface{}{"type": "date"})
+	assert.Equal(t, properties["valid_until"], map[string]interface{}{"type": "date"})
+	assert.Equal(t, properties["description"], map[string]interface{}{"type": "text"})
+	assert.Equal(t, properties["pattern"], map[string]interface{}{"type": "keyword"})
+	assert.Equal(t, properties["labels"], map[string]interface{}{"type": "keyword"})
+	assert.Equal(t, properties["confidence"], map[string]interface{}{"type": "long"})
+	assert.Equal(t, properties["revoked"], map[string]interface{}{"type": "boolean"})
+	assert.Equal(t, properties["name"].(map[string]interface{})["type"], "keyword")
+
+	//свойства вложенных структур описываются рекурсивно
+	assert.Equal(t, properties["kill_chain_phases"], map[string]interface{}{"properties": map[string]interface{}{
+		"kill_chain_name": map[string]interface{}{"type": "keyword"},
+		"phase_name":      map[string]interface{}{"type": "keyword"},
+	}})
+	references := properties["external_references"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, references["description"], map[string]interface{}{"type": "text"})
+	assert.Equal(t, references["hashes"], map[string]interface{}{"type": "object"})
+
+	assert.Equal(t, getProperties(t, "ipv4-addr")["value"], map[string]interface{}{"type": "ip", "ignore_malformed": true})
+	assert.Equal(t, getProperties(t, "ipv6-addr")["value"].(map[string]interface{})["type"], "ip")
+	assert.Equal(t, getProperties(t, "domain-name")["value"], map[string]interface{}{"type": "keyword"})
+	assert.Equal(t, getProperties(t, "file")["hashes"], map[string]interface{}{"type": "object"})
+	assert.Equal(t, getProperties(t, "file")["mtime"], map[string]interface{}{"type": "date"})
+	assert.Equal(t, getProperties(t, "file")["size"], map[string]interface{}{"type": "unsigned_long"})
+	assert.Equal(t, getProperties(t, "network-traffic")["start"], map[string]interface{}{"type": "date"})
+
+	//значения хешей на любом уровне вложенности отображаются в тип keyword
+	mapping, err := elasticexport.GetMapping("file")
+	assert.NoError(t, err)
+	assert.Contains(t, mapping["dynamic_templates"], map[string]interface{}{"hashes": map[string]interface{}{
+		"path_match": "*hashes.*",
+		"mapping":    map[string]interface{}{"type": "keyword"},
+	}})
+
+	_, err = elasticexport.GetMapping("x-custom-object")
+	assert.Error(t, err)
+
+	templates := elasticexport.GetIndexTemplates(elasticexport.Options{})
+	assert.Contains(t, templates, "stix-malware-analysis")
+	assert.Equal(t, templates["stix-indicator"]["index_patterns"], []string{"stix-indicator"})
+
+	//шаблоны сериализуются в JSON
+	_, err = json.Marshal(templates)
+	assert.NoError(t, err)
+}
+
+func TestMappingTimestamp(t *testing.T) {
+	for _, objType := range []string{"event", "task", "impact"} {
+		properties := getProperties(t, objType)
+		assert.Equal(t, properties["start_time"], map[string]interface{}{"type": "date"}, objType)
+		assert.Equal(t, properties["end_time"], map[string]interface{}{"type": "date"}, objType)
+		assert.Equal(t, properties["created"], map[string]interface{}{"type": "date"}, objType)
+	}
+
+	//точность времени не является временем
+	assert.Equal(t, getProperties(t, "event")["start_time_fidelity"], map[string]interface{}{"type": "keyword"})
+	assert.Equal(t, getProperties(t, "task")["outcome"], map[string]interface{}{"type": "keyword"})
+
+	//время во вложенных структурах
+	assert.Equal(t, getProperties(t, "sighting")["first_seen"], map[string]interface{}{"type": "date"})
+	assert.Equal(t, getProperties(t, "user-account")["account_last_login"], map[string]interface{}{"type": "date"})
+	assert.Equal(t, getProperties(t, "user-account")["account_login"], map[string]interface{}{"type": "keyword"})
+}
+
+// stubServer заглушка Elasticsearch, сохраняющая полученные запросы
+type stubServer struct {
+	mu        sync.Mutex
+	templates map[string]map[string]interface{}
+	bulk      []string
+	auth      []string
+}
+
+func (s *stubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, password, _ := r.BasicAuth()
+	s.auth = append(s.auth, user+":"+password)
+
+	b, _ := io.ReadAll(r.Body)
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/_index_template/"):
+		tmpl := map[string]interface{}{}
+		if err := json.Unmarshal(b, &tmpl); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+		s.templates[strings.TrimPrefix(r.URL.Path, "/_index_template/")] = tmpl
+		w.Write([]byte(`{"acknowledged": true}`))
+
+	case r.Method == http.MethodPost && r.URL.Path == "/_bulk":
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			w.Write([]byte(`{"error": {"type": "illegal_argument_exception", "reason": "wrong content type"}, "status": 406}`))
+
+			return
+		}
+		actions, docs, err := parseBulk(bytes.NewReader(b))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+		s.bulk = append(s.bulk, string(b))
+
+		items := []interface{}{}
+		for i, action := range actions {
+			item := map[string]interface{}{"_index": action["index"]["_index"], "_id": action["index"]["_id"], "status": 201}
+			//документ с недопустимым значением IP-адреса отклоняется
+			if value, ok := docs[i]["value"].(string); ok && value == "not-an-ip" {
+				item["status"] = 400
+				item["error"] = map[string]interface{}{"type": "mapper_parsing_exception", "reason": "failed to parse field [value]"}
+			}
+			items = append(items, map[string]interface{}{"index": item})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"took": 1, "errors": false, "items": items})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"type": "resource_not_found_exception", "reason": "not found"}, "status": 404}`))
+	}
+}
+
+func TestClient(t *testing.T) {
+	stub := &stubServer{templates: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	client, err := elasticexport.NewClient(server.URL+"/", elasticexport.ClientOptions{Username: "elastic", Password: "secret"})
+	require.NoError(t, err)
+
+	assert.NoError(t, client.PutIndexTemplates(context.Background()))
+	assert.Len(t, stub.templates, len(elasticexport.GetIndexTemplates(elasticexport.Options{})))
+	assert.Contains(t, stub.templates, "stix-indicator")
+	assert.Equal(t, stub.auth[0], "elastic:secret")
+
+	result, err := client.Bulk(context.Background(), getObjects())
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 4)
+	assert.Len(t, result.GetFailed(), 0)
+	assert.Equal(t, result.Items[1], elasticexport.BulkItem{Index: "stix-file", ID: fileID, Status: 201})
+	assert.Len(t, stub.bulk, 1)
+
+	//ошибки индексации отдельных документов возвращаются вместе с результатом
+	ipv4 := methodstixobjects.NewIPv4AddressCyberObservableObjectSTIX()
+	ipv4.SetValueID(ipv4ID)
+	ipv4.SetValueValue("not-an-ip")
+
+	result, err = client.Bulk(context.Background(), []interface{}{getObjects()[0], *ipv4})
+	assert.ErrorContains(t, err, "1 of 2 documents were not indexed")
+	assert.Equal(t, result.GetFailed(), []elasticexport.BulkItem{{Index: "stix-ipv4-addr", ID: ipv4ID, Status: 400, Error: "mapper_parsing_exception: failed to parse field [value]"}})
+
+	//ошибки запроса содержат описание из ответа Elasticsearch
+	client, err = elasticexport.NewClient(server.URL+"/unknown", elasticexport.ClientOptions{})
+	require.NoError(t, err)
+	_, err = client.Bulk(context.Background(), getObjects())
+	assert.ErrorContains(t, err, "status 404: resource_not_found_exception: not found")
+
+	_, err = elasticexport.NewClient("localhost:9200", elasticexport.ClientOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package embeddedrefs
+
+import (
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/relationshipobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/embeddedrefs"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	domainID   = "domain-name--3c10e93f-798e-5a26-a0c1-08156efab7f5"
+	ipv4ID     = "ipv4-addr--ff26c055-6336-5bc5-b98d-13d6226742dd"
+	ipv4TwoID  = "ipv4-addr--4d22aae0-2bf9-5427-8819-e4f6abf20a53"
+	identityID = "identity--f431f809-377b-45e0-aa1c-6a4751cae5ff"
+)
+
+func getRelationships(list []interface{}) []relationshipobjectsstix.RelationshipObjectSTIX {
+	result := []relationshipobjectsstix.RelationshipObjectSTIX{}
+	for _, v := range list {
+		if r, ok := v.(relationshipobjectsstix.RelationshipObjectSTIX); ok {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+func TestGetUUIDv5(t *testing.T) {
+	id, err := commonlibs.GetUUIDv5(commonlibs.STIXNamespaceUUID, "a|b|c")
+	assert.NoError(t, err)
+	assert.Equal(t, id, "e6de6c8c-1c01-580a-ae7f-da56c9c19a40")
+
+	_, err = commonlibs.GetUUIDv5("invalid", "a")
+	assert.Error(t, err)
+}
+
+func TestEmbeddedRefs(t *testing.T) {
+	relType, ok := embeddedrefs.GetRelationshipType("domain-name", "resolves_to_refs")
+	assert.True(t, ok)
+	assert.Equal(t, relType, "resolves-to")
+
+	relType, ok = embeddedrefs.GetRelationshipType("email-addr", "belongs_to_ref")
+	assert.True(t, ok)
+	assert.Equal(t, relType, "belongs-to")
+
+	_, ok = embeddedrefs.GetRelationshipType("domain-name", "object_marking_refs")
+	assert.False(t, ok)
+
+	dn := methodstixobjects.NewDomainNameCyberObservableObjectSTIX()
+	dn.SetValueID(domainID)
+	dn.SetValueValue("example.com")
+	dn.SetValueResolvesToRefs(ipv4ID)
+	dn.SetValueResolvesToRefs(ipv4TwoID)
+
+	ip := methodstixobjects.NewIPv4AddressCyberObservableObjectSTIX()
+	ip.SetValueID(ipv4ID)
+	ip.SetValueValue("198.51.100.3")
+
+	objects := []interface{}{*dn, *ip}
+
+	t.Run("Преобразование в объекты relationship", func(t *testing.T) {
+		list, err := embeddedrefs.ToRelationships(objects, embeddedrefs.Options{})
+		assert.NoError(t, err)
+		assert.Len(t, list, 4)
+
+		relationships := getRelationships(list)
+		assert.Len(t, relationships, 2)
+		assert.Equal(t, relationships[0].GetRelationshipType(), "resolves-to")
+		assert.Equal(t, string(relationships[0].GetSourceRef()), domainID)
+		assert.Equal(t, string(relationships[0].GetTargetRef()), ipv4ID)
+		assert.Equal(t, relationships[0].GetID(), embeddedrefs.GetRelationshipID(domainID, "resolves-to", ipv4ID))
+		assert.True(t, relationships[0].ValidateStruct())
+
+		//идентификаторы детерминированы
+		again, err := embeddedrefs.ToRelationships(objects, embeddedrefs.Options{})
+		assert.NoError(t, err)
+		assert.Equal(t, getRelationships(again)[1].GetID(), relationships[1].GetID())
+
+		//уже существующие объекты relationship повторно не создаются
+		list, err = embeddedrefs.ToRelationships(list, embeddedrefs.Options{})
+		assert.NoError(t, err)
+		assert.Len(t, list, 4)
+
+		//удаление встроенных ссылок
+		list, err = embeddedrefs.ToRelationships(objects, embeddedrefs.Options{RemoveEmbeddedRefs: true})
+		assert.NoError(t, err)
+		newDn, ok := list[0].(cyberobservableobjectsstix.DomainNameCyberObservableObjectSTIX)
+		assert.True(t, ok)
+		assert.Len(t, newDn.GetResolvesToRefs(), 0)
+		assert.Len(t, dn.GetResolvesToRefs(), 2)
+	})
+
+	t.Run("Обязательные встроенные ссылки", func(t *testing.T) {
+		report := methodstixobjects.NewReportDomainObjectsSTIX()
+		report.SetValueID("report--84e4d88f-44ea-4bcd-bbf3-b2c1c320bcb3")
+		report.SetValueName("suspicious domain")
+		assert.NoError(t, report.SetValuePublished("2024-02-14T12:03:06+00:00"))
+		report.SetValueObjectRefs([]stixhelpers.IdentifierTypeSTIX{domainID, ipv4ID})
+		report.SetValueCreatedByRef(identityID)
+		assert.True(t, report.ValidateStruct())
+
+		grouping := methodstixobjects.NewGroupingDomainObjectsSTIX()
+		grouping.SetValueID("grouping--84e4d88f-44ea-4bcd-bbf3-b2c1c320bcb3")
+		grouping.SetValueContext("suspicious-activity")
+		grouping.SetValueObjectRefs(domainID)
+		assert.True(t, grouping.ValidateStruct())
+
+		list, err := embeddedrefs.ToRelationships([]interface{}{*report, *grouping, *dn, *ip}, embeddedrefs.Options{RemoveEmbeddedRefs: true})
+		assert.NoError(t, err)
+		//created-by, два object для "report", один object для "grouping" и два resolves-to
+		assert.Len(t, getRelationships(list), 6)
+
+		//свойство object_refs обязательное и сохраняется, остальные встроенные ссылки удаляются
+		newReport, ok := list[0].(domainobjectsstix.ReportDomainObjectsSTIX)
+		assert.True(t, ok)
+		assert.Equal(t, newReport.GetObjectRefs(), report.GetObjectRefs())
+		assert.Equal(t, newReport.GetCreatedByRef(), stixhelpers.IdentifierTypeSTIX(""))
+		assert.True(t, newReport.ValidateStruct())
+
+		newGrouping, ok := list[1].(domainobjectsstix.GroupingDomainObjectsSTIX)
+		assert.True(t, ok)
+		assert.Equal(t, newGrouping.GetObjectRefs(), grouping.GetObjectRefs())
+		assert.True(t, newGrouping.ValidateStruct())
+
+		//обратное преобразование не дублирует ссылки
+		list, err = embeddedrefs.FromRelationships(list)
+		assert.NoError(t, err)
+		assert.Len(t, list, 4)
+		newReport, ok = list[0].(domainobjectsstix.ReportDomainObjectsSTIX)
+		assert.True(t, ok)
+		assert.Equal(t, newReport.GetObjectRefs(), report.GetObjectRefs())
+		assert.Equal(t, newReport.GetCreatedByRef(), stixhelpers.IdentifierTypeSTIX(identityID))
+	})
+
+	t.Run("Обратное преобразование", func(t *testing.T) {
+		list, err := embeddedrefs.ToRelationships(objects, embeddedrefs.Options{RemoveEmbeddedRefs: true})
+		assert.NoError(t, err)
+
+		list, err = embeddedrefs.FromRelationships(list)
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+
+		newDn, ok := list[0].(cyberobservableobjectsstix.DomainNameCyberObservableObjectSTIX)
+		assert.True(t, ok)
+		assert.ElementsMatch(t, newDn.GetResolvesToRefs(), dn.GetResolvesToRefs())
+	})
+
+	t.Run("Обратное преобразование невозможно", func(t *testing.T) {
+		withDescription := methodstixobjects.NewRelationshipObjectSTIX()
+		withDescription.SetValueID("relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad")
+		withDescription.SetValueRelationshipType("belongs-to")
+		withDescription.SetValueSourceRef("email-addr--2d77a846-6264-5d51-b586-e43822ea1ea3")
+		withDescription.SetValueTargetRef("user-account--0d5b424b-93b8-5cd8-ac36-306e1789d63c")
+		withDescription.SetValueDescription("important")
+
+		unknownType := methodstixobjects.NewRelationshipObjectSTIX()
+		unknownType.SetValueID("relationship--3ddf3e34-0d8d-4e40-b5e3-6a3b9cc4d4f3")
+		unknownType.SetValueRelationshipType("uses")
+		unknownType.SetValueSourceRef(domainID)
+		unknownType.SetValueTargetRef(ipv4ID)
+
+		list, err := embeddedrefs.FromRelationships([]interface{}{
+			json.RawMessage(`{"type": "email-addr", "id": "email-addr--2d77a846-6264-5d51-b586-e43822ea1ea3", "value": "john@example.com"}`),
+			withDescription,
+			unknownType,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, list, 3)
+
+		//объект JSON сохраняет свой тип
+		list, err = embeddedrefs.FromRelationships([]interface{}{
+			json.RawMessage(`{"type": "email-addr", "id": "email-addr--2d77a846-6264-5d51-b586-e43822ea1ea3", "value": "john@example.com"}`),
+			json.RawMessage(`{"type": "relationship", "id": "relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad", "relationship_type": "belongs-to", "source_ref": "email-addr--2d77a846-6264-5d51-b586-e43822ea1ea3", "target_ref": "user-account--0d5b424b-93b8-5cd8-ac36-306e1789d63c"}`),
+		})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		raw, ok := list[0].(json.RawMessage)
+		assert.True(t, ok)
+		assert.Contains(t, string(raw), `"belongs_to_ref":"user-account--0d5b424b-93b8-5cd8-ac36-306e1789d63c"`)
+	})
+
+	t.Run("Объект relationship с маркером TLP", func(t *testing.T) {
+		//маркер уровня объекта был бы утерян при замене встроенной ссылкой
+		list, err := embeddedrefs.FromRelationships([]interface{}{
+			json.RawMessage(`{"type": "domain-name", "id": "` + domainID + `", "value": "example.com"}`),
+			json.RawMessage(`{"type": "relationship", "id": "relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad", "relationship_type": "resolves-to", "source_ref": "` + domainID + `", "target_ref": "` + ipv4ID + `", "object_marking_refs": ["` + string(stixhelpers.TLP20RedMarkingDefinitionID) + `"]}`),
+		})
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+
+		raw, err := json.Marshal(list[0])
+		assert.NoError(t, err)
+		assert.NotContains(t, string(raw), ipv4ID)
+	})
+
+	t.Run("Объекты relationship с дополнительными сведениями", func(t *testing.T) {
+		for _, property := range []string{
+			`"created_by_ref": "` + identityID + `"`,
+			`"confidence": 80`,
+			`"labels": ["suspicious"]`,
+			`"external_references": [{"source_name": "internal"}]`,
+			`"granular_markings": [{"marking_ref": "` + string(stixhelpers.TLP20RedMarkingDefinitionID) + `", "selectors": ["relationship_type"]}]`,
+		} {
+			list, err := embeddedrefs.FromRelationships([]interface{}{
+				json.RawMessage(`{"type": "email-addr", "id": "email-addr--2d77a846-6264-5d51-b586-e43822ea1ea3", "value": "john@example.com"}`),
+				json.RawMessage(`{"type": "relationship", "id": "relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad", "relationship_type": "belongs-to", "source_ref": "email-addr--2d77a846-6264-5d51-b586-e43822ea1ea3", "target_ref": "user-account--0d5b424b-93b8-5cd8-ac36-306e1789d63c", ` + property + `}`),
+			})
+			assert.NoError(t, err)
+			assert.Len(t, list, 2, property)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package granularmarkings
+
+import (
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/granularmarkings"
+	"github.com/stretchr/testify/assert"
+)
+
+const statementMarkingID stixhelpers.IdentifierTypeSTIX = "marking-definition--d81f86b9-975b-4c0b-875e-810c5ad45a4f"
+
+func TestParseSelector(t *testing.T) {
+	path, err := granularmarkings.ParseSelector("external_references.[0].url")
+	assert.NoError(t, err)
+	assert.Equal(t, path, []string{"external_references", "[0]", "url"})
+
+	for _, s := range []string{"", "description.", "[0].url", "external_references.[a]", "labels[0]"} {
+		_, err = granularmarkings.ParseSelector(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	nr := methodstixobjects.NewReportDomainObjectsSTIX()
+	nr.SetValueName("report name")
+	nr.SetValueDescription("report description")
+	nr.SetValueExternalReferences([]stixhelpers.ExternalReferenceTypeElementSTIX{
+		{SourceName: "source 1", URL: "http://example.com/1"},
+		{SourceName: "source 2"},
+	})
+	nr.SetValueObjectMarkingRefs([]stixhelpers.IdentifierTypeSTIX{stixhelpers.TLPGreenMarkingDefinitionID})
+	nr.SetValueGranularMarkings([]stixhelpers.GranularMarkingsTypeSTIX{
+		{MarkingRef: stixhelpers.TLPRedMarkingDefinitionID, Selectors: []string{"description", "external_references.[0]"}},
+		{MarkingRef: statementMarkingID, Selectors: []string{"external_references.[0].url"}},
+		{Lang: "de", Selectors: []string{"name"}},
+	})
+
+	r, err := granularmarkings.NewResolver(nr)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Validate())
+	assert.Equal(t, len(r.GetGranularMarkings()), 3)
+
+	assert.NoError(t, r.ValidateSelector("external_references.[1].source_name"))
+	assert.Error(t, r.ValidateSelector("external_references.[2]"))
+	assert.Error(t, r.ValidateSelector("external_references.[1].url"))
+	assert.Error(t, r.ValidateSelector("not_exist"))
+	assert.Error(t, r.ValidateSelector("name.[0]"))
+
+	m, err := r.GetEffectiveMarkings("name")
+	assert.NoError(t, err)
+	assert.Equal(t, m, []stixhelpers.IdentifierTypeSTIX{stixhelpers.TLPGreenMarkingDefinitionID})
+
+	m, err = r.GetEffectiveMarkings("description")
+	assert.NoError(t, err)
+	assert.Equal(t, m, []stixhelpers.IdentifierTypeSTIX{stixhelpers.TLPGreenMarkingDefinitionID, stixhelpers.TLPRedMarkingDefinitionID})
+
+	m, err = r.GetEffectiveMarkings("external_references.[0].url")
+	assert.NoError(t, err)
+	assert.Equal(t, m, []stixhelpers.IdentifierTypeSTIX{
+		stixhelpers.TLPGreenMarkingDefinitionID,
+		stixhelpers.TLPRedMarkingDefinitionID,
+		statementMarkingID,
+	})
+
+	m, err = r.GetEffectiveMarkings("external_references.[1].source_name")
+	assert.NoError(t, err)
+	assert.Equal(t, m, []stixhelpers.IdentifierTypeSTIX{stixhelpers.TLPGreenMarkingDefinitionID})
+
+	_, err = r.GetEffectiveMarkings("external_references.[1].url")
+	assert.Error(t, err)
+
+	all := r.GetAllEffectiveMarkings()
+	assert.Equal(t, len(all["external_references.[0].source_name"]), 2)
+	assert.Equal(t, len(all["name"]), 1)
+	_, ok := all["external_references.[1].url"]
+	assert.False(t, ok)
+
+	lang, err := r.GetEffectiveLang("name")
+	assert.NoError(t, err)
+	assert.Equal(t, lang, "de")
+
+	nr.SetValueLang("en")
+	r, err = granularmarkings.NewResolver(nr)
+	assert.NoError(t, err)
+
+	lang, err = r.GetEffectiveLang("description")
+	assert.NoError(t, err)
+	assert.Equal(t, lang, "en")
+
+	nr.SetValueGranularMarkings([]stixhelpers.GranularMarkingsTypeSTIX{
+		{MarkingRef: stixhelpers.TLPRedMarkingDefinitionID, Selectors: []string{"description", "labels.[0]"}},
+	})
+	r, err = granularmarkings.NewResolver(nr)
+	assert.NoError(t, err)
+	assert.Error(t, r.Validate())
+}
+
+func TestResolverRawJSON(t *testing.T) {
+	raw := json.RawMessage(`{
+		"type": "domain-name",
+		"spec_version": "2.1",
+		"id": "domain-name--3c10e93f-798e-5a26-a0c1-08156efab7f5",
+		"value": "example.com",
+		"object_marking_refs": ["marking-definition--55d920b0-5e8b-4f79-9ee9-91f868d9b421"],
+		"granular_markings": [
+			{"marking_ref": "marking-definition--e828b379-4e03-4974-9ac4-e53a884c97c1", "selectors": ["value"]}
+		]
+	}`)
+
+	r, err := granularmarkings.NewResolver(&raw)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Validate())
+
+	m, err := r.GetEffectiveMarkings("value")
+	assert.NoError(t, err)
+	assert.Equal(t, stixhelpers.GetMostRestrictiveTLPLevel(m), stixhelpers.TLPLevelRed)
+
+	m, err = r.GetEffectiveMarkings("id")
+	assert.NoError(t, err)
+	assert.Equal(t, stixhelpers.GetMostRestrictiveTLPLevel(m), stixhelpers.TLPLevelAmber)
+}
+
+func TestGranularMarkingsLang(t *testing.T) {
+	//теги языка RFC 5646 с подтегами региона и письменности допустимы
+	for _, lang := range []string{"en", "en-US", "zh-Hant-TW"} {
+		gm := stixhelpers.GranularMarkingsTypeSTIX{Lang: lang, Selectors: []string{"name"}}
+		assert.True(t, gm.CheckGranularMarkingsTypeSTIX(), lang)
+	}
+
+	for _, lang := range []string{"en_US", "english language", "-en"} {
+		gm := stixhelpers.GranularMarkingsTypeSTIX{Lang: lang, Selectors: []string{"name"}}
+		assert.False(t, gm.CheckGranularMarkingsTypeSTIX(), lang)
+	}
+}
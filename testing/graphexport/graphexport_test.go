@@ -0,0 +1,135 @@
+package graphexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/graphexport"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	threatActorID = "threat-actor--56f3f0db-b5d5-431c-ae56-c18f02caf500"
+	malwareID     = "malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b"
+	identityID    = "identity--f431f809-377b-45e0-aa1c-6a4751cae5ff"
+	domainID      = "domain-name--3c10e93f-798e-5a26-a0c1-08156efab7f5"
+	usesID        = "relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad"
+	sightingID    = "sighting--ee20065d-2555-424f-ad9e-0f8428623c75"
+)
+
+func getObjects() []interface{} {
+	return []interface{}{
+		json.RawMessage(`{"type": "threat-actor", "id": "` + threatActorID + `", "name": "APT \"Fancy\"", "aliases": ["a1", "a2"]}`),
+		json.RawMessage(`{"type": "malware", "id": "` + malwareID + `", "name": "Poison Ivy", "is_family": true, "created_by_ref": "` + identityID + `"}`),
+		json.RawMessage(`{"type": "identity", "id": "` + identityID + `", "name": "ACME"}`),
+		json.RawMessage(`{"type": "domain-name", "id": "` + domainID + `", "value": "example.com"}`),
+		json.RawMessage(`{"type": "relationship", "id": "` + usesID + `", "relationship_type": "uses", "source_ref": "` + threatActorID + `", "target_ref": "` + malwareID + `"}`),
+		json.RawMessage(`{"type": "sighting", "id": "` + sightingID + `", "sighting_of_ref": "` + malwareID + `", "where_sighted_refs": ["` + identityID + `"]}`),
+	}
+}
+
+func TestGraphML(t *testing.T) {
+	buf := bytes.Buffer{}
+	assert.NoError(t, graphexport.WriteGraphML(&buf, getObjects(), graphexport.Options{Attributes: []string{"aliases"}}))
+
+	type graphML struct {
+		Keys []struct {
+			ID   string `xml:"id,attr"`
+			Name string `xml:"attr.name,attr"`
+		} `xml:"key"`
+		Graph struct {
+			Nodes []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				ID     string `xml:"id,attr"`
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+
+	doc := graphML{}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Len(t, doc.Graph.Nodes, 4)
+	assert.Len(t, doc.Graph.Edges, 2)
+	assert.Equal(t, doc.Graph.Edges[0].ID, usesID)
+	assert.Equal(t, doc.Graph.Edges[1].Source, malwareID)
+	assert.Equal(t, doc.Graph.Edges[1].Target, identityID)
+
+	data := map[string]string{}
+	for _, n := range doc.Graph.Nodes {
+		if n.ID == threatActorID {
+			for _, d := range n.Data {
+				data[d.Key] = d.Value
+			}
+		}
+	}
+	assert.Equal(t, data["label"], `APT "Fancy"`)
+	assert.Equal(t, data["type"], "threat-actor")
+	assert.Equal(t, data["attr0"], "a1,a2")
+	assert.Equal(t, data["color"], graphexport.DefaultStyles["threat-actor"].Color)
+
+	buf.Reset()
+	assert.NoError(t, graphexport.WriteGraphML(&buf, getObjects(), graphexport.Options{IncludeEmbeddedRefs: true}))
+	doc = graphML{}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Len(t, doc.Graph.Edges, 3)
+}
+
+func TestDOT(t *testing.T) {
+	buf := bytes.Buffer{}
+	err := graphexport.WriteDOT(&buf, getObjects(), graphexport.Options{
+		IncludeEmbeddedRefs: true,
+		Styles:              map[string]graphexport.NodeStyle{"domain-name": {Color: "#000000", Shape: "box"}},
+	})
+	assert.NoError(t, err)
+
+	dot := buf.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph stix {"))
+	assert.Contains(t, dot, `label="APT \"Fancy\""`)
+	assert.Contains(t, dot, `"`+domainID+`" [label="example.com", shape="box", fillcolor="#000000"`)
+	assert.Contains(t, dot, `"`+threatActorID+`" -> "`+malwareID+`" [label="uses", style=solid];`)
+	assert.Contains(t, dot, `"`+malwareID+`" -> "`+identityID+`" [label="created_by_ref", style=dashed];`)
+	assert.Contains(t, dot, `"`+malwareID+`" -> "`+identityID+`" [label="sighting", style=solid];`)
+
+	assert.Error(t, graphexport.WriteDOT(&buf, []interface{}{json.RawMessage(`{"name": "without id"}`)}, graphexport.Options{}))
+}
+
+func TestCytoscapeJSON(t *testing.T) {
+	cg, err := graphexport.NewCytoscapeGraph(getObjects(), graphexport.Options{IncludeEmbeddedRefs: true, Attributes: []string{"is_family"}})
+	assert.NoError(t, err)
+	assert.Len(t, cg.Elements.Nodes, 4)
+	assert.Len(t, cg.Elements.Edges, 3)
+
+	ids := map[string]bool{}
+	for _, e := range cg.Elements.Edges {
+		ids[e.Data["id"].(string)] = true
+	}
+	assert.Len(t, ids, 3)
+
+	for _, n := range cg.Elements.Nodes {
+		if n.Data["id"] == malwareID {
+			assert.Equal(t, n.Data["is_family"], "true")
+			assert.Equal(t, n.Data["label"], "Poison Ivy")
+		}
+	}
+
+	buf := bytes.Buffer{}
+	assert.NoError(t, graphexport.WriteCytoscapeJSON(&buf, getObjects(), graphexport.Options{
+		Styles: map[string]graphexport.NodeStyle{"domain-name": {Color: "#000000", Shape: "box"}},
+	}))
+
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Contains(t, buf.String(), `"selector": "node[type = \"malware\"]"`)
+	assert.Contains(t, buf.String(), `"shape": "rectangle"`)
+}
@@ -0,0 +1,93 @@
+package metaobject
+
+import (
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkingDefinitionObjectSTIX(t *testing.T) {
+	nmd := methodstixobjects.NewMarkingDefinitionObjectSTIX()
+
+	assert.Equal(t, nmd.GetType(), "marking-definition")
+	assert.Regexp(t, `^marking-definition--[0-9a-f-]{36}$`, nmd.GetID())
+	_, err := nmd.Get()
+	assert.Error(t, err)
+
+	nmd.SetValueStatement("Copyright 2024, Example Corp")
+	_, err = nmd.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, nmd.GetDefinitionType(), "statement")
+	assert.Equal(t, nmd.GetStatement(), "Copyright 2024, Example Corp")
+
+	assert.True(t, nmd.ValidateStruct())
+
+	//идентификатор не задан
+	nmd.SetValueID("")
+	assert.False(t, nmd.ValidateStruct())
+
+	nmd.SetValueID("marking-definition--34ec1e5c-2b36-4f8e-9d44-1b3b3a4b9a10")
+	assert.True(t, nmd.ValidateStruct())
+
+	err = nmd.SetValueCreated("2024-02-05T16:11:21+00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, nmd.GetCreated(), "2024-02-05T16:11:21+00:00")
+	assert.Error(t, nmd.SetValueCreated("05.02.2024"))
+
+	//содержимое definition не соответствует definition_type
+	nmd.Definition = map[string]string{"tlp": "amber"}
+	assert.False(t, nmd.ValidateStruct())
+
+	//TLP маркер с идентификатором отличным от определенного OASIS
+	err = nmd.SetValueTLP("amber")
+	assert.NoError(t, err)
+	assert.False(t, nmd.ValidateStruct())
+	assert.Error(t, nmd.SetValueTLP("orange"))
+
+	smd := methodstixobjects.NewStatementMarkingDefinitionObjectSTIX("Internal use only")
+	assert.True(t, smd.ValidateStruct())
+	assert.Regexp(t, `^marking-definition--[0-9a-f-]{36}$`, smd.GetID())
+}
+
+func TestTLPMarkingDefinitionSTIX(t *testing.T) {
+	amber, err := stixhelpers.GetTLPMarkingDefinitionSTIX("amber")
+	assert.NoError(t, err)
+	assert.Equal(t, amber.GetID(), string(stixhelpers.TLPAmberMarkingDefinitionID))
+	assert.Equal(t, amber.GetName(), "TLP:AMBER")
+	assert.Equal(t, amber.GetTLP(), "amber")
+	assert.True(t, amber.ValidateStruct())
+
+	_, err = stixhelpers.GetTLPMarkingDefinitionSTIX("orange")
+	assert.Error(t, err)
+
+	red, ok := stixhelpers.GetTLPMarkingDefinitionByIDSTIX("marking-definition--5e57c739-391a-4eb3-b6be-7d15ca92d5ed")
+	assert.True(t, ok)
+	assert.Equal(t, red.GetTLP(), "red")
+	assert.True(t, stixhelpers.IsTLPMarkingDefinitionID(stixhelpers.TLPWhiteMarkingDefinitionID))
+	assert.False(t, stixhelpers.IsTLPMarkingDefinitionID("marking-definition--34ec1e5c-2b36-4f8e-9d44-1b3b3a4b9a10"))
+	assert.Equal(t, len(stixhelpers.GetTLPMarkingDefinitionsSTIX()), 4)
+
+	raw := json.RawMessage(`{
+		"type": "marking-definition",
+		"spec_version": "2.1",
+		"id": "marking-definition--34098fce-860f-48ae-8e50-ebd3cc5e41da",
+		"created": "2017-01-20T00:00:00.000Z",
+		"definition_type": "tlp",
+		"name": "TLP:GREEN",
+		"definition": {"tlp": "green"}
+	}`)
+	obj, err := stixhelpers.MarkingDefinitionObjectSTIX{}.DecodeJSON(&raw)
+	assert.NoError(t, err)
+
+	green, ok := obj.(stixhelpers.MarkingDefinitionObjectSTIX)
+	assert.True(t, ok)
+	assert.True(t, green.ValidateStruct())
+	assert.Equal(t, green.GetCreated(), "2017-01-20T00:00:00.000Z")
+
+	b, err := green.EncodeJSON(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(*b), `"definition":{"tlp":"green"}`)
+}
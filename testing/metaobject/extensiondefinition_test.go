@@ -0,0 +1,180 @@
+package metaobject
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/relationshipobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+const rankExtensionID = "extension-definition--d83fce45-ef58-4c6c-a3f4-1fbc32e98c6e"
+
+// rankExtension расширение добавляющее к объекту сведения о его ранге
+type rankExtension struct {
+	ExtensionType string `json:"extension_type"`
+	Rank          int    `json:"rank"`
+	Toxicity      int    `json:"toxicity"`
+}
+
+func TestExtensionDefinitionObjectSTIX(t *testing.T) {
+	ned := methodstixobjects.NewExtensionDefinitionObjectSTIX()
+	assert.Equal(t, ned.GetType(), "extension-definition")
+	assert.True(t, strings.HasPrefix(ned.GetID(), "extension-definition--"))
+
+	_, err := ned.Get()
+	assert.Error(t, err)
+	assert.False(t, ned.ValidateStruct())
+
+	ned.SetValueCreatedByRef("identity--11b76a96-5d2b-45e0-8a5a-f6994f370731")
+	ned.SetValueName("Extension Foo Bar")
+	ned.SetValueDescription("This schema adds two properties to a STIX object")
+	ned.SetValueSchema("https://www.example.com/schema-foo-1/v1/")
+	ned.SetValueVersion("1.2.1")
+	ned.SetValueExtensionTypes(stixhelpers.ExtensionTypePropertyExtension)
+
+	_, err = ned.Get()
+	assert.NoError(t, err)
+	assert.True(t, ned.ValidateStruct())
+	assert.True(t, ned.IsExtensionType(stixhelpers.ExtensionTypePropertyExtension))
+
+	//свойство ExtensionProperties допустимо только для "toplevel-property-extension"
+	ned.SetValueExtensionProperties("toxicity")
+	assert.False(t, ned.ValidateStruct())
+	ned.SetValueExtensionTypes(stixhelpers.ExtensionTypeToplevelPropertyExtension)
+	assert.True(t, ned.ValidateStruct())
+
+	ned.SetValueVersion("v1")
+	assert.False(t, ned.ValidateStruct())
+
+	ned.SetAnyExtensionTypes("new-type")
+	ned.SetValueVersion("1.0.0")
+	assert.False(t, ned.ValidateStruct())
+
+	raw := json.RawMessage(`{
+		"id": "extension-definition--d83fce45-ef58-4c6c-a3f4-1fbc32e98c6e",
+		"type": "extension-definition",
+		"spec_version": "2.1",
+		"name": "Extension Foo Bar",
+		"description": "This schema creates a new object type called my-favorite-sdo-1",
+		"created": "2014-02-20T09:16:08.989000Z",
+		"modified": "2014-02-20T09:16:08.989000Z",
+		"created_by_ref": "identity--11b76a96-5d2b-45e0-8a5a-f6994f370731",
+		"schema": "https://www.example.com/schema-my-favorite-sdo-1/v1/",
+		"version": "1.2.1",
+		"extension_types": ["new-sdo"]
+	}`)
+	obj, err := stixhelpers.ExtensionDefinitionObjectSTIX{}.DecodeJSON(&raw)
+	assert.NoError(t, err)
+
+	ed := obj.(stixhelpers.ExtensionDefinitionObjectSTIX)
+	assert.True(t, ed.ValidateStruct())
+	assert.Equal(t, ed.GetExtensionTypes(), []stixhelpers.EnumTypeSTIX{stixhelpers.ExtensionTypeNewSDO})
+}
+
+func TestPropertyExtensionsRegistry(t *testing.T) {
+	err := stixhelpers.RegisterExtensionSTIX(rankExtensionID, rankExtension{}, func(i interface{}) bool {
+		v, ok := i.(rankExtension)
+
+		return ok && v.Rank >= 0 && v.Rank <= 10
+	})
+	assert.NoError(t, err)
+	defer stixhelpers.UnregisterExtensionSTIX(rankExtensionID)
+
+	assert.True(t, stixhelpers.IsRegisteredExtensionSTIX(rankExtensionID))
+	assert.Error(t, stixhelpers.RegisterExtensionSTIX("", rankExtension{}, nil))
+
+	raw := json.RawMessage(`{
+		"type": "indicator",
+		"spec_version": "2.1",
+		"id": "indicator--e97bfccf-8970-4a3c-9cd1-5b5b97ed5d0c",
+		"created": "2014-02-20T09:16:08.989Z",
+		"modified": "2014-02-20T09:16:08.989Z",
+		"name": "File hash for Poison Ivy variant",
+		"pattern": "[file:hashes.'SHA-256' = 'ef537f25c895bfa782526529a9b63d97aa631564d5d789c2b765448c8635fb6c']",
+		"pattern_type": "stix",
+		"valid_from": "2014-02-20T09:00:00Z",
+		"extensions": {
+			"extension-definition--d83fce45-ef58-4c6c-a3f4-1fbc32e98c6e": {
+				"extension_type": "property-extension",
+				"rank": 5,
+				"toxicity": 8
+			},
+			"extension-definition--a932fcc6-e032-476c-826f-cb970a5a1ade": {
+				"extension_type": "property-extension",
+				"some_property": "value"
+			}
+		}
+	}`)
+	obj, err := domainobjectsstix.IndicatorDomainObjectsSTIX{}.DecodeJSON(&raw)
+	assert.NoError(t, err)
+
+	indicator := obj.(domainobjectsstix.IndicatorDomainObjectsSTIX)
+	assert.True(t, indicator.ValidateStruct())
+
+	ext, ok := indicator.GetExtensions()[rankExtensionID].(rankExtension)
+	assert.True(t, ok)
+	assert.Equal(t, ext.Rank, 5)
+	assert.Equal(t, ext.Toxicity, 8)
+
+	//незарегистрированное расширение сохраняется в виде map[string]interface{}
+	unknown, ok := indicator.GetExtensions()["extension-definition--a932fcc6-e032-476c-826f-cb970a5a1ade"].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, unknown["some_property"], "value")
+
+	//кодирование и повторное декодирование
+	b, err := indicator.EncodeJSON(nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(*b), `"rank":5`)
+
+	rawEncoded := json.RawMessage(*b)
+	obj, err = domainobjectsstix.IndicatorDomainObjectsSTIX{}.DecodeJSON(&rawEncoded)
+	assert.NoError(t, err)
+
+	decoded := obj.(domainobjectsstix.IndicatorDomainObjectsSTIX)
+	assert.Equal(t, decoded.GetExtensions()[rankExtensionID], ext)
+
+	//значение не прошедшее проверку зарегистрированной функцией
+	indicator.SetValueExtensions(rankExtensionID, rankExtension{ExtensionType: "property-extension", Rank: 50})
+	assert.False(t, indicator.ValidateStruct())
+
+	//значение в виде словаря приводится к зарегистрированному типу
+	indicator.SetAnyTypedExtensions(rankExtensionID, map[string]interface{}{"extension_type": "property-extension", "rank": 3})
+	v, ok, err := indicator.GetExtension(rankExtensionID)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, v, rankExtension{ExtensionType: "property-extension", Rank: 3})
+	assert.True(t, indicator.ValidateStruct())
+
+	//ошибка декодирования зарегистрированного расширения
+	rawInvalid := json.RawMessage(`{"type": "indicator", "extensions": {"extension-definition--d83fce45-ef58-4c6c-a3f4-1fbc32e98c6e": {"rank": "high"}}}`)
+	_, err = domainobjectsstix.IndicatorDomainObjectsSTIX{}.DecodeJSON(&rawInvalid)
+	assert.Error(t, err)
+
+	//расширения объектов "relationship"
+	nr := methodstixobjects.NewRelationshipObjectSTIX()
+	nr.SetValueID("relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad")
+	nr.SetValueSpecVersion("2.1")
+	nr.SetValueRelationshipType("indicates")
+	nr.SetValueSourceRef("indicator--e97bfccf-8970-4a3c-9cd1-5b5b97ed5d0c")
+	nr.SetValueTargetRef("malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b")
+	nr.SetAnyTypedExtensions(rankExtensionID, map[string]interface{}{"extension_type": "property-extension", "rank": 1})
+	assert.True(t, nr.ValidateStruct())
+
+	b, err = nr.EncodeJSON(nil)
+	assert.NoError(t, err)
+
+	rawRelationship := json.RawMessage(*b)
+	obj, err = relationshipobjectsstix.RelationshipObjectSTIX{}.DecodeJSON(&rawRelationship)
+	assert.NoError(t, err)
+
+	rel := obj.(relationshipobjectsstix.RelationshipObjectSTIX)
+	assert.Equal(t, rel.GetExtensions()[rankExtensionID], rankExtension{ExtensionType: "property-extension", Rank: 1})
+
+	nr.SetValueExtensions("extension-definition--a932fcc6-e032-476c-826f-cb970a5a1ade", map[string]interface{}{"extension_type": "unknown"})
+	assert.False(t, nr.ValidateStruct())
+}
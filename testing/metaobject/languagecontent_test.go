@@ -0,0 +1,132 @@
+package metaobject
+
+import (
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/languagecontent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageContentObjectSTIX(t *testing.T) {
+	nlc := methodstixobjects.NewLanguageContentObjectSTIX()
+	assert.Equal(t, nlc.GetType(), "language-content")
+	assert.False(t, nlc.ValidateStruct())
+
+	_, err := nlc.Get()
+	assert.Error(t, err)
+
+	nlc.SetValueObjectRef("attack-pattern--7b8b5bb4-0a0e-4cdd-b1b3-2fd4a81d7e6b")
+	nlc.SetValueContents("ru", map[string]interface{}{"name": "Фишинг"})
+	nlc.SetAnyContents("de", "name", "Phishing")
+	assert.Equal(t, nlc.GetLanguages(), []string{"de", "ru"})
+	assert.True(t, nlc.ValidateStruct())
+
+	_, err = nlc.Get()
+	assert.NoError(t, err)
+
+	err = nlc.SetValueObjectModified("2024-01-01T00:00:00Z")
+	assert.NoError(t, err)
+	err = nlc.SetValueObjectModified("01.01.2024")
+	assert.Error(t, err)
+	assert.Equal(t, nlc.GetObjectModified(), "2024-01-01T00:00:00Z")
+
+	//ключ не является языковым тегом RFC 5646
+	nlc.SetAnyContents("ru_RU", "name", "Фишинг")
+	assert.False(t, nlc.ValidateStruct())
+
+	raw := json.RawMessage(`{
+		"type": "language-content",
+		"id": "language-content--b86bd89f-98bb-4fa9-8cb2-9ad421da981d",
+		"spec_version": "2.1",
+		"created": "2017-02-08T21:31:22.007Z",
+		"modified": "2017-02-08T21:31:22.007Z",
+		"object_ref": "campaign--12a111f0-b824-4baf-a224-83b80237a094",
+		"object_modified": "2017-02-08T21:31:22.007Z",
+		"contents": {
+			"de": {
+				"name": "Bank Angriff 1",
+				"description": "Weitere Informationen über Banküberfall"
+			},
+			"fr": {
+				"name": "Attaque Bank 1",
+				"description": "Plus d'informations sur la crise bancaire"
+			}
+		}
+	}`)
+	obj, err := stixhelpers.LanguageContentTypeSTIX{}.DecodeJSON(&raw)
+	assert.NoError(t, err)
+
+	lc := obj.(stixhelpers.LanguageContentTypeSTIX)
+	assert.True(t, lc.ValidateStruct())
+	assert.Equal(t, lc.GetContentsLang("fr")["name"], "Attaque Bank 1")
+	slc := lc.SanitizeStruct()
+	assert.Equal(t, slc.GetContentsLang("fr")["description"], "Plus d&apos;informations sur la crise bancaire")
+}
+
+func TestApplyLanguageContent(t *testing.T) {
+	nap := methodstixobjects.NewAttackPatternDomainObjectsSTIX()
+	nap.SetValueID("attack-pattern--7b8b5bb4-0a0e-4cdd-b1b3-2fd4a81d7e6b")
+	nap.SetValueName("Spear Phishing")
+	nap.SetValueDescription("Phishing emails targeting a specific person")
+	nap.SetValueLang("en")
+	nap.SetValueAliases("Phishing")
+	nap.SetValueAliases("Spam")
+	nap.SetValueKillChainPhases(stixhelpers.KillChainPhasesTypeElementSTIX{KillChainName: "lockheed-martin-cyber-kill-chain", PhaseName: "delivery"})
+
+	nlc := methodstixobjects.NewLanguageContentObjectSTIX()
+	nlc.SetValueObjectRef(stixhelpers.IdentifierTypeSTIX(nap.GetID()))
+	nlc.SetValueContents("ru", map[string]interface{}{
+		"name":              "Целевой фишинг",
+		"description":       "Фишинговые письма направленные конкретному человеку",
+		"aliases":           []interface{}{"", "Спам"},
+		"kill_chain_phases": []interface{}{map[string]interface{}{"phase_name": "доставка"}},
+	})
+
+	assert.NoError(t, languagecontent.ValidateLanguageContent(*nlc, nap))
+
+	obj, err := languagecontent.ApplyLanguageContent(nap, *nlc, "ru-RU")
+	assert.NoError(t, err)
+
+	localized, ok := obj.(*domainobjectsstix.AttackPatternDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, localized.GetName(), "Целевой фишинг")
+	assert.Equal(t, localized.GetLang(), "ru")
+	assert.Equal(t, localized.GetAliases(), []string{"Phishing", "Спам"})
+	assert.Equal(t, localized.GetKillChainPhases()[0].PhaseName, "доставка")
+	assert.Equal(t, localized.GetKillChainPhases()[0].KillChainName, "lockheed-martin-cyber-kill-chain")
+
+	//исходный объект не изменяется
+	assert.Equal(t, nap.GetName(), "Spear Phishing")
+
+	_, err = languagecontent.ApplyLanguageContent(nap, *nlc, "de")
+	assert.Error(t, err)
+
+	languages, err := languagecontent.GetTranslations(nap, []stixhelpers.LanguageContentTypeSTIX{*nlc})
+	assert.NoError(t, err)
+	assert.Equal(t, languages, []string{"ru"})
+
+	//версия объекта совпадает с object_modified, хотя записана в другом часовом поясе
+	nap.Modified = "2024-05-22T11:43:27+03:00"
+	assert.NoError(t, nlc.SetValueObjectModified("2024-05-22T08:43:27Z"))
+	_, err = languagecontent.ApplyLanguageContent(nap, *nlc, "ru")
+	assert.NoError(t, err)
+
+	//перевод относится к другой версии объекта
+	assert.NoError(t, nlc.SetValueObjectModified("2024-05-21T08:43:27Z"))
+	_, err = languagecontent.ApplyLanguageContent(nap, *nlc, "ru")
+	assert.Error(t, err)
+
+	//переведенные свойства отсутствуют в объекте
+	nlc.SetAnyContents("ru", "objective", "Цель")
+	nlc.SetAnyContents("ru", "aliases", []interface{}{"", "", "Третий"})
+	assert.Error(t, languagecontent.ValidateLanguageContent(*nlc, nap))
+
+	//объект "language-content" относится к другому объекту
+	nlc.SetValueContents("ru", map[string]interface{}{"name": "Целевой фишинг"})
+	nlc.SetValueObjectRef("attack-pattern--0c7b5b88-8ff7-4a4d-aa9d-feb398cd0061")
+	assert.Error(t, languagecontent.ValidateLanguageContent(*nlc, nap))
+}
@@ -0,0 +1,100 @@
+package metaobject
+
+import (
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLP20MarkingDefinitionSTIX(t *testing.T) {
+	as, err := stixhelpers.GetTLP20MarkingDefinitionSTIX("amber+strict")
+	assert.NoError(t, err)
+	assert.Equal(t, as.GetID(), string(stixhelpers.TLP20AmberStrictMarkingDefinitionID))
+	assert.Equal(t, as.GetName(), "TLP:AMBER+STRICT")
+	assert.Equal(t, as.GetTLP20(), "amber+strict")
+	assert.Equal(t, as.GetTLPLevel(), stixhelpers.TLPLevelAmberStrict)
+	//создатель маркера совпадает с опубликованным OASIS
+	assert.Equal(t, as.CreatedByRef, stixhelpers.IdentifierTypeSTIX("identity--b3bca3c2-1f3d-4b54-b44f-dac42c3a8f01"))
+	assert.True(t, as.ValidateStruct())
+
+	_, err = as.Get()
+	assert.NoError(t, err)
+
+	_, err = stixhelpers.GetTLP20MarkingDefinitionSTIX("white")
+	assert.Error(t, err)
+	assert.Equal(t, len(stixhelpers.GetTLP20MarkingDefinitionsSTIX()), 5)
+
+	raw := json.RawMessage(`{
+		"type": "marking-definition",
+		"spec_version": "2.1",
+		"id": "marking-definition--94868c89-83c2-464b-929b-a1a8aa3c8487",
+		"created_by_ref": "identity--b3bca3c2-1f3d-4b54-b44f-dac42c3a8f01",
+		"created": "2022-10-01T00:00:00.000Z",
+		"name": "TLP:CLEAR",
+		"extensions": {
+			"extension-definition--60a3c5c5-0d10-413e-aab3-9e08dde9e88d": {
+				"extension_type": "property-extension",
+				"tlp_2_0": "clear"
+			}
+		}
+	}`)
+	obj, err := stixhelpers.MarkingDefinitionObjectSTIX{}.DecodeJSON(&raw)
+	assert.NoError(t, err)
+
+	clear := obj.(stixhelpers.MarkingDefinitionObjectSTIX)
+	assert.True(t, clear.ValidateStruct())
+	canonical, _ := stixhelpers.GetTLP20MarkingDefinitionByIDSTIX(stixhelpers.TLP20ClearMarkingDefinitionID)
+	assert.Equal(t, clear.CreatedByRef, canonical.CreatedByRef)
+	assert.Equal(t, clear.GetTLPLevel(), stixhelpers.TLPLevelClear)
+	assert.True(t, stixhelpers.IsTLP20MarkingDefinitionID(stixhelpers.IdentifierTypeSTIX(clear.GetID())))
+
+	//уровень TLP 2.0 не соответствует идентификатору
+	clear.SetValueExtensions(stixhelpers.TLP20ExtensionDefinitionID, map[string]interface{}{
+		"extension_type": "property-extension",
+		"tlp_2_0":        "red",
+	})
+	assert.False(t, clear.ValidateStruct())
+}
+
+func TestTLPConversionAndLevels(t *testing.T) {
+	id, ok := stixhelpers.ConvertTLP10ToTLP20(stixhelpers.TLPWhiteMarkingDefinitionID)
+	assert.True(t, ok)
+	assert.Equal(t, id, stixhelpers.TLP20ClearMarkingDefinitionID)
+
+	id, ok = stixhelpers.ConvertTLP10ToTLP20(stixhelpers.TLPAmberMarkingDefinitionID)
+	assert.True(t, ok)
+	assert.Equal(t, id, stixhelpers.TLP20AmberMarkingDefinitionID)
+
+	id, ok = stixhelpers.ConvertTLP20ToTLP10(stixhelpers.TLP20AmberStrictMarkingDefinitionID)
+	assert.True(t, ok)
+	assert.Equal(t, id, stixhelpers.TLPAmberMarkingDefinitionID)
+
+	_, ok = stixhelpers.ConvertTLP20ToTLP10(stixhelpers.TLPRedMarkingDefinitionID)
+	assert.False(t, ok)
+
+	assert.Equal(t, stixhelpers.GetTLPLevelSTIX("TLP:WHITE"), stixhelpers.TLPLevelClear)
+	assert.Equal(t, stixhelpers.GetTLPLevelSTIX("amber+strict").String(), "amber+strict")
+	assert.Equal(t, stixhelpers.GetTLPLevelSTIX("orange"), stixhelpers.TLPLevelUndefined)
+
+	assert.Equal(t, stixhelpers.GetMostRestrictiveTLPLevel([]stixhelpers.IdentifierTypeSTIX{
+		stixhelpers.TLPGreenMarkingDefinitionID,
+		"marking-definition--34ec1e5c-2b36-4f8e-9d44-1b3b3a4b9a10",
+		stixhelpers.TLP20AmberStrictMarkingDefinitionID,
+		stixhelpers.TLPAmberMarkingDefinitionID,
+	}), stixhelpers.TLPLevelAmberStrict)
+	assert.Equal(t, stixhelpers.GetMostRestrictiveTLPLevel(nil), stixhelpers.TLPLevelUndefined)
+
+	nr := methodstixobjects.NewReportDomainObjectsSTIX()
+	nr.SetValueObjectMarkingRefs([]stixhelpers.IdentifierTypeSTIX{
+		stixhelpers.TLP20GreenMarkingDefinitionID,
+		stixhelpers.TLPRedMarkingDefinitionID,
+	})
+	assert.Equal(t, nr.GetTLPLevel(), stixhelpers.TLPLevelRed)
+
+	nip := methodstixobjects.NewIPv4AddressCyberObservableObjectSTIX()
+	nip.SetValueObjectMarkingRefs([]stixhelpers.IdentifierTypeSTIX{stixhelpers.TLP20ClearMarkingDefinitionID})
+	assert.Equal(t, nip.GetTLPLevel(), stixhelpers.TLPLevelClear)
+}
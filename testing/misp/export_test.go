@@ -0,0 +1,239 @@
+package misp
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/misp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getTagNames(tags []misp.Tag) []string {
+	names := []string{}
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	return names
+}
+
+func TestExportEvent(t *testing.T) {
+	imported, err := misp.ImportEvent([]byte(eventJSON))
+	require.NoError(t, err)
+
+	buf := bytes.Buffer{}
+	unmapped, err := misp.ExportEvent(&buf, "", imported.Objects)
+	require.NoError(t, err)
+	assert.Len(t, unmapped, 0)
+
+	doc := misp.EventDocument{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	event := doc.Event
+
+	assert.Equal(t, event.UUID, "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a01")
+	assert.Equal(t, event.Info, "Phishing campaign against banks")
+	assert.Equal(t, event.Date, "2023-03-01")
+	assert.Equal(t, event.Timestamp, misp.StringValue("1677715200"))
+	assert.Equal(t, event.Orgc, &misp.Organisation{UUID: "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a02", Name: "CERT Example"})
+
+	//метки TLP, метки события и метки кластеров галактик
+	assert.Equal(t, getTagNames(event.Tags), []string{
+		"tlp:amber",
+		"osint",
+		`misp-galaxy:threat-actor="APT 28"`,
+		`misp-galaxy:mitre-attack-pattern="Phishing - T1566"`,
+		`misp-galaxy:mitre-malware="X-Agent - S0161"`,
+	})
+
+	t.Run("Атрибуты", func(t *testing.T) {
+		attributes := map[string]misp.Attribute{}
+		for _, a := range event.Attributes {
+			attributes[a.Value] = a
+		}
+
+		ip := attributes["198.51.100.7"]
+		assert.Equal(t, ip.Type, "ip-dst")
+		assert.Equal(t, ip.Category, "Network activity")
+		assert.False(t, bool(ip.ToIDs))
+		assert.Equal(t, getTagNames(ip.Tags), []string{"tlp:amber"})
+
+		//индикаторы преобразуются в атрибуты с признаком to_ids
+		ip = attributes["2001:db8::1"]
+		assert.Equal(t, ip.UUID, "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a11")
+		assert.True(t, bool(ip.ToIDs))
+		assert.Equal(t, ip.Comment, "C2")
+		assert.Equal(t, ip.Timestamp, misp.StringValue("1677718800"))
+		assert.Equal(t, getTagNames(ip.Tags), []string{"tlp:red", "c2"})
+
+		assert.Equal(t, attributes["d41d8cd98f00b204e9800998ecf8427e"].Type, "md5")
+		assert.True(t, bool(attributes["d41d8cd98f00b204e9800998ecf8427e"].ToIDs))
+		assert.Equal(t, attributes["o'brien@example.com"].Type, "email-src")
+		assert.Equal(t, attributes["evil.example.com"].Type, "domain")
+		assert.Equal(t, attributes["http://evil.example.com/login"].Type, "url")
+		assert.Equal(t, attributes["203.0.113.5"].Type, "ip-dst")
+
+		//объект STIX CO, на который ссылаются отчет и "observed-data", экспортируется один раз
+		count := 0
+		for _, a := range event.Attributes {
+			if a.Value == "evil.example.com" {
+				count++
+			}
+		}
+		assert.Equal(t, count, 1)
+	})
+
+	t.Run("Объекты", func(t *testing.T) {
+		//объект "file" и индикатор с шаблоном из нескольких сравнений преобразуются в объекты MISP "file"
+		require.Len(t, event.Objects, 2)
+
+		indicator := event.Objects[1]
+		assert.Equal(t, indicator.UUID, "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a20")
+		assert.Equal(t, indicator.Name, "file")
+
+		file := event.Objects[0]
+		relations := map[string]misp.Attribute{}
+		for _, a := range file.Attributes {
+			relations[a.ObjectRelation] = a
+		}
+		assert.Equal(t, relations["filename"].Value, "invoice.exe")
+		assert.Equal(t, relations["md5"].Value, "0cc175b9c0f1b6a831c399e269772661")
+		assert.Equal(t, relations["sha256"].Value, "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb")
+		assert.Equal(t, relations["size-in-bytes"].Value, "1024")
+		assert.False(t, bool(relations["md5"].ToIDs))
+
+		for _, a := range indicator.Attributes {
+			assert.True(t, bool(a.ToIDs))
+		}
+	})
+
+	t.Run("Галактики", func(t *testing.T) {
+		require.Len(t, event.Galaxies, 3)
+		assert.Equal(t, event.Galaxies[0].Type, "threat-actor")
+		assert.Equal(t, event.Galaxies[1].Type, "mitre-attack-pattern")
+
+		cluster := event.Galaxies[1].Clusters[0]
+		assert.Equal(t, cluster.UUID, "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a31")
+		assert.Equal(t, cluster.Value, "Phishing - T1566")
+
+		cluster = event.Galaxies[0].Clusters[0]
+		assert.Equal(t, cluster.Value, "APT 28")
+		assert.Equal(t, cluster.Description, "Russian group")
+		assert.Equal(t, cluster.Meta["synonyms"], []interface{}{"Fancy Bear", "Sofacy"})
+	})
+
+	t.Run("Повторный импорт", func(t *testing.T) {
+		//повторный импорт экспортированного события создает те же объекты
+		again, err := misp.ImportEvent(buf.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, again.Report.ID, imported.Report.ID)
+		assert.Equal(t, again.Report.Labels, imported.Report.Labels)
+		assert.Equal(t, again.Report.ObjectMarkingRefs, imported.Report.ObjectMarkingRefs)
+
+		objects, _ := getObjectsByID(t, again.Objects)
+		for _, id := range []string{
+			"indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a11",
+			"indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a14",
+			"threat-actor--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a30",
+			"attack-pattern--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a31",
+			"malware--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a32",
+		} {
+			assert.Contains(t, objects, id)
+		}
+	})
+}
+
+func TestConvertToEvent(t *testing.T) {
+	identity := methodstixobjects.NewIdentityDomainObjectsSTIX()
+	identity.SetValueID("identity--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b01")
+	identity.SetValueName("SOC")
+
+	ip := methodstixobjects.NewIPv4AddressCyberObservableObjectSTIX()
+	ip.SetValueID("ipv4-addr--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b02")
+	ip.SetValueValue("192.0.2.10")
+	ip.SetValueObjectMarkingRefs([]stixhelpers.IdentifierTypeSTIX{stixhelpers.TLP20AmberStrictMarkingDefinitionID, "marking-definition--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b09"})
+
+	file := methodstixobjects.NewFileCyberObservableObjectSTIX()
+	file.SetValueID("file--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b10")
+	file.SetValueName("a.exe")
+	file.SetValueHashes(stixhelpers.HashesTypeSTIX{"MD5": "0cc175b9c0f1b6a831c399e269772661", "SHA3-256": "80084bf2fba02475726feb2cab2d8215eab14bc6bdd8bfb2c8151257032ecd8b"})
+
+	complexIndicator := methodstixobjects.NewIndicatorDomainObjectsSTIX()
+	complexIndicator.SetValueID("indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b03")
+	complexIndicator.SetValuePattern("[ipv4-addr:value = '192.0.2.1'] OR [domain-name:value = 'example.com']")
+	complexIndicator.SetValuePatternType("stix")
+
+	sigmaIndicator := methodstixobjects.NewIndicatorDomainObjectsSTIX()
+	sigmaIndicator.SetValueID("indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b04")
+	sigmaIndicator.SetValuePattern("title: rule")
+	sigmaIndicator.SetValuePatternType("sigma")
+
+	attackPattern := methodstixobjects.NewAttackPatternDomainObjectsSTIX()
+	attackPattern.SetValueID("attack-pattern--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b05")
+	attackPattern.SetValueName("Spearphishing Link")
+	attackPattern.SetValueExternalReferences([]stixhelpers.ExternalReferenceTypeElementSTIX{{SourceName: "mitre-attack", ExternalID: "T1566.002", URL: "https://attack.mitre.org/techniques/T1566/002"}})
+	attackPattern.SetValueKillChainPhases(stixhelpers.KillChainPhasesTypeElementSTIX{KillChainName: "mitre-attack", PhaseName: "initial-access"})
+
+	grouping := methodstixobjects.NewGroupingDomainObjectsSTIX()
+	grouping.SetValueID("grouping--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b06")
+	grouping.SetValueName("Suspicious activity")
+	grouping.SetValueContext("suspicious-activity")
+	grouping.SetValueCreated("2023-05-10T08:00:00Z")
+	grouping.SetValueModified("2023-05-11T08:00:00Z")
+	grouping.SetValueCreatedByRef("identity--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b01")
+	grouping.SetValueObjectMarkingRefs([]stixhelpers.IdentifierTypeSTIX{stixhelpers.TLPGreenMarkingDefinitionID})
+	grouping.SetFullValueObjectRefs([]stixhelpers.IdentifierTypeSTIX{
+		"ipv4-addr--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b02",
+		"indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b03",
+		"indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b04",
+		"attack-pattern--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b05",
+		"identity--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b01",
+		"note--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b07",
+		"file--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b10",
+	})
+
+	objects := []interface{}{*identity, *ip, *complexIndicator, *sigmaIndicator, *attackPattern, *grouping, *file}
+
+	result, err := misp.ConvertToEvent("grouping--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b06", objects)
+	require.NoError(t, err)
+
+	event := result.Event
+	assert.Equal(t, event.UUID, "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b06")
+	assert.Equal(t, event.Date, "2023-05-10")
+	assert.Equal(t, event.Orgc.Name, "SOC")
+	assert.Equal(t, getTagNames(event.Tags), []string{"tlp:green", `misp-galaxy:mitre-attack-pattern="Spearphishing Link - T1566.002"`})
+
+	require.Len(t, event.Attributes, 1)
+	assert.Equal(t, event.Attributes[0].UUID, "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b02")
+	assert.Equal(t, event.Attributes[0].Value, "192.0.2.10")
+	//маркеры TLP 2.0 преобразуются в метки
+	assert.Equal(t, getTagNames(event.Attributes[0].Tags), []string{"tlp:amber+strict"})
+
+	require.Len(t, event.Objects, 1)
+	assert.Len(t, event.Objects[0].Attributes, 2)
+
+	require.Len(t, event.Galaxies, 1)
+	cluster := event.Galaxies[0].Clusters[0]
+	assert.Equal(t, cluster.Meta["external_id"], []string{"T1566.002"})
+	assert.Equal(t, cluster.Meta["refs"], []string{"https://attack.mitre.org/techniques/T1566/002"})
+	assert.Equal(t, cluster.Meta["kill_chain"], []string{"mitre-attack:initial-access"})
+
+	//объекты, не имеющие соответствия в MISP, перечисляются
+	assert.Equal(t, result.Unmapped, []string{
+		"ipv4-addr--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b02: unsupported marking definition 'marking-definition--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b09'",
+		"indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b03: the pattern is not a simple equality pattern",
+		"indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b04: unsupported pattern type 'sigma'",
+		"identity--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b01: unsupported object type 'identity'",
+		"note--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b07: the object is not found",
+		"file--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b10: unsupported hash algorithm 'SHA3-256'",
+	})
+
+	_, err = misp.ConvertToEvent("attack-pattern--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b05", objects)
+	assert.Error(t, err)
+
+	_, err = misp.ConvertToEvent("report--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1b08", objects)
+	assert.Error(t, err)
+}
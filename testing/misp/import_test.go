@@ -0,0 +1,290 @@
+package misp
+
+import (
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/commonlibs"
+	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/misp"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eventJSON = `{
+	"Event": {
+		"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a01",
+		"info": "Phishing campaign against banks",
+		"date": "2023-03-01",
+		"timestamp": "1677715200",
+		"publish_timestamp": "1677801600",
+		"published": true,
+		"threat_level_id": "1",
+		"Orgc": {"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a02", "name": "CERT Example"},
+		"Tag": [
+			{"name": "tlp:amber"},
+			{"name": "osint"},
+			{"name": "misp-galaxy:threat-actor=\"APT 28\""}
+		],
+		"Attribute": [
+			{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a10", "type": "ip-dst", "category": "Network activity", "value": "198.51.100.7", "to_ids": false, "timestamp": "1677715200"},
+			{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a11", "type": "ip-src", "value": "2001:db8::1", "to_ids": "1", "timestamp": 1677718800, "comment": "C2", "Tag": [{"name": "tlp:red"}, {"name": "c2"}]},
+			{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a12", "type": "domain", "value": "evil.example.com", "to_ids": false},
+			{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a13", "type": "url", "value": "http://evil.example.com/login", "to_ids": false},
+			{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a14", "type": "md5", "value": "d41d8cd98f00b204e9800998ecf8427e", "to_ids": true},
+			{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a15", "type": "email-src", "value": "o'brien@example.com", "to_ids": true},
+			{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a16", "type": "text", "value": "some text", "to_ids": false}
+		],
+		"Object": [
+			{
+				"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a20",
+				"name": "file",
+				"meta-category": "file",
+				"timestamp": "1677722400",
+				"Attribute": [
+					{"type": "filename", "object_relation": "filename", "value": "invoice.exe", "to_ids": false},
+					{"type": "md5", "object_relation": "md5", "value": "0cc175b9c0f1b6a831c399e269772661", "to_ids": false},
+					{"type": "sha256", "object_relation": "sha256", "value": "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb", "to_ids": true},
+					{"type": "size-in-bytes", "object_relation": "size-in-bytes", "value": "1024", "to_ids": false}
+				]
+			},
+			{
+				"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a21",
+				"name": "domain-ip",
+				"Attribute": [
+					{"type": "domain", "object_relation": "domain", "value": "evil.example.com", "to_ids": false},
+					{"type": "ip-dst", "object_relation": "ip", "value": "203.0.113.5", "to_ids": false},
+					{"type": "port", "object_relation": "port", "value": "443", "to_ids": false}
+				]
+			}
+		],
+		"Galaxy": [
+			{
+				"type": "threat-actor",
+				"name": "Threat Actor",
+				"GalaxyCluster": [
+					{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a30", "type": "threat-actor", "value": "APT 28", "description": "Russian group", "meta": {"synonyms": ["Fancy Bear", "Sofacy"], "refs": ["https://example.com/apt28"]}}
+				]
+			},
+			{
+				"type": "mitre-attack-pattern",
+				"GalaxyCluster": [
+					{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a31", "value": "Phishing - T1566", "meta": {"external_id": ["T1566"], "kill_chain": ["mitre-attack:enterprise-attack:initial-access"]}}
+				]
+			},
+			{
+				"type": "mitre-malware",
+				"GalaxyCluster": [
+					{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a32", "value": "X-Agent - S0161", "meta": {"external_id": "S0161"}}
+				]
+			},
+			{
+				"type": "sector",
+				"GalaxyCluster": [
+					{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a33", "value": "Finance"}
+				]
+			}
+		]
+	}
+}`
+
+func getObjectsByID(t *testing.T, list []interface{}) (map[string]interface{}, []string) {
+	objects := map[string]interface{}{}
+	ids := []string{}
+	for _, obj := range list {
+		m, err := commonlibs.ToMap(obj)
+		require.NoError(t, err)
+
+		info, err := stixstore.GetObjectInfo(m)
+		require.NoError(t, err)
+		objects[info.ID] = obj
+		ids = append(ids, info.ID)
+	}
+
+	return objects, ids
+}
+
+func TestImportEvent(t *testing.T) {
+	result, err := misp.ImportEvent([]byte(eventJSON))
+	require.NoError(t, err)
+
+	objects, ids := getObjectsByID(t, result.Objects)
+	//объекты не повторяются
+	assert.Len(t, objects, len(ids))
+	assert.Equal(t, ids[0], "report--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a01")
+
+	report := result.Report
+	assert.Equal(t, report.Name, "Phishing campaign against banks")
+	assert.Equal(t, report.Published, "2023-03-01T00:00:00Z")
+	assert.Equal(t, report.Created, "2023-03-02T00:00:00Z")
+	assert.Equal(t, report.Labels, []string{"osint"})
+	assert.Equal(t, report.ObjectMarkingRefs, []stixhelpers.IdentifierTypeSTIX{stixhelpers.TLPAmberMarkingDefinitionID})
+	assert.Equal(t, report.CreatedByRef, stixhelpers.IdentifierTypeSTIX("identity--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a02"))
+
+	identity, ok := objects["identity--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a02"].(domainobjectsstix.IdentityDomainObjectsSTIX)
+	require.True(t, ok)
+	assert.Equal(t, identity.Name, "CERT Example")
+
+	//используемые маркеры TLP добавляются в результат
+	_, ok = objects[string(stixhelpers.TLPAmberMarkingDefinitionID)].(stixhelpers.MarkingDefinitionObjectSTIX)
+	assert.True(t, ok)
+	_, ok = objects[string(stixhelpers.TLPRedMarkingDefinitionID)].(stixhelpers.MarkingDefinitionObjectSTIX)
+	assert.True(t, ok)
+
+	//на объекты "identity" и "marking-definition" отчет не ссылается
+	refs := map[stixhelpers.IdentifierTypeSTIX]bool{}
+	for _, ref := range report.ObjectRefs {
+		refs[ref] = true
+		assert.Contains(t, objects, string(ref))
+	}
+	assert.Len(t, refs, len(objects)-4)
+
+	t.Run("Атрибуты", func(t *testing.T) {
+		types := map[string][]interface{}{}
+		for _, obj := range result.Objects {
+			m, err := commonlibs.ToMap(obj)
+			require.NoError(t, err)
+			types[m["type"].(string)] = append(types[m["type"].(string)], obj)
+		}
+
+		require.Len(t, types["ipv4-addr"], 2)
+		ipv4, ok := types["ipv4-addr"][0].(cyberobservableobjectsstix.IPv4AddressCyberObservableObjectSTIX)
+		require.True(t, ok)
+		assert.Equal(t, ipv4.Value, "198.51.100.7")
+		assert.Equal(t, ipv4.ObjectMarkingRefs, []stixhelpers.IdentifierTypeSTIX{stixhelpers.TLPAmberMarkingDefinitionID})
+
+		//домен атрибута и объекта MISP является одним объектом STIX CO
+		require.Len(t, types["domain-name"], 1)
+		require.Len(t, types["url"], 1)
+		assert.Len(t, types["ipv6-addr"], 0)
+		assert.Len(t, types["email-addr"], 0)
+
+		//атрибуты с признаком to_ids преобразуются в индикаторы
+		indicator, ok := objects["indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a11"].(domainobjectsstix.IndicatorDomainObjectsSTIX)
+		require.True(t, ok)
+		assert.Equal(t, indicator.Pattern, "[ipv6-addr:value = '2001:db8::1']")
+		assert.Equal(t, indicator.PatternType, stixhelpers.OpenVocabTypeSTIX("stix"))
+		assert.Equal(t, indicator.ValidFrom, "2023-03-02T01:00:00Z")
+		assert.Equal(t, indicator.Description, "C2")
+		assert.Equal(t, indicator.Labels, []string{"c2"})
+		assert.Equal(t, indicator.ObjectMarkingRefs, []stixhelpers.IdentifierTypeSTIX{stixhelpers.TLPRedMarkingDefinitionID})
+
+		indicator = objects["indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a14"].(domainobjectsstix.IndicatorDomainObjectsSTIX)
+		assert.Equal(t, indicator.Pattern, "[file:hashes.MD5 = 'd41d8cd98f00b204e9800998ecf8427e']")
+
+		indicator = objects["indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a15"].(domainobjectsstix.IndicatorDomainObjectsSTIX)
+		assert.Equal(t, indicator.Pattern, `[email-addr:value = 'o\'brien@example.com']`)
+
+		assert.Equal(t, result.Unmapped, []string{
+			"attribute text 'some text'",
+			"attribute port '443' of object domain-ip",
+			"galaxy cluster sector 'Finance'",
+		})
+	})
+
+	t.Run("Объекты", func(t *testing.T) {
+		observedData, ok := objects["observed-data--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a20"].(domainobjectsstix.ObservedDataDomainObjectsSTIX)
+		require.True(t, ok)
+		assert.Equal(t, observedData.NumberObserved, 1)
+		assert.Equal(t, observedData.FirstObserved, "2023-03-02T02:00:00Z")
+		require.Len(t, observedData.ObjectRefs, 1)
+
+		//атрибуты объекта "file" объединяются в один объект, идентификатор которого формируется из хеша MD5 и имени
+		file, ok := objects[string(observedData.ObjectRefs[0])].(cyberobservableobjectsstix.FileCyberObservableObjectSTIX)
+		require.True(t, ok)
+		assert.Equal(t, file.ID, "file--04536941-f87c-577c-8523-20c97d20f3db")
+		assert.Equal(t, file.Name, "invoice.exe")
+		assert.Equal(t, file.Size, uint64(1024))
+		assert.Equal(t, file.Hashes, stixhelpers.HashesTypeSTIX{
+			"MD5":     "0cc175b9c0f1b6a831c399e269772661",
+			"SHA-256": "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb",
+		})
+
+		indicator, ok := objects["indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a20"].(domainobjectsstix.IndicatorDomainObjectsSTIX)
+		require.True(t, ok)
+		assert.Equal(t, indicator.Pattern, "[file:name = 'invoice.exe' AND file:hashes.MD5 = '0cc175b9c0f1b6a831c399e269772661' AND file:hashes.'SHA-256' = 'ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb']")
+
+		observedData = objects["observed-data--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a21"].(domainobjectsstix.ObservedDataDomainObjectsSTIX)
+		assert.Len(t, observedData.ObjectRefs, 2)
+
+		//объекты без признака to_ids не порождают индикаторов
+		assert.NotContains(t, objects, "indicator--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a21")
+	})
+
+	t.Run("Галактики", func(t *testing.T) {
+		threatActor, ok := objects["threat-actor--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a30"].(domainobjectsstix.ThreatActorDomainObjectsSTIX)
+		require.True(t, ok)
+		assert.Equal(t, threatActor.Name, "APT 28")
+		assert.Equal(t, threatActor.Aliases, []string{"Fancy Bear", "Sofacy"})
+		assert.Equal(t, threatActor.ExternalReferences, []stixhelpers.ExternalReferenceTypeElementSTIX{{SourceName: "threat-actor", URL: "https://example.com/apt28"}})
+		assert.Empty(t, threatActor.CreatedByRef)
+
+		attackPattern, ok := objects["attack-pattern--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a31"].(domainobjectsstix.AttackPatternDomainObjectsSTIX)
+		require.True(t, ok)
+		assert.Equal(t, attackPattern.Name, "Phishing")
+		assert.Equal(t, attackPattern.ExternalReferences, []stixhelpers.ExternalReferenceTypeElementSTIX{{SourceName: "mitre-attack", ExternalID: "T1566"}})
+		assert.Equal(t, attackPattern.KillChainPhases, []stixhelpers.KillChainPhasesTypeElementSTIX{{KillChainName: "mitre-attack", PhaseName: "initial-access"}})
+
+		malware, ok := objects["malware--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a32"].(domainobjectsstix.MalwareDomainObjectsSTIX)
+		require.True(t, ok)
+		assert.Equal(t, malware.Name, "X-Agent")
+		assert.True(t, malware.IsFamily)
+	})
+
+	t.Run("Повторный импорт", func(t *testing.T) {
+		//идентификаторы объектов при повторном импорте не изменяются
+		again, err := misp.ImportEvent([]byte(eventJSON))
+		require.NoError(t, err)
+		_, againIDs := getObjectsByID(t, again.Objects)
+		assert.Equal(t, againIDs, ids)
+
+		//все объекты могут быть добавлены в хранилище
+		_, err = stixstore.NewMemoryStore(result.Objects...)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Метки TLP 2.0", func(t *testing.T) {
+		result, err := misp.ImportEvent([]byte(`{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a50", "info": "TLP", "date": "2023-01-01", "Tag": [{"name": "TLP:AMBER+STRICT"}],
+			"Attribute": [{"type": "domain", "value": "example.com", "Tag": [{"name": "tlp:clear"}]}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, result.Report.ObjectMarkingRefs, []stixhelpers.IdentifierTypeSTIX{stixhelpers.TLP20AmberStrictMarkingDefinitionID})
+
+		objects, _ := getObjectsByID(t, result.Objects)
+		_, ok := objects[string(stixhelpers.TLP20AmberStrictMarkingDefinitionID)].(stixhelpers.MarkingDefinitionObjectSTIX)
+		assert.True(t, ok)
+
+		require.Len(t, result.Objects, 4)
+		domain, ok := result.Objects[3].(cyberobservableobjectsstix.DomainNameCyberObservableObjectSTIX)
+		require.True(t, ok)
+		assert.Equal(t, domain.ObjectMarkingRefs, []stixhelpers.IdentifierTypeSTIX{stixhelpers.TLP20ClearMarkingDefinitionID})
+	})
+
+	t.Run("Событие без свойства Event", func(t *testing.T) {
+		result, err := misp.ImportEvent([]byte(`{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a40", "info": "Plain", "date": "2023-01-01", "Attribute": [{"type": "filename|sha1", "value": "a.dll|da39a3ee5e6b4b0d3255bfef95601890afd80709", "to_ids": "0"}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, result.Report.ID, "report--5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a40")
+		assert.Equal(t, result.Report.Created, "2023-01-01T00:00:00Z")
+		assert.Empty(t, result.Report.CreatedByRef)
+		require.Len(t, result.Objects, 2)
+
+		file, ok := result.Objects[1].(cyberobservableobjectsstix.FileCyberObservableObjectSTIX)
+		require.True(t, ok)
+		assert.Equal(t, file.Name, "a.dll")
+		assert.Equal(t, file.Hashes, stixhelpers.HashesTypeSTIX{"SHA-1": "da39a3ee5e6b4b0d3255bfef95601890afd80709"})
+
+		_, err = misp.ImportEvent([]byte(`{"Event": {"uuid": "x", "published": "maybe"}}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("Событие без преобразуемых элементов", func(t *testing.T) {
+		//отчет без ссылок на объекты не формируется
+		_, err := misp.ImportEvent([]byte(`{"uuid": "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a60", "info": "Empty", "date": "2023-01-01", "Orgc": {"name": "CIRCL"},
+			"Attribute": [{"type": "text", "value": "some text"}], "Galaxy": [{"type": "sector", "GalaxyCluster": [{"value": "Finance"}]}]}`))
+		assert.Error(t, err)
+
+		_, err = misp.ConvertEvent(misp.Event{UUID: "5e1b1b7a-2b7c-4c37-9d2a-0a5c6b8f1a61", Info: "Empty"})
+		assert.Error(t, err)
+	})
+}
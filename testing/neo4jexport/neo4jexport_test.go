@@ -0,0 +1,159 @@
+package neo4jexport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/neo4jexport"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	threatActorID = "threat-actor--56f3f0db-b5d5-431c-ae56-c18f02caf500"
+	malwareID     = "malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b"
+	identityID    = "identity--f431f809-377b-45e0-aa1c-6a4751cae5ff"
+	fileID        = "file--e277603e-1060-5ad4-9937-c26c97f1ca68"
+	usesID        = "relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad"
+	brokenID      = "relationship--9f3e6e44-1d2d-4c5a-8b6a-22cbe3bd0c11"
+)
+
+func getObjects() []interface{} {
+	return []interface{}{
+		json.RawMessage(`{"type": "threat-actor", "id": "` + threatActorID + `", "name": "APT, \"Fancy\"", "aliases": ["a1", "a2"], "created_by_ref": "` + identityID + `"}`),
+		json.RawMessage(`{"type": "malware", "id": "` + malwareID + `", "name": "Poison Ivy", "is_family": true, "sample_refs": ["` + fileID + `"]}`),
+		json.RawMessage(`{"type": "identity", "id": "` + identityID + `", "name": "ACME"}`),
+		json.RawMessage(`{"type": "file", "id": "` + fileID + `", "name": "a.exe", "size": 25536, "hashes": {"MD5": "1717b7fff97d37a1e1a0029d83492de1"}}`),
+		json.RawMessage(`{"type": "relationship", "id": "` + usesID + `", "relationship_type": "uses", "source_ref": "` + threatActorID + `", "target_ref": "` + malwareID + `", "confidence": 80}`),
+		json.RawMessage(`{"type": "relationship", "id": "` + brokenID + `", "relationship_type": "attributed-to", "source_ref": "campaign--83422c77-904c-4dc1-aff5-5c38f3a2c55c", "target_ref": "` + threatActorID + `"}`),
+	}
+}
+
+func getFile(t *testing.T, list []neo4jexport.CSVFile, name string) neo4jexport.CSVFile {
+	for _, f := range list {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	t.Fatalf("file '%s' not found", name)
+
+	return neo4jexport.CSVFile{}
+}
+
+func TestNeo4jExport(t *testing.T) {
+	assert.Equal(t, neo4jexport.GetLabel("threat-actor"), "ThreatActor")
+	assert.Equal(t, neo4jexport.GetRelationshipType("attributed-to"), "ATTRIBUTED_TO")
+
+	export, err := neo4jexport.NewExport(getObjects(), neo4jexport.Options{IncludeEmbeddedRefs: true, ArrayDelimiter: "|"})
+	assert.NoError(t, err)
+	assert.Len(t, export.Nodes, 4)
+	assert.Len(t, export.Relationships, 2)
+
+	t.Run("Файлы узлов", func(t *testing.T) {
+		f := getFile(t, export.Nodes, "nodes_threat_actor.csv")
+		assert.Equal(t, f.Header, []string{"id:ID", ":LABEL", "aliases:string[]", "created_by_ref", "name", "type"})
+		assert.Equal(t, f.Rows, [][]string{{threatActorID, "STIXObject|ThreatActor", "a1|a2", identityID, `APT, "Fancy"`, "threat-actor"}})
+
+		f = getFile(t, export.Nodes, "nodes_file.csv")
+		assert.Contains(t, f.Header, "hashes.MD5")
+		assert.Contains(t, f.Header, "size:long")
+
+		f = getFile(t, export.Nodes, "nodes_malware.csv")
+		assert.Contains(t, f.Header, "is_family:boolean")
+	})
+
+	t.Run("Файлы связей", func(t *testing.T) {
+		f := getFile(t, export.Relationships, "relationships.csv")
+		assert.Equal(t, f.Header[:3], []string{":START_ID", ":END_ID", ":TYPE"})
+		assert.Contains(t, f.Header, "confidence:long")
+		//связь с отсутствующим объектом не формируется
+		assert.Len(t, f.Rows, 1)
+		assert.Equal(t, f.Rows[0][:3], []string{threatActorID, malwareID, "USES"})
+
+		f = getFile(t, export.Relationships, "embedded_refs.csv")
+		assert.Equal(t, f.Rows, [][]string{
+			{threatActorID, identityID, "CREATED_BY_REF", "created_by_ref"},
+			{malwareID, fileID, "SAMPLE_REFS", "sample_refs"},
+		})
+	})
+
+	t.Run("Запись файлов", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, export.WriteDir(dir))
+
+		b, err := os.ReadFile(filepath.Join(dir, "nodes_threat_actor.csv"))
+		assert.NoError(t, err)
+
+		records, err := csv.NewReader(bytes.NewReader(b)).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, records[1][4], `APT, "Fancy"`)
+
+		args := export.GetImportArguments(dir)
+		assert.Equal(t, args[0], "--array-delimiter=|")
+		assert.Contains(t, args, "--relationships="+filepath.Join(dir, "relationships.csv"))
+	})
+
+	t.Run("Без встроенных ссылок", func(t *testing.T) {
+		export, err := neo4jexport.NewExport(getObjects(), neo4jexport.Options{})
+		assert.NoError(t, err)
+		assert.Len(t, export.Relationships, 1)
+		assert.Equal(t, export.ArrayDelimiter, neo4jexport.DefaultArrayDelimiter)
+	})
+
+	t.Run("Элемент массива содержит разделитель", func(t *testing.T) {
+		objects := []interface{}{
+			json.RawMessage(`{"type": "threat-actor", "id": "` + threatActorID + `", "name": "APT", "aliases": ["a1;a2", "a3"]}`),
+		}
+
+		_, err := neo4jexport.NewExport(objects, neo4jexport.Options{})
+		assert.Error(t, err)
+
+		export, err := neo4jexport.NewExport(objects, neo4jexport.Options{ArrayDelimiter: "|"})
+		assert.NoError(t, err)
+		assert.Contains(t, export.Nodes[0].Rows[0], "a1;a2|a3")
+	})
+
+	_, err = neo4jexport.NewExport([]interface{}{json.RawMessage(`{"name": "without id"}`)}, neo4jexport.Options{})
+	assert.Error(t, err)
+}
+
+func TestCypherStatements(t *testing.T) {
+	list, err := neo4jexport.GetCypherStatements(getObjects(), neo4jexport.Options{IncludeEmbeddedRefs: true})
+	assert.NoError(t, err)
+	//ограничение, 4 узла, 1 связь, 2 встроенные ссылки
+	assert.Len(t, list, 8)
+
+	assert.True(t, strings.HasPrefix(list[0].Query, "CREATE CONSTRAINT"))
+	assert.Equal(t, list[1].Query, "MERGE (n:STIXObject {id: $id}) SET n:`ThreatActor` SET n += $properties")
+	assert.Equal(t, list[1].Parameters["id"], threatActorID)
+
+	assert.Contains(t, list[5].Query, "MERGE (s)-[r:`USES` {id: $id}]->(t)")
+	assert.Equal(t, list[5].Parameters["source"], threatActorID)
+	properties := list[5].Parameters["properties"].(map[string]interface{})
+	assert.NotContains(t, properties, "source_ref")
+	assert.Equal(t, properties["relationship_type"], "uses")
+
+	assert.Contains(t, list[6].Query, "CREATED_BY_REF")
+
+	list, err = neo4jexport.GetCypherStatements(getObjects(), neo4jexport.Options{})
+	assert.NoError(t, err)
+	assert.Len(t, list, 6)
+
+	//обратные кавычки в типе объекта и типе связи экранируются
+	list, err = neo4jexport.GetCypherStatements([]interface{}{
+		json.RawMessage(`{"type": "x-a` + "`" + `b", "id": "x-a--56f3f0db-b5d5-431c-ae56-c18f02caf500"}`),
+		json.RawMessage(`{"type": "threat-actor", "id": "` + threatActorID + `", "name": "APT"}`),
+		json.RawMessage(`{"type": "malware", "id": "` + malwareID + `", "name": "Poison Ivy"}`),
+		json.RawMessage(`{"type": "relationship", "id": "` + usesID + `", "relationship_type": "uses` + "`" + `]->(t) DETACH DELETE t //", "source_ref": "` + threatActorID + `", "target_ref": "` + malwareID + `"}`),
+	}, neo4jexport.Options{})
+	assert.NoError(t, err)
+	assert.Len(t, list, 5)
+	assert.Equal(t, list[1].Query, "MERGE (n:STIXObject {id: $id}) SET n:`XA``b` SET n += $properties")
+	assert.Contains(t, list[4].Query, "MERGE (s)-[r:`USES``]_>(T) DETACH DELETE T //` {id: $id}]->(t)")
+}
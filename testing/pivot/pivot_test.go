@@ -0,0 +1,163 @@
+package pivot
+
+import (
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/pivot"
+	"github.com/av-belyakov/methodstixobjects/stixgraph"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	campaignID     = "campaign--83422c77-904c-4dc1-aff5-5c38f3a2c55c"
+	intrusionSetID = "intrusion-set--4e78f46f-a023-4e5f-bc24-71b3ca22ec29"
+	threatActorID  = "threat-actor--56f3f0db-b5d5-431c-ae56-c18f02caf500"
+	malwareID      = "malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b"
+	variantID      = "malware--0c7b5b88-8ff7-4a4d-aa9d-feb398cd0061"
+	toolID         = "tool--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	indicatorID    = "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	toolIndID      = "indicator--26ffb872-1dd9-446e-b6f5-d58527e5b5d2"
+	identityID     = "identity--f431f809-377b-45e0-aa1c-6a4751cae5ff"
+	vulnID         = "vulnerability--0c7b5b88-8ff7-4a4d-aa9d-feb398cd0061"
+)
+
+func getRelationship(relType, source, target string) interface{} {
+	r := methodstixobjects.NewRelationshipObjectSTIX()
+	r.SetValueID("relationship--" + source[len(source)-12:] + "-" + target[len(target)-12:])
+	r.SetValueRelationshipType(relType)
+	r.SetValueSourceRef(stixhelpers.IdentifierTypeSTIX(source))
+	r.SetValueTargetRef(stixhelpers.IdentifierTypeSTIX(target))
+
+	return r
+}
+
+func getObjects() []interface{} {
+	ta := methodstixobjects.NewThreatActorDomainObjectsSTIX()
+	ta.SetValueID(threatActorID)
+	ta.SetValueName("APT")
+
+	m := methodstixobjects.NewMalwareDomainObjectsSTIX()
+	m.SetValueID(malwareID)
+	m.SetValueName("Poison Ivy")
+
+	return []interface{}{
+		json.RawMessage(`{"type": "campaign", "id": "` + campaignID + `", "name": "Operation X"}`),
+		json.RawMessage(`{"type": "intrusion-set", "id": "` + intrusionSetID + `", "name": "Group X"}`),
+		ta, m,
+		json.RawMessage(`{"type": "malware", "id": "` + variantID + `", "name": "Poison Ivy v2", "is_family": false}`),
+		json.RawMessage(`{"type": "tool", "id": "` + toolID + `", "name": "nmap"}`),
+		json.RawMessage(`{"type": "indicator", "id": "` + indicatorID + `", "name": "Poison Ivy hash", "pattern": "[file:hashes.MD5 = '1717b7fff97d37a1e1a0029d83492de1']"}`),
+		json.RawMessage(`{"type": "indicator", "id": "` + toolIndID + `", "name": "nmap hash"}`),
+		json.RawMessage(`{"type": "identity", "id": "` + identityID + `", "name": "ACME"}`),
+		json.RawMessage(`{"type": "vulnerability", "id": "` + vulnID + `", "name": "CVE-2024-0001"}`),
+		getRelationship("attributed-to", campaignID, intrusionSetID),
+		getRelationship("attributed-to", intrusionSetID, threatActorID),
+		getRelationship("uses", threatActorID, malwareID),
+		getRelationship("uses", threatActorID, toolID),
+		getRelationship("indicates", indicatorID, malwareID),
+		getRelationship("indicates", toolIndID, toolID),
+		getRelationship("variant-of", variantID, malwareID),
+		getRelationship("targets", threatActorID, identityID),
+		getRelationship("targets", threatActorID, vulnID),
+		getRelationship("related-to", identityID, campaignID),
+	}
+}
+
+func TestPivot(t *testing.T) {
+	p, err := pivot.NewPivot(getObjects())
+	assert.NoError(t, err)
+
+	t.Run("Цепочка attributed-to", func(t *testing.T) {
+		list, err := p.GetAttributedThreatActors(campaignID)
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, list[0].Object.GetName(), "APT")
+		assert.Equal(t, list[0].Path.Nodes, []string{campaignID, intrusionSetID, threatActorID})
+		assert.Equal(t, list[0].Path.Edges[0].Type, "attributed-to")
+
+		sets, err := p.GetAttributedIntrusionSets(campaignID)
+		assert.NoError(t, err)
+		assert.Len(t, sets, 1)
+		assert.Equal(t, sets[0].Object.GetName(), "Group X")
+
+		campaigns, err := p.GetAttributedCampaigns(threatActorID)
+		assert.NoError(t, err)
+		assert.Len(t, campaigns, 1)
+		assert.Equal(t, campaigns[0].Object.GetID(), campaignID)
+		assert.Len(t, campaigns[0].Path.Edges, 2)
+	})
+
+	t.Run("Используемые объекты", func(t *testing.T) {
+		malware, err := p.GetUsedMalware(threatActorID)
+		assert.NoError(t, err)
+		assert.Len(t, malware, 1)
+		assert.Equal(t, malware[0].Object.GetName(), "Poison Ivy")
+
+		tools, err := p.GetUsedTools(threatActorID)
+		assert.NoError(t, err)
+		assert.Len(t, tools, 1)
+
+		patterns, err := p.GetUsedAttackPatterns(threatActorID)
+		assert.NoError(t, err)
+		assert.Len(t, patterns, 0)
+	})
+
+	t.Run("Индикаторы", func(t *testing.T) {
+		list, err := p.GetIndicators(malwareID)
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, list[0].Object.GetPattern(), "[file:hashes.MD5 = '1717b7fff97d37a1e1a0029d83492de1']")
+
+		list, err = p.GetIndicatorsOfUsed(threatActorID)
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+		for _, v := range list {
+			assert.Equal(t, v.Path.Nodes[0], threatActorID)
+			assert.Len(t, v.Path.Edges, 2)
+			assert.Equal(t, v.Path.Edges[0].Type, "uses")
+			assert.Equal(t, v.Path.Edges[1].Type, "indicates")
+		}
+	})
+
+	t.Run("Цели, варианты и связанные объекты", func(t *testing.T) {
+		identities, err := p.GetTargetedIdentities(threatActorID)
+		assert.NoError(t, err)
+		assert.Len(t, identities, 1)
+
+		vulnerabilities, err := p.GetTargetedVulnerabilities(threatActorID)
+		assert.NoError(t, err)
+		assert.Len(t, vulnerabilities, 1)
+		assert.Equal(t, vulnerabilities[0].Object.GetName(), "CVE-2024-0001")
+
+		locations, err := p.GetTargetedLocations(threatActorID)
+		assert.NoError(t, err)
+		assert.Len(t, locations, 0)
+
+		variants, err := p.GetMalwareVariants(malwareID)
+		assert.NoError(t, err)
+		assert.Len(t, variants, 1)
+		assert.Equal(t, variants[0].Object.GetID(), variantID)
+
+		related, err := p.GetRelated(campaignID)
+		assert.NoError(t, err)
+		assert.Len(t, related, 1)
+		assert.Equal(t, related[0].ID, identityID)
+		assert.Equal(t, related[0].Path.Edges[0].Source, identityID)
+	})
+
+	t.Run("Произвольные переходы", func(t *testing.T) {
+		list, err := p.Follow(campaignID,
+			pivot.Step{RelationshipType: "attributed-to", Direction: stixgraph.DirectionOutgoing, Transitive: true},
+			pivot.Step{RelationshipType: "uses", Direction: stixgraph.DirectionOutgoing, TargetTypes: []string{"tool"}})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, list[0].ID, toolID)
+		assert.Len(t, list[0].Path.Edges, 3)
+
+		_, err = p.Follow("campaign--00000000-0000-0000-0000-000000000000")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,153 @@
+package redaction
+
+import (
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/relationshipobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/redaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	statement := methodstixobjects.NewStatementMarkingDefinitionObjectSTIX("Copyright 2024, Example Corp")
+	tlpRed, _ := stixhelpers.GetTLPMarkingDefinitionSTIX("red")
+
+	indicator := methodstixobjects.NewIndicatorDomainObjectsSTIX()
+	indicator.SetValueID("indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f")
+	indicator.SetValueName("indicator name")
+	indicator.SetValueDescription("secret description")
+	indicator.SetValuePattern("[ipv4-addr:value = '198.51.100.1']")
+	indicator.SetValueObjectMarkingRefs([]stixhelpers.IdentifierTypeSTIX{stixhelpers.TLP20GreenMarkingDefinitionID})
+	indicator.SetValueExternalReferences([]stixhelpers.ExternalReferenceTypeElementSTIX{
+		{SourceName: "internal", URL: "http://internal.example.com"},
+		{SourceName: "public", URL: "http://example.com"},
+	})
+	indicator.SetValueGranularMarkings([]stixhelpers.GranularMarkingsTypeSTIX{
+		{MarkingRef: stixhelpers.TLPRedMarkingDefinitionID, Selectors: []string{"description", "external_references.[0]"}},
+		{MarkingRef: stixhelpers.TLPGreenMarkingDefinitionID, Selectors: []string{"external_references.[1].url"}},
+		{MarkingRef: stixhelpers.IdentifierTypeSTIX(statement.GetID()), Selectors: []string{"name"}},
+	})
+
+	malware := methodstixobjects.NewMalwareDomainObjectsSTIX()
+	malware.SetValueID("malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b")
+	malware.SetValueName("malware name")
+	malware.SetValueObjectMarkingRefs([]stixhelpers.IdentifierTypeSTIX{stixhelpers.TLP20RedMarkingDefinitionID})
+
+	relationship := methodstixobjects.NewRelationshipObjectSTIX()
+	relationship.SetValueID("relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad")
+	relationship.SetValueRelationshipType("indicates")
+	relationship.SetValueSourceRef(stixhelpers.IdentifierTypeSTIX(indicator.GetID()))
+	relationship.SetValueTargetRef(stixhelpers.IdentifierTypeSTIX(malware.GetID()))
+
+	//отношение ссылающееся на удаляемое отношение
+	relationshipOfRelationship := methodstixobjects.NewRelationshipObjectSTIX()
+	relationshipOfRelationship.SetValueID("relationship--a1a7b2a0-f2c4-4f5e-8a0c-cc4f2f0c1e57")
+	relationshipOfRelationship.SetValueRelationshipType("related-to")
+	relationshipOfRelationship.SetValueSourceRef(stixhelpers.IdentifierTypeSTIX(relationship.GetID()))
+	relationshipOfRelationship.SetValueTargetRef(stixhelpers.IdentifierTypeSTIX(indicator.GetID()))
+
+	sighting := methodstixobjects.NewSightingObjectSTIX()
+	sighting.SetValueID("sighting--ee20065d-2555-424f-ad9e-0f8428623c75")
+	sighting.SetValueSightingOfRef(stixhelpers.IdentifierTypeSTIX(indicator.GetID()))
+	sighting.SetValueWhereSightedRefs([]stixhelpers.IdentifierTypeSTIX{"identity--b38dfe21-7477-40d1-aa90-5c8671ce51ca"})
+
+	objects := []interface{}{*statement, tlpRed, indicator, *malware, *relationship, *relationshipOfRelationship, *sighting}
+
+	result, report, err := redaction.Redact(objects, redaction.Policy{MaxTLP: stixhelpers.TLPLevelAmber})
+	assert.NoError(t, err)
+	assert.Equal(t, len(result), 2)
+	assert.Equal(t, len(report.RemovedObjects), 5)
+	assert.Equal(t, len(report.RedactedProperties), 3)
+
+	//исходные объекты не изменяются
+	assert.Equal(t, indicator.GetDescription(), "secret description")
+
+	ri, ok := result[0].(*domainobjectsstix.IndicatorDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, ri.GetName(), "")
+	assert.Equal(t, ri.GetDescription(), "")
+	assert.Equal(t, ri.GetPattern(), "[ipv4-addr:value = '198.51.100.1']")
+	assert.Equal(t, len(ri.GetExternalReferences()), 1)
+	assert.Equal(t, ri.GetExternalReferences()[0].SourceName, "public")
+	assert.Equal(t, ri.GetGranularMarkings(), []stixhelpers.GranularMarkingsTypeSTIX{
+		{MarkingRef: stixhelpers.TLPGreenMarkingDefinitionID, Selectors: []string{"external_references.[0].url"}},
+	})
+
+	_, ok = result[1].(relationshipobjectsstix.SightingObjectSTIX)
+	assert.True(t, ok)
+
+	removed := map[string]string{}
+	for _, v := range report.RemovedObjects {
+		removed[v.ID] = v.Reason
+	}
+	assert.Equal(t, removed[statement.GetID()], redaction.ReasonMarkingDefinition)
+	assert.Equal(t, removed[tlpRed.GetID()], redaction.ReasonMarkingDefinition)
+	assert.Equal(t, removed[malware.GetID()], redaction.ReasonObjectMarking)
+	assert.Equal(t, removed[relationship.GetID()], redaction.ReasonReference)
+	assert.Equal(t, removed[relationshipOfRelationship.GetID()], redaction.ReasonReference)
+
+	//получатель с более широкими правами
+	result, report, err = redaction.Redact(objects, redaction.Policy{
+		MaxTLP:            stixhelpers.TLPLevelRed,
+		AllowedStatements: []stixhelpers.IdentifierTypeSTIX{stixhelpers.IdentifierTypeSTIX(statement.GetID())},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(result), len(objects))
+	assert.Equal(t, len(report.RemovedObjects), 0)
+	assert.Equal(t, len(report.RedactedProperties), 0)
+
+	ri, ok = result[2].(*domainobjectsstix.IndicatorDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, ri.GetDescription(), "secret description")
+	assert.Equal(t, len(ri.GetGranularMarkings()), 3)
+}
+
+func TestRedactMalformedGranularMarkings(t *testing.T) {
+	indicator := methodstixobjects.NewIndicatorDomainObjectsSTIX()
+	indicator.SetValueID("indicator--0b1a7c7e-47c1-4e3a-9b1f-1e6f52c2f0d4")
+	indicator.SetValueName("indicator name")
+	indicator.SetValuePattern("[ipv4-addr:value = '198.51.100.1']")
+	indicator.SetValueGranularMarkings([]stixhelpers.GranularMarkingsTypeSTIX{
+		{MarkingRef: stixhelpers.TLPRedMarkingDefinitionID, Selectors: []string{"external_references.[x"}},
+	})
+
+	malware := methodstixobjects.NewMalwareDomainObjectsSTIX()
+	malware.SetValueID("malware--5f7d1e2a-8a9b-4c3d-9e0f-1a2b3c4d5e6f")
+	malware.SetValueName("malware name")
+
+	//отчет ссылающийся на удаляемый объект
+	report := methodstixobjects.NewReportDomainObjectsSTIX()
+	report.SetValueID("report--84e4d88f-44ea-4bcd-bbf3-b2c1c320bcb3")
+	report.SetValueName("report name")
+	report.SetValueObjectRefs([]stixhelpers.IdentifierTypeSTIX{
+		stixhelpers.IdentifierTypeSTIX(indicator.GetID()),
+		stixhelpers.IdentifierTypeSTIX(malware.GetID()),
+	})
+
+	//отчет ссылающийся только на удаляемый объект
+	reportIndicator := methodstixobjects.NewReportDomainObjectsSTIX()
+	reportIndicator.SetValueID("report--9f3c2b1a-0e4d-4f5a-8b6c-7d8e9f0a1b2c")
+	reportIndicator.SetValueName("report indicator")
+	reportIndicator.SetValueObjectRefs([]stixhelpers.IdentifierTypeSTIX{stixhelpers.IdentifierTypeSTIX(indicator.GetID())})
+
+	objects := []interface{}{indicator, malware, report, reportIndicator}
+
+	result, rep, err := redaction.Redact(objects, redaction.Policy{MaxTLP: stixhelpers.TLPLevelAmber})
+	assert.NoError(t, err)
+	assert.Equal(t, len(result), 2)
+	assert.Equal(t, rep.RemovedObjects, []redaction.RemovedObject{
+		{ID: indicator.GetID(), Type: "indicator", Reason: redaction.ReasonMalformedGranularMarking},
+		{ID: reportIndicator.GetID(), Type: "report", Reason: redaction.ReasonReference},
+	})
+	assert.Equal(t, rep.RemovedRefs, []redaction.RemovedRef{{ID: report.GetID(), Ref: indicator.GetID()}})
+
+	rr, ok := result[1].(*domainobjectsstix.ReportDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, rr.GetObjectRefs(), []stixhelpers.IdentifierTypeSTIX{stixhelpers.IdentifierTypeSTIX(malware.GetID())})
+
+	//исходные объекты не изменяются
+	assert.Equal(t, len(report.GetObjectRefs()), 2)
+}
@@ -23,6 +23,21 @@ func TestCreateOptionalCommonPropertiesRelationshipObjectSTIX(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestValidateStructCommonFieldsRelationshipObjectSTIX(t *testing.T) {
+	nocp := methodstixobjects.NewOptionalCommonPropertiesRelationshipObjectSTIX()
+
+	//корректное значение spec_version проходит проверку
+	nocp.SetValueSpecVersion("2.1")
+	assert.True(t, nocp.ValidateStructCommonFields())
+
+	//некорректное или пустое значение spec_version проверку не проходит
+	nocp.SetValueSpecVersion("2.1'; DROP")
+	assert.False(t, nocp.ValidateStructCommonFields())
+
+	nocp.SetValueSpecVersion("")
+	assert.False(t, nocp.ValidateStructCommonFields())
+}
+
 func TestRelationshipObjectSTIX(t *testing.T) {
 	nro := methodstixobjects.NewRelationshipObjectSTIX()
 
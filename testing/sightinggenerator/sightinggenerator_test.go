@@ -0,0 +1,144 @@
+package sightinggenerator
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/sightinggenerator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSightingGenerator(t *testing.T) {
+	sg := sightinggenerator.NewSightingGenerator()
+
+	_, _, err := sg.HandleMatch(sightinggenerator.MatchResult{})
+	assert.Error(t, err)
+
+	indicatorID := stixhelpers.IdentifierTypeSTIX("indicator--9c3e8d1f-6e4a-4b0b-a4a5-0d3e5c9a1f7b")
+
+	s, created, err := sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID:      indicatorID,
+		ObservedDataRefs: []stixhelpers.IdentifierTypeSTIX{"observed-data--b67d30ff-02ac-498a-92f9-32f845f448cf"},
+		WhereSightedRefs: []stixhelpers.IdentifierTypeSTIX{"identity--f431f809-377b-45e0-aa1c-6a4751cae5ff"},
+		FirstSeen:        "2024-02-04T14:31:21+00:00",
+		LastSeen:         "2024-02-04T15:01:11+00:00",
+	})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, s.GetType(), "sighting")
+	assert.Regexp(t, `^sighting--[0-9a-f-]{36}$`, s.GetID())
+	assert.Equal(t, s.GetSightingOfRef(), indicatorID)
+	assert.Equal(t, s.GetCount(), 1)
+	assert.True(t, s.ValidateStruct())
+
+	s, created, err = sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID:      indicatorID,
+		ObservedDataRefs: []stixhelpers.IdentifierTypeSTIX{"observed-data--2a3b9d8c-6a1d-4d4e-8f33-3c4e2b0f6a11"},
+		WhereSightedRefs: []stixhelpers.IdentifierTypeSTIX{
+			"identity--f431f809-377b-45e0-aa1c-6a4751cae5ff",
+			"location--a6e9345f-5a15-4c29-8bb3-7dcc5d168d64",
+		},
+		FirstSeen: "2024-02-01T10:00:00+00:00",
+		LastSeen:  "2024-02-03T10:00:00+00:00",
+		Count:     3,
+	})
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, s.GetCount(), 4)
+	assert.Equal(t, s.GetFirstSeen(), "2024-02-01T10:00:00+00:00")
+	assert.Equal(t, s.GetLastSeen(), "2024-02-04T15:01:11+00:00")
+	assert.Equal(t, len(s.GetObservedDataRefs()), 2)
+	assert.Equal(t, len(s.GetWhereSightedRefs()), 2)
+
+	_, _, err = sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID: indicatorID,
+		FirstSeen:   "04.02.2024",
+	})
+	assert.Error(t, err)
+
+	//sighting_of_ref должен ссылаться на объект "indicator"
+	_, _, err = sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID: "ipv4-addr--c3a5a5d2-1a8b-4a4c-9f1e-2d1b3c4d5e6f",
+	})
+	assert.Error(t, err)
+}
+
+func TestSightingGeneratorTimeWindow(t *testing.T) {
+	sg := sightinggenerator.NewSightingGenerator()
+
+	indicatorID := stixhelpers.IdentifierTypeSTIX("indicator--9c3e8d1f-6e4a-4b0b-a4a5-0d3e5c9a1f7b")
+
+	//LastSeen не может быть раньше FirstSeen
+	_, _, err := sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID: indicatorID,
+		FirstSeen:   "2024-02-04T15:00:00+00:00",
+		LastSeen:    "2024-02-04T14:00:00+00:00",
+	})
+	assert.Error(t, err)
+
+	_, _, err = sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID: indicatorID,
+		FirstSeen:   "2024-02-04T15:00:00+03:00",
+	})
+	assert.NoError(t, err)
+
+	//совпадения поступают не по порядку и с разными часовыми поясами, окно только расширяется
+	s, _, err := sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID: indicatorID,
+		FirstSeen:   "2024-02-04T13:00:00Z",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, s.GetFirstSeen(), "2024-02-04T15:00:00+03:00")
+	assert.Equal(t, s.GetLastSeen(), "2024-02-04T13:00:00Z")
+
+	s, _, err = sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID: indicatorID,
+		FirstSeen:   "2024-02-04T12:30:00+00:00",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, s.GetFirstSeen(), "2024-02-04T15:00:00+03:00")
+	assert.Equal(t, s.GetLastSeen(), "2024-02-04T13:00:00Z")
+
+	s, _, err = sg.HandleMatch(sightinggenerator.MatchResult{
+		IndicatorID: indicatorID,
+		FirstSeen:   "2024-02-04T11:00:00Z",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, s.GetFirstSeen(), "2024-02-04T11:00:00Z")
+	assert.Equal(t, s.GetLastSeen(), "2024-02-04T13:00:00Z")
+	assert.Equal(t, s.GetCount(), 4)
+}
+
+func TestSightingGeneratorConcurrency(t *testing.T) {
+	sg := sightinggenerator.NewSightingGenerator()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(num int) {
+			defer wg.Done()
+
+			_, _, err := sg.HandleMatch(sightinggenerator.MatchResult{
+				IndicatorID:      stixhelpers.IdentifierTypeSTIX(fmt.Sprintf("indicator--00000000-0000-4000-8000-00000000000%d", num%2)),
+				WhereSightedRefs: []stixhelpers.IdentifierTypeSTIX{"identity--f431f809-377b-45e0-aa1c-6a4751cae5ff"},
+				FirstSeen:        fmt.Sprintf("2024-02-04T14:%02d:00+00:00", num),
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	list := sg.GetSightings()
+	assert.Equal(t, len(list), 2)
+	for _, s := range list {
+		assert.Equal(t, s.GetCount(), 25)
+		assert.Equal(t, len(s.GetWhereSightedRefs()), 1)
+	}
+
+	s, ok := sg.GetSighting("indicator--00000000-0000-4000-8000-000000000000")
+	assert.True(t, ok)
+	assert.Equal(t, s.GetFirstSeen(), "2024-02-04T14:00:00+00:00")
+	assert.Equal(t, s.GetLastSeen(), "2024-02-04T14:48:00+00:00")
+}
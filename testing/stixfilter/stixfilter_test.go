@@ -0,0 +1,170 @@
+package stixfilter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/stixfilter"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func getObjects() []interface{} {
+	return []interface{}{
+		json.RawMessage(`{"type": "indicator", "id": "indicator--1", "name": "APT hash", "pattern_type": "stix", "valid_until": "2099-01-01T00:00:00Z", "labels": ["apt", "malicious-activity"], "confidence": 80,
+			"external_references": [{"source_name": "capec", "external_id": "CAPEC-163"}, {"source_name": "mitre-attack", "external_id": "T1566"}]}`),
+		json.RawMessage(`{"type": "indicator", "id": "indicator--2", "name": "Old rule", "pattern_type": "snort", "valid_until": "2000-01-01T00:00:00+03:00", "labels": ["benign"], "confidence": 20}`),
+		json.RawMessage(`{"type": "file", "id": "file--3", "name": "a.exe", "hashes": {"SHA-256": "4bac27393bdd9777ce02453256c5577cd02275510b2227f473d03f533924f877"}}`),
+	}
+}
+
+func getIDs(t *testing.T, expr stixfilter.Expression) []string {
+	list, err := stixfilter.Apply(getObjects(), expr)
+	assert.NoError(t, err)
+
+	ids := []string{}
+	for _, v := range list {
+		obj := struct {
+			ID string `json:"id"`
+		}{}
+		assert.NoError(t, json.Unmarshal(v.(json.RawMessage), &obj))
+		ids = append(ids, obj.ID)
+	}
+
+	return ids
+}
+
+func TestFilter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Операторы", func(t *testing.T) {
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "pattern_type", Operator: stixfilter.OperatorEqual, Value: "stix"}), []string{"indicator--1"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "type", Operator: stixfilter.OperatorNotEqual, Value: "file"}), []string{"indicator--1", "indicator--2"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "confidence", Operator: stixfilter.OperatorGreater, Value: 50}), []string{"indicator--1"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "confidence", Operator: stixfilter.OperatorLess, Value: 50.5}), []string{"indicator--2"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "pattern_type", Operator: stixfilter.OperatorIn, Value: []string{"snort", "yara"}}), []string{"indicator--2"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "labels", Operator: stixfilter.OperatorContains, Value: "apt"}), []string{"indicator--1"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "name", Operator: stixfilter.OperatorContains, Value: ".exe"}), []string{"file--3"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "labels", Operator: stixfilter.OperatorExists}), []string{"indicator--1", "indicator--2"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "labels", Operator: stixfilter.OperatorExists, Value: false}), []string{"file--3"})
+
+		//отсутствующее свойство не удовлетворяет условию
+		assert.Len(t, getIDs(t, stixfilter.Filter{Property: "pattern_type", Operator: stixfilter.OperatorNotEqual, Value: "stix"}), 1)
+	})
+
+	t.Run("Вложенные свойства и время", func(t *testing.T) {
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "external_references.source_name", Operator: stixfilter.OperatorEqual, Value: "mitre-attack"}), []string{"indicator--1"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "external_references.1.external_id", Operator: stixfilter.OperatorEqual, Value: "T1566"}), []string{"indicator--1"})
+		assert.Len(t, getIDs(t, stixfilter.Filter{Property: "external_references.0.external_id", Operator: stixfilter.OperatorEqual, Value: "T1566"}), 0)
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "hashes.SHA-256", Operator: stixfilter.OperatorExists}), []string{"file--3"})
+
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "valid_until", Operator: stixfilter.OperatorGreater, Value: now}), []string{"indicator--1"})
+		//строки в формате RFC3339 сравниваются как время с учетом часового пояса
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "valid_until", Operator: stixfilter.OperatorLess, Value: "1999-12-31T22:00:00.001Z"}), []string{"indicator--2"})
+		assert.Equal(t, getIDs(t, stixfilter.Filter{Property: "valid_until", Operator: stixfilter.OperatorEqual, Value: "1999-12-31T21:00:00Z"}), []string{"indicator--2"})
+	})
+
+	t.Run("Объединение условий", func(t *testing.T) {
+		expr := stixfilter.And{
+			stixfilter.Filter{Property: "type", Operator: stixfilter.OperatorEqual, Value: "indicator"},
+			stixfilter.Or{
+				stixfilter.Filter{Property: "valid_until", Operator: stixfilter.OperatorGreater, Value: now},
+				stixfilter.Filter{Property: "labels", Operator: stixfilter.OperatorContains, Value: "benign"},
+			},
+		}
+		assert.Equal(t, getIDs(t, expr), []string{"indicator--1", "indicator--2"})
+		assert.Len(t, getIDs(t, stixfilter.Or{}), 0)
+		assert.Len(t, getIDs(t, stixfilter.And{}), 3)
+	})
+
+	t.Run("Проверка условий", func(t *testing.T) {
+		_, err := stixfilter.NewFilter("name", "like", "a")
+		assert.Error(t, err)
+		_, err = stixfilter.NewFilter("name", stixfilter.OperatorIn, "a")
+		assert.Error(t, err)
+		_, err = stixfilter.NewFilter("", stixfilter.OperatorEqual, "a")
+		assert.Error(t, err)
+		_, err = stixfilter.NewFilter("name", stixfilter.OperatorExists, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Типы объектов", func(t *testing.T) {
+		m := methodstixobjects.NewMalwareDomainObjectsSTIX()
+		m.SetValueID("malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b")
+		m.SetValueName("Poison Ivy")
+
+		list, err := stixfilter.Apply([]interface{}{*m}, stixfilter.Filter{Property: "name", Operator: stixfilter.OperatorEqual, Value: "Poison Ivy"})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+
+		//незаполненные свойства считаются отсутствующими
+		list, err = stixfilter.Apply([]interface{}{*m}, stixfilter.Filter{Property: "description", Operator: stixfilter.OperatorExists})
+		assert.NoError(t, err)
+		assert.Len(t, list, 0)
+
+		//время по умолчанию, которым конструкторы заполняют свойства, считается отсутствующим значением
+		i := methodstixobjects.NewIndicatorDomainObjectsSTIX()
+		i.SetValueID("indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f")
+
+		expr, err := stixfilter.Parse("valid_until exists false")
+		assert.NoError(t, err)
+		list, err = stixfilter.Apply([]interface{}{*i}, expr)
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+
+		list, err = stixfilter.Apply([]interface{}{*i}, stixfilter.Filter{Property: "valid_until", Operator: stixfilter.OperatorLess, Value: now})
+		assert.NoError(t, err)
+		assert.Len(t, list, 0)
+
+		assert.NoError(t, i.SetValueValidUntil("2099-01-01T00:00:00Z"))
+		list, err = stixfilter.Apply([]interface{}{*i}, expr)
+		assert.NoError(t, err)
+		assert.Len(t, list, 0)
+	})
+
+	t.Run("Использование хранилищем", func(t *testing.T) {
+		s, err := stixstore.NewMemoryStore(getObjects()...)
+		assert.NoError(t, err)
+
+		list, err := s.Query(stixfilter.Filter{Property: "labels", Operator: stixfilter.OperatorContains, Value: "apt"})
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+}
+
+func TestParse(t *testing.T) {
+	expr, err := stixfilter.Parse(`type = 'indicator' AND (valid_until > "2024-01-01T00:00:00Z" OR labels contains 'benign')`)
+	assert.NoError(t, err)
+	assert.Equal(t, getIDs(t, expr), []string{"indicator--1", "indicator--2"})
+
+	expr, err = stixfilter.Parse(`external_references.source_name in ('capec', 'x') or hashes.SHA-256 exists`)
+	assert.NoError(t, err)
+	assert.Equal(t, getIDs(t, expr), []string{"indicator--1", "file--3"})
+
+	expr, err = stixfilter.Parse(`confidence >= 10`)
+	assert.Error(t, err)
+	assert.Nil(t, expr)
+
+	expr, err = stixfilter.Parse(`confidence > -1 AND labels exists false`)
+	assert.NoError(t, err)
+	assert.Equal(t, getIDs(t, expr), []string{})
+
+	expr, err = stixfilter.Parse(`name = 'O\'Brien' AND x != true`)
+	assert.NoError(t, err)
+	assert.Equal(t, expr, stixfilter.And{
+		stixfilter.Filter{Property: "name", Operator: stixfilter.OperatorEqual, Value: "O'Brien"},
+		stixfilter.Filter{Property: "x", Operator: stixfilter.OperatorNotEqual, Value: true},
+	})
+
+	//строковое представление разбирается в исходное условие
+	again, err := stixfilter.Parse(expr.(stixfilter.And).String())
+	assert.NoError(t, err)
+	assert.Equal(t, again, expr)
+
+	for _, v := range []string{`name =`, `(name = 'a'`, `name = 'a' extra`, `name in 'a'`, `= 'a'`, `name = 'a`} {
+		_, err := stixfilter.Parse(v)
+		assert.Error(t, err, v)
+	}
+}
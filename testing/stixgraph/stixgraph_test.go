@@ -0,0 +1,172 @@
+package stixgraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/relationshipobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/stixgraph"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	threatActorID = "threat-actor--56f3f0db-b5d5-431c-ae56-c18f02caf500"
+	malwareID     = "malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b"
+	indicatorID   = "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	identityID    = "identity--f431f809-377b-45e0-aa1c-6a4751cae5ff"
+	toolID        = "tool--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	usesID        = "relationship--44298a74-ba52-4f0c-87a3-1824e67d7fad"
+	indicatesID   = "relationship--3ddf3e34-0d8d-4e40-b5e3-6a3b9cc4d4f3"
+	brokenID      = "relationship--9f3e6e44-1d2d-4c5a-8b6a-22cbe3bd0c11"
+	sightingID    = "sighting--ee20065d-2555-424f-ad9e-0f8428623c75"
+	campaignID    = "campaign--83422c77-904c-4dc1-aff5-5c38f3a2c55c"
+)
+
+func getRelationship(id, relType, source, target string) *relationshipobjectsstix.RelationshipObjectSTIX {
+	r := methodstixobjects.NewRelationshipObjectSTIX()
+	r.SetValueID(id)
+	r.SetValueRelationshipType(relType)
+	r.SetValueSourceRef(stixhelpers.IdentifierTypeSTIX(source))
+	r.SetValueTargetRef(stixhelpers.IdentifierTypeSTIX(target))
+
+	return r
+}
+
+func getObjects() []interface{} {
+	ta := methodstixobjects.NewThreatActorDomainObjectsSTIX()
+	ta.SetValueID(threatActorID)
+	ta.SetValueName("APT")
+
+	m := methodstixobjects.NewMalwareDomainObjectsSTIX()
+	m.SetValueID(malwareID)
+	m.SetValueName("Poison Ivy")
+	m.SetValueCreatedByRef(identityID)
+
+	i := methodstixobjects.NewIndicatorDomainObjectsSTIX()
+	i.SetValueID(indicatorID)
+	i.SetValueName("Poison Ivy hash")
+
+	id := methodstixobjects.NewIdentityDomainObjectsSTIX()
+	id.SetValueID(identityID)
+	id.SetValueName("ACME")
+
+	tool := methodstixobjects.NewToolDomainObjectsSTIX()
+	tool.SetValueID(toolID)
+	tool.SetValueName("nmap")
+
+	s := methodstixobjects.NewSightingObjectSTIX()
+	s.SetValueID(sightingID)
+	s.SetValueSightingOfRef(indicatorID)
+	s.SetValueWhereSightedRefs([]stixhelpers.IdentifierTypeSTIX{identityID})
+
+	return []interface{}{
+		ta, m, i, id, tool, s,
+		getRelationship(usesID, "uses", threatActorID, malwareID),
+		getRelationship(indicatesID, "indicates", indicatorID, malwareID),
+		getRelationship(brokenID, "attributed-to", campaignID, threatActorID),
+	}
+}
+
+func TestGraph(t *testing.T) {
+	g, err := stixgraph.NewGraph(getObjects())
+	assert.NoError(t, err)
+
+	assert.Equal(t, g.GetNodes(), []string{identityID, indicatorID, malwareID, threatActorID, toolID})
+	assert.Len(t, g.GetEdges(), 5)
+
+	t.Run("Соседние узлы", func(t *testing.T) {
+		list := g.GetNeighbours(malwareID, stixgraph.EdgeFilter{})
+		ids := []string{}
+		for _, v := range list {
+			ids = append(ids, v.ID)
+		}
+		assert.ElementsMatch(t, ids, []string{identityID, threatActorID, indicatorID})
+
+		list = g.GetNeighbours(malwareID, stixgraph.EdgeFilter{Direction: stixgraph.DirectionIncoming, Types: []string{"uses"}})
+		assert.Len(t, list, 1)
+		assert.Equal(t, list[0].ID, threatActorID)
+		assert.Equal(t, list[0].Edge.ID, usesID)
+
+		list = g.GetNeighbours(malwareID, stixgraph.EdgeFilter{Direction: stixgraph.DirectionOutgoing})
+		assert.Len(t, list, 1)
+		assert.Equal(t, list[0].ID, identityID)
+		assert.True(t, list[0].Edge.IsEmbedded())
+		assert.Equal(t, list[0].Edge.Type, "created_by_ref")
+
+		//ребро к отсутствующему узлу не учитывается
+		assert.Len(t, g.GetNeighbours(threatActorID, stixgraph.EdgeFilter{Direction: stixgraph.DirectionIncoming}), 0)
+	})
+
+	t.Run("Кратчайший путь", func(t *testing.T) {
+		path, err := g.GetShortestPath(threatActorID, identityID, stixgraph.EdgeFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, path.Nodes, []string{threatActorID, malwareID, identityID})
+		assert.Len(t, path.Edges, 2)
+
+		_, err = g.GetShortestPath(threatActorID, identityID, stixgraph.EdgeFilter{Direction: stixgraph.DirectionOutgoing, Types: []string{"uses"}})
+		assert.Error(t, err)
+
+		_, err = g.GetShortestPath(threatActorID, toolID, stixgraph.EdgeFilter{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Компоненты связности", func(t *testing.T) {
+		assert.Equal(t, g.GetConnectedComponents(), [][]string{
+			{identityID, indicatorID, malwareID, threatActorID},
+			{toolID},
+		})
+	})
+
+	t.Run("Подграф", func(t *testing.T) {
+		bundle, err := g.GetSubgraph(threatActorID, 1, stixgraph.EdgeFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, bundle.Type, "bundle")
+
+		ids := []string{}
+		for _, v := range bundle.Objects {
+			b, _ := json.Marshal(v)
+			obj := struct {
+				ID string `json:"id"`
+			}{}
+			assert.NoError(t, json.Unmarshal(b, &obj))
+			ids = append(ids, obj.ID)
+		}
+		assert.Equal(t, ids, []string{threatActorID, malwareID, usesID})
+
+		bundle, err = g.GetSubgraph(threatActorID, 2, stixgraph.EdgeFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, bundle.Objects, 7)
+
+		_, err = g.GetSubgraph(campaignID, 1, stixgraph.EdgeFilter{})
+		assert.Error(t, err)
+	})
+
+	t.Run("Объекты в виде JSON", func(t *testing.T) {
+		g, err := stixgraph.NewGraph([]interface{}{
+			json.RawMessage(`{"type": "malware", "id": "` + malwareID + `", "name": "Poison Ivy"}`),
+			json.RawMessage(`{"type": "threat-actor", "id": "` + threatActorID + `", "name": "APT"}`),
+			json.RawMessage(`{"type": "relationship", "id": "` + usesID + `", "relationship_type": "uses", "source_ref": "` + threatActorID + `", "target_ref": "` + malwareID + `"}`),
+		})
+		assert.NoError(t, err)
+		assert.Len(t, g.GetNeighbours(threatActorID, stixgraph.EdgeFilter{}), 1)
+
+		_, err = stixgraph.NewGraph([]interface{}{json.RawMessage(`{"name": "without id"}`)})
+		assert.Error(t, err)
+	})
+
+	t.Run("Версии объекта в разных часовых поясах", func(t *testing.T) {
+		//12:00+03:00 раньше 10:00Z, хотя при сравнении строк наоборот
+		g, err := stixgraph.NewGraph([]interface{}{
+			json.RawMessage(`{"type": "malware", "id": "` + malwareID + `", "name": "old", "modified": "2024-01-01T12:00:00+03:00"}`),
+			json.RawMessage(`{"type": "malware", "id": "` + malwareID + `", "name": "new", "modified": "2024-01-01T10:00:00Z"}`),
+			json.RawMessage(`{"type": "malware", "id": "` + malwareID + `", "name": "older", "modified": "2024-01-01T08:00:00Z"}`),
+		})
+		assert.NoError(t, err)
+
+		obj, ok := g.GetNode(malwareID)
+		assert.True(t, ok)
+		assert.Contains(t, string(obj.(json.RawMessage)), `"name": "new"`)
+	})
+}
@@ -0,0 +1,165 @@
+package stixindex
+
+import (
+	"encoding/json"
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/stixhelpers"
+	"github.com/av-belyakov/methodstixobjects/stixindex"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	toolID     = "tool--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	malwareID  = "malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b"
+	reportID   = "report--84e4d88f-44ea-4bcd-bbf3-b2c1c320bcb3"
+	fileID     = "file--5a27d487-c542-5f97-a131-a8866b477b46"
+	domainID   = "domain-name--3c10e93f-798e-5a26-a0c1-08156efab7f5"
+	identityID = "identity--311b2d2d-f010-4473-83ec-1edf84858f4c"
+)
+
+func getObjects() []interface{} {
+	tool := methodstixobjects.NewToolDomainObjectsSTIX()
+	tool.SetValueID(toolID)
+	tool.SetValueName("Cobalt Strike")
+	tool.SetValueDescription("Commercial adversary simulation software")
+	tool.SetValueAliases("CS")
+	tool.SetValueAliases("Beacon")
+	tool.SetValueExternalReferences([]stixhelpers.ExternalReferenceTypeElementSTIX{{SourceName: "mitre-attack", ExternalID: "S0154"}})
+
+	malware := methodstixobjects.NewMalwareDomainObjectsSTIX()
+	malware.SetValueID(malwareID)
+	malware.SetValueName("Poison Ivy")
+	malware.SetValueDescription("Remote access trojan, often delivered together with cobalt strike beacons")
+
+	file := methodstixobjects.NewFileCyberObservableObjectSTIX()
+	file.SetValueID(fileID)
+	file.SetValueName("beacon.exe")
+	file.SetValueHashes(stixhelpers.HashesTypeSTIX{"SHA-256": "4BAC27393BDD9777CE02453256C5577CD02275510B2227F473D03F533924F877"})
+
+	return []interface{}{
+		*tool,
+		*malware,
+		*file,
+		json.RawMessage(`{"type": "report", "id": "` + reportID + `", "name": "Cobaltic group activity", "description": "Campaign using CVE-2021-44228"}`),
+		json.RawMessage(`{"type": "domain-name", "id": "` + domainID + `", "value": "evil-domain.example.com"}`),
+		//объект пользовательского типа индексируется по строковым свойствам
+		json.RawMessage(`{"type": "x-custom-object", "id": "x-custom-object--1", "title": "notes about cobalt", "object_refs": ["` + toolID + `"]}`),
+	}
+}
+
+func getIDs(list []stixindex.Result) []string {
+	ids := []string{}
+	for _, v := range list {
+		ids = append(ids, v.ID)
+	}
+
+	return ids
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, stixindex.Tokenize("Evil-Domain.com, 10.0.0.1 (CVE-2021-44228)."), []string{
+		"evil-domain.com", "evil", "domain", "com", "10.0.0.1", "10", "0", "1", "cve-2021-44228", "cve", "2021", "44228",
+	})
+	assert.Equal(t, stixindex.Tokenize("Вредоносное ПО"), []string{"вредоносное", "по"})
+	assert.Len(t, stixindex.Tokenize(" ... "), 0)
+}
+
+func TestGeneratingDataForIndexing(t *testing.T) {
+	data, err := stixindex.GetDataForIndexing(getObjects()[0])
+	assert.NoError(t, err)
+	assert.Equal(t, data["name"], "Cobalt Strike")
+	assert.Equal(t, data["external_id"], "S0154")
+	assert.Contains(t, data["aliases"], "Beacon")
+
+	data, err = stixindex.GetDataForIndexing(getObjects()[2])
+	assert.NoError(t, err)
+	assert.Equal(t, data["hashes"], "4BAC27393BDD9777CE02453256C5577CD02275510B2227F473D03F533924F877")
+
+	//объекты в виде JSON декодируются в типы пакета
+	data, err = stixindex.GetDataForIndexing(getObjects()[4])
+	assert.NoError(t, err)
+	assert.Equal(t, data, map[string]string{"id": domainID, "type": "domain-name", "value": "evil-domain.example.com"})
+
+	data, err = stixindex.GetDataForIndexing(getObjects()[5])
+	assert.NoError(t, err)
+	assert.Equal(t, data, map[string]string{"id": "x-custom-object--1", "type": "x-custom-object", "title": "notes about cobalt"})
+
+	identity := methodstixobjects.NewIdentityDomainObjectsSTIX()
+	identity.SetValueID(identityID)
+	identity.SetValueName("ACME")
+	identity.SetValueSectors("financial-services")
+	identity.SetValueSectors("technology")
+	assert.Equal(t, identity.GeneratingDataForIndexing()["sectors"], "financial-services technology")
+
+	indicator := methodstixobjects.NewIndicatorDomainObjectsSTIX()
+	indicator.SetValueID("indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f")
+	indicator.SetValuePattern("[file:name = 'beacon.exe']")
+	assert.Equal(t, indicator.GeneratingDataForIndexing()["pattern"], "[file:name = 'beacon.exe']")
+
+	relationship := methodstixobjects.NewRelationshipObjectSTIX()
+	relationship.SetValueID("relationship--1")
+	relationship.SetValueRelationshipType("uses")
+	relationship.SetValueTargetRef(toolID)
+	assert.Equal(t, relationship.GeneratingDataForIndexing()["relationship_type"], "uses")
+	assert.Equal(t, relationship.GeneratingDataForIndexing()["target_ref"], toolID)
+}
+
+func TestIndex(t *testing.T) {
+	idx := stixindex.NewIndex(stixindex.Options{})
+	assert.NoError(t, idx.Add(getObjects()...))
+	assert.Equal(t, idx.Len(), 6)
+
+	t.Run("Поиск по всем типам объектов", func(t *testing.T) {
+		list := idx.Search("cobalt", stixindex.SearchOptions{})
+
+		//совпадение в наименовании оценивается выше совпадения в описании, совпадение целиком выше совпадения по префиксу
+		assert.Equal(t, getIDs(list), []string{toolID, reportID, malwareID, "x-custom-object--1"})
+		assert.Equal(t, list[0].Type, "tool")
+		assert.Equal(t, list[0].Fields, []string{"name"})
+		assert.Greater(t, list[0].Score, list[1].Score)
+	})
+
+	t.Run("Поиск по префиксу и нескольким лексемам", func(t *testing.T) {
+		assert.Equal(t, getIDs(idx.Search("Cobalt Str", stixindex.SearchOptions{})), []string{toolID, malwareID})
+		assert.Equal(t, getIDs(idx.Search("beac", stixindex.SearchOptions{})), []string{fileID, toolID, malwareID})
+		assert.Equal(t, getIDs(idx.Search("cobalt", stixindex.SearchOptions{ExactOnly: true})), []string{toolID, malwareID, "x-custom-object--1"})
+		assert.Len(t, idx.Search("cobalt ivy-league", stixindex.SearchOptions{}), 0)
+		assert.Len(t, idx.Search("  ", stixindex.SearchOptions{}), 0)
+	})
+
+	t.Run("Поиск значений", func(t *testing.T) {
+		assert.Equal(t, getIDs(idx.Search("CVE-2021-44228", stixindex.SearchOptions{})), []string{reportID})
+		assert.Equal(t, getIDs(idx.Search("s0154", stixindex.SearchOptions{})), []string{toolID})
+		assert.Equal(t, getIDs(idx.Search("4bac2739", stixindex.SearchOptions{})), []string{fileID})
+		assert.Equal(t, getIDs(idx.Search("evil-domain", stixindex.SearchOptions{})), []string{domainID})
+		assert.Equal(t, getIDs(idx.Search(malwareID, stixindex.SearchOptions{})), []string{malwareID})
+
+		//тип объекта в составе идентификатора не индексируется
+		assert.Len(t, idx.Search("tool", stixindex.SearchOptions{}), 0)
+	})
+
+	t.Run("Ограничения поиска", func(t *testing.T) {
+		assert.Equal(t, getIDs(idx.Search("cobalt", stixindex.SearchOptions{Types: []string{"malware", "report"}})), []string{reportID, malwareID})
+		assert.Equal(t, getIDs(idx.Search("cobalt", stixindex.SearchOptions{Fields: []string{"description"}})), []string{malwareID})
+		assert.Equal(t, getIDs(idx.Search("cobalt", stixindex.SearchOptions{Limit: 2})), []string{toolID, reportID})
+	})
+
+	t.Run("Замена и удаление", func(t *testing.T) {
+		tool := methodstixobjects.NewToolDomainObjectsSTIX()
+		tool.SetValueID(toolID)
+		tool.SetValueName("Brute Ratel")
+		assert.NoError(t, idx.Add(*tool))
+
+		assert.Equal(t, getIDs(idx.Search("cobalt", stixindex.SearchOptions{})), []string{reportID, malwareID, "x-custom-object--1"})
+		assert.Equal(t, getIDs(idx.Search("ratel", stixindex.SearchOptions{})), []string{toolID})
+
+		assert.True(t, idx.Remove(toolID))
+		assert.False(t, idx.Remove(toolID))
+		assert.Len(t, idx.Search("ratel", stixindex.SearchOptions{}), 0)
+		assert.Equal(t, idx.Len(), 5)
+	})
+
+	assert.Error(t, idx.Add(json.RawMessage(`{"type": "x-custom-object"}`)))
+}
@@ -0,0 +1,130 @@
+package stixstore
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+	"github.com/av-belyakov/methodstixobjects/stixstore/storetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSystemStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) stixstore.Store {
+		s, err := stixstore.NewFileSystemStore(t.TempDir())
+		assert.NoError(t, err)
+
+		return s
+	})
+}
+
+func TestFileSystemStoreLayout(t *testing.T) {
+	root := t.TempDir()
+	s, err := stixstore.NewFileSystemStore(root)
+	assert.NoError(t, err)
+	assert.NoError(t, s.Add(storetest.GetObjects()...))
+
+	//структура директорий python-stix2 FileSystemStore
+	for _, f := range []string{
+		"malware/" + storetest.MalwareID + "/20230101100000000000.json",
+		"malware/" + storetest.MalwareID + "/20230201100000000000.json",
+		"indicator/" + storetest.IndicatorID + "/20230105000000000000.json",
+		"domain-name/" + storetest.DomainID + ".json",
+	} {
+		_, err := os.Stat(filepath.Join(root, f))
+		assert.NoError(t, err, f)
+	}
+
+	//временные файлы не остаются
+	matches, err := filepath.Glob(filepath.Join(root, "malware", storetest.MalwareID, ".tmp-*"))
+	assert.NoError(t, err)
+	assert.Len(t, matches, 0)
+
+	//объекты декодируются методами DecodeJSON соответствующих типов
+	obj, err := s.Get(storetest.MalwareID)
+	assert.NoError(t, err)
+	malware, ok := obj.(domainobjectsstix.MalwareDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, malware.GetName(), "Poison Ivy v2")
+
+	obj, err = s.Get(storetest.DomainID)
+	assert.NoError(t, err)
+	_, ok = obj.(cyberobservableobjectsstix.DomainNameCyberObservableObjectSTIX)
+	assert.True(t, ok)
+
+	_, err = s.Get("../malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b")
+	assert.Error(t, err)
+}
+
+func TestFileSystemStorePythonCompatibility(t *testing.T) {
+	root := t.TempDir()
+	const (
+		attackPatternID = "attack-pattern--0a3ead4e-6d47-4ccb-854c-a6a4f9d96b22"
+		campaignID      = "campaign--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+		customID        = "x-custom-object--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	)
+
+	files := map[string]string{
+		//объект, записанный python-stix2 (STIX 2.1)
+		"attack-pattern/" + attackPatternID + "/20170531213258226477.json": `{
+    "type": "attack-pattern",
+    "spec_version": "2.1",
+    "id": "` + attackPatternID + `",
+    "created": "2017-05-31T21:32:58.226477Z",
+    "modified": "2017-05-31T21:32:58.226477Z",
+    "name": "Spearphishing Link"
+}`,
+		//объект в составе "bundle" (формат STIX 2.0)
+		"campaign/" + campaignID + "/20160405000000000000.json": `{
+    "type": "bundle",
+    "id": "bundle--f64de948-7067-4534-8018-85f03d470625",
+    "spec_version": "2.0",
+    "objects": [{"type": "campaign", "id": "` + campaignID + `", "created": "2016-04-05T00:00:00.000Z", "modified": "2016-04-05T00:00:00.000Z", "name": "Green Group Attacks"}]
+}`,
+		"x-custom-object/" + customID + "/20200101000000000000.json": `{"type": "x-custom-object", "id": "` + customID + `", "created": "2020-01-01T00:00:00Z", "modified": "2020-01-01T00:00:00Z", "x_value": 1}`,
+	}
+
+	for k, v := range files {
+		path := filepath.Join(root, filepath.FromSlash(k))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(v), 0o644))
+	}
+
+	s, err := stixstore.NewFileSystemStore(root)
+	assert.NoError(t, err)
+
+	obj, err := s.GetVersion(attackPatternID, "2017-05-31T21:32:58.226477Z")
+	assert.NoError(t, err)
+	ap, ok := obj.(domainobjectsstix.AttackPatternDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, ap.GetName(), "Spearphishing Link")
+
+	obj, err = s.Get(campaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, storetest.GetProperty(t, obj, "name"), "Green Group Attacks")
+
+	//объекты неизвестных типов возвращаются в виде json.RawMessage
+	obj, err = s.Get(customID)
+	assert.NoError(t, err)
+	_, ok = obj.(json.RawMessage)
+	assert.True(t, ok)
+
+	list, err := s.Query(nil)
+	assert.NoError(t, err)
+	assert.Len(t, list, 3)
+
+	//новая версия записывается в формате python-stix2
+	assert.NoError(t, s.Revoke(attackPatternID))
+	versions, err := s.GetAllVersions(attackPatternID)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+
+	entries, err := os.ReadDir(filepath.Join(root, "attack-pattern", attackPatternID))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Len(t, entries[1].Name(), len("20170531213258226477.json"))
+}
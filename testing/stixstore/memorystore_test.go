@@ -0,0 +1,42 @@
+package stixstore
+
+import (
+	"testing"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+	"github.com/av-belyakov/methodstixobjects/stixstore/storetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) stixstore.Store {
+		s, err := stixstore.NewMemoryStore()
+		assert.NoError(t, err)
+
+		return s
+	})
+}
+
+func TestMemoryStoreTypedObjects(t *testing.T) {
+	m := methodstixobjects.NewMalwareDomainObjectsSTIX()
+	m.SetValueID(storetest.MalwareID)
+	m.SetValueName("Poison Ivy")
+	m.SetValueCreated("2023-01-01T10:00:00+00:00")
+	m.SetValueModified("2023-01-01T10:00:00+00:00")
+
+	s, err := stixstore.NewMemoryStore(*m)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Revoke(storetest.MalwareID))
+
+	obj, err := s.Get(storetest.MalwareID)
+	assert.NoError(t, err)
+
+	//отозванная версия имеет тот же тип, что и исходный объект
+	revoked, ok := obj.(domainobjectsstix.MalwareDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.True(t, revoked.GetRevoked())
+	assert.Equal(t, revoked.GetName(), "Poison Ivy")
+}
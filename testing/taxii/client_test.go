@@ -0,0 +1,258 @@
+package taxii
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	methodstixobjects "github.com/av-belyakov/methodstixobjects/cmd"
+	"github.com/av-belyakov/methodstixobjects/datamodels/cyberobservableobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/datamodels/domainobjectsstix"
+	"github.com/av-belyakov/methodstixobjects/taxii"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getObjectIDs(t *testing.T, list []interface{}) []string {
+	ids := []string{}
+	for _, v := range list {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+
+		obj := struct {
+			ID string `json:"id"`
+		}{}
+		require.NoError(t, json.Unmarshal(b, &obj))
+		ids = append(ids, obj.ID)
+	}
+
+	return ids
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	ts, collection := newTestServer(t, taxii.ServerOptions{
+		PageSize: 2,
+		Authenticate: func(username, password string) bool {
+			return users[username] == password
+		},
+	})
+
+	client := taxii.NewClient(taxii.ClientOptions{Username: "writer", Password: users["writer"]})
+
+	discovery, err := client.GetDiscovery(ctx, ts.URL+"/taxii2/")
+	require.NoError(t, err)
+	require.Len(t, discovery.APIRoots, 1)
+	apiRootURL := discovery.APIRoots[0]
+
+	root, err := client.GetAPIRoot(ctx, apiRootURL)
+	assert.NoError(t, err)
+	assert.Equal(t, root.Title, "Threat intelligence")
+
+	collections, err := client.GetCollections(ctx, apiRootURL)
+	assert.NoError(t, err)
+	assert.Equal(t, collections, []taxii.Collection{collection})
+
+	info, err := client.GetCollection(ctx, apiRootURL, "intel")
+	assert.NoError(t, err)
+	assert.Equal(t, info.ID, collection.ID)
+
+	//объекты декодируются в типы объектов STIX
+	result, err := client.GetObjects(ctx, apiRootURL, collection.ID, taxii.ObjectsQuery{})
+	require.NoError(t, err)
+	require.Len(t, result.Objects, 1)
+	indicator, ok := result.Objects[0].(domainobjectsstix.IndicatorDomainObjectsSTIX)
+	assert.True(t, ok)
+	assert.Equal(t, indicator.GetName(), "APT hash")
+	bookmark := result.DateAddedLast
+	assert.NotEmpty(t, bookmark)
+
+	t.Run("Добавление объектов", func(t *testing.T) {
+		malware := methodstixobjects.NewMalwareDomainObjectsSTIX()
+		malware.SetValueID(malwareID)
+		malware.SetValueName("Poison Ivy")
+		malware.SetValueCreated("2023-02-01T00:00:00Z")
+		malware.SetValueModified("2023-02-01T00:00:00Z")
+
+		file := methodstixobjects.NewFileCyberObservableObjectSTIX()
+		file.SetValueID(fileID)
+		file.SetValueName("beacon.exe")
+
+		status, err := client.AddObjects(ctx, apiRootURL, collection.ID, []interface{}{
+			*malware,
+			file,
+			json.RawMessage(`{"type": "tool", "id": "` + toolID + `", "name": "Cobalt Strike"}`),
+			json.RawMessage(`{"type": "tool", "name": "without id"}`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, status.Status, taxii.StatusComplete)
+		assert.Equal(t, status.SuccessCount, 3)
+		assert.Len(t, status.Failures, 1)
+
+		versions, err := client.GetVersions(ctx, apiRootURL, collection.ID, malwareID)
+		assert.NoError(t, err)
+		assert.Equal(t, versions, []string{"2023-02-01T00:00:00Z"})
+
+		manifest, err := client.GetManifest(ctx, apiRootURL, collection.ID, taxii.ObjectsQuery{Types: []string{"tool"}})
+		assert.NoError(t, err)
+		require.Len(t, manifest.Objects, 1)
+		assert.Equal(t, manifest.Objects[0].ID, toolID)
+	})
+
+	t.Run("Постраничное получение новых объектов", func(t *testing.T) {
+		//страницы объектов запрашиваются до получения всех объектов
+		result, err := client.GetObjects(ctx, apiRootURL, collection.ID, taxii.ObjectsQuery{AddedAfter: bookmark})
+		require.NoError(t, err)
+		assert.Equal(t, getObjectIDs(t, result.Objects), []string{fileID, malwareID, toolID})
+		_, ok := result.Objects[0].(cyberobservableobjectsstix.FileCyberObservableObjectSTIX)
+		assert.True(t, ok)
+		assert.Greater(t, result.DateAddedLast, bookmark)
+
+		all, err := client.GetObjects(ctx, apiRootURL, collection.ID, taxii.ObjectsQuery{Limit: 1})
+		require.NoError(t, err)
+		assert.Len(t, all.Objects, 4)
+		assert.Equal(t, all.DateAddedLast, result.DateAddedLast)
+
+		//при отсутствии новых объектов время последнего объекта не изменяется
+		next, err := client.GetObjects(ctx, apiRootURL, collection.ID, taxii.ObjectsQuery{AddedAfter: result.DateAddedLast})
+		require.NoError(t, err)
+		assert.Len(t, next.Objects, 0)
+		assert.Equal(t, next.DateAddedLast, result.DateAddedLast)
+
+		result, err = client.GetObjects(ctx, apiRootURL, collection.ID, taxii.ObjectsQuery{Types: []string{"malware", "indicator"}, Versions: []string{"all"}})
+		require.NoError(t, err)
+		assert.Equal(t, getObjectIDs(t, result.Objects), []string{indicatorID, malwareID})
+	})
+
+	t.Run("Ошибки", func(t *testing.T) {
+		_, err := taxii.NewClient(taxii.ClientOptions{Username: "writer", Password: "wrong"}).GetCollections(ctx, apiRootURL)
+		e := taxii.Error{}
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, e.HTTPStatus, "401")
+
+		_, err = client.GetCollection(ctx, apiRootURL, "unknown")
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, e.HTTPStatus, "404")
+	})
+}
+
+// stubServer заглушка сервера TAXII, не поддерживающая параметр next и обрабатывающая запросы на добавление
+// объектов асинхронно
+type stubServer struct {
+	mu          sync.Mutex
+	objects     []string
+	statusCalls int
+	queries     []string
+}
+
+func (s *stubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", taxii.MediaType)
+
+	switch r.URL.Path {
+	case "/taxii2/":
+		w.Write([]byte(`{"title": "Stub", "default": "/api1/", "api_roots": ["/api1/", "https://example.com/api2/"]}`))
+
+	case "/api1/collections/feed/objects/":
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id": "status-1", "status": "pending", "total_count": 1, "success_count": 0, "failure_count": 0, "pending_count": 1}`))
+
+			return
+		}
+
+		s.queries = append(s.queries, r.URL.RawQuery)
+
+		//время добавления объекта i равно 2023-01-01T00:00:0i.000000Z, на странице не более двух объектов
+		page := []json.RawMessage{}
+		last := ""
+		addedAfter := r.URL.Query().Get("added_after")
+		for i, v := range s.objects {
+			dateAdded := fmt.Sprintf("2023-01-01T00:00:0%d.000000Z", i)
+			if dateAdded <= addedAfter || len(page) == 2 {
+				continue
+			}
+
+			page = append(page, json.RawMessage(v))
+			last = dateAdded
+		}
+
+		more := last != "" && last < fmt.Sprintf("2023-01-01T00:00:0%d.000000Z", len(s.objects)-1)
+		if last != "" {
+			w.Header().Set(taxii.HeaderDateAddedLast, last)
+		}
+		json.NewEncoder(w).Encode(taxii.Envelope{More: more, Objects: page})
+
+	case "/api1/status/status-1/":
+		s.statusCalls++
+		if s.statusCalls < 3 {
+			w.Write([]byte(`{"id": "status-1", "status": "pending", "total_count": 1, "success_count": 0, "failure_count": 0, "pending_count": 1}`))
+
+			return
+		}
+		w.Write([]byte(`{"id": "status-1", "status": "complete", "total_count": 1, "success_count": 1, "successes": [{"id": "` + toolID + `", "version": "2023-01-01T00:00:00Z"}], "failure_count": 0, "pending_count": 0}`))
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`not found`))
+	}
+}
+
+func TestClientStubServer(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubServer{}
+	for i := 0; i < 5; i++ {
+		stub.objects = append(stub.objects, fmt.Sprintf(`{"type": "x-custom-object", "id": "x-custom-object--%d"}`, i))
+	}
+
+	ts := httptest.NewServer(stub)
+	defer ts.Close()
+
+	client := taxii.NewClient(taxii.ClientOptions{PollInterval: 10 * time.Millisecond})
+
+	//относительные адреса корней API преобразуются в абсолютные
+	discovery, err := client.GetDiscovery(ctx, ts.URL+"/taxii2/")
+	require.NoError(t, err)
+	assert.Equal(t, discovery.APIRoots, []string{ts.URL + "/api1/", "https://example.com/api2/"})
+	assert.Equal(t, discovery.Default, ts.URL+"/api1/")
+
+	//при отсутствии параметра next следующая страница запрашивается по времени добавления последнего объекта
+	result, err := client.GetObjects(ctx, discovery.Default, "feed", taxii.ObjectsQuery{AddedAfter: "2023-01-01T00:00:00.000000Z"})
+	require.NoError(t, err)
+	assert.Equal(t, getObjectIDs(t, result.Objects), []string{"x-custom-object--1", "x-custom-object--2", "x-custom-object--3", "x-custom-object--4"})
+	assert.Equal(t, result.DateAddedLast, "2023-01-01T00:00:04.000000Z")
+	assert.Equal(t, stub.queries, []string{
+		"added_after=2023-01-01T00%3A00%3A00.000000Z",
+		"added_after=2023-01-01T00%3A00%3A02.000000Z",
+	})
+
+	//объекты неизвестных типов возвращаются в виде JSON
+	_, ok := result.Objects[0].(json.RawMessage)
+	assert.True(t, ok)
+
+	//состояние обработки запроса запрашивается до его завершения
+	status, err := client.AddObjects(ctx, discovery.Default, "feed", []interface{}{json.RawMessage(`{"type": "tool", "id": "` + toolID + `"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, status.Status, taxii.StatusComplete)
+	assert.Equal(t, status.Successes, []taxii.StatusDetails{{ID: toolID, Version: "2023-01-01T00:00:00Z"}})
+	assert.Equal(t, stub.statusCalls, 3)
+
+	//ожидание завершается при отмене контекста
+	stub.statusCalls = 0
+	cancelCtx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	status, err = client.WaitStatus(cancelCtx, discovery.Default, taxii.Status{ID: "status-1", Status: taxii.StatusPending})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, status.Status, taxii.StatusPending)
+
+	_, err = client.GetCollections(ctx, discovery.Default)
+	assert.ErrorContains(t, err, "Not Found: not found")
+}
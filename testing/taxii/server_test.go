@@ -0,0 +1,326 @@
+package taxii
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/av-belyakov/methodstixobjects/stixstore"
+	"github.com/av-belyakov/methodstixobjects/taxii"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	indicatorID = "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+	malwareID   = "malware--31b940d4-6f7f-459a-80ea-9c1f17b5891b"
+	fileID      = "file--5a27d487-c542-5f97-a131-a8866b477b46"
+	toolID      = "tool--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f"
+)
+
+var users = map[string]string{"reader": "reader-password", "writer": "writer-password"}
+
+// newTestServer создает сервер с корнем API "api1" и коллекцией "intel", содержащей индикатор
+func newTestServer(t *testing.T, opts taxii.ServerOptions) (*httptest.Server, taxii.Collection) {
+	store, err := stixstore.NewMemoryStore(json.RawMessage(`{"type": "indicator", "spec_version": "2.1", "id": "` + indicatorID + `", "created": "2023-01-01T00:00:00Z", "modified": "2023-01-01T00:00:00Z", "name": "APT hash", "pattern": "[file:hashes.MD5 = 'd41d8cd98f00b204e9800998ecf8427e']", "pattern_type": "stix", "valid_from": "2023-01-01T00:00:00Z"}`))
+	require.NoError(t, err)
+
+	server := taxii.NewServer(opts)
+	require.NoError(t, server.AddAPIRoot("api1", taxii.APIRoot{Title: "Threat intelligence", MaxContentLength: 4096}))
+	assert.Error(t, server.AddAPIRoot("api1", taxii.APIRoot{}))
+	assert.Error(t, server.AddAPIRoot("taxii2", taxii.APIRoot{}))
+
+	collection, err := server.AddCollection("api1", taxii.Collection{Title: "Intel", Alias: "intel", CanRead: true, CanWrite: true}, store)
+	require.NoError(t, err)
+	assert.NotEmpty(t, collection.ID)
+
+	_, err = server.AddCollection("api2", taxii.Collection{Title: "Unknown"}, store)
+	assert.Error(t, err)
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	return ts, collection
+}
+
+func request(t *testing.T, method, u, username string, body interface{}) (*http.Response, []byte) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, u, reader)
+	require.NoError(t, err)
+	req.Header.Set("Accept", taxii.MediaType)
+	if body != nil {
+		req.Header.Set("Content-Type", taxii.MediaType)
+	}
+
+	if username != "" {
+		req.SetBasicAuth(username, users[username])
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return res, b
+}
+
+func get[T any](t *testing.T, u, username string) (T, http.Header) {
+	var v T
+
+	res, b := request(t, http.MethodGet, u, username, nil)
+	require.Equal(t, res.StatusCode, http.StatusOK, string(b))
+	assert.Equal(t, res.Header.Get("Content-Type"), taxii.MediaType)
+	require.NoError(t, json.Unmarshal(b, &v))
+
+	return v, res.Header
+}
+
+func getIDs(t *testing.T, envelope taxii.Envelope) []string {
+	ids := []string{}
+	for _, raw := range envelope.Objects {
+		obj := struct {
+			ID string `json:"id"`
+		}{}
+		require.NoError(t, json.Unmarshal(raw, &obj))
+		ids = append(ids, obj.ID)
+	}
+
+	return ids
+}
+
+func getEnvelope(objects ...string) taxii.Envelope {
+	envelope := taxii.Envelope{}
+	for _, v := range objects {
+		envelope.Objects = append(envelope.Objects, json.RawMessage(v))
+	}
+
+	return envelope
+}
+
+func TestServerResources(t *testing.T) {
+	ts, collection := newTestServer(t, taxii.ServerOptions{Title: "TAXII server", Default: "api1"})
+
+	discovery, _ := get[taxii.Discovery](t, ts.URL+"/taxii2/", "")
+	assert.Equal(t, discovery, taxii.Discovery{Title: "TAXII server", Default: ts.URL + "/api1/", APIRoots: []string{ts.URL + "/api1/"}})
+
+	root, _ := get[taxii.APIRoot](t, ts.URL+"/api1/", "")
+	assert.Equal(t, root, taxii.APIRoot{Title: "Threat intelligence", Versions: []string{taxii.MediaType}, MaxContentLength: 4096})
+
+	collections, _ := get[taxii.Collections](t, ts.URL+"/api1/collections/", "")
+	assert.Equal(t, collections.Collections, []taxii.Collection{collection})
+
+	//коллекция доступна по идентификатору и по псевдониму
+	info, _ := get[taxii.Collection](t, ts.URL+"/api1/collections/"+collection.ID+"/", "")
+	assert.Equal(t, info.MediaTypes, []string{taxii.STIXMediaType})
+	info, _ = get[taxii.Collection](t, ts.URL+"/api1/collections/intel/", "")
+	assert.Equal(t, info.ID, collection.ID)
+
+	for u, status := range map[string]int{
+		"/api2/":                                          http.StatusNotFound,
+		"/api1/collections/unknown/":                      http.StatusNotFound,
+		"/api1/collections/intel/unknown/":                http.StatusNotFound,
+		"/api1/status/unknown/":                           http.StatusNotFound,
+		"/api1/collections/intel/objects/" + toolID + "/": http.StatusNotFound,
+	} {
+		res, b := request(t, http.MethodGet, ts.URL+u, "", nil)
+		assert.Equal(t, res.StatusCode, status, u)
+
+		e := taxii.Error{}
+		assert.NoError(t, json.Unmarshal(b, &e))
+		assert.Equal(t, e.HTTPStatus, "404")
+	}
+
+	res, _ := request(t, http.MethodPost, ts.URL+"/api1/collections/intel/", "", taxii.Envelope{})
+	assert.Equal(t, res.StatusCode, http.StatusMethodNotAllowed)
+
+	//ответ возвращается только в формате TAXII 2.1
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/taxii2/", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, res.StatusCode, http.StatusNotAcceptable)
+
+	req.Header.Set("Accept", "application/json, application/taxii+json")
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+}
+
+func TestServerObjects(t *testing.T) {
+	ts, _ := newTestServer(t, taxii.ServerOptions{PageSize: 3})
+	objectsURL := ts.URL + "/api1/collections/intel/objects/"
+
+	envelope, header := get[taxii.Envelope](t, objectsURL, "")
+	assert.Equal(t, getIDs(t, envelope), []string{indicatorID})
+	assert.False(t, envelope.More)
+	lastAdded := header.Get(taxii.HeaderDateAddedLast)
+	assert.NotEmpty(t, lastAdded)
+
+	t.Run("Добавление объектов", func(t *testing.T) {
+		res, b := request(t, http.MethodPost, objectsURL, "", getEnvelope(
+			`{"type": "malware", "spec_version": "2.1", "id": "`+malwareID+`", "created": "2023-02-01T00:00:00Z", "modified": "2023-02-01T00:00:00Z", "name": "Poison Ivy", "is_family": true}`,
+			`{"type": "indicator", "spec_version": "2.1", "id": "`+indicatorID+`", "created": "2023-01-01T00:00:00Z", "modified": "2023-03-01T03:00:00+03:00", "name": "APT hash v2", "pattern": "[file:hashes.MD5 = 'd41d8cd98f00b204e9800998ecf8427e']", "pattern_type": "stix", "valid_from": "2023-01-01T00:00:00Z"}`,
+			`{"type": "file", "id": "`+fileID+`", "name": "beacon.exe"}`,
+			`{"type": "tool", "name": "without id"}`,
+		))
+		require.Equal(t, res.StatusCode, http.StatusAccepted, string(b))
+
+		status := taxii.Status{}
+		require.NoError(t, json.Unmarshal(b, &status))
+		assert.Equal(t, status.Status, taxii.StatusComplete)
+		assert.Equal(t, status.TotalCount, 4)
+		assert.Equal(t, status.SuccessCount, 3)
+		assert.Equal(t, status.FailureCount, 1)
+		assert.Equal(t, status.Successes[1], taxii.StatusDetails{ID: indicatorID, Version: "2023-03-01T03:00:00+03:00"})
+
+		saved, _ := get[taxii.Status](t, ts.URL+"/api1/status/"+status.ID+"/", "")
+		assert.Equal(t, saved, status)
+
+		//тело запроса должно быть в формате TAXII и не превышать максимальный размер
+		req, err := http.NewRequest(http.MethodPost, objectsURL, strings.NewReader(`{"objects": []}`))
+		require.NoError(t, err)
+		req.Header.Set("Accept", taxii.MediaType)
+		req.Header.Set("Content-Type", "application/json")
+		res, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, res.StatusCode, http.StatusUnsupportedMediaType)
+
+		res, _ = request(t, http.MethodPost, objectsURL, "", getEnvelope(`{"type": "note", "content": "`+strings.Repeat("x", 5000)+`"}`))
+		assert.Equal(t, res.StatusCode, http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("Отбор объектов", func(t *testing.T) {
+		//объекты, добавленные одним запросом, упорядочены по идентификатору
+		envelope, _ := get[taxii.Envelope](t, objectsURL, "")
+		assert.Equal(t, getIDs(t, envelope), []string{fileID, indicatorID, malwareID})
+
+		//добавленные после предыдущего запроса объекты
+		envelope, header := get[taxii.Envelope](t, objectsURL+"?added_after="+url.QueryEscape(lastAdded), "")
+		assert.Equal(t, getIDs(t, envelope), []string{fileID, indicatorID, malwareID})
+		envelope, _ = get[taxii.Envelope](t, objectsURL+"?added_after="+url.QueryEscape(header.Get(taxii.HeaderDateAddedLast)), "")
+		assert.Len(t, envelope.Objects, 0)
+
+		envelope, _ = get[taxii.Envelope](t, objectsURL+"?match[type]=malware,file", "")
+		assert.Equal(t, getIDs(t, envelope), []string{fileID, malwareID})
+		envelope, _ = get[taxii.Envelope](t, objectsURL+"?match[id]="+indicatorID+"&match[id]="+fileID, "")
+		assert.Equal(t, getIDs(t, envelope), []string{fileID, indicatorID})
+		envelope, _ = get[taxii.Envelope](t, objectsURL+"?match[spec_version]=2.0", "")
+		assert.Len(t, envelope.Objects, 0)
+
+		//версии объектов
+		envelope, _ = get[taxii.Envelope](t, objectsURL+"?match[version]=all&match[type]=indicator", "")
+		assert.Equal(t, getIDs(t, envelope), []string{indicatorID, indicatorID})
+		assert.Contains(t, string(envelope.Objects[0]), `"name":"APT hash"`)
+		envelope, _ = get[taxii.Envelope](t, objectsURL+"?match[version]=first&match[type]=indicator", "")
+		assert.Contains(t, string(envelope.Objects[0]), `"name":"APT hash"`)
+		envelope, _ = get[taxii.Envelope](t, objectsURL+"?match[version]=2023-03-01T00:00:00Z", "")
+		assert.Equal(t, getIDs(t, envelope), []string{indicatorID})
+		assert.Contains(t, string(envelope.Objects[0]), `"name":"APT hash v2"`)
+
+		for _, query := range []string{"?added_after=yesterday", "?limit=0", "?match[version]=latest", "?next=!!!"} {
+			res, _ := request(t, http.MethodGet, objectsURL+query, "", nil)
+			assert.Equal(t, res.StatusCode, http.StatusBadRequest, query)
+		}
+	})
+
+	t.Run("Постраничный вывод", func(t *testing.T) {
+		ids := []string{}
+		next := ""
+		for i := 0; i < 10; i++ {
+			envelope, _ := get[taxii.Envelope](t, objectsURL+"?match[version]=all&limit=2&next="+next, "")
+			ids = append(ids, getIDs(t, envelope)...)
+
+			if !envelope.More {
+				assert.Empty(t, envelope.Next)
+
+				break
+			}
+
+			assert.Len(t, envelope.Objects, 2)
+			next = envelope.Next
+		}
+		assert.Equal(t, ids, []string{indicatorID, fileID, indicatorID, malwareID})
+
+		//размер страницы не превышает максимального, заданного параметрами сервера
+		envelope, _ := get[taxii.Envelope](t, objectsURL+"?match[version]=all&limit=100", "")
+		assert.Len(t, envelope.Objects, 3)
+		assert.True(t, envelope.More)
+	})
+
+	t.Run("Манифест и версии", func(t *testing.T) {
+		manifest, _ := get[taxii.Manifest](t, ts.URL+"/api1/collections/intel/manifest/?match[type]=indicator&match[version]=all", "")
+		require.Len(t, manifest.Objects, 2)
+		assert.Equal(t, manifest.Objects[0].ID, indicatorID)
+		assert.Equal(t, manifest.Objects[0].Version, "2023-01-01T00:00:00Z")
+		assert.Equal(t, manifest.Objects[0].MediaType, taxii.STIXMediaType)
+		assert.Less(t, manifest.Objects[0].DateAdded, manifest.Objects[1].DateAdded)
+
+		versions, header := get[taxii.Versions](t, objectsURL+indicatorID+"/versions/", "")
+		assert.Equal(t, versions.Versions, []string{"2023-01-01T00:00:00Z", "2023-03-01T03:00:00+03:00"})
+		assert.Equal(t, header.Get(taxii.HeaderDateAddedFirst), manifest.Objects[0].DateAdded)
+
+		//у объекта без времени изменения версией является время добавления
+		versions, _ = get[taxii.Versions](t, objectsURL+fileID+"/versions/", "")
+		assert.Len(t, versions.Versions, 1)
+
+		envelope, _ := get[taxii.Envelope](t, objectsURL+indicatorID+"/", "")
+		assert.Len(t, envelope.Objects, 1)
+		assert.Contains(t, string(envelope.Objects[0]), `"name":"APT hash v2"`)
+	})
+
+	t.Run("Удаление объекта", func(t *testing.T) {
+		res, _ := request(t, http.MethodDelete, objectsURL+malwareID+"/", "", nil)
+		assert.Equal(t, res.StatusCode, http.StatusOK)
+
+		res, _ = request(t, http.MethodDelete, objectsURL+malwareID+"/", "", nil)
+		assert.Equal(t, res.StatusCode, http.StatusNotFound)
+
+		envelope, _ := get[taxii.Envelope](t, objectsURL, "")
+		assert.Equal(t, getIDs(t, envelope), []string{fileID, indicatorID})
+	})
+}
+
+func TestServerAuth(t *testing.T) {
+	ts, collection := newTestServer(t, taxii.ServerOptions{
+		Authenticate: func(username, password string) bool {
+			v, ok := users[username]
+
+			return ok && v == password
+		},
+		Authorize: func(username string, collection taxii.Collection, write bool) bool {
+			return !write || username == "writer"
+		},
+	})
+
+	res, _ := request(t, http.MethodGet, ts.URL+"/taxii2/", "", nil)
+	assert.Equal(t, res.StatusCode, http.StatusUnauthorized)
+	assert.Equal(t, res.Header.Get("WWW-Authenticate"), `Basic realm="TAXII"`)
+
+	collections, _ := get[taxii.Collections](t, ts.URL+"/api1/collections/", "reader")
+	assert.True(t, collections.Collections[0].CanRead)
+	assert.False(t, collections.Collections[0].CanWrite)
+
+	envelope := getEnvelope(`{"type": "file", "id": "` + fileID + `", "name": "beacon.exe"}`)
+	res, _ = request(t, http.MethodPost, ts.URL+"/api1/collections/"+collection.ID+"/objects/", "reader", envelope)
+	assert.Equal(t, res.StatusCode, http.StatusForbidden)
+
+	res, _ = request(t, http.MethodPost, ts.URL+"/api1/collections/"+collection.ID+"/objects/", "writer", envelope)
+	assert.Equal(t, res.StatusCode, http.StatusAccepted)
+}